@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"a9s/internal/cmd/ctx"
+
+	"github.com/spf13/cobra"
+)
+
+var ctxCmd = &cobra.Command{
+	Use:   "ctx",
+	Short: "Manage contexts (saved profile, region and resource)",
+}
+
+var ctxListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List contexts",
+	Run:   ctx.List,
+}
+
+var ctxUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Start a9s in a context",
+	Args:  cobra.ExactArgs(1),
+	Run:   ctx.Use,
+}
+
+func init() {
+	ctxCmd.AddCommand(ctxListCmd)
+	ctxCmd.AddCommand(ctxUseCmd)
+	rootCmd.AddCommand(ctxCmd)
+}
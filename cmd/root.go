@@ -3,8 +3,11 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
 	"a9s/internal/cmd/root"
+	"a9s/internal/resources"
 	"a9s/pkg/log"
 
 	"github.com/spf13/cobra"
@@ -19,13 +22,67 @@ var rootCmd = &cobra.Command{
 }
 
 func init() {
-	cobra.OnInitialize(initLogger)
+	cobra.OnInitialize(initConfig, initLogger)
 
+	rootCmd.PersistentFlags().String("config", "", "Path of the configuration file (default ~/.a9s/config.yaml)")
 	rootCmd.PersistentFlags().Bool("debug", false, "Enable debug mode")
+	rootCmd.PersistentFlags().Bool("demo", false, "Explore a9s on fixture data, without credentials nor network")
+	rootCmd.PersistentFlags().String("context", "", "Context to start in, overrides 'a9s ctx use' (env A9S_CONTEXT)")
+	rootCmd.PersistentFlags().Bool("ticket", false, "Prompt for a change ticket before mutating actions")
+	rootCmd.PersistentFlags().String("audit-log", "", "Path of the audit log (default ~/.a9s/audit.log)")
+	rootCmd.PersistentFlags().Duration("idle-timeout", 0, "Lock the UI after this period of inactivity (0 disables)")
+	rootCmd.PersistentFlags().Bool("preflight", false, "Check the IAM permissions of a resource before its first fetch")
+	rootCmd.PersistentFlags().Bool("mask", true, "Mask sensitive values until revealed with Ctrl+R")
+	rootCmd.PersistentFlags().Duration("key-max-age", 90*24*time.Hour, "Highlight IAM access keys older than this age")
+	rootCmd.PersistentFlags().Duration("anomaly-check", 0, "Check for new cost anomalies at this interval (0 disables)")
+	rootCmd.PersistentFlags().Duration("cache-ttl", 30*time.Second, "Show a view again without fetching it while its data is younger than this (0 disables)")
+	rootCmd.PersistentFlags().String("retry-mode", "", "Retry mode of the calls to AWS, standard or adaptive (default: the SDK's, or AWS_RETRY_MODE)")
+	rootCmd.PersistentFlags().Int("retry-max-attempts", 0, "Attempts of a call to AWS, the first one included (0 keeps the SDK's default)")
+	rootCmd.PersistentFlags().Duration("retry-max-backoff", 0, "Longest wait between two attempts of a call to AWS (0 keeps the SDK's default)")
+	rootCmd.PersistentFlags().StringSlice("required-tags", resources.DefaultRequiredTags, "Tags every resource must have, checked by the tag-compliance view")
+	rootCmd.PersistentFlags().StringSlice("mask-patterns", nil, "Regular expressions of values to mask (default: passwords, secrets, tokens, keys, emails)")
 
 	viper.BindPFlag("debug", rootCmd.PersistentFlags().Lookup("debug"))
+	viper.BindPFlag("demo", rootCmd.PersistentFlags().Lookup("demo"))
+	viper.BindPFlag("context", rootCmd.PersistentFlags().Lookup("context"))
+	viper.BindEnv("context", "A9S_CONTEXT")
+	viper.BindPFlag("ticket", rootCmd.PersistentFlags().Lookup("ticket"))
+	viper.BindPFlag("audit-log", rootCmd.PersistentFlags().Lookup("audit-log"))
+	viper.BindPFlag("idle-timeout", rootCmd.PersistentFlags().Lookup("idle-timeout"))
+	viper.BindPFlag("preflight", rootCmd.PersistentFlags().Lookup("preflight"))
+	viper.BindPFlag("mask", rootCmd.PersistentFlags().Lookup("mask"))
+	viper.BindPFlag("mask-patterns", rootCmd.PersistentFlags().Lookup("mask-patterns"))
+	viper.BindPFlag("key-max-age", rootCmd.PersistentFlags().Lookup("key-max-age"))
+	viper.BindPFlag("anomaly-check", rootCmd.PersistentFlags().Lookup("anomaly-check"))
+	viper.BindPFlag("cache-ttl", rootCmd.PersistentFlags().Lookup("cache-ttl"))
+	viper.BindPFlag("retry-mode", rootCmd.PersistentFlags().Lookup("retry-mode"))
+	viper.BindPFlag("retry-max-attempts", rootCmd.PersistentFlags().Lookup("retry-max-attempts"))
+	viper.BindPFlag("retry-max-backoff", rootCmd.PersistentFlags().Lookup("retry-max-backoff"))
+	viper.BindPFlag("required-tags", rootCmd.PersistentFlags().Lookup("required-tags"))
 
 	viper.SetDefault("debug", false)
+	viper.SetDefault("ticket", false)
+	viper.SetDefault("preflight", false)
+	viper.SetDefault("mask", true)
+}
+
+func initConfig() {
+	if path, _ := rootCmd.PersistentFlags().GetString("config"); path != "" {
+		viper.SetConfigFile(path)
+	} else {
+		if home, err := os.UserHomeDir(); err == nil {
+			viper.AddConfigPath(filepath.Join(home, ".a9s"))
+		}
+		viper.SetConfigName("config")
+		viper.SetConfigType("yaml")
+	}
+
+	if err := viper.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			fmt.Fprintf(os.Stderr, "Failed to read configuration: %v\n", err)
+			os.Exit(1)
+		}
+	}
 }
 
 func initLogger() {
@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Entry represents a single record of the audit log
+type Entry struct {
+	Time     time.Time `json:"time"`
+	Profile  string    `json:"profile"`
+	Region   string    `json:"region"`
+	Resource string    `json:"resource"`
+	Action   string    `json:"action"`
+	Target   string    `json:"target,omitempty"`
+	Ticket   string    `json:"ticket,omitempty"`
+	Error    string    `json:"error,omitempty"`
+
+	// Due is set on reminder entries, e.g. to delete a rotated access key
+	Due *time.Time `json:"due,omitempty"`
+}
+
+var (
+	mu   sync.Mutex
+	file *os.File
+)
+
+// DefaultPath returns the default location of the audit log
+func DefaultPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "a9s-audit.log"
+	}
+	return filepath.Join(home, ".a9s", "audit.log")
+}
+
+// Init opens the audit log at the given path, creating it if needed
+func Init(path string) error {
+	if path == "" {
+		path = DefaultPath()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log %s: %w", path, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	file = f
+	return nil
+}
+
+// Record appends an entry to the audit log, it is a no-op when the log is not initialized
+func Record(entry Entry) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if file == nil {
+		return
+	}
+
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	file.Write(append(data, '\n'))
+}
+
+// Close closes the audit log
+func Close() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if file != nil {
+		file.Close()
+		file = nil
+	}
+}
@@ -0,0 +1,247 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
+)
+
+// roleDuration is the lifetime of the credentials of an assumed role before they are refreshed
+const roleDuration = time.Hour
+
+// assumedRole holds the temporary credentials of a role assumed on top of the profile
+type assumedRole struct {
+	arn         string
+	credentials aws.CredentialsProvider
+	profile     aws.CredentialsProvider // credentials of the profile the role was assumed from
+	mfa         bool                    // whether assuming the role asks for an MFA code
+
+	mu      sync.Mutex
+	account string // alias of the account, its ID without one
+	expires time.Time
+}
+
+// accountName returns the alias, or the ID, of the account of the role
+func (r *assumedRole) accountName() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.account
+}
+
+// provide sets the credentials of the role, refreshed by assuming it again when they expire,
+// serial is the MFA device whose code is asked for on each refresh when the role requires it
+func (r *assumedRole) provide(api stscreds.AssumeRoleAPIClient, serial, code string) {
+	r.mfa = serial != ""
+	provider := stscreds.NewAssumeRoleProvider(api, r.arn, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = fmt.Sprintf("a9s-%d", time.Now().Unix())
+		o.Duration = roleDuration
+		if serial == "" {
+			return
+		}
+		o.SerialNumber = aws.String(serial)
+		o.TokenProvider = func() (string, error) {
+			// The code given when assuming the role is used once, a refresh needs a new one
+			if code != "" {
+				given := code
+				code = ""
+				return given, nil
+			}
+			return promptMFA(serial)
+		}
+	})
+	r.credentials = aws.NewCredentialsCache(aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+		credentials, err := provider.Retrieve(ctx)
+		if err == nil {
+			r.mu.Lock()
+			r.expires = credentials.Expires
+			r.mu.Unlock()
+		}
+		return credentials, err
+	}))
+}
+
+// expiration returns the expiration of the current credentials of the role
+func (r *assumedRole) expiration() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.expires
+}
+
+// AssumeRole assumes a role with the current credentials and reinitializes the clients with
+// its temporary ones, assumed again before they expire, mfaCode is the code of the MFA device
+// of the caller, if the role requires it
+func (c *Client) AssumeRole(ctx context.Context, roleARN, mfaCode string) error {
+	role := &assumedRole{arn: roleARN, profile: c.ProfileConfig().Credentials}
+
+	var serial string
+	if mfaCode != "" {
+		var err error
+		if serial, err = c.mfaSerial(ctx); err != nil {
+			return err
+		}
+	}
+	role.provide(c.STS(), serial, mfaCode)
+	_, err := role.credentials.Retrieve(ctx)
+	if err != nil && mfaCode == "" && isAccessDenied(err) {
+		// The trust policy of the role may require MFA, the code is asked for and the call retried
+		if serial, serialErr := c.mfaSerial(ctx); serialErr == nil {
+			role.provide(c.STS(), serial, "")
+			_, err = role.credentials.Retrieve(ctx)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to assume role %s: %w", roleARN, err)
+	}
+
+	c.setRole(role, role.credentials)
+	account := c.accountAlias(ctx, roleARN)
+	role.mu.Lock()
+	role.account = account
+	role.mu.Unlock()
+	return nil
+}
+
+// SwitchAccount assumes a role from the credentials of the profile rather than those of the
+// role currently assumed, so that going from one account to another does not chain roles
+func (c *Client) SwitchAccount(ctx context.Context, roleARN string) error {
+	previous := c.role()
+	if previous == nil {
+		return c.AssumeRole(ctx, roleARN, "")
+	}
+
+	c.setRole(nil, previous.profile)
+	if err := c.AssumeRole(ctx, roleARN, ""); err != nil {
+		// The credentials of the previous role are still valid, it stays assumed
+		c.setRole(previous, previous.credentials)
+		return err
+	}
+	return nil
+}
+
+// accountAlias returns the alias of the account of the assumed role, or its ID when it has
+// none or the role cannot list it
+func (c *Client) accountAlias(ctx context.Context, roleARN string) string {
+	output, err := c.IAM().ListAccountAliases(ctx, &iam.ListAccountAliasesInput{})
+	if err == nil && len(output.AccountAliases) > 0 {
+		return output.AccountAliases[0]
+	}
+	if parts := strings.Split(roleARN, ":"); len(parts) > 4 {
+		return parts[4]
+	}
+	return ""
+}
+
+// DropRole goes back to the credentials of the profile
+func (c *Client) DropRole(ctx context.Context) error {
+	role := c.role()
+	if role == nil {
+		return errors.New("no role assumed")
+	}
+
+	c.setRole(nil, role.profile)
+	return nil
+}
+
+// role returns the assumed role, nil when the profile credentials are used
+func (c *Client) role() *assumedRole {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.assumed
+}
+
+// setRole changes the assumed role, nil for none, and the credentials the clients are
+// rebuilt with on their next use
+func (c *Client) setRole(role *assumedRole, credentials aws.CredentialsProvider) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.assumed = role
+	c.cfg = c.cfg.Copy()
+	c.cfg.Credentials = credentials
+	c.clients = make(map[string]any)
+}
+
+// AssumedRole returns the ARN of the assumed role and the expiration of its credentials,
+// the ARN is empty when the profile credentials are used
+func (c *Client) AssumedRole() (string, time.Time) {
+	role := c.role()
+	if role == nil {
+		return "", time.Time{}
+	}
+	return role.arn, role.expiration()
+}
+
+// AssumedWithMFA reports whether the assumed role was assumed with an MFA code, which
+// other programs assuming it again cannot ask for
+func (c *Client) AssumedWithMFA() bool {
+	role := c.role()
+	return role != nil && role.mfa
+}
+
+// AssumedAccount returns the alias, or the ID, of the account of the assumed role, empty
+// when the profile credentials are used
+func (c *Client) AssumedAccount() string {
+	role := c.role()
+	if role == nil {
+		return ""
+	}
+	return role.accountName()
+}
+
+// ProfileConfig returns the configuration of the clients with the credentials of the profile,
+// whatever role is assumed, e.g. to list the accounts of the organization from its management account
+func (c *Client) ProfileConfig() aws.Config {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cfg := c.cfg.Copy()
+	if c.assumed != nil {
+		cfg.Credentials = c.assumed.profile
+	}
+	return cfg
+}
+
+// ProfileOrganizations returns the Organizations client with the credentials of the profile, whatever role is assumed
+func (c *Client) ProfileOrganizations() OrganizationsAPI {
+	if c.role() == nil {
+		return c.Organizations()
+	}
+	return organizations.NewFromConfig(c.ProfileConfig())
+}
+
+// isAccessDenied reports whether a call was denied, e.g. by a condition on MFA
+func isAccessDenied(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == "AccessDenied"
+}
+
+// mfaSerial returns the serial number of the MFA device of the calling IAM user
+func (c *Client) mfaSerial(ctx context.Context) (string, error) {
+	identity, err := c.STS().GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get caller identity: %w", err)
+	}
+	arn := aws.ToString(identity.Arn)
+	if !strings.Contains(arn, ":user/") {
+		return "", fmt.Errorf("MFA is only supported for IAM users, the caller is %s", arn)
+	}
+
+	output, err := c.IAM().ListMFADevices(ctx, &iam.ListMFADevicesInput{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list MFA devices: %w", err)
+	}
+	if len(output.MFADevices) == 0 {
+		return "", fmt.Errorf("no MFA device found for %s", arn)
+	}
+	return aws.ToString(output.MFADevices[0].SerialNumber), nil
+}
@@ -3,15 +3,22 @@ package client
 import (
 	"context"
 	"os"
+	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/acm"
 	"github.com/aws/aws-sdk-go-v2/service/apigateway"
 	"github.com/aws/aws-sdk-go-v2/service/apigatewayv2"
+	"github.com/aws/aws-sdk-go-v2/service/applicationautoscaling"
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
 	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider"
 	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	"github.com/aws/aws-sdk-go-v2/service/databasemigrationservice"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ecr"
@@ -19,49 +26,70 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	"github.com/aws/aws-sdk-go-v2/service/elasticache"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 	"github.com/aws/aws-sdk-go-v2/service/kms"
 	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
 	"github.com/aws/aws-sdk-go-v2/service/route53"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/savingsplans"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/sfn"
 	"github.com/aws/aws-sdk-go-v2/service/sns"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/aws-sdk-go-v2/service/support"
 )
 
-// Client wraps AWS SDK clients for various services
+// Client holds the configuration of the current profile and region, the client of each
+// service is built on first use and dropped when the profile, region or role changes, mu
+// guards them all as the views switch them while background fetches read them
 type Client struct {
-	cfg                  aws.Config
-	ec2Client            *ec2.Client
-	s3Client             *s3.Client
-	lambdaClient         *lambda.Client
-	ecsClient            *ecs.Client
-	eksClient            *eks.Client
-	rdsClient            *rds.Client
-	acmClient            *acm.Client
-	costExplorerClient   *costexplorer.Client
-	cloudfrontClient     *cloudfront.Client
-	elbv2Client          *elasticloadbalancingv2.Client
-	dynamodbClient       *dynamodb.Client
-	secretsmanagerClient *secretsmanager.Client
-	kmsClient            *kms.Client
-	ecrClient            *ecr.Client
-	cognitoClient        *cognitoidentityprovider.Client
-	iamClient            *iam.Client
-	sqsClient            *sqs.Client
-	snsClient            *sns.Client
-	apiGatewayClient     *apigateway.Client
-	apiGatewayV2Client   *apigatewayv2.Client
-	elasticacheClient    *elasticache.Client
-	route53Client        *route53.Client
-	region               string
-	profile              string
+	cfg     aws.Config
+	region  string
+	profile string
+	assumed *assumedRole
+
+	mu      sync.Mutex
+	clients map[string]any
+	static  map[string]any
+}
+
+// service returns the client of a service, built from the current configuration on first use,
+// or its static implementation
+func service[T any, C any, O any](c *Client, name string, build func(aws.Config, ...func(*O)) C) T {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if api, ok := c.static[name]; ok {
+		return api.(T)
+	}
+	if client, ok := c.clients[name]; ok {
+		return client.(T)
+	}
+	client := any(build(c.cfg)).(T)
+	c.clients[name] = client
+	return client
+}
+
+// loadConfig loads the shared configuration with the MFA prompt, the retry options and the middlewares of a9s
+func loadConfig(ctx context.Context, opts ...func(*config.LoadOptions) error) (aws.Config, error) {
+	opts = append(opts, withMFAPrompt(), withRetryer())
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return aws.Config{}, err
+	}
+	cfg.APIOptions = append(cfg.APIOptions, addTicketUserAgent, addRateLimit, addThrottleNotice)
+	return cfg, nil
 }
 
 // New creates a new AWS client with the default configuration
 func New(ctx context.Context) (*Client, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
+	cfg, err := loadConfig(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -73,37 +101,16 @@ func New(ctx context.Context) (*Client, error) {
 	}
 
 	return &Client{
-		cfg:                  cfg,
-		ec2Client:            ec2.NewFromConfig(cfg),
-		s3Client:             s3.NewFromConfig(cfg),
-		lambdaClient:         lambda.NewFromConfig(cfg),
-		ecsClient:            ecs.NewFromConfig(cfg),
-		eksClient:            eks.NewFromConfig(cfg),
-		rdsClient:            rds.NewFromConfig(cfg),
-		acmClient:            acm.NewFromConfig(cfg),
-		costExplorerClient:   costexplorer.NewFromConfig(cfg),
-		cloudfrontClient:     cloudfront.NewFromConfig(cfg),
-		elbv2Client:          elasticloadbalancingv2.NewFromConfig(cfg),
-		dynamodbClient:       dynamodb.NewFromConfig(cfg),
-		secretsmanagerClient: secretsmanager.NewFromConfig(cfg),
-		kmsClient:            kms.NewFromConfig(cfg),
-		ecrClient:            ecr.NewFromConfig(cfg),
-		cognitoClient:        cognitoidentityprovider.NewFromConfig(cfg),
-		iamClient:            iam.NewFromConfig(cfg),
-		sqsClient:            sqs.NewFromConfig(cfg),
-		snsClient:            sns.NewFromConfig(cfg),
-		apiGatewayClient:     apigateway.NewFromConfig(cfg),
-		apiGatewayV2Client:   apigatewayv2.NewFromConfig(cfg),
-		elasticacheClient:    elasticache.NewFromConfig(cfg),
-		route53Client:        route53.NewFromConfig(cfg),
-		region:               cfg.Region,
-		profile:              profile,
+		cfg:     cfg,
+		region:  cfg.Region,
+		profile: profile,
+		clients: make(map[string]any),
 	}, nil
 }
 
 // NewWithRegion creates a new AWS client for a specific region
 func NewWithRegion(ctx context.Context, region string) (*Client, error) {
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	cfg, err := loadConfig(ctx, config.WithRegion(region))
 	if err != nil {
 		return nil, err
 	}
@@ -115,228 +122,294 @@ func NewWithRegion(ctx context.Context, region string) (*Client, error) {
 	}
 
 	return &Client{
-		cfg:                  cfg,
-		ec2Client:            ec2.NewFromConfig(cfg),
-		s3Client:             s3.NewFromConfig(cfg),
-		lambdaClient:         lambda.NewFromConfig(cfg),
-		ecsClient:            ecs.NewFromConfig(cfg),
-		eksClient:            eks.NewFromConfig(cfg),
-		rdsClient:            rds.NewFromConfig(cfg),
-		acmClient:            acm.NewFromConfig(cfg),
-		costExplorerClient:   costexplorer.NewFromConfig(cfg),
-		cloudfrontClient:     cloudfront.NewFromConfig(cfg),
-		elbv2Client:          elasticloadbalancingv2.NewFromConfig(cfg),
-		dynamodbClient:       dynamodb.NewFromConfig(cfg),
-		secretsmanagerClient: secretsmanager.NewFromConfig(cfg),
-		kmsClient:            kms.NewFromConfig(cfg),
-		ecrClient:            ecr.NewFromConfig(cfg),
-		cognitoClient:        cognitoidentityprovider.NewFromConfig(cfg),
-		iamClient:            iam.NewFromConfig(cfg),
-		sqsClient:            sqs.NewFromConfig(cfg),
-		snsClient:            sns.NewFromConfig(cfg),
-		apiGatewayClient:     apigateway.NewFromConfig(cfg),
-		apiGatewayV2Client:   apigatewayv2.NewFromConfig(cfg),
-		elasticacheClient:    elasticache.NewFromConfig(cfg),
-		route53Client:        route53.NewFromConfig(cfg),
-		region:               region,
-		profile:              profile,
+		cfg:     cfg,
+		region:  region,
+		profile: profile,
+		clients: make(map[string]any),
 	}, nil
 }
 
+// NewStatic creates a client whose services are the given implementations of their API, keyed
+// by the name of their SDK package, e.g. "ec2" with an EC2API, for the resources to run against
+// mocks or fixtures, the services left out are built from the given configuration, which is
+// kept when switching region or profile
+func NewStatic(cfg aws.Config, profile string, services map[string]any) *Client {
+	return &Client{
+		cfg:     cfg,
+		region:  cfg.Region,
+		profile: profile,
+		clients: make(map[string]any),
+		static:  services,
+	}
+}
+
 // Region returns the current AWS region
 func (c *Client) Region() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return c.region
 }
 
 // Profile returns the current AWS profile
 func (c *Client) Profile() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return c.profile
 }
 
-// SetRegion changes the region and reinitializes clients
-func (c *Client) SetRegion(ctx context.Context, region string) error {
-	opts := []func(*config.LoadOptions) error{config.WithRegion(region)}
-	if c.profile != "" && c.profile != "default" {
-		opts = append(opts, config.WithSharedConfigProfile(c.profile))
-	}
+// Credentials returns the credentials provider of the current profile
+func (c *Client) Credentials() aws.CredentialsProvider {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cfg.Credentials
+}
 
-	cfg, err := config.LoadDefaultConfig(ctx, opts...)
-	if err != nil {
-		return err
-	}
+// InRegion returns a copy of the client for another region, the client itself is left untouched
+func (c *Client) InRegion(ctx context.Context, region string) (*Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	c.cfg = cfg
-	c.ec2Client = ec2.NewFromConfig(cfg)
-	c.s3Client = s3.NewFromConfig(cfg)
-	c.lambdaClient = lambda.NewFromConfig(cfg)
-	c.ecsClient = ecs.NewFromConfig(cfg)
-	c.eksClient = eks.NewFromConfig(cfg)
-	c.rdsClient = rds.NewFromConfig(cfg)
-	c.acmClient = acm.NewFromConfig(cfg)
-	c.costExplorerClient = costexplorer.NewFromConfig(cfg)
-	c.cloudfrontClient = cloudfront.NewFromConfig(cfg)
-	c.elbv2Client = elasticloadbalancingv2.NewFromConfig(cfg)
-	c.dynamodbClient = dynamodb.NewFromConfig(cfg)
-	c.secretsmanagerClient = secretsmanager.NewFromConfig(cfg)
-	c.kmsClient = kms.NewFromConfig(cfg)
-	c.ecrClient = ecr.NewFromConfig(cfg)
-	c.cognitoClient = cognitoidentityprovider.NewFromConfig(cfg)
-	c.iamClient = iam.NewFromConfig(cfg)
-	c.sqsClient = sqs.NewFromConfig(cfg)
-	c.snsClient = sns.NewFromConfig(cfg)
-	c.apiGatewayClient = apigateway.NewFromConfig(cfg)
-	c.apiGatewayV2Client = apigatewayv2.NewFromConfig(cfg)
-	c.elasticacheClient = elasticache.NewFromConfig(cfg)
-	c.route53Client = route53.NewFromConfig(cfg)
+	cfg := c.cfg.Copy()
+	cfg.Region = region
+	return &Client{
+		cfg:     cfg,
+		region:  region,
+		profile: c.profile,
+		assumed: c.assumed,
+		clients: make(map[string]any),
+		static:  c.static,
+	}, nil
+}
+
+// SetRegion changes the region, the clients are rebuilt on their next use with the same
+// credentials, so that they are neither loaded nor asked for again
+func (c *Client) SetRegion(ctx context.Context, region string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cfg = c.cfg.Copy()
+	c.cfg.Region = region
 	c.region = region
+	c.clients = make(map[string]any)
 	return nil
 }
 
-// SetProfile changes the profile and reinitializes clients
+// SetProfile changes the profile, an assumed role is dropped and the clients are rebuilt on their next use
 func (c *Client) SetProfile(ctx context.Context, profile string) error {
-	opts := []func(*config.LoadOptions) error{config.WithSharedConfigProfile(profile)}
-	if c.region != "" {
-		opts = append(opts, config.WithRegion(c.region))
+	c.mu.Lock()
+	cfg := c.cfg.Copy()
+	region := c.region
+	c.mu.Unlock()
+
+	if c.static == nil {
+		opts := []func(*config.LoadOptions) error{config.WithSharedConfigProfile(profile)}
+		if region != "" {
+			opts = append(opts, config.WithRegion(region))
+		}
+
+		var err error
+		if cfg, err = loadConfig(ctx, opts...); err != nil {
+			return err
+		}
 	}
 
-	cfg, err := config.LoadDefaultConfig(ctx, opts...)
-	if err != nil {
-		return err
-	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	c.cfg = cfg
-	c.ec2Client = ec2.NewFromConfig(cfg)
-	c.s3Client = s3.NewFromConfig(cfg)
-	c.lambdaClient = lambda.NewFromConfig(cfg)
-	c.ecsClient = ecs.NewFromConfig(cfg)
-	c.eksClient = eks.NewFromConfig(cfg)
-	c.rdsClient = rds.NewFromConfig(cfg)
-	c.acmClient = acm.NewFromConfig(cfg)
-	c.costExplorerClient = costexplorer.NewFromConfig(cfg)
-	c.cloudfrontClient = cloudfront.NewFromConfig(cfg)
-	c.elbv2Client = elasticloadbalancingv2.NewFromConfig(cfg)
-	c.dynamodbClient = dynamodb.NewFromConfig(cfg)
-	c.secretsmanagerClient = secretsmanager.NewFromConfig(cfg)
-	c.kmsClient = kms.NewFromConfig(cfg)
-	c.ecrClient = ecr.NewFromConfig(cfg)
-	c.cognitoClient = cognitoidentityprovider.NewFromConfig(cfg)
-	c.iamClient = iam.NewFromConfig(cfg)
-	c.sqsClient = sqs.NewFromConfig(cfg)
-	c.snsClient = sns.NewFromConfig(cfg)
-	c.apiGatewayClient = apigateway.NewFromConfig(cfg)
-	c.apiGatewayV2Client = apigatewayv2.NewFromConfig(cfg)
-	c.elasticacheClient = elasticache.NewFromConfig(cfg)
-	c.route53Client = route53.NewFromConfig(cfg)
 	c.profile = profile
+	c.assumed = nil
+	c.clients = make(map[string]any)
 	return nil
 }
 
 // EC2 returns the EC2 client
-func (c *Client) EC2() *ec2.Client {
-	return c.ec2Client
+func (c *Client) EC2() EC2API {
+	return service[EC2API](c, "ec2", ec2.NewFromConfig)
 }
 
 // S3 returns the S3 client
-func (c *Client) S3() *s3.Client {
-	return c.s3Client
+func (c *Client) S3() S3API {
+	return service[S3API](c, "s3", s3.NewFromConfig)
+}
+
+// S3Presign returns a client presigning S3 requests
+func (c *Client) S3Presign(optFns ...func(*s3.PresignOptions)) S3PresignAPI {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if api, ok := c.static["s3presign"]; ok {
+		return api.(S3PresignAPI)
+	}
+	return s3.NewPresignClient(s3.NewFromConfig(c.cfg), optFns...)
 }
 
 // Lambda returns the Lambda client
-func (c *Client) Lambda() *lambda.Client {
-	return c.lambdaClient
+func (c *Client) Lambda() LambdaAPI {
+	return service[LambdaAPI](c, "lambda", lambda.NewFromConfig)
 }
 
 // ECS returns the ECS client
-func (c *Client) ECS() *ecs.Client {
-	return c.ecsClient
+func (c *Client) ECS() ECSAPI {
+	return service[ECSAPI](c, "ecs", ecs.NewFromConfig)
 }
 
 // EKS returns the EKS client
-func (c *Client) EKS() *eks.Client {
-	return c.eksClient
+func (c *Client) EKS() EKSAPI {
+	return service[EKSAPI](c, "eks", eks.NewFromConfig)
 }
 
 // RDS returns the RDS client
-func (c *Client) RDS() *rds.Client {
-	return c.rdsClient
+func (c *Client) RDS() RDSAPI {
+	return service[RDSAPI](c, "rds", rds.NewFromConfig)
 }
 
 // ACM returns the ACM client
-func (c *Client) ACM() *acm.Client {
-	return c.acmClient
+func (c *Client) ACM() ACMAPI {
+	return service[ACMAPI](c, "acm", acm.NewFromConfig)
 }
 
 // CostExplorer returns the Cost Explorer client
-func (c *Client) CostExplorer() *costexplorer.Client {
-	return c.costExplorerClient
+func (c *Client) CostExplorer() CostExplorerAPI {
+	return service[CostExplorerAPI](c, "costexplorer", costexplorer.NewFromConfig)
 }
 
 // CloudFront returns the CloudFront client
-func (c *Client) CloudFront() *cloudfront.Client {
-	return c.cloudfrontClient
+func (c *Client) CloudFront() CloudFrontAPI {
+	return service[CloudFrontAPI](c, "cloudfront", cloudfront.NewFromConfig)
 }
 
 // ELBv2 returns the Elastic Load Balancing v2 client
-func (c *Client) ELBv2() *elasticloadbalancingv2.Client {
-	return c.elbv2Client
+func (c *Client) ELBv2() ELBv2API {
+	return service[ELBv2API](c, "elasticloadbalancingv2", elasticloadbalancingv2.NewFromConfig)
 }
 
 // DynamoDB returns the DynamoDB client
-func (c *Client) DynamoDB() *dynamodb.Client {
-	return c.dynamodbClient
+func (c *Client) DynamoDB() DynamoDBAPI {
+	return service[DynamoDBAPI](c, "dynamodb", dynamodb.NewFromConfig)
 }
 
 // SecretsManager returns the Secrets Manager client
-func (c *Client) SecretsManager() *secretsmanager.Client {
-	return c.secretsmanagerClient
+func (c *Client) SecretsManager() SecretsManagerAPI {
+	return service[SecretsManagerAPI](c, "secretsmanager", secretsmanager.NewFromConfig)
 }
 
 // KMS returns the KMS client
-func (c *Client) KMS() *kms.Client {
-	return c.kmsClient
+func (c *Client) KMS() KMSAPI {
+	return service[KMSAPI](c, "kms", kms.NewFromConfig)
 }
 
 // ECR returns the ECR client
-func (c *Client) ECR() *ecr.Client {
-	return c.ecrClient
+func (c *Client) ECR() ECRAPI {
+	return service[ECRAPI](c, "ecr", ecr.NewFromConfig)
 }
 
 // Cognito returns the Cognito Identity Provider client
-func (c *Client) Cognito() *cognitoidentityprovider.Client {
-	return c.cognitoClient
+func (c *Client) Cognito() CognitoAPI {
+	return service[CognitoAPI](c, "cognitoidentityprovider", cognitoidentityprovider.NewFromConfig)
 }
 
 // IAM returns the IAM client
-func (c *Client) IAM() *iam.Client {
-	return c.iamClient
+func (c *Client) IAM() IAMAPI {
+	return service[IAMAPI](c, "iam", iam.NewFromConfig)
 }
 
 // SQS returns the SQS client
-func (c *Client) SQS() *sqs.Client {
-	return c.sqsClient
+func (c *Client) SQS() SQSAPI {
+	return service[SQSAPI](c, "sqs", sqs.NewFromConfig)
 }
 
 // SNS returns the SNS client
-func (c *Client) SNS() *sns.Client {
-	return c.snsClient
+func (c *Client) SNS() SNSAPI {
+	return service[SNSAPI](c, "sns", sns.NewFromConfig)
 }
 
 // APIGateway returns the API Gateway client
-func (c *Client) APIGateway() *apigateway.Client {
-	return c.apiGatewayClient
+func (c *Client) APIGateway() APIGatewayAPI {
+	return service[APIGatewayAPI](c, "apigateway", apigateway.NewFromConfig)
 }
 
 // APIGatewayV2 returns the API Gateway V2 client
-func (c *Client) APIGatewayV2() *apigatewayv2.Client {
-	return c.apiGatewayV2Client
+func (c *Client) APIGatewayV2() APIGatewayV2API {
+	return service[APIGatewayV2API](c, "apigatewayv2", apigatewayv2.NewFromConfig)
 }
 
 // ElastiCache returns the ElastiCache client
-func (c *Client) ElastiCache() *elasticache.Client {
-	return c.elasticacheClient
+func (c *Client) ElastiCache() ElastiCacheAPI {
+	return service[ElastiCacheAPI](c, "elasticache", elasticache.NewFromConfig)
 }
 
 // Route53 returns the Route53 client
-func (c *Client) Route53() *route53.Client {
-	return c.route53Client
+func (c *Client) Route53() Route53API {
+	return service[Route53API](c, "route53", route53.NewFromConfig)
+}
+
+// CloudControl returns the Cloud Control API client
+func (c *Client) CloudControl() CloudControlAPI {
+	return service[CloudControlAPI](c, "cloudcontrol", cloudcontrol.NewFromConfig)
+}
+
+// STS returns the STS client
+func (c *Client) STS() STSAPI {
+	return service[STSAPI](c, "sts", sts.NewFromConfig)
+}
+
+// CloudWatch returns the CloudWatch client
+func (c *Client) CloudWatch() CloudWatchAPI {
+	return service[CloudWatchAPI](c, "cloudwatch", cloudwatch.NewFromConfig)
+}
+
+// ApplicationAutoScaling returns the Application Auto Scaling client
+func (c *Client) ApplicationAutoScaling() ApplicationAutoScalingAPI {
+	return service[ApplicationAutoScalingAPI](c, "applicationautoscaling", applicationautoscaling.NewFromConfig)
+}
+
+// CloudWatchLogs returns the CloudWatch Logs client
+func (c *Client) CloudWatchLogs() CloudWatchLogsAPI {
+	return service[CloudWatchLogsAPI](c, "cloudwatchlogs", cloudwatchlogs.NewFromConfig)
+}
+
+// CloudFormation returns the CloudFormation client
+func (c *Client) CloudFormation() CloudFormationAPI {
+	return service[CloudFormationAPI](c, "cloudformation", cloudformation.NewFromConfig)
+}
+
+// SSM returns the Systems Manager client
+func (c *Client) SSM() SSMAPI {
+	return service[SSMAPI](c, "ssm", ssm.NewFromConfig)
+}
+
+// SavingsPlans returns the Savings Plans client
+func (c *Client) SavingsPlans() SavingsPlansAPI {
+	return service[SavingsPlansAPI](c, "savingsplans", savingsplans.NewFromConfig)
+}
+
+// Tagging returns the Resource Groups Tagging client
+func (c *Client) Tagging() TaggingAPI {
+	return service[TaggingAPI](c, "resourcegroupstaggingapi", resourcegroupstaggingapi.NewFromConfig)
+}
+
+// Support returns the Support client
+func (c *Client) Support() SupportAPI {
+	return service[SupportAPI](c, "support", support.NewFromConfig)
+}
+
+// DMS returns the DMS client
+func (c *Client) DMS() DMSAPI {
+	return service[DMSAPI](c, "databasemigrationservice", databasemigrationservice.NewFromConfig)
+}
+
+// EventBridge returns the EventBridge client
+func (c *Client) EventBridge() EventBridgeAPI {
+	return service[EventBridgeAPI](c, "eventbridge", eventbridge.NewFromConfig)
+}
+
+// SFN returns the Step Functions client
+func (c *Client) SFN() SFNAPI {
+	return service[SFNAPI](c, "sfn", sfn.NewFromConfig)
+}
+
+// Organizations returns the Organizations client
+func (c *Client) Organizations() OrganizationsAPI {
+	return service[OrganizationsAPI](c, "organizations", organizations.NewFromConfig)
 }
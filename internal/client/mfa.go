@@ -0,0 +1,53 @@
+package client
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+)
+
+// MFAPrompt asks the user for the current code of the MFA device with the given serial number
+type MFAPrompt func(serial string) (string, error)
+
+var (
+	mfaPromptMu sync.RWMutex
+	mfaPrompt   MFAPrompt
+
+	// mfaAsking serializes the prompts, e.g. of the clients of several regions refreshing at once
+	mfaAsking sync.Mutex
+)
+
+// SetMFAPrompt sets how MFA codes are asked for, e.g. in a modal of the UI, the terminal
+// is used until then
+func SetMFAPrompt(prompt MFAPrompt) {
+	mfaPromptMu.Lock()
+	defer mfaPromptMu.Unlock()
+	mfaPrompt = prompt
+}
+
+// promptMFA asks for the code of an MFA device, one prompt at a time
+func promptMFA(serial string) (string, error) {
+	mfaPromptMu.RLock()
+	prompt := mfaPrompt
+	mfaPromptMu.RUnlock()
+
+	mfaAsking.Lock()
+	defer mfaAsking.Unlock()
+
+	if prompt == nil {
+		return stscreds.StdinTokenProvider()
+	}
+	return prompt(serial)
+}
+
+// withMFAPrompt completes the MFA of the profiles assuming a role with an mfa_serial, the
+// code is asked for when the credentials are first needed, and again once they expire
+func withMFAPrompt() func(*config.LoadOptions) error {
+	return config.WithAssumeRoleCredentialOptions(func(o *stscreds.AssumeRoleOptions) {
+		o.TokenProvider = func() (string, error) {
+			return promptMFA(aws.ToString(o.SerialNumber))
+		}
+	})
+}
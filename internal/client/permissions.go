@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// MissingPermissions simulates the IAM actions for the current identity and
+// returns the ones which are not allowed
+func (c *Client) MissingPermissions(ctx context.Context, actions []string) ([]string, error) {
+	if len(actions) == 0 {
+		return nil, nil
+	}
+
+	identity, err := c.STS().GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get caller identity: %w", err)
+	}
+
+	principal := principalARN(*identity.Arn)
+	output, err := c.IAM().SimulatePrincipalPolicy(ctx, &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: &principal,
+		ActionNames:     actions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to simulate permissions of %s: %w", principal, err)
+	}
+
+	missing := make([]string, 0)
+	for _, result := range output.EvaluationResults {
+		if result.EvalDecision != iamtypes.PolicyEvaluationDecisionTypeAllowed {
+			missing = append(missing, *result.EvalActionName)
+		}
+	}
+	return missing, nil
+}
+
+// principalARN converts an assumed role session ARN into the ARN of its role,
+// which is what the IAM policy simulator expects
+//
+//	arn:aws:sts::123456789012:assumed-role/Admin/session -> arn:aws:iam::123456789012:role/Admin
+func principalARN(arn string) string {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 || parts[2] != "sts" || !strings.HasPrefix(parts[5], "assumed-role/") {
+		return arn
+	}
+
+	role := strings.Split(strings.TrimPrefix(parts[5], "assumed-role/"), "/")[0]
+	return fmt.Sprintf("arn:%s:iam::%s:role/%s", parts[1], parts[4], role)
+}
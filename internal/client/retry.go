@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	"golang.org/x/time/rate"
+)
+
+// RetryOptions configures how the calls to AWS are retried and rate limited, the zero
+// values keep the defaults of the SDK, or of AWS_RETRY_MODE and AWS_MAX_ATTEMPTS:
+//
+//	retry-mode: adaptive
+//	retry-max-attempts: 5
+//	retry-max-backoff: 20s
+//	rate-limits:
+//	  ec2: 5
+//	  cloudwatchlogs: 2
+type RetryOptions struct {
+	Mode        string             // "standard" or "adaptive"
+	MaxAttempts int                // Attempts of a call, the first one included
+	MaxBackoff  time.Duration      // Longest wait between two attempts
+	RateLimits  map[string]float64 // Calls per second by service, keyed by the name of its SDK package
+}
+
+// ThrottleNotifier is told when a call is throttled by AWS, before the SDK backs off and retries it
+type ThrottleNotifier func(service, operation, code string)
+
+var (
+	retryMu          sync.RWMutex
+	retryOptions     RetryOptions
+	throttleNotifier ThrottleNotifier
+	rateLimiters     = make(map[string]*rate.Limiter)
+)
+
+// SetRetryOptions sets how the calls are retried and rate limited, for the clients built from then on
+func SetRetryOptions(opts RetryOptions) error {
+	switch opts.Mode {
+	case "", string(aws.RetryModeStandard), string(aws.RetryModeAdaptive):
+	default:
+		return fmt.Errorf("unknown retry mode %q, expected standard or adaptive", opts.Mode)
+	}
+	if opts.MaxAttempts < 0 || opts.MaxBackoff < 0 {
+		return errors.New("the retry attempts and backoff cannot be negative")
+	}
+
+	limiters := make(map[string]*rate.Limiter)
+	for name, limit := range opts.RateLimits {
+		if limit <= 0 {
+			return fmt.Errorf("the rate limit of %s must be positive", name)
+		}
+		// Bursts are limited to one second worth of calls
+		limiters[strings.ToLower(name)] = rate.NewLimiter(rate.Limit(limit), max(1, int(limit)))
+	}
+
+	retryMu.Lock()
+	defer retryMu.Unlock()
+	retryOptions = opts
+	rateLimiters = limiters
+	return nil
+}
+
+// SetThrottleNotifier sets who is told about throttled calls, e.g. the status bar of the UI
+func SetThrottleNotifier(notify ThrottleNotifier) {
+	retryMu.Lock()
+	defer retryMu.Unlock()
+	throttleNotifier = notify
+}
+
+// withRetryer applies the retry options to a configuration, when any is set
+func withRetryer() func(*config.LoadOptions) error {
+	retryMu.RLock()
+	opts := retryOptions
+	retryMu.RUnlock()
+
+	if opts.Mode == "" && opts.MaxAttempts == 0 && opts.MaxBackoff == 0 {
+		return func(*config.LoadOptions) error { return nil }
+	}
+
+	standard := func(o *retry.StandardOptions) {
+		if opts.MaxAttempts > 0 {
+			o.MaxAttempts = opts.MaxAttempts
+		}
+		if opts.MaxBackoff > 0 {
+			o.MaxBackoff = opts.MaxBackoff
+		}
+	}
+	return config.WithRetryer(func() aws.Retryer {
+		if opts.Mode == string(aws.RetryModeAdaptive) {
+			return retry.NewAdaptiveMode(func(o *retry.AdaptiveModeOptions) {
+				o.StandardOptions = append(o.StandardOptions, standard)
+			})
+		}
+		return retry.NewStandard(standard)
+	})
+}
+
+// serviceKey returns the name of the SDK package of a service from its ID, e.g. "CloudWatch Logs" gives "cloudwatchlogs"
+func serviceKey(serviceID string) string {
+	return strings.ToLower(strings.ReplaceAll(serviceID, " ", ""))
+}
+
+// addRateLimit waits for the limiter of the service before each attempt of a call, retries included
+func addRateLimit(stack *middleware.Stack) error {
+	return stack.Finalize.Add(middleware.FinalizeMiddlewareFunc("A9sRateLimit", func(
+		ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler,
+	) (middleware.FinalizeOutput, middleware.Metadata, error) {
+		retryMu.RLock()
+		limiter := rateLimiters[serviceKey(awsmiddleware.GetServiceID(ctx))]
+		retryMu.RUnlock()
+
+		if limiter != nil {
+			if err := limiter.Wait(ctx); err != nil {
+				return middleware.FinalizeOutput{}, middleware.Metadata{}, err
+			}
+		}
+		return next.HandleFinalize(ctx, in)
+	}), middleware.After)
+}
+
+// addThrottleNotice tells the throttle notifier about the attempts throttled by AWS
+func addThrottleNotice(stack *middleware.Stack) error {
+	return stack.Finalize.Add(middleware.FinalizeMiddlewareFunc("A9sThrottleNotice", func(
+		ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler,
+	) (middleware.FinalizeOutput, middleware.Metadata, error) {
+		out, metadata, err := next.HandleFinalize(ctx, in)
+		if code, ok := ThrottleCode(err); ok {
+			retryMu.RLock()
+			notify := throttleNotifier
+			retryMu.RUnlock()
+
+			if notify != nil {
+				notify(awsmiddleware.GetServiceID(ctx), awsmiddleware.GetOperationName(ctx), code)
+			}
+		}
+		return out, metadata, err
+	}), middleware.After)
+}
+
+// ThrottleCode returns the error code when an error is AWS throttling the calls, e.g. RequestLimitExceeded
+func ThrottleCode(err error) (string, bool) {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return "", false
+	}
+	if retry.IsErrorThrottles(retry.DefaultThrottles).IsErrorThrottle(err) != aws.TrueTernary {
+		return "", false
+	}
+	return apiErr.ErrorCode(), true
+}
@@ -0,0 +1,39 @@
+package client
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+type ticketKey struct{}
+
+// WithTicket returns a context carrying a change ticket reference
+func WithTicket(ctx context.Context, ticket string) context.Context {
+	return context.WithValue(ctx, ticketKey{}, ticket)
+}
+
+// TicketFromContext returns the change ticket carried by the context, if any
+func TicketFromContext(ctx context.Context) string {
+	ticket, _ := ctx.Value(ticketKey{}).(string)
+	return ticket
+}
+
+// addTicketUserAgent appends the change ticket of the request context to the
+// User-Agent header so that it shows up in CloudTrail
+func addTicketUserAgent(stack *middleware.Stack) error {
+	return stack.Build.Add(middleware.BuildMiddlewareFunc("A9sTicketUserAgent", func(
+		ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler,
+	) (middleware.BuildOutput, middleware.Metadata, error) {
+		if ticket := TicketFromContext(ctx); ticket != "" {
+			if req, ok := in.Request.(*smithyhttp.Request); ok {
+				// User-Agent tokens cannot contain spaces
+				token := strings.Join(strings.Fields(ticket), "-")
+				req.Header.Set("User-Agent", req.Header.Get("User-Agent")+" ticket/"+token)
+			}
+		}
+		return next.HandleBuild(ctx, in)
+	}), middleware.After)
+}
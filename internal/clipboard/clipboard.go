@@ -0,0 +1,43 @@
+package clipboard
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrUnavailable is returned when no clipboard utility can be found
+var ErrUnavailable = errors.New("no clipboard utility found (install xclip, xsel or wl-clipboard)")
+
+// commands returns the clipboard utilities to try for the current platform
+func commands() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		return [][]string{{"clip.exe"}}
+	default:
+		return [][]string{
+			{"wl-copy"},
+			{"xclip", "-selection", "clipboard"},
+			{"xsel", "--clipboard", "--input"},
+			{"clip.exe"}, // WSL
+		}
+	}
+}
+
+// Write copies the text to the system clipboard
+func Write(text string) error {
+	for _, args := range commands() {
+		path, err := exec.LookPath(args[0])
+		if err != nil {
+			continue
+		}
+
+		cmd := exec.Command(path, args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd.Run()
+	}
+	return ErrUnavailable
+}
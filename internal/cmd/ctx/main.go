@@ -0,0 +1,46 @@
+package ctx
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"a9s/internal/workspace"
+
+	"github.com/spf13/cobra"
+)
+
+// List prints the contexts of the configuration, the current one is starred
+func List(cmd *cobra.Command, args []string) {
+	contexts, err := workspace.List()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	if len(contexts) == 0 {
+		fmt.Println("No contexts defined, add them under 'contexts' in ~/.a9s/config.yaml")
+		return
+	}
+
+	current := workspace.Current()
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "CURRENT\tNAME\tPROFILE\tREGION\tRESOURCE")
+	for _, name := range workspace.Names(contexts) {
+		marker := ""
+		if name == current {
+			marker = "*"
+		}
+		c := contexts[name]
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", marker, name, c.Profile, c.Region, c.Resource)
+	}
+	w.Flush()
+}
+
+// Use pins the context a9s starts in
+func Use(cmd *cobra.Command, args []string) {
+	if err := workspace.Use(args[0]); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to use context: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Switched to context %s\n", args[0])
+}
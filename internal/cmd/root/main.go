@@ -5,25 +5,114 @@ import (
 	"fmt"
 	"os"
 
+	"a9s/internal/audit"
 	"a9s/internal/client"
+	"a9s/internal/demo"
+	"a9s/internal/resources"
 	"a9s/internal/view"
+	"a9s/internal/workspace"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 func Run(cmd *cobra.Command, args []string) {
 	ctx := context.Background()
 
+	// Set how the calls to AWS are retried and rate limited
+	var rateLimits map[string]float64
+	if err := viper.UnmarshalKey("rate-limits", &rateLimits); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read rate limits: %v\n", err)
+		os.Exit(1)
+	}
+	if err := client.SetRetryOptions(client.RetryOptions{
+		Mode:        viper.GetString("retry-mode"),
+		MaxAttempts: viper.GetInt("retry-max-attempts"),
+		MaxBackoff:  viper.GetDuration("retry-max-backoff"),
+		RateLimits:  rateLimits,
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid retry options: %v\n", err)
+		os.Exit(1)
+	}
+
+	// The demo serves fixture data, without credentials, pinned context nor audit log
+	demoMode := viper.GetBool("demo")
+
 	// Initialize AWS client
-	c, err := client.New(ctx)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to initialize AWS client: %v\n", err)
-		fmt.Fprintf(os.Stderr, "Make sure your AWS credentials are configured.\n")
+	var c *client.Client
+	var err error
+	if demoMode {
+		c = demo.Client()
+	} else {
+		c, err = client.New(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to initialize AWS client: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Make sure your AWS credentials are configured, or try a9s --demo.\n")
+			os.Exit(1)
+		}
+	}
+
+	// Start in the pinned context, if any
+	var start workspace.Context
+	if name := workspace.Current(); name != "" && !demoMode {
+		start, err = workspace.Get(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load context: %v\n", err)
+			os.Exit(1)
+		}
+		if start.Profile != "" {
+			if err := c.SetProfile(ctx, start.Profile); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to switch to profile %s: %v\n", start.Profile, err)
+				os.Exit(1)
+			}
+		}
+		if start.Region != "" {
+			if err := c.SetRegion(ctx, start.Region); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to switch to region %s: %v\n", start.Region, err)
+				os.Exit(1)
+			}
+		}
+	}
+
+	// Open the audit log of mutating actions
+	if !demoMode {
+		if err := audit.Init(viper.GetString("audit-log")); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to initialize audit log: %v\n", err)
+			os.Exit(1)
+		}
+		defer audit.Close()
+	}
+
+	// Read the users and keys used to ssh into instances
+	var sshRules []resources.SSHRule
+	if err := viper.UnmarshalKey("ssh", &sshRules); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read ssh rules: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Read the accounts of the switcher
+	var accounts []resources.AccountRole
+	if err := viper.UnmarshalKey("accounts", &accounts); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read accounts: %v\n", err)
 		os.Exit(1)
 	}
 
 	// Create and run the application
-	app := view.New(ctx, c)
+	app := view.New(ctx, c, view.Config{
+		TicketPrompt:     viper.GetBool("ticket"),
+		IdleTimeout:      viper.GetDuration("idle-timeout"),
+		Preflight:        viper.GetBool("preflight") && !demoMode,
+		Mask:             viper.GetBool("mask"),
+		MaskPatterns:     viper.GetStringSlice("mask-patterns"),
+		Resource:         start.Resource,
+		KeyMaxAge:        viper.GetDuration("key-max-age"),
+		AnomalyCheck:     viper.GetDuration("anomaly-check"),
+		RequiredTags:     viper.GetStringSlice("required-tags"),
+		SSHRules:         sshRules,
+		CacheTTL:         viper.GetDuration("cache-ttl"),
+		Accounts:         accounts,
+		OrganizationRole: viper.GetString("organization-role"),
+	})
 	if err := app.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Application error: %v\n", err)
 		os.Exit(1)
@@ -0,0 +1,288 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+)
+
+// ec2API serves the instances, volumes, spot requests and networks of the demo account
+type ec2API struct {
+	client.EC2API
+}
+
+// ec2Tags returns the tags of an EC2 resource, the Owner one is left out without owner
+func ec2Tags(name, environment, owner string) []ec2types.Tag {
+	tags := []ec2types.Tag{
+		{Key: aws.String("Name"), Value: aws.String(name)},
+		{Key: aws.String("Environment"), Value: aws.String(environment)},
+		{Key: aws.String("CostCenter"), Value: aws.String("cc-" + environment)},
+	}
+	if owner != "" {
+		tags = append(tags, ec2types.Tag{Key: aws.String("Owner"), Value: aws.String(owner)})
+	}
+	return tags
+}
+
+// demoInstance returns an instance of the demo account
+func demoInstance(id, name string, state ec2types.InstanceStateName, instanceType ec2types.InstanceType, privateIP, publicIP, zone, owner string, age int) ec2types.Instance {
+	instance := ec2types.Instance{
+		InstanceId:       aws.String(id),
+		State:            &ec2types.InstanceState{Name: state},
+		InstanceType:     instanceType,
+		PrivateIpAddress: aws.String(privateIP),
+		Placement:        &ec2types.Placement{AvailabilityZone: aws.String(zone)},
+		LaunchTime:       ago(time.Duration(age) * day),
+		Tags:             ec2Tags(name, "prod", owner),
+		VpcId:            aws.String("vpc-0a1b2c3d4e5f60001"),
+	}
+	if publicIP != "" {
+		instance.PublicIpAddress = aws.String(publicIP)
+	}
+	return instance
+}
+
+// instances are the EC2 instances of the demo account
+var instances = []ec2types.Instance{
+	demoInstance("i-0a1b2c3d4e5f60001", "web-1", ec2types.InstanceStateNameRunning, ec2types.InstanceTypeT3Medium, "10.0.1.10", "34.240.12.5", "eu-west-1a", "web-team", 42),
+	demoInstance("i-0a1b2c3d4e5f60002", "web-2", ec2types.InstanceStateNameRunning, ec2types.InstanceTypeT3Medium, "10.0.2.10", "34.240.12.6", "eu-west-1b", "web-team", 42),
+	demoInstance("i-0a1b2c3d4e5f60003", "worker-1", ec2types.InstanceStateNameRunning, ec2types.InstanceTypeM5Large, "10.0.11.20", "", "eu-west-1a", "data-team", 17),
+	demoInstance("i-0a1b2c3d4e5f60004", "bastion", ec2types.InstanceStateNameRunning, ec2types.InstanceTypeT3Micro, "10.0.1.5", "52.18.77.201", "eu-west-1a", "platform-team", 210),
+	demoInstance("i-0a1b2c3d4e5f60005", "batch-1", ec2types.InstanceStateNameStopped, ec2types.InstanceTypeC5Xlarge, "10.0.12.30", "", "eu-west-1b", "data-team", 95),
+	demoInstance("i-0a1b2c3d4e5f60006", "legacy-app", ec2types.InstanceStateNameStopped, ec2types.InstanceTypeT2Small, "10.0.2.99", "", "eu-west-1b", "", 730),
+}
+
+// DescribeInstances returns the instances, filtered by ID
+func (ec2API) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	reservation := ec2types.Reservation{ReservationId: aws.String("r-0a1b2c3d4e5f60001"), OwnerId: aws.String(AccountID)}
+	for _, instance := range instances {
+		if len(params.InstanceIds) > 0 && !slices.Contains(params.InstanceIds, aws.ToString(instance.InstanceId)) {
+			continue
+		}
+		reservation.Instances = append(reservation.Instances, instance)
+	}
+	return &ec2.DescribeInstancesOutput{Reservations: []ec2types.Reservation{reservation}}, nil
+}
+
+// demoVolume returns a volume, attached to an instance when one is given
+func demoVolume(id, name string, size int32, volumeType ec2types.VolumeType, instanceID, device string, age int) ec2types.Volume {
+	volume := ec2types.Volume{
+		VolumeId:         aws.String(id),
+		Size:             aws.Int32(size),
+		VolumeType:       volumeType,
+		AvailabilityZone: aws.String("eu-west-1a"),
+		CreateTime:       ago(time.Duration(age) * day),
+		Tags:             ec2Tags(name, "prod", "platform-team"),
+		State:            ec2types.VolumeStateAvailable,
+	}
+	if volumeType == ec2types.VolumeTypeGp3 {
+		volume.Iops, volume.Throughput = aws.Int32(3000), aws.Int32(125)
+	}
+	if instanceID != "" {
+		volume.State = ec2types.VolumeStateInUse
+		volume.Attachments = []ec2types.VolumeAttachment{{
+			InstanceId: aws.String(instanceID),
+			Device:     aws.String(device),
+			VolumeId:   aws.String(id),
+			State:      ec2types.VolumeAttachmentStateAttached,
+		}}
+	}
+	return volume
+}
+
+// volumes are the EBS volumes of the demo account, one of them unattached
+var volumes = []ec2types.Volume{
+	demoVolume("vol-0a1b2c3d4e5f60001", "web-1-root", 20, ec2types.VolumeTypeGp3, "i-0a1b2c3d4e5f60001", "/dev/xvda", 42),
+	demoVolume("vol-0a1b2c3d4e5f60002", "web-2-root", 20, ec2types.VolumeTypeGp3, "i-0a1b2c3d4e5f60002", "/dev/xvda", 42),
+	demoVolume("vol-0a1b2c3d4e5f60003", "worker-1-data", 500, ec2types.VolumeTypeGp3, "i-0a1b2c3d4e5f60003", "/dev/sdf", 17),
+	demoVolume("vol-0a1b2c3d4e5f60004", "bastion-root", 8, ec2types.VolumeTypeGp2, "i-0a1b2c3d4e5f60004", "/dev/xvda", 210),
+	demoVolume("vol-0a1b2c3d4e5f60005", "old-backup", 100, ec2types.VolumeTypeGp2, "", "", 400),
+}
+
+// DescribeVolumes returns the volumes, filtered by ID or by status
+func (ec2API) DescribeVolumes(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+	output := &ec2.DescribeVolumesOutput{}
+	for _, volume := range volumes {
+		if len(params.VolumeIds) > 0 && !slices.Contains(params.VolumeIds, aws.ToString(volume.VolumeId)) {
+			continue
+		}
+		if !matchesFilters(params.Filters, map[string]string{"status": string(volume.State)}) {
+			continue
+		}
+		output.Volumes = append(output.Volumes, volume)
+	}
+	return output, nil
+}
+
+// matchesFilters reports whether the known fields of a resource match the filters of a request,
+// the filters on other fields are ignored
+func matchesFilters(filters []ec2types.Filter, fields map[string]string) bool {
+	for _, filter := range filters {
+		value, ok := fields[aws.ToString(filter.Name)]
+		if ok && !slices.Contains(filter.Values, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// DescribeSpotInstanceRequests returns a fulfilled spot request
+func (ec2API) DescribeSpotInstanceRequests(ctx context.Context, params *ec2.DescribeSpotInstanceRequestsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSpotInstanceRequestsOutput, error) {
+	return &ec2.DescribeSpotInstanceRequestsOutput{
+		SpotInstanceRequests: []ec2types.SpotInstanceRequest{{
+			SpotInstanceRequestId: aws.String("sir-0a1b2c3d"),
+			State:                 ec2types.SpotInstanceStateActive,
+			SpotPrice:             aws.String("0.0420"),
+			InstanceId:            aws.String("i-0a1b2c3d4e5f60003"),
+			LaunchSpecification:   &ec2types.LaunchSpecification{InstanceType: ec2types.InstanceTypeM5Large},
+			Status:                &ec2types.SpotInstanceStatus{Code: aws.String("fulfilled")},
+			CreateTime:            ago(17 * day),
+		}},
+	}, nil
+}
+
+// DescribeSpotFleetRequests returns a spot fleet
+func (ec2API) DescribeSpotFleetRequests(ctx context.Context, params *ec2.DescribeSpotFleetRequestsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSpotFleetRequestsOutput, error) {
+	return &ec2.DescribeSpotFleetRequestsOutput{
+		SpotFleetRequestConfigs: []ec2types.SpotFleetRequestConfig{{
+			SpotFleetRequestId:    aws.String("sfr-0a1b2c3d-4e5f-6071-8293-a4b5c6d7e8f9"),
+			SpotFleetRequestState: ec2types.BatchStateActive,
+			ActivityStatus:        ec2types.ActivityStatusFulfilled,
+			CreateTime:            ago(30 * day),
+			SpotFleetRequestConfig: &ec2types.SpotFleetRequestConfigData{
+				SpotPrice:         aws.String("0.1000"),
+				TargetCapacity:    aws.Int32(4),
+				FulfilledCapacity: aws.Float64(4),
+				LaunchSpecifications: []ec2types.SpotFleetLaunchSpecification{
+					{InstanceType: ec2types.InstanceTypeC5Large},
+					{InstanceType: ec2types.InstanceTypeC5aLarge},
+				},
+			},
+		}},
+	}, nil
+}
+
+// lambdaAPI serves the functions of the demo account
+type lambdaAPI struct {
+	client.LambdaAPI
+}
+
+// demoFunction returns a function
+func demoFunction(name, description string, runtime lambdatypes.Runtime, memory, timeout int32, age int) lambdatypes.FunctionConfiguration {
+	return lambdatypes.FunctionConfiguration{
+		FunctionName: aws.String(name),
+		FunctionArn:  aws.String(arn("lambda", "function:"+name)),
+		Description:  aws.String(description),
+		Runtime:      runtime,
+		Handler:      aws.String("index.handler"),
+		MemorySize:   aws.Int32(memory),
+		Timeout:      aws.Int32(timeout),
+		LastModified: aws.String(ago(time.Duration(age) * day).Format("2006-01-02T15:04:05.000-0700")),
+		Role:         aws.String(globalARN("iam", "role/"+name+"-role")),
+	}
+}
+
+// functions are the Lambda functions of the demo account
+var functions = []lambdatypes.FunctionConfiguration{
+	demoFunction("orders-api", "Orders REST API", lambdatypes.RuntimeNodejs20x, 512, 30, 3),
+	demoFunction("thumbnail-generator", "Resizes the uploaded images", lambdatypes.RuntimePython312, 1024, 60, 12),
+	demoFunction("nightly-report", "Sends the daily sales report", lambdatypes.RuntimePython312, 256, 300, 45),
+	demoFunction("stream-processor", "Consumes the orders stream", lambdatypes.RuntimeJava21, 2048, 120, 8),
+	demoFunction("legacy-webhook", "Deprecated partner webhook", lambdatypes.RuntimeNodejs18x, 128, 10, 400),
+}
+
+// ListFunctions returns the functions
+func (lambdaAPI) ListFunctions(ctx context.Context, params *lambda.ListFunctionsInput, optFns ...func(*lambda.Options)) (*lambda.ListFunctionsOutput, error) {
+	return &lambda.ListFunctionsOutput{Functions: functions}, nil
+}
+
+// ecsAPI serves the clusters and task definitions of the demo account
+type ecsAPI struct {
+	client.ECSAPI
+}
+
+// clusters are the ECS clusters of the demo account
+var clusters = []ecstypes.Cluster{
+	{ClusterName: aws.String("production"), ClusterArn: aws.String(arn("ecs", "cluster/production")), Status: aws.String("ACTIVE"), RunningTasksCount: 12, ActiveServicesCount: 5},
+	{ClusterName: aws.String("staging"), ClusterArn: aws.String(arn("ecs", "cluster/staging")), Status: aws.String("ACTIVE"), RunningTasksCount: 4, PendingTasksCount: 1, ActiveServicesCount: 5},
+	{ClusterName: aws.String("batch"), ClusterArn: aws.String(arn("ecs", "cluster/batch")), Status: aws.String("ACTIVE"), RegisteredContainerInstancesCount: 2},
+}
+
+// ListClusters returns the ARNs of the clusters
+func (ecsAPI) ListClusters(ctx context.Context, params *ecs.ListClustersInput, optFns ...func(*ecs.Options)) (*ecs.ListClustersOutput, error) {
+	output := &ecs.ListClustersOutput{}
+	for _, cluster := range clusters {
+		output.ClusterArns = append(output.ClusterArns, aws.ToString(cluster.ClusterArn))
+	}
+	return output, nil
+}
+
+// DescribeClusters returns the clusters
+func (ecsAPI) DescribeClusters(ctx context.Context, params *ecs.DescribeClustersInput, optFns ...func(*ecs.Options)) (*ecs.DescribeClustersOutput, error) {
+	output := &ecs.DescribeClustersOutput{}
+	for _, cluster := range clusters {
+		if slices.Contains(params.Clusters, aws.ToString(cluster.ClusterArn)) || slices.Contains(params.Clusters, aws.ToString(cluster.ClusterName)) {
+			output.Clusters = append(output.Clusters, cluster)
+		}
+	}
+	return output, nil
+}
+
+// ListTaskDefinitions returns the task definitions, latest revisions first
+func (ecsAPI) ListTaskDefinitions(ctx context.Context, params *ecs.ListTaskDefinitionsInput, optFns ...func(*ecs.Options)) (*ecs.ListTaskDefinitionsOutput, error) {
+	output := &ecs.ListTaskDefinitionsOutput{}
+	for family, revision := range map[string]int{"orders-api": 42, "payments": 17, "frontend": 108, "report-worker": 6} {
+		for r := revision; r > revision-3 && r > 0; r-- {
+			output.TaskDefinitionArns = append(output.TaskDefinitionArns, arn("ecs", fmt.Sprintf("task-definition/%s:%d", family, r)))
+		}
+	}
+	slices.Sort(output.TaskDefinitionArns)
+	return output, nil
+}
+
+// eksAPI serves the clusters of the demo account
+type eksAPI struct {
+	client.EKSAPI
+}
+
+// kubernetesClusters are the EKS clusters of the demo account
+var kubernetesClusters = []ekstypes.Cluster{
+	{Name: aws.String("platform"), Status: ekstypes.ClusterStatusActive, Version: aws.String("1.31"), PlatformVersion: aws.String("eks.12"), CreatedAt: ago(300 * day)},
+	{Name: aws.String("analytics"), Status: ekstypes.ClusterStatusActive, Version: aws.String("1.29"), PlatformVersion: aws.String("eks.18"), CreatedAt: ago(520 * day)},
+	{Name: aws.String("sandbox"), Status: ekstypes.ClusterStatusUpdating, Version: aws.String("1.32"), PlatformVersion: aws.String("eks.3"), CreatedAt: ago(20 * day)},
+}
+
+// ListClusters returns the names of the clusters
+func (eksAPI) ListClusters(ctx context.Context, params *eks.ListClustersInput, optFns ...func(*eks.Options)) (*eks.ListClustersOutput, error) {
+	output := &eks.ListClustersOutput{}
+	for _, cluster := range kubernetesClusters {
+		output.Clusters = append(output.Clusters, aws.ToString(cluster.Name))
+	}
+	return output, nil
+}
+
+// DescribeCluster returns a cluster
+func (eksAPI) DescribeCluster(ctx context.Context, params *eks.DescribeClusterInput, optFns ...func(*eks.Options)) (*eks.DescribeClusterOutput, error) {
+	for _, cluster := range kubernetesClusters {
+		if aws.ToString(cluster.Name) == aws.ToString(params.Name) {
+			cluster.Arn = aws.String(arn("eks", "cluster/"+aws.ToString(cluster.Name)))
+			cluster.Endpoint = aws.String(fmt.Sprintf("https://%X.gr7.%s.eks.amazonaws.com", spread(aws.ToString(cluster.Name), 1<<28, 1<<30), Region))
+			cluster.RoleArn = aws.String(globalARN("iam", "role/eks-cluster-role"))
+			return &eks.DescribeClusterOutput{Cluster: &cluster}, nil
+		}
+	}
+	return nil, fmt.Errorf("no cluster named %s", aws.ToString(params.Name))
+}
@@ -0,0 +1,368 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	dms "github.com/aws/aws-sdk-go-v2/service/databasemigrationservice"
+	dmstypes "github.com/aws/aws-sdk-go-v2/service/databasemigrationservice/types"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	ectypes "github.com/aws/aws-sdk-go-v2/service/elasticache/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// s3API serves the buckets of the demo account
+type s3API struct {
+	client.S3API
+}
+
+// buckets are the S3 buckets of the demo account with their region
+var buckets = []struct {
+	name   string
+	region string
+	age    int
+}{
+	{"acme-website-assets", "eu-west-1", 900},
+	{"acme-uploads-prod", "eu-west-1", 640},
+	{"acme-data-lake", "eu-west-1", 420},
+	{"acme-logs-archive", "eu-central-1", 1200},
+	{"acme-terraform-state", "us-east-1", 1500},
+	{"acme-tmp-exports", "eu-west-1", 3},
+}
+
+// ListBuckets returns the buckets
+func (s3API) ListBuckets(ctx context.Context, params *s3.ListBucketsInput, optFns ...func(*s3.Options)) (*s3.ListBucketsOutput, error) {
+	output := &s3.ListBucketsOutput{}
+	for _, bucket := range buckets {
+		output.Buckets = append(output.Buckets, s3types.Bucket{
+			Name:         aws.String(bucket.name),
+			CreationDate: ago(time.Duration(bucket.age) * day),
+		})
+	}
+	return output, nil
+}
+
+// GetBucketLocation returns the region of a bucket, empty for us-east-1
+func (s3API) GetBucketLocation(ctx context.Context, params *s3.GetBucketLocationInput, optFns ...func(*s3.Options)) (*s3.GetBucketLocationOutput, error) {
+	for _, bucket := range buckets {
+		if bucket.name != aws.ToString(params.Bucket) {
+			continue
+		}
+		if bucket.region == "us-east-1" {
+			return &s3.GetBucketLocationOutput{}, nil
+		}
+		return &s3.GetBucketLocationOutput{LocationConstraint: s3types.BucketLocationConstraint(bucket.region)}, nil
+	}
+	return nil, fmt.Errorf("no bucket named %s", aws.ToString(params.Bucket))
+}
+
+// rdsAPI serves the databases of the demo account
+type rdsAPI struct {
+	client.RDSAPI
+}
+
+// demoDatabase returns a database instance
+func demoDatabase(id, cluster, class, engine, version, status, zone string, multiAZ bool, storage int32) rdstypes.DBInstance {
+	port := int32(5432)
+	if engine == "mysql" || engine == "aurora-mysql" {
+		port = 3306
+	}
+	family := map[string]string{"postgres": "postgres16", "mysql": "mysql8.0", "aurora-postgresql": "aurora-postgresql15"}[engine]
+
+	db := rdstypes.DBInstance{
+		DBInstanceArn:        aws.String(arn("rds", "db:"+id)),
+		DBInstanceIdentifier: aws.String(id),
+		DBInstanceClass:      aws.String(class),
+		Engine:               aws.String(engine),
+		EngineVersion:        aws.String(version),
+		DBInstanceStatus:     aws.String(status),
+		AvailabilityZone:     aws.String(zone),
+		MultiAZ:              aws.Bool(multiAZ),
+		StorageType:          aws.String("gp3"),
+		AllocatedStorage:     aws.Int32(storage),
+		Endpoint: &rdstypes.Endpoint{
+			Address: aws.String(fmt.Sprintf("%s.c9akciq32.%s.rds.amazonaws.com", id, Region)),
+			Port:    aws.Int32(port),
+		},
+		DBParameterGroups: []rdstypes.DBParameterGroupStatus{{
+			DBParameterGroupName: aws.String("acme-" + family),
+			ParameterApplyStatus: aws.String("in-sync"),
+		}},
+	}
+	if cluster != "" {
+		db.DBClusterIdentifier = aws.String(cluster)
+	}
+	if engine == "mysql" {
+		db.OptionGroupMemberships = []rdstypes.OptionGroupMembership{{OptionGroupName: aws.String("acme-mysql-audit"), Status: aws.String("in-sync")}}
+		db.DBParameterGroups[0].ParameterApplyStatus = aws.String("pending-reboot")
+	}
+	return db
+}
+
+// databases are the RDS instances of the demo account, two of them members of the Aurora cluster
+var databases = []rdstypes.DBInstance{
+	demoDatabase("orders-db", "", "db.r6g.large", "postgres", "16.4", "available", "eu-west-1a", true, 200),
+	demoDatabase("legacy-crm", "", "db.t3.medium", "mysql", "8.0.39", "available", "eu-west-1b", false, 100),
+	demoDatabase("reporting-replica", "", "db.t4g.large", "postgres", "16.4", "stopped", "eu-west-1a", false, 200),
+	demoDatabase("catalog-1", "catalog", "db.r6g.xlarge", "aurora-postgresql", "15.8", "available", "eu-west-1a", false, 1),
+	demoDatabase("catalog-2", "catalog", "db.r6g.xlarge", "aurora-postgresql", "15.8", "available", "eu-west-1b", false, 1),
+}
+
+// DescribeDBInstances returns the instances, or the one requested
+func (rdsAPI) DescribeDBInstances(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+	output := &rds.DescribeDBInstancesOutput{}
+	for _, db := range databases {
+		if params.DBInstanceIdentifier == nil || aws.ToString(params.DBInstanceIdentifier) == aws.ToString(db.DBInstanceIdentifier) {
+			output.DBInstances = append(output.DBInstances, db)
+		}
+	}
+	return output, nil
+}
+
+// DescribeDBSnapshots returns no snapshot in progress
+func (rdsAPI) DescribeDBSnapshots(ctx context.Context, params *rds.DescribeDBSnapshotsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBSnapshotsOutput, error) {
+	return &rds.DescribeDBSnapshotsOutput{}, nil
+}
+
+// DescribeDBClusters returns the Aurora cluster
+func (rdsAPI) DescribeDBClusters(ctx context.Context, params *rds.DescribeDBClustersInput, optFns ...func(*rds.Options)) (*rds.DescribeDBClustersOutput, error) {
+	return &rds.DescribeDBClustersOutput{
+		DBClusters: []rdstypes.DBCluster{{
+			DBClusterArn:        aws.String(arn("rds", "cluster:catalog")),
+			DBClusterIdentifier: aws.String("catalog"),
+			Engine:              aws.String("aurora-postgresql"),
+			EngineVersion:       aws.String("15.8"),
+			Status:              aws.String("available"),
+			MultiAZ:             aws.Bool(true),
+			Endpoint:            aws.String(fmt.Sprintf("catalog.cluster-c9akciq32.%s.rds.amazonaws.com", Region)),
+			Port:                aws.Int32(5432),
+			DBClusterMembers: []rdstypes.DBClusterMember{
+				{DBInstanceIdentifier: aws.String("catalog-1"), IsClusterWriter: aws.Bool(true)},
+				{DBInstanceIdentifier: aws.String("catalog-2"), IsClusterWriter: aws.Bool(false)},
+			},
+		}},
+	}, nil
+}
+
+// DescribeDBClusterSnapshots returns no snapshot in progress
+func (rdsAPI) DescribeDBClusterSnapshots(ctx context.Context, params *rds.DescribeDBClusterSnapshotsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBClusterSnapshotsOutput, error) {
+	return &rds.DescribeDBClusterSnapshotsOutput{}, nil
+}
+
+// DescribeDBParameterGroups returns the parameter groups of the instances, and an unused one
+func (rdsAPI) DescribeDBParameterGroups(ctx context.Context, params *rds.DescribeDBParameterGroupsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBParameterGroupsOutput, error) {
+	output := &rds.DescribeDBParameterGroupsOutput{}
+	for _, family := range []string{"postgres16", "mysql8.0", "aurora-postgresql15", "postgres14"} {
+		output.DBParameterGroups = append(output.DBParameterGroups, rdstypes.DBParameterGroup{
+			DBParameterGroupName:   aws.String("acme-" + family),
+			DBParameterGroupFamily: aws.String(family),
+			Description:            aws.String("ACME defaults for " + family),
+		})
+	}
+	return output, nil
+}
+
+// DescribeOptionGroups returns the option group of the MySQL instance
+func (rdsAPI) DescribeOptionGroups(ctx context.Context, params *rds.DescribeOptionGroupsInput, optFns ...func(*rds.Options)) (*rds.DescribeOptionGroupsOutput, error) {
+	return &rds.DescribeOptionGroupsOutput{
+		OptionGroupsList: []rdstypes.OptionGroup{{
+			OptionGroupName:        aws.String("acme-mysql-audit"),
+			EngineName:             aws.String("mysql"),
+			MajorEngineVersion:     aws.String("8.0"),
+			OptionGroupDescription: aws.String("MariaDB audit plugin"),
+		}},
+	}, nil
+}
+
+// dynamoDBAPI serves the tables of the demo account
+type dynamoDBAPI struct {
+	client.DynamoDBAPI
+}
+
+// tables are the DynamoDB tables of the demo account
+var tables = []dynamodbtypes.TableDescription{
+	demoTable("sessions", "userId", "", dynamodbtypes.BillingModePayPerRequest, 184_203, 96_000_000, 300),
+	demoTable("orders", "customerId", "orderDate", dynamodbtypes.BillingModeProvisioned, 2_481_930, 1_700_000_000, 640),
+	demoTable("feature-flags", "flag", "", dynamodbtypes.BillingModePayPerRequest, 87, 12_400, 120),
+	demoTable("idempotency-keys", "key", "", dynamodbtypes.BillingModePayPerRequest, 51_002, 8_100_000, 30),
+}
+
+// demoTable returns a table
+func demoTable(name, partitionKey, sortKey string, billing dynamodbtypes.BillingMode, items, size int64, age int) dynamodbtypes.TableDescription {
+	table := dynamodbtypes.TableDescription{
+		TableName:          aws.String(name),
+		TableArn:           aws.String(arn("dynamodb", "table/"+name)),
+		TableStatus:        dynamodbtypes.TableStatusActive,
+		ItemCount:          aws.Int64(items),
+		TableSizeBytes:     aws.Int64(size),
+		BillingModeSummary: &dynamodbtypes.BillingModeSummary{BillingMode: billing},
+		KeySchema:          []dynamodbtypes.KeySchemaElement{{AttributeName: aws.String(partitionKey), KeyType: dynamodbtypes.KeyTypeHash}},
+		CreationDateTime:   ago(time.Duration(age) * day),
+	}
+	if sortKey != "" {
+		table.KeySchema = append(table.KeySchema, dynamodbtypes.KeySchemaElement{AttributeName: aws.String(sortKey), KeyType: dynamodbtypes.KeyTypeRange})
+	}
+	return table
+}
+
+// ListTables returns the names of the tables
+func (dynamoDBAPI) ListTables(ctx context.Context, params *dynamodb.ListTablesInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ListTablesOutput, error) {
+	output := &dynamodb.ListTablesOutput{}
+	for _, table := range tables {
+		output.TableNames = append(output.TableNames, aws.ToString(table.TableName))
+	}
+	return output, nil
+}
+
+// DescribeTable returns a table
+func (dynamoDBAPI) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	for _, table := range tables {
+		if aws.ToString(table.TableName) == aws.ToString(params.TableName) {
+			return &dynamodb.DescribeTableOutput{Table: &table}, nil
+		}
+	}
+	return nil, fmt.Errorf("no table named %s", aws.ToString(params.TableName))
+}
+
+// elastiCacheAPI serves the caches of the demo account
+type elastiCacheAPI struct {
+	client.ElastiCacheAPI
+}
+
+// cacheEndpoint returns the endpoint of a cache
+func cacheEndpoint(name string, port int32) *ectypes.Endpoint {
+	return &ectypes.Endpoint{Address: aws.String(fmt.Sprintf("%s.x1k2pz.cache.amazonaws.com", name)), Port: aws.Int32(port)}
+}
+
+// DescribeCacheClusters returns the nodes of the replication group and a Memcached cluster
+func (elastiCacheAPI) DescribeCacheClusters(ctx context.Context, params *elasticache.DescribeCacheClustersInput, optFns ...func(*elasticache.Options)) (*elasticache.DescribeCacheClustersOutput, error) {
+	output := &elasticache.DescribeCacheClustersOutput{
+		CacheClusters: []ectypes.CacheCluster{{
+			CacheClusterId:            aws.String("catalog-memcached"),
+			Engine:                    aws.String("memcached"),
+			EngineVersion:             aws.String("1.6.22"),
+			CacheNodeType:             aws.String("cache.t4g.medium"),
+			NumCacheNodes:             aws.Int32(2),
+			CacheClusterStatus:        aws.String("available"),
+			PreferredAvailabilityZone: aws.String("Multiple"),
+			ConfigurationEndpoint:     cacheEndpoint("catalog-memcached.cfg", 11211),
+		}},
+	}
+	for i, zone := range []string{"eu-west-1a", "eu-west-1b"} {
+		id := fmt.Sprintf("sessions-redis-00%d", i+1)
+		output.CacheClusters = append(output.CacheClusters, ectypes.CacheCluster{
+			CacheClusterId:            aws.String(id),
+			Engine:                    aws.String("redis"),
+			EngineVersion:             aws.String("7.1.0"),
+			CacheNodeType:             aws.String("cache.r7g.large"),
+			NumCacheNodes:             aws.Int32(1),
+			CacheClusterStatus:        aws.String("available"),
+			PreferredAvailabilityZone: aws.String(zone),
+			TransitEncryptionEnabled:  aws.Bool(true),
+			AtRestEncryptionEnabled:   aws.Bool(true),
+			AuthTokenEnabled:          aws.Bool(true),
+			ReplicationGroupId:        aws.String("sessions-redis"),
+			CacheNodes:                []ectypes.CacheNode{{Endpoint: cacheEndpoint(id, 6379)}},
+		})
+	}
+	return output, nil
+}
+
+// DescribeReplicationGroups returns the Redis replication group
+func (elastiCacheAPI) DescribeReplicationGroups(ctx context.Context, params *elasticache.DescribeReplicationGroupsInput, optFns ...func(*elasticache.Options)) (*elasticache.DescribeReplicationGroupsOutput, error) {
+	return &elasticache.DescribeReplicationGroupsOutput{
+		ReplicationGroups: []ectypes.ReplicationGroup{{
+			ReplicationGroupId:       aws.String("sessions-redis"),
+			Description:              aws.String("Web sessions"),
+			Status:                   aws.String("available"),
+			Engine:                   aws.String("redis"),
+			ClusterEnabled:           aws.Bool(false),
+			CacheNodeType:            aws.String("cache.r7g.large"),
+			MemberClusters:           []string{"sessions-redis-001", "sessions-redis-002"},
+			NodeGroups:               []ectypes.NodeGroup{{NodeGroupId: aws.String("0001"), PrimaryEndpoint: cacheEndpoint("master.sessions-redis", 6379)}},
+			TransitEncryptionEnabled: aws.Bool(true),
+			AtRestEncryptionEnabled:  aws.Bool(true),
+			AuthTokenEnabled:         aws.Bool(true),
+		}},
+	}, nil
+}
+
+// DescribeServerlessCaches returns a serverless Valkey cache
+func (elastiCacheAPI) DescribeServerlessCaches(ctx context.Context, params *elasticache.DescribeServerlessCachesInput, optFns ...func(*elasticache.Options)) (*elasticache.DescribeServerlessCachesOutput, error) {
+	return &elasticache.DescribeServerlessCachesOutput{
+		ServerlessCaches: []ectypes.ServerlessCache{{
+			ServerlessCacheName: aws.String("rate-limiter"),
+			Engine:              aws.String("valkey"),
+			FullEngineVersion:   aws.String("8.0"),
+			Status:              aws.String("available"),
+			Endpoint:            cacheEndpoint("rate-limiter-x1k2pz.serverless", 6379),
+			CacheUsageLimits: &ectypes.CacheUsageLimits{
+				DataStorage:   &ectypes.DataStorage{Maximum: aws.Int32(10), Unit: ectypes.DataStorageUnitGb},
+				ECPUPerSecond: &ectypes.ECPUPerSecond{Maximum: aws.Int32(50000)},
+			},
+			CreateTime: ago(60 * day),
+		}},
+	}, nil
+}
+
+// dmsAPI serves the replications of the demo account
+type dmsAPI struct {
+	client.DMSAPI
+}
+
+// replicationInstanceARN is the ARN of the replication instance
+var replicationInstanceARN = arn("dms", "rep:LEGACYMIGRATION")
+
+// DescribeReplicationInstances returns the replication instance
+func (dmsAPI) DescribeReplicationInstances(ctx context.Context, params *dms.DescribeReplicationInstancesInput, optFns ...func(*dms.Options)) (*dms.DescribeReplicationInstancesOutput, error) {
+	return &dms.DescribeReplicationInstancesOutput{
+		ReplicationInstances: []dmstypes.ReplicationInstance{{
+			ReplicationInstanceArn:        aws.String(replicationInstanceARN),
+			ReplicationInstanceIdentifier: aws.String("legacy-migration"),
+			ReplicationInstanceClass:      aws.String("dms.t3.medium"),
+			ReplicationInstanceStatus:     aws.String("available"),
+			EngineVersion:                 aws.String("3.5.3"),
+			AllocatedStorage:              50,
+			AvailabilityZone:              aws.String("eu-west-1a"),
+			InstanceCreateTime:            ago(90 * day),
+		}},
+	}, nil
+}
+
+// DescribeReplicationTasks returns the tasks of the replication instance
+func (dmsAPI) DescribeReplicationTasks(ctx context.Context, params *dms.DescribeReplicationTasksInput, optFns ...func(*dms.Options)) (*dms.DescribeReplicationTasksOutput, error) {
+	for _, filter := range params.Filters {
+		if aws.ToString(filter.Name) == "replication-instance-arn" && !slices.Contains(filter.Values, replicationInstanceARN) {
+			return &dms.DescribeReplicationTasksOutput{}, nil
+		}
+	}
+	return &dms.DescribeReplicationTasksOutput{
+		ReplicationTasks: []dmstypes.ReplicationTask{
+			{
+				ReplicationTaskArn:        aws.String(arn("dms", "task:CRMFULLLOAD")),
+				ReplicationTaskIdentifier: aws.String("crm-full-load-and-cdc"),
+				Status:                    aws.String("running"),
+				MigrationType:             dmstypes.MigrationTypeValueFullLoadAndCdc,
+				ReplicationTaskStats:      &dmstypes.ReplicationTaskStats{FullLoadProgressPercent: 100, TablesLoaded: 42, StartDate: ago(12 * day)},
+			},
+			{
+				ReplicationTaskArn:        aws.String(arn("dms", "task:ORDERSARCHIVE")),
+				ReplicationTaskIdentifier: aws.String("orders-archive"),
+				Status:                    aws.String("failed"),
+				MigrationType:             dmstypes.MigrationTypeValueFullLoad,
+				LastFailureMessage:        aws.String("Last Error ORA-01555: snapshot too old. Task error notification received from subtask 0"),
+				ReplicationTaskStats:      &dmstypes.ReplicationTaskStats{FullLoadProgressPercent: 63, TablesLoaded: 11, TablesErrored: 1, TablesQueued: 6, StartDate: ago(2 * day)},
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,142 @@
+// Package demo serves canned fixture data to every registered resource, so that the UI can be
+// explored, screenshotted and developed without credentials nor network
+package demo
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"time"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/apigateway"
+	"github.com/aws/aws-sdk-go-v2/service/apigatewayv2"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	"github.com/aws/aws-sdk-go-v2/service/databasemigrationservice"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/savingsplans"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/sfn"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/aws-sdk-go-v2/service/support"
+)
+
+const (
+	// Region of the demo session
+	Region = "eu-west-1"
+
+	// Profile of the demo session
+	Profile = "demo"
+
+	// AccountID is the account owning the fixtures
+	AccountID = "123456789012"
+)
+
+// day is the unit of most fixture ages
+const day = 24 * time.Hour
+
+// ErrOffline is returned by the calls which have no fixture data, e.g. the mutating actions
+var ErrOffline = errors.New("not available in demo mode")
+
+// offline fails every request reaching the network
+type offline struct{}
+
+// Do fails the request
+func (offline) Do(*http.Request) (*http.Response, error) {
+	return nil, ErrOffline
+}
+
+// Client returns a client serving the fixtures, the other calls fail with ErrOffline
+func Client() *client.Client {
+	cfg := aws.Config{
+		Region:      Region,
+		Credentials: aws.AnonymousCredentials{},
+		HTTPClient:  offline{},
+		Retryer:     func() aws.Retryer { return aws.NopRetryer{} },
+	}
+
+	return client.NewStatic(cfg, Profile, map[string]any{
+		"ec2":                      &ec2API{EC2API: ec2.NewFromConfig(cfg)},
+		"s3":                       &s3API{S3API: s3.NewFromConfig(cfg)},
+		"lambda":                   &lambdaAPI{LambdaAPI: lambda.NewFromConfig(cfg)},
+		"ecs":                      &ecsAPI{ECSAPI: ecs.NewFromConfig(cfg)},
+		"eks":                      &eksAPI{EKSAPI: eks.NewFromConfig(cfg)},
+		"rds":                      &rdsAPI{RDSAPI: rds.NewFromConfig(cfg)},
+		"acm":                      &acmAPI{ACMAPI: acm.NewFromConfig(cfg)},
+		"costexplorer":             &costExplorerAPI{CostExplorerAPI: costexplorer.NewFromConfig(cfg)},
+		"cloudfront":               &cloudFrontAPI{CloudFrontAPI: cloudfront.NewFromConfig(cfg)},
+		"elasticloadbalancingv2":   &elbv2API{ELBv2API: elasticloadbalancingv2.NewFromConfig(cfg)},
+		"dynamodb":                 &dynamoDBAPI{DynamoDBAPI: dynamodb.NewFromConfig(cfg)},
+		"secretsmanager":           &secretsManagerAPI{SecretsManagerAPI: secretsmanager.NewFromConfig(cfg)},
+		"kms":                      &kmsAPI{KMSAPI: kms.NewFromConfig(cfg)},
+		"ecr":                      &ecrAPI{ECRAPI: ecr.NewFromConfig(cfg)},
+		"cognitoidentityprovider":  &cognitoAPI{CognitoAPI: cognitoidentityprovider.NewFromConfig(cfg)},
+		"iam":                      &iamAPI{IAMAPI: iam.NewFromConfig(cfg)},
+		"sqs":                      &sqsAPI{SQSAPI: sqs.NewFromConfig(cfg)},
+		"sns":                      &snsAPI{SNSAPI: sns.NewFromConfig(cfg)},
+		"apigateway":               &apiGatewayAPI{APIGatewayAPI: apigateway.NewFromConfig(cfg)},
+		"apigatewayv2":             &apiGatewayV2API{APIGatewayV2API: apigatewayv2.NewFromConfig(cfg)},
+		"elasticache":              &elastiCacheAPI{ElastiCacheAPI: elasticache.NewFromConfig(cfg)},
+		"route53":                  &route53API{Route53API: route53.NewFromConfig(cfg)},
+		"cloudwatch":               &cloudWatchAPI{CloudWatchAPI: cloudwatch.NewFromConfig(cfg)},
+		"cloudformation":           &cloudFormationAPI{CloudFormationAPI: cloudformation.NewFromConfig(cfg)},
+		"ssm":                      &ssmAPI{SSMAPI: ssm.NewFromConfig(cfg)},
+		"savingsplans":             &savingsPlansAPI{SavingsPlansAPI: savingsplans.NewFromConfig(cfg)},
+		"resourcegroupstaggingapi": &taggingAPI{TaggingAPI: resourcegroupstaggingapi.NewFromConfig(cfg)},
+		"support":                  &supportAPI{SupportAPI: support.NewFromConfig(cfg)},
+		"databasemigrationservice": &dmsAPI{DMSAPI: databasemigrationservice.NewFromConfig(cfg)},
+		"eventbridge":              &eventBridgeAPI{EventBridgeAPI: eventbridge.NewFromConfig(cfg)},
+		"sfn":                      &sfnAPI{SFNAPI: sfn.NewFromConfig(cfg)},
+		"organizations":            &organizationsAPI{OrganizationsAPI: organizations.NewFromConfig(cfg)},
+		"sts":                      &stsAPI{STSAPI: sts.NewFromConfig(cfg)},
+	})
+}
+
+// ago returns the time the given duration before now
+func ago(d time.Duration) *time.Time {
+	return aws.Time(time.Now().Add(-d).Truncate(time.Minute))
+}
+
+// arn returns the ARN of a resource of the demo account
+func arn(service, resource string) string {
+	return fmt.Sprintf("arn:aws:%s:%s:%s:%s", service, Region, AccountID, resource)
+}
+
+// globalARN returns the ARN of a resource of a global service, e.g. IAM
+func globalARN(service, resource string) string {
+	return fmt.Sprintf("arn:aws:%s::%s:%s", service, AccountID, resource)
+}
+
+// spread returns a stable pseudo-random number in [low, high) for a name, for the fixture
+// values which are too many to be written out, e.g. metrics
+func spread(name string, low, high int) int {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return low + int(h.Sum32()%uint32(high-low))
+}
@@ -0,0 +1,175 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	eventbridgetypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/aws/aws-sdk-go-v2/service/sfn"
+	sfntypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// sqsAPI serves the queues of the demo account
+type sqsAPI struct {
+	client.SQSAPI
+}
+
+// queues are the queues of the demo account with the dead-letter queue of each
+var queues = []struct {
+	name       string
+	deadLetter string
+}{
+	{"orders", "orders-dlq"},
+	{"orders-dlq", ""},
+	{"emails", "emails-dlq"},
+	{"emails-dlq", ""},
+	{"thumbnails.fifo", ""},
+}
+
+// ListQueues returns the URLs of the queues
+func (sqsAPI) ListQueues(ctx context.Context, params *sqs.ListQueuesInput, optFns ...func(*sqs.Options)) (*sqs.ListQueuesOutput, error) {
+	output := &sqs.ListQueuesOutput{}
+	for _, queue := range queues {
+		output.QueueUrls = append(output.QueueUrls, fmt.Sprintf("https://sqs.%s.amazonaws.com/%s/%s", Region, AccountID, queue.name))
+	}
+	return output, nil
+}
+
+// GetQueueAttributes returns the attributes of a queue, messages piling up in a dead-letter queue
+func (sqsAPI) GetQueueAttributes(ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error) {
+	url := aws.ToString(params.QueueUrl)
+	name := url[strings.LastIndex(url, "/")+1:]
+	for _, queue := range queues {
+		if queue.name != name {
+			continue
+		}
+		attributes := map[string]string{
+			"QueueArn":                              arn("sqs", name),
+			"ApproximateNumberOfMessages":           fmt.Sprint(spread(name, 0, 500)),
+			"ApproximateNumberOfMessagesNotVisible": fmt.Sprint(spread(name, 0, 20)),
+			"MessageRetentionPeriod":                "345600",
+		}
+		if strings.HasSuffix(name, "-dlq") {
+			attributes["ApproximateNumberOfMessagesNotVisible"] = "0"
+			attributes["MessageRetentionPeriod"] = "1209600"
+		}
+		if queue.deadLetter != "" {
+			attributes["RedrivePolicy"] = fmt.Sprintf(`{"deadLetterTargetArn":"%s","maxReceiveCount":5}`, arn("sqs", queue.deadLetter))
+		}
+		return &sqs.GetQueueAttributesOutput{Attributes: attributes}, nil
+	}
+	return nil, fmt.Errorf("no queue %s", url)
+}
+
+// snsAPI serves the topics of the demo account
+type snsAPI struct {
+	client.SNSAPI
+}
+
+// topics are the names of the topics of the demo account
+var topics = []string{"order-events", "alarms", "deployments"}
+
+// ListTopics returns the topics
+func (snsAPI) ListTopics(ctx context.Context, params *sns.ListTopicsInput, optFns ...func(*sns.Options)) (*sns.ListTopicsOutput, error) {
+	output := &sns.ListTopicsOutput{}
+	for _, topic := range topics {
+		output.Topics = append(output.Topics, snstypes.Topic{TopicArn: aws.String(arn("sns", topic))})
+	}
+	return output, nil
+}
+
+// GetTopicAttributes returns the subscription counts of a topic
+func (snsAPI) GetTopicAttributes(ctx context.Context, params *sns.GetTopicAttributesInput, optFns ...func(*sns.Options)) (*sns.GetTopicAttributesOutput, error) {
+	name := aws.ToString(params.TopicArn)
+	return &sns.GetTopicAttributesOutput{
+		Attributes: map[string]string{
+			"TopicArn":               name,
+			"SubscriptionsPending":   fmt.Sprint(spread(name, 0, 2)),
+			"SubscriptionsConfirmed": fmt.Sprint(spread(name, 1, 6)),
+			"SubscriptionsDeleted":   "0",
+		},
+	}, nil
+}
+
+// eventBridgeAPI serves the event buses and rules of the demo account
+type eventBridgeAPI struct {
+	client.EventBridgeAPI
+}
+
+// ListEventBuses returns the default bus and the one of the orders
+func (eventBridgeAPI) ListEventBuses(ctx context.Context, params *eventbridge.ListEventBusesInput, optFns ...func(*eventbridge.Options)) (*eventbridge.ListEventBusesOutput, error) {
+	return &eventbridge.ListEventBusesOutput{
+		EventBuses: []eventbridgetypes.EventBus{
+			{Name: aws.String("default"), Arn: aws.String(arn("events", "event-bus/default"))},
+			{Name: aws.String("orders"), Arn: aws.String(arn("events", "event-bus/orders"))},
+		},
+	}, nil
+}
+
+// ListRules returns the rules of a bus
+func (eventBridgeAPI) ListRules(ctx context.Context, params *eventbridge.ListRulesInput, optFns ...func(*eventbridge.Options)) (*eventbridge.ListRulesOutput, error) {
+	bus := aws.ToString(params.EventBusName)
+	rule := func(name, description string, state eventbridgetypes.RuleState) eventbridgetypes.Rule {
+		resource := "rule/" + name
+		if bus != "default" {
+			resource = fmt.Sprintf("rule/%s/%s", bus, name)
+		}
+		return eventbridgetypes.Rule{
+			Arn:          aws.String(arn("events", resource)),
+			Name:         aws.String(name),
+			EventBusName: aws.String(bus),
+			State:        state,
+			Description:  aws.String(description),
+		}
+	}
+
+	output := &eventbridge.ListRulesOutput{}
+	switch bus {
+	case "default":
+		nightly := rule("nightly-export", "Export the orders every night", eventbridgetypes.RuleStateEnabled)
+		nightly.ScheduleExpression = aws.String("cron(0 2 * * ? *)")
+		cleanup := rule("weekly-cleanup", "Remove the expired carts", eventbridgetypes.RuleStateDisabled)
+		cleanup.ScheduleExpression = aws.String("rate(7 days)")
+		stateChange := rule("instance-state", "Notify the instance state changes", eventbridgetypes.RuleStateEnabled)
+		stateChange.EventPattern = aws.String(`{"source":["aws.ec2"],"detail-type":["EC2 Instance State-change Notification"]}`)
+		output.Rules = []eventbridgetypes.Rule{nightly, cleanup, stateChange}
+	case "orders":
+		placed := rule("order-placed", "Send the confirmation email", eventbridgetypes.RuleStateEnabled)
+		placed.EventPattern = aws.String(`{"source":["acme.orders"],"detail-type":["OrderPlaced"]}`)
+		output.Rules = []eventbridgetypes.Rule{placed}
+	}
+	return output, nil
+}
+
+// sfnAPI serves the state machines of the demo account
+type sfnAPI struct {
+	client.SFNAPI
+}
+
+// ListStateMachines returns the state machines
+func (sfnAPI) ListStateMachines(ctx context.Context, params *sfn.ListStateMachinesInput, optFns ...func(*sfn.Options)) (*sfn.ListStateMachinesOutput, error) {
+	machine := func(name string, machineType sfntypes.StateMachineType, age int) sfntypes.StateMachineListItem {
+		return sfntypes.StateMachineListItem{
+			StateMachineArn: aws.String(arn("states", "stateMachine:"+name)),
+			Name:            aws.String(name),
+			Type:            machineType,
+			CreationDate:    ago(time.Duration(age) * day),
+		}
+	}
+	return &sfn.ListStateMachinesOutput{
+		StateMachines: []sfntypes.StateMachineListItem{
+			machine("order-fulfilment", sfntypes.StateMachineTypeStandard, 320),
+			machine("refund", sfntypes.StateMachineTypeStandard, 150),
+			machine("thumbnail-pipeline", sfntypes.StateMachineTypeExpress, 60),
+		},
+	}, nil
+}
@@ -0,0 +1,407 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cfntypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	cetypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	orgtypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	taggingtypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+	"github.com/aws/aws-sdk-go-v2/service/savingsplans"
+	sptypes "github.com/aws/aws-sdk-go-v2/service/savingsplans/types"
+	"github.com/aws/aws-sdk-go-v2/service/support"
+	supporttypes "github.com/aws/aws-sdk-go-v2/service/support/types"
+)
+
+// cloudWatchAPI serves made up metrics of the demo resources
+type cloudWatchAPI struct {
+	client.CloudWatchAPI
+}
+
+// metricRange is the range of the values of a metric, in units
+type metricRange struct {
+	low, high int
+	unit      float64
+}
+
+// metricRanges are the ranges of the metrics shown by the views, the others are percentages
+var metricRanges = map[string]metricRange{
+	"Invocations":                        {100, 50000, 1},
+	"Errors":                             {0, 50, 1},
+	"Duration":                           {20, 2000, 1},
+	"Throttles":                          {0, 20, 1},
+	"BucketSizeBytes":                    {1, 500, 1 << 30},
+	"NumberOfObjects":                    {1000, 5000000, 1},
+	"ApproximateNumberOfMessagesVisible": {0, 500, 1},
+	"ApproximateAgeOfOldestMessage":      {0, 3600, 1},
+}
+
+// GetMetricData returns a stable series per query, around a level picked from its dimensions
+func (cloudWatchAPI) GetMetricData(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+	start, end := aws.ToTime(params.StartTime), aws.ToTime(params.EndTime)
+	output := &cloudwatch.GetMetricDataOutput{}
+	for _, query := range params.MetricDataQueries {
+		result := cwtypes.MetricDataResult{Id: query.Id, StatusCode: cwtypes.StatusCodeComplete}
+		if query.MetricStat == nil || query.MetricStat.Metric == nil {
+			output.MetricDataResults = append(output.MetricDataResults, result)
+			continue
+		}
+
+		metric := query.MetricStat.Metric
+		name := aws.ToString(metric.MetricName)
+		key := name
+		storage := ""
+		for _, dimension := range metric.Dimensions {
+			key += "/" + aws.ToString(dimension.Value)
+			if aws.ToString(dimension.Name) == "StorageType" {
+				storage = aws.ToString(dimension.Value)
+			}
+		}
+
+		// Only the standard storage class holds data, and few functions are throttled
+		quiet := (name == "BucketSizeBytes" && storage != "StandardStorage") || (name == "Throttles" && spread(key, 0, 4) != 0)
+		if !quiet {
+			valueRange, ok := metricRanges[name]
+			if !ok {
+				valueRange = metricRange{0, 100, 1}
+			}
+			level := float64(spread(key, valueRange.low, valueRange.high+1))
+			period := time.Duration(max(aws.ToInt32(query.MetricStat.Period), 60)) * time.Second
+			points := min(max(int(end.Sub(start)/period), 1), 48)
+			for i := 0; i < points; i++ {
+				variation := float64(spread(fmt.Sprint(key, i), 60, 141)) / 100
+				result.Timestamps = append(result.Timestamps, end.Add(-time.Duration(points-i)*period))
+				result.Values = append(result.Values, level*variation*valueRange.unit)
+			}
+		}
+		output.MetricDataResults = append(output.MetricDataResults, result)
+	}
+	return output, nil
+}
+
+// cloudFormationAPI serves the stacks of the demo account
+type cloudFormationAPI struct {
+	client.CloudFormationAPI
+}
+
+// DescribeStacks returns the stacks, one of them drifted and one rolled back
+func (cloudFormationAPI) DescribeStacks(ctx context.Context, params *cloudformation.DescribeStacksInput, optFns ...func(*cloudformation.Options)) (*cloudformation.DescribeStacksOutput, error) {
+	stack := func(name, description string, status cfntypes.StackStatus, drift cfntypes.StackDriftStatus, age, updated int) cfntypes.Stack {
+		return cfntypes.Stack{
+			StackName:        aws.String(name),
+			StackId:          aws.String(arn("cloudformation", fmt.Sprintf("stack/%s/%08x-1a2b-3c4d-5e6f-0a1b2c3d4e5f", name, spread(name, 1<<28, 1<<31)))),
+			Description:      aws.String(description),
+			StackStatus:      status,
+			DriftInformation: &cfntypes.StackDriftInformation{StackDriftStatus: drift},
+			CreationTime:     ago(time.Duration(age) * day),
+			LastUpdatedTime:  ago(time.Duration(updated) * day),
+		}
+	}
+	return &cloudformation.DescribeStacksOutput{
+		Stacks: []cfntypes.Stack{
+			stack("network-prod", "VPC, subnets and gateways", cfntypes.StackStatusUpdateComplete, cfntypes.StackDriftStatusInSync, 900, 120),
+			stack("orders-prod", "Orders service", cfntypes.StackStatusUpdateComplete, cfntypes.StackDriftStatusDrifted, 500, 2),
+			stack("orders-staging", "Orders service", cfntypes.StackStatusUpdateRollbackComplete, cfntypes.StackDriftStatusNotChecked, 480, 0),
+			stack("monitoring", "Alarms and dashboards", cfntypes.StackStatusCreateComplete, cfntypes.StackDriftStatusNotChecked, 60, 60),
+		},
+	}, nil
+}
+
+// costExplorerAPI serves the costs of the demo account
+type costExplorerAPI struct {
+	client.CostExplorerAPI
+}
+
+// monthlyCosts are the costs of a whole month per service
+var monthlyCosts = []struct {
+	service string
+	amount  float64
+}{
+	{"Amazon Elastic Compute Cloud - Compute", 1843.27},
+	{"Amazon Relational Database Service", 1210.55},
+	{"Amazon Simple Storage Service", 412.08},
+	{"AWS Lambda", 96.41},
+	{"Amazon CloudFront", 87.90},
+	{"Amazon DynamoDB", 64.12},
+	{"Amazon ElastiCache", 58.33},
+	{"Elastic Load Balancing", 49.60},
+	{"AmazonCloudWatch", 21.75},
+	{"AWS Key Management Service", 4.00},
+}
+
+// GetCostAndUsage returns the costs per service of the month so far
+func (costExplorerAPI) GetCostAndUsage(ctx context.Context, params *costexplorer.GetCostAndUsageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageOutput, error) {
+	elapsed := float64(time.Now().Day()) / 30
+	result := cetypes.ResultByTime{TimePeriod: params.TimePeriod}
+	for _, cost := range monthlyCosts {
+		result.Groups = append(result.Groups, cetypes.Group{
+			Keys: []string{cost.service},
+			Metrics: map[string]cetypes.MetricValue{
+				"UnblendedCost": {Amount: aws.String(fmt.Sprintf("%.2f", cost.amount*elapsed)), Unit: aws.String("USD")},
+			},
+		})
+	}
+	return &costexplorer.GetCostAndUsageOutput{ResultsByTime: []cetypes.ResultByTime{result}}, nil
+}
+
+// GetAnomalies returns an ongoing anomaly and a past one, those since the start date
+func (costExplorerAPI) GetAnomalies(ctx context.Context, params *costexplorer.GetAnomaliesInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetAnomaliesOutput, error) {
+	date := func(days int) *string {
+		return aws.String(time.Now().Add(-time.Duration(days) * day).Format("2006-01-02"))
+	}
+	monitor := aws.String(globalARN("ce", "anomalymonitor/0a1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d"))
+	anomalies := []cetypes.Anomaly{
+		{
+			AnomalyId:        aws.String("0a1b2c3d-0001-4e5f-8a9b-0c1d2e3f4a5b"),
+			AnomalyStartDate: date(2),
+			DimensionValue:   aws.String("Amazon Elastic Compute Cloud - Compute"),
+			MonitorArn:       monitor,
+			AnomalyScore:     &cetypes.AnomalyScore{CurrentScore: 0.82, MaxScore: 0.91},
+			Impact:           &cetypes.Impact{TotalImpact: 212.40, MaxImpact: 118.35, TotalImpactPercentage: aws.Float64(64.3)},
+			RootCauses: []cetypes.RootCause{
+				{Service: aws.String("Amazon Elastic Compute Cloud - Compute"), Region: aws.String(Region), UsageType: aws.String("EU-BoxUsage:c5.xlarge"), LinkedAccount: aws.String(AccountID)},
+				{Service: aws.String("Amazon Elastic Compute Cloud - Compute"), Region: aws.String(Region), UsageType: aws.String("EU-EBS:VolumeUsage.gp3"), LinkedAccount: aws.String(AccountID)},
+			},
+		},
+		{
+			AnomalyId:        aws.String("0a1b2c3d-0002-4e5f-8a9b-0c1d2e3f4a5b"),
+			AnomalyStartDate: date(19),
+			AnomalyEndDate:   date(17),
+			DimensionValue:   aws.String("Amazon CloudFront"),
+			MonitorArn:       monitor,
+			Feedback:         cetypes.AnomalyFeedbackTypePlannedActivity,
+			AnomalyScore:     &cetypes.AnomalyScore{CurrentScore: 0.12, MaxScore: 0.74},
+			Impact:           &cetypes.Impact{TotalImpact: 48.10, MaxImpact: 30.02, TotalImpactPercentage: aws.Float64(121.8)},
+			RootCauses: []cetypes.RootCause{
+				{Service: aws.String("Amazon CloudFront"), Region: aws.String("global"), UsageType: aws.String("EU-DataTransfer-Out-Bytes"), LinkedAccount: aws.String(AccountID)},
+			},
+		},
+	}
+
+	output := &costexplorer.GetAnomaliesOutput{}
+	for _, anomaly := range anomalies {
+		if params.DateInterval == nil || aws.ToString(anomaly.AnomalyStartDate) >= aws.ToString(params.DateInterval.StartDate) {
+			output.Anomalies = append(output.Anomalies, anomaly)
+		}
+	}
+	return output, nil
+}
+
+// DescribeReservedInstances returns the active reservations
+func (ec2API) DescribeReservedInstances(ctx context.Context, params *ec2.DescribeReservedInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeReservedInstancesOutput, error) {
+	const year = 365 * day
+	return &ec2.DescribeReservedInstancesOutput{
+		ReservedInstances: []ec2types.ReservedInstances{
+			{
+				ReservedInstancesId: aws.String("3a1b2c3d-0001-4e5f-8a9b-0c1d2e3f4a5b"),
+				InstanceType:        ec2types.InstanceTypeT3Medium,
+				InstanceCount:       aws.Int32(2),
+				Duration:            aws.Int64(int64(year.Seconds())),
+				OfferingType:        ec2types.OfferingTypeValuesNoUpfront,
+				State:               ec2types.ReservedInstanceStateActive,
+				Start:               ago(year - 25*day),
+				End:                 aws.Time(time.Now().Add(25 * day).Truncate(time.Minute)),
+			},
+		},
+	}, nil
+}
+
+// GetReservationUtilization returns the utilization of the reservation
+func (costExplorerAPI) GetReservationUtilization(ctx context.Context, params *costexplorer.GetReservationUtilizationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationUtilizationOutput, error) {
+	return &costexplorer.GetReservationUtilizationOutput{
+		UtilizationsByTime: []cetypes.UtilizationByTime{{
+			TimePeriod: params.TimePeriod,
+			Groups: []cetypes.ReservationUtilizationGroup{{
+				Key:         aws.String("SUBSCRIPTION_ID"),
+				Value:       aws.String("3a1b2c3d-0001-4e5f-8a9b-0c1d2e3f4a5b"),
+				Attributes:  map[string]string{"leaseId": "3a1b2c3d-0001-4e5f-8a9b-0c1d2e3f4a5b"},
+				Utilization: &cetypes.ReservationAggregates{UtilizationPercentage: aws.String("100")},
+			}},
+		}},
+	}, nil
+}
+
+// GetReservationCoverage returns the share of the instance hours covered by the reservation
+func (costExplorerAPI) GetReservationCoverage(ctx context.Context, params *costexplorer.GetReservationCoverageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationCoverageOutput, error) {
+	return &costexplorer.GetReservationCoverageOutput{
+		Total: &cetypes.Coverage{CoverageHours: &cetypes.CoverageHours{CoverageHoursPercentage: aws.String("33.3")}},
+	}, nil
+}
+
+// savingsPlansAPI serves the savings plans of the demo account
+type savingsPlansAPI struct {
+	client.SavingsPlansAPI
+}
+
+// savingsPlanARN is the ARN of the savings plan of the demo account
+var savingsPlanARN = globalARN("savingsplans", "savingsplan/4a1b2c3d-0001-4e5f-8a9b-0c1d2e3f4a5b")
+
+// DescribeSavingsPlans returns the active savings plan
+func (savingsPlansAPI) DescribeSavingsPlans(ctx context.Context, params *savingsplans.DescribeSavingsPlansInput, optFns ...func(*savingsplans.Options)) (*savingsplans.DescribeSavingsPlansOutput, error) {
+	const term = 3 * 365 * day
+	start := time.Now().Add(-400 * day).UTC().Truncate(time.Hour)
+	return &savingsplans.DescribeSavingsPlansOutput{
+		SavingsPlans: []sptypes.SavingsPlan{{
+			SavingsPlanId:         aws.String("4a1b2c3d-0001-4e5f-8a9b-0c1d2e3f4a5b"),
+			SavingsPlanArn:        aws.String(savingsPlanARN),
+			SavingsPlanType:       sptypes.SavingsPlanTypeCompute,
+			Commitment:            aws.String("1.25"),
+			Currency:              sptypes.CurrencyCodeUsd,
+			TermDurationInSeconds: int64(term.Seconds()),
+			PaymentOption:         sptypes.SavingsPlanPaymentOptionPartialUpfront,
+			State:                 sptypes.SavingsPlanStateActive,
+			Start:                 aws.String(start.Format(time.RFC3339)),
+			End:                   aws.String(start.Add(term).Format(time.RFC3339)),
+		}},
+	}, nil
+}
+
+// GetSavingsPlansUtilizationDetails returns the utilization of the savings plan
+func (costExplorerAPI) GetSavingsPlansUtilizationDetails(ctx context.Context, params *costexplorer.GetSavingsPlansUtilizationDetailsInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetSavingsPlansUtilizationDetailsOutput, error) {
+	return &costexplorer.GetSavingsPlansUtilizationDetailsOutput{
+		TimePeriod: params.TimePeriod,
+		SavingsPlansUtilizationDetails: []cetypes.SavingsPlansUtilizationDetail{{
+			SavingsPlanArn: aws.String(savingsPlanARN),
+			Utilization:    &cetypes.SavingsPlansUtilization{UtilizationPercentage: aws.String("87.4")},
+		}},
+	}, nil
+}
+
+// GetSavingsPlansCoverage returns the spend covered by the savings plan
+func (costExplorerAPI) GetSavingsPlansCoverage(ctx context.Context, params *costexplorer.GetSavingsPlansCoverageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetSavingsPlansCoverageOutput, error) {
+	return &costexplorer.GetSavingsPlansCoverageOutput{
+		SavingsPlansCoverages: []cetypes.SavingsPlansCoverage{{
+			TimePeriod: params.TimePeriod,
+			Coverage: &cetypes.SavingsPlansCoverageData{
+				SpendCoveredBySavingsPlans: aws.String("912.50"),
+				TotalCost:                  aws.String("1843.27"),
+			},
+		}},
+	}, nil
+}
+
+// supportAPI serves the Trusted Advisor checks of the demo account
+type supportAPI struct {
+	client.SupportAPI
+}
+
+// advisorChecks are the Trusted Advisor checks with their status and flagged resources
+var advisorChecks = []struct {
+	id, name, category, status string
+	flagged                    int64
+	savings                    float64
+}{
+	{"Qch7DwouX1", "Low Utilization Amazon EC2 Instances", "cost_optimizing", "warning", 2, 96.36},
+	{"DAvU99Dc4C", "Underutilized Amazon EBS Volumes", "cost_optimizing", "warning", 1, 8.00},
+	{"HCP4007jGY", "Security Groups - Specific Ports Unrestricted", "security", "error", 1, 0},
+	{"7DAFEmoDos", "MFA on Root Account", "security", "ok", 0, 0},
+	{"iqdCTZKCUp", "Load Balancer Optimization", "fault_tolerance", "ok", 0, 0},
+	{"opQPADkZvH", "Amazon RDS Backups", "fault_tolerance", "ok", 0, 0},
+	{"eW7HH0l7J9", "Service Limits", "performance", "not_available", 0, 0},
+}
+
+// DescribeTrustedAdvisorChecks returns the checks
+func (supportAPI) DescribeTrustedAdvisorChecks(ctx context.Context, params *support.DescribeTrustedAdvisorChecksInput, optFns ...func(*support.Options)) (*support.DescribeTrustedAdvisorChecksOutput, error) {
+	output := &support.DescribeTrustedAdvisorChecksOutput{}
+	for _, check := range advisorChecks {
+		output.Checks = append(output.Checks, supporttypes.TrustedAdvisorCheckDescription{
+			Id:          aws.String(check.id),
+			Name:        aws.String(check.name),
+			Category:    aws.String(check.category),
+			Description: aws.String(check.name),
+		})
+	}
+	return output, nil
+}
+
+// DescribeTrustedAdvisorCheckSummaries returns the summaries of the checks, refreshed this morning
+func (supportAPI) DescribeTrustedAdvisorCheckSummaries(ctx context.Context, params *support.DescribeTrustedAdvisorCheckSummariesInput, optFns ...func(*support.Options)) (*support.DescribeTrustedAdvisorCheckSummariesOutput, error) {
+	refreshed := time.Now().UTC().Truncate(day).Add(6 * time.Hour).Format(time.RFC3339)
+	output := &support.DescribeTrustedAdvisorCheckSummariesOutput{}
+	for _, check := range advisorChecks {
+		summary := supporttypes.TrustedAdvisorCheckSummary{
+			CheckId:             aws.String(check.id),
+			Status:              aws.String(check.status),
+			Timestamp:           aws.String(refreshed),
+			HasFlaggedResources: check.flagged > 0,
+			ResourcesSummary: &supporttypes.TrustedAdvisorResourcesSummary{
+				ResourcesFlagged:   check.flagged,
+				ResourcesProcessed: check.flagged + int64(spread(check.id, 2, 20)),
+			},
+		}
+		if check.savings > 0 {
+			summary.CategorySpecificSummary = &supporttypes.TrustedAdvisorCategorySpecificSummary{
+				CostOptimizing: &supporttypes.TrustedAdvisorCostOptimizingSummary{EstimatedMonthlySavings: check.savings},
+			}
+		}
+		output.Summaries = append(output.Summaries, summary)
+	}
+	return output, nil
+}
+
+// taggingAPI serves the tags of the demo resources
+type taggingAPI struct {
+	client.TaggingAPI
+}
+
+// GetResources returns the tags of the instances and volumes, a few of them without owner
+func (taggingAPI) GetResources(ctx context.Context, params *resourcegroupstaggingapi.GetResourcesInput, optFns ...func(*resourcegroupstaggingapi.Options)) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+	output := &resourcegroupstaggingapi.GetResourcesOutput{}
+	mapping := func(resource string, tags []ec2types.Tag) {
+		converted := make([]taggingtypes.Tag, 0, len(tags))
+		for _, tag := range tags {
+			converted = append(converted, taggingtypes.Tag{Key: tag.Key, Value: tag.Value})
+		}
+		output.ResourceTagMappingList = append(output.ResourceTagMappingList, taggingtypes.ResourceTagMapping{
+			ResourceARN: aws.String(arn("ec2", resource)),
+			Tags:        converted,
+		})
+	}
+	for _, instance := range instances {
+		mapping("instance/"+aws.ToString(instance.InstanceId), instance.Tags)
+	}
+	for _, volume := range volumes {
+		mapping("volume/"+aws.ToString(volume.VolumeId), volume.Tags)
+	}
+	return output, nil
+}
+
+// organizationsAPI serves the accounts of the demo organization
+type organizationsAPI struct {
+	client.OrganizationsAPI
+}
+
+// ListAccounts returns the accounts of the organization, a suspended one included
+func (organizationsAPI) ListAccounts(ctx context.Context, params *organizations.ListAccountsInput, optFns ...func(*organizations.Options)) (*organizations.ListAccountsOutput, error) {
+	account := func(id, name string, status orgtypes.AccountStatus) orgtypes.Account {
+		return orgtypes.Account{
+			Id:     aws.String(id),
+			Arn:    aws.String(fmt.Sprintf("arn:aws:organizations::%s:account/o-a1b2c3d4e5/%s", AccountID, id)),
+			Name:   aws.String(name),
+			Email:  aws.String(fmt.Sprintf("aws+%s@acme.example", strings.ToLower(name))),
+			Status: status,
+		}
+	}
+	return &organizations.ListAccountsOutput{
+		Accounts: []orgtypes.Account{
+			account(AccountID, "Management", orgtypes.AccountStatusActive),
+			account("210987654321", "Production", orgtypes.AccountStatusActive),
+			account("345678901234", "Staging", orgtypes.AccountStatusActive),
+			account("456789012345", "Sandbox", orgtypes.AccountStatusSuspended),
+		},
+	}, nil
+}
@@ -0,0 +1,336 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/apigateway"
+	apigatewaytypes "github.com/aws/aws-sdk-go-v2/service/apigateway/types"
+	"github.com/aws/aws-sdk-go-v2/service/apigatewayv2"
+	apigatewayv2types "github.com/aws/aws-sdk-go-v2/service/apigatewayv2/types"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	cftypes "github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbtypes "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	route53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+)
+
+// vpcs are the VPCs of the demo account, the default one unused
+var vpcs = []ec2types.Vpc{
+	{VpcId: aws.String("vpc-0a1b2c3d4e5f60001"), CidrBlock: aws.String("10.0.0.0/16"), State: ec2types.VpcStateAvailable, IsDefault: aws.Bool(false), Tags: ec2Tags("prod", "prod", "platform-team")},
+	{VpcId: aws.String("vpc-0a1b2c3d4e5f60002"), CidrBlock: aws.String("10.1.0.0/16"), State: ec2types.VpcStateAvailable, IsDefault: aws.Bool(false), Tags: ec2Tags("staging", "staging", "platform-team")},
+	{VpcId: aws.String("vpc-0d1e2f3a"), CidrBlock: aws.String("172.31.0.0/16"), State: ec2types.VpcStateAvailable, IsDefault: aws.Bool(true)},
+}
+
+// DescribeVpcs returns the VPCs, filtered by default
+func (ec2API) DescribeVpcs(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
+	output := &ec2.DescribeVpcsOutput{}
+	for _, vpc := range vpcs {
+		if matchesFilters(params.Filters, map[string]string{"is-default": fmt.Sprint(aws.ToBool(vpc.IsDefault))}) {
+			output.Vpcs = append(output.Vpcs, vpc)
+		}
+	}
+	return output, nil
+}
+
+// DescribeNetworkInterfaces returns the interfaces of the instances, none being in the default VPC
+func (ec2API) DescribeNetworkInterfaces(ctx context.Context, params *ec2.DescribeNetworkInterfacesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNetworkInterfacesOutput, error) {
+	output := &ec2.DescribeNetworkInterfacesOutput{}
+	for i, instance := range instances {
+		if !matchesFilters(params.Filters, map[string]string{"vpc-id": aws.ToString(instance.VpcId)}) {
+			continue
+		}
+		output.NetworkInterfaces = append(output.NetworkInterfaces, ec2types.NetworkInterface{
+			NetworkInterfaceId: aws.String(fmt.Sprintf("eni-0a1b2c3d4e5f6000%d", i+1)),
+			VpcId:              instance.VpcId,
+			PrivateIpAddress:   instance.PrivateIpAddress,
+		})
+	}
+	return output, nil
+}
+
+// DescribeSubnets returns a public and a private subnet per zone of the production VPC
+func (ec2API) DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	output := &ec2.DescribeSubnetsOutput{}
+	for i, zone := range []string{"eu-west-1a", "eu-west-1b", "eu-west-1c"} {
+		for j, tier := range []string{"public", "private"} {
+			output.Subnets = append(output.Subnets, ec2types.Subnet{
+				SubnetId:         aws.String(fmt.Sprintf("subnet-0a1b2c3d4e5f6%d%d", j, i+1)),
+				VpcId:            aws.String("vpc-0a1b2c3d4e5f60001"),
+				CidrBlock:        aws.String(fmt.Sprintf("10.0.%d.0/24", 10*j+i+1)),
+				AvailabilityZone: aws.String(zone),
+				State:            ec2types.SubnetStateAvailable,
+				Tags:             ec2Tags(fmt.Sprintf("prod-%s-%s", tier, zone[len(zone)-1:]), "prod", "platform-team"),
+			})
+		}
+	}
+	return output, nil
+}
+
+// DescribeSecurityGroups returns the security groups
+func (ec2API) DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+	output := &ec2.DescribeSecurityGroupsOutput{}
+	for i, group := range [][2]string{
+		{"default", "default VPC security group"},
+		{"web-alb", "HTTPS from the internet"},
+		{"web", "HTTP from the load balancer"},
+		{"bastion", "SSH from the office"},
+		{"databases", "PostgreSQL and MySQL from the web and workers"},
+	} {
+		output.SecurityGroups = append(output.SecurityGroups, ec2types.SecurityGroup{
+			GroupId:     aws.String(fmt.Sprintf("sg-0a1b2c3d4e5f6000%d", i+1)),
+			GroupName:   aws.String(group[0]),
+			Description: aws.String(group[1]),
+			VpcId:       aws.String("vpc-0a1b2c3d4e5f60001"),
+			OwnerId:     aws.String(AccountID),
+		})
+	}
+	return output, nil
+}
+
+// DescribeAddresses returns the Elastic IPs, one of them not associated
+func (ec2API) DescribeAddresses(ctx context.Context, params *ec2.DescribeAddressesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAddressesOutput, error) {
+	return &ec2.DescribeAddressesOutput{
+		Addresses: []ec2types.Address{
+			{
+				AllocationId:       aws.String("eipalloc-0a1b2c3d4e5f60001"),
+				PublicIp:           aws.String("52.18.77.201"),
+				InstanceId:         aws.String("i-0a1b2c3d4e5f60004"),
+				AssociationId:      aws.String("eipassoc-0a1b2c3d4e5f60001"),
+				NetworkInterfaceId: aws.String("eni-0a1b2c3d4e5f60004"),
+				PrivateIpAddress:   aws.String("10.0.1.5"),
+				Domain:             ec2types.DomainTypeVpc,
+				Tags:               ec2Tags("bastion", "prod", "platform-team"),
+			},
+			{
+				AllocationId: aws.String("eipalloc-0a1b2c3d4e5f60002"),
+				PublicIp:     aws.String("54.76.3.118"),
+				Domain:       ec2types.DomainTypeVpc,
+				Tags:         ec2Tags("old-nat", "prod", ""),
+			},
+		},
+	}, nil
+}
+
+// elbv2API serves the load balancers of the demo account
+type elbv2API struct {
+	client.ELBv2API
+}
+
+// loadBalancers are the load balancers of the demo account, the internal one without target
+var loadBalancers = []elbtypes.LoadBalancer{
+	demoLoadBalancer("web-prod", elbtypes.LoadBalancerTypeEnumApplication, elbtypes.LoadBalancerSchemeEnumInternetFacing, 420),
+	demoLoadBalancer("api-internal", elbtypes.LoadBalancerTypeEnumApplication, elbtypes.LoadBalancerSchemeEnumInternal, 180),
+	demoLoadBalancer("mqtt-ingest", elbtypes.LoadBalancerTypeEnumNetwork, elbtypes.LoadBalancerSchemeEnumInternetFacing, 75),
+}
+
+// demoLoadBalancer returns a load balancer
+func demoLoadBalancer(name string, lbType elbtypes.LoadBalancerTypeEnum, scheme elbtypes.LoadBalancerSchemeEnum, age int) elbtypes.LoadBalancer {
+	kind := "app"
+	if lbType == elbtypes.LoadBalancerTypeEnumNetwork {
+		kind = "net"
+	}
+	return elbtypes.LoadBalancer{
+		LoadBalancerArn:  aws.String(arn("elasticloadbalancing", fmt.Sprintf("loadbalancer/%s/%s/%x", kind, name, spread(name, 1<<28, 1<<31)))),
+		LoadBalancerName: aws.String(name),
+		DNSName:          aws.String(fmt.Sprintf("%s-%d.%s.elb.amazonaws.com", name, spread(name, 100000000, 999999999), Region)),
+		Scheme:           scheme,
+		Type:             lbType,
+		VpcId:            aws.String("vpc-0a1b2c3d4e5f60001"),
+		State:            &elbtypes.LoadBalancerState{Code: elbtypes.LoadBalancerStateEnumActive},
+		AvailabilityZones: []elbtypes.AvailabilityZone{
+			{ZoneName: aws.String("eu-west-1a")},
+			{ZoneName: aws.String("eu-west-1b")},
+		},
+		CreatedTime: ago(time.Duration(age) * day),
+	}
+}
+
+// DescribeLoadBalancers returns the load balancers
+func (elbv2API) DescribeLoadBalancers(ctx context.Context, params *elasticloadbalancingv2.DescribeLoadBalancersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error) {
+	return &elasticloadbalancingv2.DescribeLoadBalancersOutput{LoadBalancers: loadBalancers}, nil
+}
+
+// DescribeTargetGroups returns the target group of a load balancer, named after it
+func (elbv2API) DescribeTargetGroups(ctx context.Context, params *elasticloadbalancingv2.DescribeTargetGroupsInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetGroupsOutput, error) {
+	output := &elasticloadbalancingv2.DescribeTargetGroupsOutput{}
+	for _, lb := range loadBalancers {
+		if params.LoadBalancerArn != nil && aws.ToString(params.LoadBalancerArn) != aws.ToString(lb.LoadBalancerArn) {
+			continue
+		}
+		name := aws.ToString(lb.LoadBalancerName)
+		output.TargetGroups = append(output.TargetGroups, elbtypes.TargetGroup{
+			TargetGroupArn:   aws.String(arn("elasticloadbalancing", fmt.Sprintf("targetgroup/%s/%x", name, spread(name, 1<<28, 1<<31)))),
+			TargetGroupName:  aws.String(name),
+			LoadBalancerArns: []string{aws.ToString(lb.LoadBalancerArn)},
+		})
+	}
+	return output, nil
+}
+
+// DescribeTargetHealth returns the web instances as the targets of the web load balancer
+func (elbv2API) DescribeTargetHealth(ctx context.Context, params *elasticloadbalancingv2.DescribeTargetHealthInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetHealthOutput, error) {
+	output := &elasticloadbalancingv2.DescribeTargetHealthOutput{}
+	if strings.Contains(aws.ToString(params.TargetGroupArn), "/web-prod/") || strings.Contains(aws.ToString(params.TargetGroupArn), "/mqtt-ingest/") {
+		for _, id := range []string{"i-0a1b2c3d4e5f60001", "i-0a1b2c3d4e5f60002"} {
+			output.TargetHealthDescriptions = append(output.TargetHealthDescriptions, elbtypes.TargetHealthDescription{
+				Target:       &elbtypes.TargetDescription{Id: aws.String(id), Port: aws.Int32(80)},
+				TargetHealth: &elbtypes.TargetHealth{State: elbtypes.TargetHealthStateEnumHealthy},
+			})
+		}
+	}
+	return output, nil
+}
+
+// listeners returns the listeners of a load balancer, forwarding to its target group, the web
+// one also redirecting HTTP to HTTPS
+func listeners(lb elbtypes.LoadBalancer) []elbtypes.Listener {
+	name := aws.ToString(lb.LoadBalancerName)
+	lbARN := aws.ToString(lb.LoadBalancerArn)
+	listener := func(port int32, protocol elbtypes.ProtocolEnum, action elbtypes.Action) elbtypes.Listener {
+		return elbtypes.Listener{
+			ListenerArn:     aws.String(fmt.Sprintf("%s/%x", strings.Replace(lbARN, ":loadbalancer/", ":listener/", 1), spread(fmt.Sprint(name, port), 1<<28, 1<<31))),
+			LoadBalancerArn: lb.LoadBalancerArn,
+			Port:            aws.Int32(port),
+			Protocol:        protocol,
+			DefaultActions:  []elbtypes.Action{action},
+		}
+	}
+	forward := elbtypes.Action{
+		Type:           elbtypes.ActionTypeEnumForward,
+		TargetGroupArn: aws.String(arn("elasticloadbalancing", fmt.Sprintf("targetgroup/%s/%x", name, spread(name, 1<<28, 1<<31)))),
+	}
+
+	if lb.Type == elbtypes.LoadBalancerTypeEnumNetwork {
+		return []elbtypes.Listener{listener(8883, elbtypes.ProtocolEnumTls, forward)}
+	}
+	result := []elbtypes.Listener{listener(443, elbtypes.ProtocolEnumHttps, forward)}
+	if name == "web-prod" {
+		result = append(result, listener(80, elbtypes.ProtocolEnumHttp, elbtypes.Action{
+			Type: elbtypes.ActionTypeEnumRedirect,
+			RedirectConfig: &elbtypes.RedirectActionConfig{
+				Protocol:   aws.String("HTTPS"),
+				Port:       aws.String("443"),
+				StatusCode: elbtypes.RedirectActionStatusCodeEnumHttp301,
+			},
+		}))
+	}
+	return result
+}
+
+// DescribeListeners returns the listeners of a load balancer
+func (elbv2API) DescribeListeners(ctx context.Context, params *elasticloadbalancingv2.DescribeListenersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeListenersOutput, error) {
+	output := &elasticloadbalancingv2.DescribeListenersOutput{}
+	for _, lb := range loadBalancers {
+		if aws.ToString(params.LoadBalancerArn) == aws.ToString(lb.LoadBalancerArn) {
+			output.Listeners = listeners(lb)
+		}
+	}
+	return output, nil
+}
+
+// DescribeRules returns the default rule of a listener, running its default actions
+func (elbv2API) DescribeRules(ctx context.Context, params *elasticloadbalancingv2.DescribeRulesInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeRulesOutput, error) {
+	output := &elasticloadbalancingv2.DescribeRulesOutput{}
+	for _, lb := range loadBalancers {
+		for _, listener := range listeners(lb) {
+			if aws.ToString(params.ListenerArn) != aws.ToString(listener.ListenerArn) {
+				continue
+			}
+			output.Rules = append(output.Rules, elbtypes.Rule{
+				RuleArn:   aws.String(strings.Replace(aws.ToString(listener.ListenerArn), ":listener/", ":listener-rule/", 1) + "/default"),
+				Priority:  aws.String("default"),
+				IsDefault: aws.Bool(true),
+				Actions:   listener.DefaultActions,
+			})
+		}
+	}
+	return output, nil
+}
+
+// cloudFrontAPI serves the distributions of the demo account
+type cloudFrontAPI struct {
+	client.CloudFrontAPI
+}
+
+// ListDistributions returns the distributions
+func (cloudFrontAPI) ListDistributions(ctx context.Context, params *cloudfront.ListDistributionsInput, optFns ...func(*cloudfront.Options)) (*cloudfront.ListDistributionsOutput, error) {
+	distribution := func(id, alias string, origins int32, enabled bool, priceClass cftypes.PriceClass) cftypes.DistributionSummary {
+		return cftypes.DistributionSummary{
+			Id:               aws.String(id),
+			DomainName:       aws.String(fmt.Sprintf("d%x.cloudfront.net", spread(id, 1<<28, 1<<31))),
+			Status:           aws.String("Deployed"),
+			Enabled:          aws.Bool(enabled),
+			PriceClass:       priceClass,
+			Origins:          &cftypes.Origins{Quantity: aws.Int32(origins)},
+			Aliases:          &cftypes.Aliases{Quantity: aws.Int32(1), Items: []string{alias}},
+			LastModifiedTime: ago(time.Duration(spread(id, 1, 200)) * day),
+		}
+	}
+	items := []cftypes.DistributionSummary{
+		distribution("E1A2B3C4D5E6F7", "www.acme.example", 2, true, cftypes.PriceClassPriceClassAll),
+		distribution("E2B3C4D5E6F7A8", "static.acme.example", 1, true, cftypes.PriceClassPriceClass100),
+		distribution("E3C4D5E6F7A8B9", "old.acme.example", 1, false, cftypes.PriceClassPriceClass100),
+	}
+	return &cloudfront.ListDistributionsOutput{
+		DistributionList: &cftypes.DistributionList{
+			Items:       items,
+			Quantity:    aws.Int32(int32(len(items))),
+			IsTruncated: aws.Bool(false),
+		},
+	}, nil
+}
+
+// route53API serves the hosted zones of the demo account
+type route53API struct {
+	client.Route53API
+}
+
+// ListHostedZones returns the public zone and the private one
+func (route53API) ListHostedZones(ctx context.Context, params *route53.ListHostedZonesInput, optFns ...func(*route53.Options)) (*route53.ListHostedZonesOutput, error) {
+	return &route53.ListHostedZonesOutput{
+		HostedZones: []route53types.HostedZone{
+			{Id: aws.String("/hostedzone/Z0123456789ABCDEFGHIJ"), Name: aws.String("acme.example."), ResourceRecordSetCount: aws.Int64(37), Config: &route53types.HostedZoneConfig{Comment: aws.String("Public zone")}},
+			{Id: aws.String("/hostedzone/Z9876543210JIHGFEDCBA"), Name: aws.String("internal.acme.example."), ResourceRecordSetCount: aws.Int64(12), Config: &route53types.HostedZoneConfig{PrivateZone: true, Comment: aws.String("Service discovery")}},
+		},
+		IsTruncated: false,
+	}, nil
+}
+
+// apiGatewayAPI serves the REST APIs of the demo account
+type apiGatewayAPI struct {
+	client.APIGatewayAPI
+}
+
+// GetRestApis returns the REST APIs
+func (apiGatewayAPI) GetRestApis(ctx context.Context, params *apigateway.GetRestApisInput, optFns ...func(*apigateway.Options)) (*apigateway.GetRestApisOutput, error) {
+	return &apigateway.GetRestApisOutput{
+		Items: []apigatewaytypes.RestApi{
+			{Id: aws.String("a1b2c3d4e5"), Name: aws.String("orders"), Description: aws.String("Orders public API"), Version: aws.String("2.3.0"), CreatedDate: ago(500 * day)},
+			{Id: aws.String("f6g7h8i9j0"), Name: aws.String("partners"), Description: aws.String("Partner integrations"), Version: aws.String("1.0.0"), CreatedDate: ago(900 * day)},
+		},
+	}, nil
+}
+
+// apiGatewayV2API serves the HTTP and WebSocket APIs of the demo account
+type apiGatewayV2API struct {
+	client.APIGatewayV2API
+}
+
+// GetApis returns the HTTP and WebSocket APIs
+func (apiGatewayV2API) GetApis(ctx context.Context, params *apigatewayv2.GetApisInput, optFns ...func(*apigatewayv2.Options)) (*apigatewayv2.GetApisOutput, error) {
+	return &apigatewayv2.GetApisOutput{
+		Items: []apigatewayv2types.Api{
+			{ApiId: aws.String("k1l2m3n4o5"), Name: aws.String("checkout"), Description: aws.String("Checkout HTTP API"), ProtocolType: apigatewayv2types.ProtocolTypeHttp, CreatedDate: ago(120 * day)},
+			{ApiId: aws.String("p6q7r8s9t0"), Name: aws.String("notifications"), Description: aws.String("Live notifications"), ProtocolType: apigatewayv2types.ProtocolTypeWebsocket, CreatedDate: ago(45 * day)},
+		},
+	}, nil
+}
@@ -0,0 +1,432 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	acmtypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider"
+	cognitotypes "github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	secretstypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// acmAPI serves the certificates of the demo account
+type acmAPI struct {
+	client.ACMAPI
+}
+
+// certificates are the certificates of the demo account, one of them about to expire
+var certificates = []acmtypes.CertificateDetail{
+	demoCertificate("acme.example", acmtypes.CertificateStatusIssued, 300, 2),
+	demoCertificate("api.acme.example", acmtypes.CertificateStatusIssued, 350, 1),
+	demoCertificate("legacy.acme.example", acmtypes.CertificateStatusIssued, 380, 0),
+	demoCertificate("beta.acme.example", acmtypes.CertificateStatusPendingValidation, 0, 0),
+}
+
+// demoCertificate returns an Amazon issued certificate, valid since the given number of days
+func demoCertificate(domain string, status acmtypes.CertificateStatus, age, users int) acmtypes.CertificateDetail {
+	certificate := acmtypes.CertificateDetail{
+		CertificateArn: aws.String(arn("acm", fmt.Sprintf("certificate/%08x-1a2b-3c4d-5e6f-%012x", spread(domain, 1<<28, 1<<31), spread(domain, 1<<30, 1<<31)))),
+		DomainName:     aws.String(domain),
+		Status:         status,
+		Type:           acmtypes.CertificateTypeAmazonIssued,
+	}
+	for i := 0; i < users; i++ {
+		certificate.InUseBy = append(certificate.InUseBy, aws.ToString(loadBalancers[i].LoadBalancerArn))
+	}
+	if status == acmtypes.CertificateStatusIssued {
+		certificate.NotBefore = ago(time.Duration(age) * day)
+		certificate.NotAfter = aws.Time(certificate.NotBefore.Add(395 * day))
+		certificate.RenewalEligibility = acmtypes.RenewalEligibilityEligible
+		if users == 0 {
+			certificate.RenewalEligibility = acmtypes.RenewalEligibilityIneligible
+		}
+	}
+	return certificate
+}
+
+// ListCertificates returns the certificates
+func (acmAPI) ListCertificates(ctx context.Context, params *acm.ListCertificatesInput, optFns ...func(*acm.Options)) (*acm.ListCertificatesOutput, error) {
+	output := &acm.ListCertificatesOutput{}
+	for _, certificate := range certificates {
+		output.CertificateSummaryList = append(output.CertificateSummaryList, acmtypes.CertificateSummary{
+			CertificateArn: certificate.CertificateArn,
+			DomainName:     certificate.DomainName,
+		})
+	}
+	return output, nil
+}
+
+// DescribeCertificate returns a certificate
+func (acmAPI) DescribeCertificate(ctx context.Context, params *acm.DescribeCertificateInput, optFns ...func(*acm.Options)) (*acm.DescribeCertificateOutput, error) {
+	for _, certificate := range certificates {
+		if aws.ToString(certificate.CertificateArn) == aws.ToString(params.CertificateArn) {
+			return &acm.DescribeCertificateOutput{Certificate: &certificate}, nil
+		}
+	}
+	return nil, fmt.Errorf("no certificate %s", aws.ToString(params.CertificateArn))
+}
+
+// secretsManagerAPI serves the secrets of the demo account
+type secretsManagerAPI struct {
+	client.SecretsManagerAPI
+}
+
+// ListSecrets returns the secrets
+func (secretsManagerAPI) ListSecrets(ctx context.Context, params *secretsmanager.ListSecretsInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.ListSecretsOutput, error) {
+	secret := func(name, description string, rotation bool, age, changed, accessed int) secretstypes.SecretListEntry {
+		return secretstypes.SecretListEntry{
+			ARN:              aws.String(arn("secretsmanager", fmt.Sprintf("secret:%s-%06x", name, spread(name, 1<<20, 1<<24)))),
+			Name:             aws.String(name),
+			Description:      aws.String(description),
+			RotationEnabled:  aws.Bool(rotation),
+			CreatedDate:      ago(time.Duration(age) * day),
+			LastChangedDate:  ago(time.Duration(changed) * day),
+			LastAccessedDate: ago(time.Duration(accessed) * day),
+		}
+	}
+	return &secretsmanager.ListSecretsOutput{
+		SecretList: []secretstypes.SecretListEntry{
+			secret("prod/orders/db", "Orders database credentials", true, 400, 12, 0),
+			secret("prod/payments/stripe", "Payment provider API key", false, 250, 250, 1),
+			secret("staging/orders/db", "Orders database credentials", true, 380, 12, 3),
+			secret("legacy/ftp", "Partner FTP password", false, 900, 800, 420),
+		},
+	}, nil
+}
+
+// kmsAPI serves the keys of the demo account
+type kmsAPI struct {
+	client.KMSAPI
+}
+
+// keys are the customer managed keys of the demo account with their alias
+var keys = []struct {
+	metadata kmstypes.KeyMetadata
+	alias    string
+}{
+	{demoKey("1a2b3c4d-0001-4e5f-8a9b-0c1d2e3f4a5b", "Orders database encryption", kmstypes.KeyStateEnabled, 600), "alias/orders"},
+	{demoKey("1a2b3c4d-0002-4e5f-8a9b-0c1d2e3f4a5b", "Secrets encryption", kmstypes.KeyStateEnabled, 400), "alias/secrets"},
+	{demoKey("1a2b3c4d-0003-4e5f-8a9b-0c1d2e3f4a5b", "Former backups", kmstypes.KeyStatePendingDeletion, 1200), ""},
+}
+
+// demoKey returns a symmetric encryption key
+func demoKey(id, description string, state kmstypes.KeyState, age int) kmstypes.KeyMetadata {
+	return kmstypes.KeyMetadata{
+		KeyId:        aws.String(id),
+		Arn:          aws.String(arn("kms", "key/"+id)),
+		Description:  aws.String(description),
+		KeyState:     state,
+		KeyUsage:     kmstypes.KeyUsageTypeEncryptDecrypt,
+		KeySpec:      kmstypes.KeySpecSymmetricDefault,
+		CreationDate: ago(time.Duration(age) * day),
+	}
+}
+
+// ListKeys returns the keys
+func (kmsAPI) ListKeys(ctx context.Context, params *kms.ListKeysInput, optFns ...func(*kms.Options)) (*kms.ListKeysOutput, error) {
+	output := &kms.ListKeysOutput{}
+	for _, key := range keys {
+		output.Keys = append(output.Keys, kmstypes.KeyListEntry{KeyId: key.metadata.KeyId, KeyArn: key.metadata.Arn})
+	}
+	return output, nil
+}
+
+// DescribeKey returns a key
+func (kmsAPI) DescribeKey(ctx context.Context, params *kms.DescribeKeyInput, optFns ...func(*kms.Options)) (*kms.DescribeKeyOutput, error) {
+	for _, key := range keys {
+		if aws.ToString(key.metadata.KeyId) == aws.ToString(params.KeyId) {
+			return &kms.DescribeKeyOutput{KeyMetadata: &key.metadata}, nil
+		}
+	}
+	return nil, fmt.Errorf("no key %s", aws.ToString(params.KeyId))
+}
+
+// ListAliases returns the aliases of the keys and the AWS managed one of Secrets Manager
+func (kmsAPI) ListAliases(ctx context.Context, params *kms.ListAliasesInput, optFns ...func(*kms.Options)) (*kms.ListAliasesOutput, error) {
+	output := &kms.ListAliasesOutput{
+		Aliases: []kmstypes.AliasListEntry{{
+			AliasName:   aws.String("alias/aws/secretsmanager"),
+			AliasArn:    aws.String(arn("kms", "alias/aws/secretsmanager")),
+			TargetKeyId: aws.String("9f8e7d6c-0001-4b5a-9c8d-7e6f5a4b3c2d"),
+		}},
+	}
+	for _, key := range keys {
+		if key.alias == "" {
+			continue
+		}
+		output.Aliases = append(output.Aliases, kmstypes.AliasListEntry{
+			AliasName:   aws.String(key.alias),
+			AliasArn:    aws.String(arn("kms", key.alias)),
+			TargetKeyId: key.metadata.KeyId,
+		})
+	}
+	return output, nil
+}
+
+// ssmAPI serves the parameters of the demo account
+type ssmAPI struct {
+	client.SSMAPI
+}
+
+// DescribeParameters returns the parameters
+func (ssmAPI) DescribeParameters(ctx context.Context, params *ssm.DescribeParametersInput, optFns ...func(*ssm.Options)) (*ssm.DescribeParametersOutput, error) {
+	parameter := func(name, description string, parameterType ssmtypes.ParameterType, version int64, age int) ssmtypes.ParameterMetadata {
+		return ssmtypes.ParameterMetadata{
+			Name:             aws.String(name),
+			Description:      aws.String(description),
+			Type:             parameterType,
+			Tier:             ssmtypes.ParameterTierStandard,
+			Version:          version,
+			LastModifiedDate: ago(time.Duration(age) * day),
+		}
+	}
+	return &ssm.DescribeParametersOutput{
+		Parameters: []ssmtypes.ParameterMetadata{
+			parameter("/prod/orders/feature-flags", "Enabled features", ssmtypes.ParameterTypeStringList, 14, 3),
+			parameter("/prod/orders/api-url", "Public URL of the API", ssmtypes.ParameterTypeString, 2, 200),
+			parameter("/prod/payments/webhook-secret", "Webhook signing secret", ssmtypes.ParameterTypeSecureString, 5, 40),
+			parameter("/staging/orders/api-url", "Public URL of the API", ssmtypes.ParameterTypeString, 3, 90),
+		},
+	}, nil
+}
+
+// ecrAPI serves the container repositories of the demo account
+type ecrAPI struct {
+	client.ECRAPI
+}
+
+// DescribeRepositories returns the repositories
+func (ecrAPI) DescribeRepositories(ctx context.Context, params *ecr.DescribeRepositoriesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeRepositoriesOutput, error) {
+	repository := func(name string, scan bool, age int) ecrtypes.Repository {
+		return ecrtypes.Repository{
+			RepositoryName:             aws.String(name),
+			RepositoryArn:              aws.String(arn("ecr", "repository/"+name)),
+			RepositoryUri:              aws.String(fmt.Sprintf("%s.dkr.ecr.%s.amazonaws.com/%s", AccountID, Region, name)),
+			ImageTagMutability:         ecrtypes.ImageTagMutabilityImmutable,
+			ImageScanningConfiguration: &ecrtypes.ImageScanningConfiguration{ScanOnPush: scan},
+			EncryptionConfiguration:    &ecrtypes.EncryptionConfiguration{EncryptionType: ecrtypes.EncryptionTypeAes256},
+			CreatedAt:                  ago(time.Duration(age) * day),
+		}
+	}
+	return &ecr.DescribeRepositoriesOutput{
+		Repositories: []ecrtypes.Repository{
+			repository("orders-api", true, 500),
+			repository("orders-worker", true, 480),
+			repository("nginx-base", false, 900),
+		},
+	}, nil
+}
+
+// DescribeImages returns a few releases per repository, the oldest one untagged
+func (ecrAPI) DescribeImages(ctx context.Context, params *ecr.DescribeImagesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeImagesOutput, error) {
+	name := aws.ToString(params.RepositoryName)
+	output := &ecr.DescribeImagesOutput{}
+	for i := 0; i < spread(name, 3, 7); i++ {
+		image := ecrtypes.ImageDetail{
+			RepositoryName:   params.RepositoryName,
+			ImageDigest:      aws.String(fmt.Sprintf("sha256:%08x%056d", spread(fmt.Sprint(name, i), 1<<28, 1<<31), i)),
+			ImageSizeInBytes: aws.Int64(int64(spread(name, 40, 400)) << 20),
+			ImagePushedAt:    ago(time.Duration(7*i+1) * day),
+			ImageScanStatus:  &ecrtypes.ImageScanStatus{Status: ecrtypes.ScanStatusComplete},
+			ImageScanFindingsSummary: &ecrtypes.ImageScanFindingsSummary{
+				FindingSeverityCounts: map[string]int32{
+					string(ecrtypes.FindingSeverityCritical): int32(i / 3),
+					string(ecrtypes.FindingSeverityHigh):     int32(i),
+				},
+			},
+		}
+		if i == 0 {
+			image.ImageTags = []string{"latest", "v1.4.2"}
+		} else if i < 3 {
+			image.ImageTags = []string{fmt.Sprintf("v1.4.%d", 2-i)}
+		}
+		output.ImageDetails = append(output.ImageDetails, image)
+	}
+	return output, nil
+}
+
+// cognitoAPI serves the user pools of the demo account
+type cognitoAPI struct {
+	client.CognitoAPI
+}
+
+// userPools are the user pools of the demo account
+var userPools = []cognitotypes.UserPoolType{
+	{Id: aws.String(Region + "_AbCdEf123"), Name: aws.String("customers"), MfaConfiguration: cognitotypes.UserPoolMfaTypeOptional, EstimatedNumberOfUsers: 48213, CreationDate: ago(700 * day), LastModifiedDate: ago(20 * day)},
+	{Id: aws.String(Region + "_GhIjKl456"), Name: aws.String("back-office"), MfaConfiguration: cognitotypes.UserPoolMfaTypeOn, EstimatedNumberOfUsers: 37, CreationDate: ago(500 * day), LastModifiedDate: ago(90 * day)},
+}
+
+// ListUserPools returns the user pools
+func (cognitoAPI) ListUserPools(ctx context.Context, params *cognitoidentityprovider.ListUserPoolsInput, optFns ...func(*cognitoidentityprovider.Options)) (*cognitoidentityprovider.ListUserPoolsOutput, error) {
+	output := &cognitoidentityprovider.ListUserPoolsOutput{}
+	for _, pool := range userPools {
+		output.UserPools = append(output.UserPools, cognitotypes.UserPoolDescriptionType{
+			Id:               pool.Id,
+			Name:             pool.Name,
+			CreationDate:     pool.CreationDate,
+			LastModifiedDate: pool.LastModifiedDate,
+		})
+	}
+	return output, nil
+}
+
+// DescribeUserPool returns a user pool
+func (cognitoAPI) DescribeUserPool(ctx context.Context, params *cognitoidentityprovider.DescribeUserPoolInput, optFns ...func(*cognitoidentityprovider.Options)) (*cognitoidentityprovider.DescribeUserPoolOutput, error) {
+	for _, pool := range userPools {
+		if aws.ToString(pool.Id) == aws.ToString(params.UserPoolId) {
+			return &cognitoidentityprovider.DescribeUserPoolOutput{UserPool: &pool}, nil
+		}
+	}
+	return nil, fmt.Errorf("no user pool %s", aws.ToString(params.UserPoolId))
+}
+
+// iamAPI serves the users, roles and policies of the demo account
+type iamAPI struct {
+	client.IAMAPI
+}
+
+// users are the IAM users of the demo account with the age of their access keys, the CI one
+// has not been rotated for long
+var users = []struct {
+	name string
+	age  int
+	keys []int
+}{
+	{"alice", 900, []int{60}},
+	{"bob", 400, nil},
+	{"ci-deploy", 1100, []int{1050}},
+	{"reporting", 300, []int{200, 10}},
+}
+
+// ListUsers returns the users
+func (iamAPI) ListUsers(ctx context.Context, params *iam.ListUsersInput, optFns ...func(*iam.Options)) (*iam.ListUsersOutput, error) {
+	output := &iam.ListUsersOutput{}
+	for _, user := range users {
+		output.Users = append(output.Users, iamtypes.User{
+			UserName:   aws.String(user.name),
+			UserId:     aws.String(fmt.Sprintf("AIDA%016X", spread(user.name, 1<<30, 1<<31))),
+			Arn:        aws.String(globalARN("iam", "user/"+user.name)),
+			CreateDate: ago(time.Duration(user.age) * day),
+		})
+	}
+	return output, nil
+}
+
+// ListAccessKeys returns the access keys of a user
+func (iamAPI) ListAccessKeys(ctx context.Context, params *iam.ListAccessKeysInput, optFns ...func(*iam.Options)) (*iam.ListAccessKeysOutput, error) {
+	output := &iam.ListAccessKeysOutput{}
+	for _, user := range users {
+		if user.name != aws.ToString(params.UserName) {
+			continue
+		}
+		for i, age := range user.keys {
+			status := iamtypes.StatusTypeActive
+			if i > 0 {
+				status = iamtypes.StatusTypeInactive
+			}
+			output.AccessKeyMetadata = append(output.AccessKeyMetadata, iamtypes.AccessKeyMetadata{
+				UserName:    params.UserName,
+				AccessKeyId: aws.String(accessKeyID(user.name, i)),
+				Status:      status,
+				CreateDate:  ago(time.Duration(age) * day),
+			})
+		}
+	}
+	return output, nil
+}
+
+// accessKeyID returns the ID of the given access key of a user
+func accessKeyID(userName string, index int) string {
+	return fmt.Sprintf("AKIA%015X%d", spread(userName, 1<<30, 1<<31), index)
+}
+
+// GetAccessKeyLastUsed returns a recent use of the active keys, the inactive ones were never used
+func (iamAPI) GetAccessKeyLastUsed(ctx context.Context, params *iam.GetAccessKeyLastUsedInput, optFns ...func(*iam.Options)) (*iam.GetAccessKeyLastUsedOutput, error) {
+	for _, user := range users {
+		if len(user.keys) == 0 || accessKeyID(user.name, 0) != aws.ToString(params.AccessKeyId) {
+			continue
+		}
+		return &iam.GetAccessKeyLastUsedOutput{
+			UserName: aws.String(user.name),
+			AccessKeyLastUsed: &iamtypes.AccessKeyLastUsed{
+				LastUsedDate: ago(time.Duration(spread(user.name, 1, 72)) * time.Hour),
+				ServiceName:  aws.String("s3"),
+				Region:       aws.String(Region),
+			},
+		}, nil
+	}
+	return &iam.GetAccessKeyLastUsedOutput{AccessKeyLastUsed: &iamtypes.AccessKeyLastUsed{}}, nil
+}
+
+// ListGroups returns the groups
+func (iamAPI) ListGroups(ctx context.Context, params *iam.ListGroupsInput, optFns ...func(*iam.Options)) (*iam.ListGroupsOutput, error) {
+	output := &iam.ListGroupsOutput{}
+	for _, group := range []string{"admins", "developers", "billing", "readonly"} {
+		output.Groups = append(output.Groups, iamtypes.Group{
+			GroupName:  aws.String(group),
+			GroupId:    aws.String(fmt.Sprintf("AGPA%016X", spread(group, 1<<30, 1<<31))),
+			Arn:        aws.String(globalARN("iam", "group/"+group)),
+			CreateDate: ago(time.Duration(spread(group, 300, 1200)) * day),
+		})
+	}
+	return output, nil
+}
+
+// ListRoles returns the roles
+func (iamAPI) ListRoles(ctx context.Context, params *iam.ListRolesInput, optFns ...func(*iam.Options)) (*iam.ListRolesOutput, error) {
+	output := &iam.ListRolesOutput{}
+	for _, role := range []string{"OrganizationAccountAccessRole", "orders-api-task", "orders-worker-task", "lambda-thumbnails", "github-actions-deploy", "ReadOnly"} {
+		output.Roles = append(output.Roles, iamtypes.Role{
+			RoleName:   aws.String(role),
+			RoleId:     aws.String(fmt.Sprintf("AROA%016X", spread(role, 1<<30, 1<<31))),
+			Arn:        aws.String(globalARN("iam", "role/"+role)),
+			CreateDate: ago(time.Duration(spread(role, 30, 1200)) * day),
+		})
+	}
+	return output, nil
+}
+
+// ListPolicies returns the customer managed policies
+func (iamAPI) ListPolicies(ctx context.Context, params *iam.ListPoliciesInput, optFns ...func(*iam.Options)) (*iam.ListPoliciesOutput, error) {
+	output := &iam.ListPoliciesOutput{}
+	for i, policy := range []string{"orders-s3-access", "orders-secrets-read", "deploy-ecs", "billing-read", "unused-legacy"} {
+		output.Policies = append(output.Policies, iamtypes.Policy{
+			PolicyName:      aws.String(policy),
+			PolicyId:        aws.String(fmt.Sprintf("ANPA%016X", spread(policy, 1<<30, 1<<31))),
+			Arn:             aws.String(globalARN("iam", "policy/"+policy)),
+			AttachmentCount: aws.Int32(int32(4 - i)),
+			CreateDate:      ago(time.Duration(spread(policy, 30, 1200)) * day),
+		})
+	}
+	return output, nil
+}
+
+// stsAPI serves the identity of the demo session
+type stsAPI struct {
+	client.STSAPI
+}
+
+// GetCallerIdentity returns the first IAM user of the demo account
+func (stsAPI) GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+	return &sts.GetCallerIdentityOutput{
+		Account: aws.String(AccountID),
+		Arn:     aws.String(globalARN("iam", "user/"+users[0].name)),
+		UserId:  aws.String(fmt.Sprintf("AIDA%016X", spread(users[0].name, 1<<30, 1<<31))),
+	}, nil
+}
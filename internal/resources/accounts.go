@@ -0,0 +1,166 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	orgtypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+)
+
+// AccountRole is a role assumed to browse another account, defined in the configuration
+// file, the accounts of the organization can also be discovered with the role to assume in them:
+//
+//	accounts:
+//	  - name: prod
+//	    role: arn:aws:iam::111111111111:role/ReadOnly
+//	organization-role: OrganizationAccountAccessRole
+type AccountRole struct {
+	Name string `mapstructure:"name"` // Shown in the switcher
+	Role string `mapstructure:"role"` // ARN of the role to assume
+}
+
+// Account represents an account the session can switch to
+type Account struct {
+	Name   string
+	ID     string
+	Role   string
+	Source string // "config" or "organization"
+}
+
+// Accounts implements Resource for the accounts reachable by role assumption
+type Accounts struct {
+	roles            []AccountRole
+	organizationRole string
+	current          string
+	accounts         []Account
+}
+
+// NewAccounts creates a new Accounts resource
+func NewAccounts(roles []AccountRole, organizationRole string) *Accounts {
+	return &Accounts{
+		roles:            roles,
+		organizationRole: organizationRole,
+		accounts:         make([]Account, 0),
+	}
+}
+
+// Name returns the display name
+func (a *Accounts) Name() string {
+	return "Accounts"
+}
+
+// Columns returns the column definitions
+func (a *Accounts) Columns() []Column {
+	return []Column{
+		{Name: "Current", Width: 8},
+		{Name: "Name", Width: 30},
+		{Name: "Account ID", Width: 14},
+		{Name: "Role", Width: 70},
+		{Name: "Source", Width: 12},
+	}
+}
+
+// Fetch lists the configured accounts, then the other accounts of the organization when discovery is on
+func (a *Accounts) Fetch(ctx context.Context, c *client.Client) error {
+	a.accounts = make([]Account, 0)
+	a.current, _ = c.AssumedRole()
+
+	known := make(map[string]bool)
+	for _, role := range a.roles {
+		id := roleAccountID(role.Role)
+		known[id] = true
+		a.accounts = append(a.accounts, Account{Name: role.Name, ID: id, Role: role.Role, Source: "config"})
+	}
+	if a.organizationRole == "" {
+		return nil
+	}
+
+	// The organization is listed from the profile, the management account or a delegated administrator
+	discovered := make([]Account, 0)
+	paginator := organizations.NewListAccountsPaginator(c.ProfileOrganizations(), &organizations.ListAccountsInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list the accounts of the organization: %w", err)
+		}
+		for _, account := range output.Accounts {
+			id := stringValue(account.Id)
+			if known[id] || account.Status != orgtypes.AccountStatusActive {
+				continue
+			}
+			partition := "aws"
+			if parts := strings.Split(stringValue(account.Arn), ":"); len(parts) > 1 {
+				partition = parts[1]
+			}
+			discovered = append(discovered, Account{
+				Name:   stringValue(account.Name),
+				ID:     id,
+				Role:   fmt.Sprintf("arn:%s:iam::%s:role/%s", partition, id, a.organizationRole),
+				Source: "organization",
+			})
+		}
+	}
+	sort.Slice(discovered, func(i, j int) bool {
+		return discovered[i].Name < discovered[j].Name
+	})
+	a.accounts = append(a.accounts, discovered...)
+
+	return nil
+}
+
+// roleAccountID returns the account ID of a role ARN
+func roleAccountID(roleARN string) string {
+	if parts := strings.Split(roleARN, ":"); len(parts) > 4 {
+		return parts[4]
+	}
+	return ""
+}
+
+// Rows returns the table data, the account of the assumed role is starred
+func (a *Accounts) Rows() [][]string {
+	rows := make([][]string, len(a.accounts))
+	for i, account := range a.accounts {
+		marker := ""
+		if account.Role == a.current {
+			marker = "*"
+		}
+		rows[i] = []string{
+			marker,
+			account.Name,
+			account.ID,
+			account.Role,
+			account.Source,
+		}
+	}
+	return rows
+}
+
+// GetID returns the role ARN at the given index
+func (a *Accounts) GetID(index int) string {
+	if index >= 0 && index < len(a.accounts) {
+		return a.accounts[index].Role
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for accounts
+func (a *Accounts) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:            'A',
+			Label:          "switch",
+			Description:    "Switch to the account, Ctrl+U goes back to the profile",
+			NeedsSelection: true,
+			ReadOnly:       true,
+			// A role requiring MFA asks for the code in the prompt of the client
+			Handler: func(ctx context.Context, c *client.Client, roleARN string) error {
+				return c.SwitchAccount(ctx, roleARN)
+			},
+		},
+	}
+}
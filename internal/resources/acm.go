@@ -4,12 +4,22 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"a9s/internal/client"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/aws/aws-sdk-go-v2/service/acm"
+	acmtypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
 )
 
+// acmValidationWait is how long a new certificate is polled for its DNS validation records,
+// which ACM generates a few seconds after the request
+const acmValidationWait = 30 * time.Second
+
 // ACMCertificate represents an ACM certificate
 type ACMCertificate struct {
 	DomainName         string
@@ -25,6 +35,7 @@ type ACMCertificate struct {
 // ACMCertificates implements Resource for ACM certificates
 type ACMCertificates struct {
 	certificates []ACMCertificate
+	requested    string // ARN of the certificate last requested, its validations follow the request
 }
 
 // NewACMCertificates creates a new ACMCertificates resource
@@ -128,7 +139,156 @@ func (a *ACMCertificates) GetID(index int) string {
 
 // QuickActions returns the available quick actions for ACM certificates
 func (a *ACMCertificates) QuickActions() []QuickAction {
-	return []QuickAction{}
+	return []QuickAction{
+		{
+			Key:            'u',
+			Label:          "usages",
+			Description:    "Show the resources using the certificate",
+			NeedsSelection: true,
+			View: func(certificateArn string) Resource {
+				return NewACMCertificateUsages(certificateArn)
+			},
+		},
+		{
+			Key:         'c',
+			Label:       "request",
+			Description: "Request a public certificate validated by DNS",
+			InputLabel:  "Domain names (space separated, the first one is the main name): ",
+			InputPlan:   a.planRequest,
+			Follow: func(string) Resource {
+				return NewACMValidations(a.requested)
+			},
+		},
+		{
+			Key:             'D',
+			Label:           "delete",
+			Description:     "Delete the unused certificate",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "[red]delete[-] certificate [white]%s[-]?",
+			ConfirmCheck:    checkCertificateUnused,
+			Handler: func(ctx context.Context, c *client.Client, certificateArn string) error {
+				_, err := c.ACM().DeleteCertificate(ctx, &acm.DeleteCertificateInput{CertificateArn: aws.String(certificateArn)})
+				if err != nil {
+					return fmt.Errorf("failed to delete certificate %s: %w", certificateArn, err)
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// planRequest plans the request of a public certificate validated by DNS, then waits for
+// its validation records to be created in the zones of the domains
+func (a *ACMCertificates) planRequest(ctx context.Context, c *client.Client, _, input string) ([]Step, error) {
+	domains := strings.Fields(input)
+	if len(domains) == 0 {
+		return nil, fmt.Errorf("at least one domain name is required")
+	}
+	for _, domain := range domains {
+		name := strings.TrimPrefix(domain, "*.")
+		if !strings.Contains(name, ".") || strings.ContainsAny(name, "*/:@") || strings.HasSuffix(name, ".") {
+			return nil, fmt.Errorf("%s is not a domain name", domain)
+		}
+	}
+
+	description := fmt.Sprintf("Request a certificate for %s validated by DNS", domains[0])
+	if len(domains) > 1 {
+		description += fmt.Sprintf(", also for %s", strings.Join(domains[1:], ", "))
+	}
+	return []Step{
+		{
+			Description: description,
+			Run: func(ctx context.Context, c *client.Client) error {
+				request := &acm.RequestCertificateInput{
+					DomainName:       aws.String(domains[0]),
+					ValidationMethod: acmtypes.ValidationMethodDns,
+				}
+				if len(domains) > 1 {
+					request.SubjectAlternativeNames = domains[1:]
+				}
+				output, err := c.ACM().RequestCertificate(ctx, request)
+				if err != nil {
+					return fmt.Errorf("failed to request certificate: %w", err)
+				}
+				a.requested = stringValue(output.CertificateArn)
+				return nil
+			},
+		},
+		{
+			Description: "Get the DNS records to create to validate it, Enter on the certificate shows them again",
+			RunText: func(ctx context.Context, c *client.Client) (string, error) {
+				return certificateValidationRecords(ctx, c, a.requested)
+			},
+		},
+	}, nil
+}
+
+// certificateValidationRecords waits for the validation records of a new certificate and
+// returns them to be created in the zones of the domains
+func certificateValidationRecords(ctx context.Context, c *client.Client, certificateArn string) (string, error) {
+	var options []acmtypes.DomainValidation
+	deadline := time.Now().Add(acmValidationWait)
+	for {
+		describe, err := c.ACM().DescribeCertificate(ctx, &acm.DescribeCertificateInput{CertificateArn: aws.String(certificateArn)})
+		if err != nil {
+			return "", fmt.Errorf("failed to describe certificate %s: %w", certificateArn, err)
+		}
+		if describe.Certificate != nil {
+			options = describe.Certificate.DomainValidationOptions
+		}
+		ready := len(options) > 0
+		for _, option := range options {
+			if option.ResourceRecord == nil {
+				ready = false
+			}
+		}
+		if ready || time.Now().After(deadline) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", certificateArn)
+	// The records of a wildcard and of its base domain are the same
+	seen := make(map[string]bool)
+	for _, option := range options {
+		if option.ResourceRecord == nil {
+			fmt.Fprintf(&b, "%s: not generated yet\n", stringValue(option.DomainName))
+			continue
+		}
+		record := fmt.Sprintf("%s %s %s", stringValue(option.ResourceRecord.Name), option.ResourceRecord.Type, stringValue(option.ResourceRecord.Value))
+		if !seen[record] {
+			seen[record] = true
+			b.WriteString(record + "\n")
+		}
+	}
+	return b.String(), nil
+}
+
+// checkCertificateUnused refuses to delete a certificate still used by resources
+func checkCertificateUnused(ctx context.Context, c *client.Client, certificateArn string) (string, error) {
+	output, err := c.ACM().DescribeCertificate(ctx, &acm.DescribeCertificateInput{CertificateArn: aws.String(certificateArn)})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe certificate %s: %w", certificateArn, err)
+	}
+	if output.Certificate == nil {
+		return "", nil
+	}
+	if n := len(output.Certificate.InUseBy); n > 0 {
+		return "", fmt.Errorf("the certificate is used by %d resources, see them with u", n)
+	}
+	return fmt.Sprintf("Domain: %s", stringValue(output.Certificate.DomainName)), nil
+}
+
+// DrillDown opens the domain validations of the certificate
+func (a *ACMCertificates) DrillDown(certificateArn string) Resource {
+	return NewACMValidations(certificateArn)
 }
 
 // formatCertType formats the certificate type for display
@@ -144,3 +304,352 @@ func formatCertType(certType string) string {
 		return strings.ReplaceAll(certType, "_", " ")
 	}
 }
+
+// ACMCertificateUsage represents a resource using an ACM certificate
+type ACMCertificateUsage struct {
+	ARN      string
+	Service  string
+	Resource string
+	Detail   string
+	// Registry key and ID of the using resource in a9s, empty when it has no view
+	ViewKey string
+	ViewID  string
+}
+
+// ACMCertificateUsages implements Resource for the resources using an ACM certificate
+type ACMCertificateUsages struct {
+	certificateArn string
+	usages         []ACMCertificateUsage
+}
+
+// NewACMCertificateUsages creates a new ACMCertificateUsages resource
+func NewACMCertificateUsages(certificateArn string) *ACMCertificateUsages {
+	return &ACMCertificateUsages{
+		certificateArn: certificateArn,
+		usages:         make([]ACMCertificateUsage, 0),
+	}
+}
+
+// Name returns the display name
+func (a *ACMCertificateUsages) Name() string {
+	return fmt.Sprintf("ACM Certificate Usages (%s)", certificateID(a.certificateArn))
+}
+
+// Columns returns the column definitions
+func (a *ACMCertificateUsages) Columns() []Column {
+	return []Column{
+		{Name: "Service", Width: 15},
+		{Name: "Resource", Width: 35},
+		{Name: "Detail", Width: 45},
+		{Name: "ARN", Width: 70},
+	}
+}
+
+// Fetch resolves the InUseBy ARNs of the certificate into the using resources
+func (a *ACMCertificateUsages) Fetch(ctx context.Context, c *client.Client) error {
+	a.usages = make([]ACMCertificateUsage, 0)
+
+	output, err := c.ACM().DescribeCertificate(ctx, &acm.DescribeCertificateInput{
+		CertificateArn: &a.certificateArn,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe certificate %s: %w", a.certificateArn, err)
+	}
+	if output.Certificate == nil {
+		return nil
+	}
+
+	for _, usedBy := range output.Certificate.InUseBy {
+		usage, err := a.resolve(ctx, c, usedBy)
+		if err != nil {
+			return err
+		}
+		a.usages = append(a.usages, usage)
+	}
+
+	return nil
+}
+
+// resolve describes the resource behind an InUseBy ARN, unknown services keep the raw ARN
+func (a *ACMCertificateUsages) resolve(ctx context.Context, c *client.Client, resourceArn string) (ACMCertificateUsage, error) {
+	usage := ACMCertificateUsage{ARN: resourceArn, Resource: resourceArn}
+
+	parsed, err := arn.Parse(resourceArn)
+	if err != nil {
+		return usage, nil
+	}
+	usage.Service = parsed.Service
+
+	switch {
+	case parsed.Service == "elasticloadbalancing" && strings.HasPrefix(parsed.Resource, "loadbalancer/"):
+		return a.resolveLoadBalancer(ctx, c, usage, parsed.Resource)
+	case parsed.Service == "cloudfront" && strings.HasPrefix(parsed.Resource, "distribution/"):
+		return a.resolveDistribution(ctx, c, usage, strings.TrimPrefix(parsed.Resource, "distribution/"))
+	case parsed.Service == "apigateway" && strings.HasPrefix(parsed.Resource, "/domainnames/"):
+		usage.Resource = strings.TrimPrefix(parsed.Resource, "/domainnames/")
+		usage.Detail = "custom domain name"
+	}
+
+	return usage, nil
+}
+
+// resolveLoadBalancer lists the secure listeners of a load balancer serving the certificate
+func (a *ACMCertificateUsages) resolveLoadBalancer(ctx context.Context, c *client.Client, usage ACMCertificateUsage, resource string) (ACMCertificateUsage, error) {
+	// loadbalancer/<type>/<name>/<id>
+	parts := strings.Split(resource, "/")
+	if len(parts) >= 3 {
+		usage.Resource = parts[2]
+	}
+	usage.ViewKey = "alb"
+	usage.ViewID = usage.ARN
+
+	paginator := elasticloadbalancingv2.NewDescribeListenersPaginator(c.ELBv2(), &elasticloadbalancingv2.DescribeListenersInput{
+		LoadBalancerArn: &usage.ARN,
+	})
+
+	listeners := make([]string, 0)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return usage, fmt.Errorf("failed to describe listeners of %s: %w", usage.Resource, err)
+		}
+
+		for _, listener := range output.Listeners {
+			if len(listener.Certificates) == 0 {
+				continue
+			}
+
+			certificates, err := c.ELBv2().DescribeListenerCertificates(ctx, &elasticloadbalancingv2.DescribeListenerCertificatesInput{
+				ListenerArn: listener.ListenerArn,
+			})
+			if err != nil {
+				return usage, fmt.Errorf("failed to describe certificates of listener %s: %w", stringValue(listener.ListenerArn), err)
+			}
+
+			for _, certificate := range certificates.Certificates {
+				if stringValue(certificate.CertificateArn) != a.certificateArn {
+					continue
+				}
+				listenerName := fmt.Sprintf("%s:%d", listener.Protocol, ptrInt32Value(listener.Port))
+				if !ptrBoolValue(certificate.IsDefault) {
+					listenerName += " (SNI)"
+				}
+				listeners = append(listeners, listenerName)
+				break
+			}
+		}
+	}
+
+	usage.Detail = "listeners " + strings.Join(listeners, ", ")
+	if len(listeners) == 0 {
+		usage.Detail = "no listener"
+	}
+	return usage, nil
+}
+
+// resolveDistribution describes the domain and aliases of a CloudFront distribution
+func (a *ACMCertificateUsages) resolveDistribution(ctx context.Context, c *client.Client, usage ACMCertificateUsage, distributionID string) (ACMCertificateUsage, error) {
+	usage.Resource = distributionID
+	usage.ViewKey = "cloudfront"
+	usage.ViewID = distributionID
+
+	output, err := c.CloudFront().GetDistribution(ctx, &cloudfront.GetDistributionInput{
+		Id: &distributionID,
+	})
+	if err != nil {
+		return usage, fmt.Errorf("failed to get distribution %s: %w", distributionID, err)
+	}
+	if output.Distribution == nil {
+		return usage, nil
+	}
+
+	domains := []string{stringValue(output.Distribution.DomainName)}
+	if config := output.Distribution.DistributionConfig; config != nil && config.Aliases != nil {
+		domains = append(domains, config.Aliases.Items...)
+	}
+	usage.Detail = strings.Join(domains, ", ")
+	return usage, nil
+}
+
+// Rows returns the table data
+func (a *ACMCertificateUsages) Rows() [][]string {
+	rows := make([][]string, len(a.usages))
+	for i, usage := range a.usages {
+		rows[i] = []string{
+			usage.Service,
+			usage.Resource,
+			usage.Detail,
+			usage.ARN,
+		}
+	}
+	return rows
+}
+
+// GetID returns the ARN of the using resource at the given index
+func (a *ACMCertificateUsages) GetID(index int) string {
+	if index >= 0 && index < len(a.usages) {
+		return a.usages[index].ARN
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for certificate usages
+func (a *ACMCertificateUsages) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:            'g',
+			Label:          "goto",
+			Description:    "Open the using resource in its view",
+			NeedsSelection: true,
+			Goto: func(resourceArn string) (string, string, error) {
+				for _, usage := range a.usages {
+					if usage.ARN != resourceArn {
+						continue
+					}
+					if usage.ViewKey == "" {
+						return "", "", fmt.Errorf("no view for %s resources", usage.Service)
+					}
+					return usage.ViewKey, usage.ViewID, nil
+				}
+				return "", "", fmt.Errorf("%s is not listed", resourceArn)
+			},
+		},
+	}
+}
+
+// certificateID returns the ID part of a certificate ARN
+func certificateID(certificateArn string) string {
+	if i := strings.LastIndex(certificateArn, "/"); i >= 0 {
+		return certificateArn[i+1:]
+	}
+	return certificateArn
+}
+
+// ACMValidation represents the validation of a domain of an ACM certificate
+type ACMValidation struct {
+	Domain      string
+	Method      string
+	Status      string
+	RecordName  string
+	RecordType  string
+	RecordValue string
+}
+
+// ACMValidations implements Resource for the domain validations of an ACM certificate
+type ACMValidations struct {
+	certificateArn string
+	validations    []ACMValidation
+}
+
+// NewACMValidations creates a new ACMValidations resource
+func NewACMValidations(certificateArn string) *ACMValidations {
+	return &ACMValidations{
+		certificateArn: certificateArn,
+		validations:    make([]ACMValidation, 0),
+	}
+}
+
+// Name returns the display name
+func (a *ACMValidations) Name() string {
+	return fmt.Sprintf("ACM Validations (%s)", certificateID(a.certificateArn))
+}
+
+// Columns returns the column definitions
+func (a *ACMValidations) Columns() []Column {
+	return []Column{
+		{Name: "Domain", Width: 35},
+		{Name: "Method", Width: 8},
+		{Name: "Status", Width: 20},
+		{Name: "Record Name", Width: 55},
+		{Name: "Type", Width: 6},
+		{Name: "Record Value", Width: 60},
+	}
+}
+
+// Fetch retrieves the domain validation options of the certificate
+func (a *ACMValidations) Fetch(ctx context.Context, c *client.Client) error {
+	a.validations = make([]ACMValidation, 0)
+
+	output, err := c.ACM().DescribeCertificate(ctx, &acm.DescribeCertificateInput{
+		CertificateArn: &a.certificateArn,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe certificate %s: %w", a.certificateArn, err)
+	}
+	if output.Certificate == nil {
+		return nil
+	}
+
+	for _, option := range output.Certificate.DomainValidationOptions {
+		validation := ACMValidation{
+			Domain: stringValue(option.DomainName),
+			Method: string(option.ValidationMethod),
+			Status: string(option.ValidationStatus),
+		}
+		if record := option.ResourceRecord; record != nil {
+			validation.RecordName = stringValue(record.Name)
+			validation.RecordType = string(record.Type)
+			validation.RecordValue = stringValue(record.Value)
+		}
+		a.validations = append(a.validations, validation)
+	}
+
+	return nil
+}
+
+// Rows returns the table data
+func (a *ACMValidations) Rows() [][]string {
+	rows := make([][]string, len(a.validations))
+	for i, validation := range a.validations {
+		rows[i] = []string{
+			validation.Domain,
+			validation.Method,
+			validation.Status,
+			validation.RecordName,
+			validation.RecordType,
+			validation.RecordValue,
+		}
+	}
+	return rows
+}
+
+// GetID returns the validated domain at the given index
+func (a *ACMValidations) GetID(index int) string {
+	if index >= 0 && index < len(a.validations) {
+		return a.validations[index].Domain
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for certificate validations
+func (a *ACMValidations) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:            'y',
+			Label:          "copy record",
+			Description:    "Copy the DNS validation record",
+			NeedsSelection: true,
+			Clipboard:      true,
+			TextHandler: func(ctx context.Context, c *client.Client, domain string) (string, error) {
+				for _, validation := range a.validations {
+					if validation.Domain != domain {
+						continue
+					}
+					if validation.RecordName == "" {
+						return "", fmt.Errorf("%s has no DNS validation record", domain)
+					}
+					return fmt.Sprintf("%s %s %s", validation.RecordName, validation.RecordType, validation.RecordValue), nil
+				}
+				return "", fmt.Errorf("%s is not listed", domain)
+			},
+		},
+		{
+			Key:         'u',
+			Label:       "usages",
+			Description: "Show the resources using the certificate",
+			View: func(string) Resource {
+				return NewACMCertificateUsages(a.certificateArn)
+			},
+		},
+	}
+}
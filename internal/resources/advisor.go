@@ -0,0 +1,338 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/support"
+	"github.com/aws/smithy-go"
+)
+
+// supportRegion is the only region serving the Support API
+const supportRegion = "us-east-1"
+
+// advisorStatusOrder sorts the checks needing action first
+var advisorStatusOrder = map[string]int{
+	"error":         0,
+	"warning":       1,
+	"ok":            2,
+	"not_available": 3,
+}
+
+// inSupportRegion sends a Support API call to its region whatever the current one
+func inSupportRegion(o *support.Options) {
+	o.Region = supportRegion
+}
+
+// supportError explains the error returned to accounts without a Business or Enterprise support plan
+func supportError(err error) error {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "SubscriptionRequiredException" {
+		return fmt.Errorf("a Business or Enterprise support plan is needed for Trusted Advisor: %w", err)
+	}
+	return err
+}
+
+// TrustedAdvisorCheck represents a Trusted Advisor check with its latest result
+type TrustedAdvisorCheck struct {
+	ID        string
+	Name      string
+	Category  string
+	Status    string
+	Flagged   int64
+	Processed int64
+	Savings   float64
+	Refreshed string
+}
+
+// TrustedAdvisorChecks implements Resource for the Trusted Advisor checks of the account
+type TrustedAdvisorChecks struct {
+	checks []TrustedAdvisorCheck
+}
+
+// NewTrustedAdvisorChecks creates a new TrustedAdvisorChecks resource
+func NewTrustedAdvisorChecks() *TrustedAdvisorChecks {
+	return &TrustedAdvisorChecks{
+		checks: make([]TrustedAdvisorCheck, 0),
+	}
+}
+
+// Name returns the display name
+func (t *TrustedAdvisorChecks) Name() string {
+	return "Trusted Advisor"
+}
+
+// Columns returns the column definitions
+func (t *TrustedAdvisorChecks) Columns() []Column {
+	return []Column{
+		{Name: "Category", Width: 18},
+		{Name: "Check", Width: 50},
+		{Name: "Status", Width: 14},
+		{Name: "Flagged", Width: 8},
+		{Name: "Processed", Width: 10},
+		{Name: "Savings/Month", Width: 14},
+		{Name: "Refreshed", Width: 20},
+	}
+}
+
+// Fetch retrieves the checks and the summaries of their latest results
+func (t *TrustedAdvisorChecks) Fetch(ctx context.Context, c *client.Client) error {
+	t.checks = make([]TrustedAdvisorCheck, 0)
+
+	output, err := c.Support().DescribeTrustedAdvisorChecks(ctx, &support.DescribeTrustedAdvisorChecksInput{
+		Language: aws.String("en"),
+	}, inSupportRegion)
+	if err != nil {
+		return fmt.Errorf("failed to describe Trusted Advisor checks: %w", supportError(err))
+	}
+
+	checks := make(map[string]*TrustedAdvisorCheck)
+	ids := make([]string, 0, len(output.Checks))
+	for _, description := range output.Checks {
+		id := stringValue(description.Id)
+		checks[id] = &TrustedAdvisorCheck{
+			ID:       id,
+			Name:     stringValue(description.Name),
+			Category: strings.ReplaceAll(stringValue(description.Category), "_", " "),
+			Status:   "not_available",
+		}
+		ids = append(ids, id)
+	}
+
+	summaries, err := c.Support().DescribeTrustedAdvisorCheckSummaries(ctx, &support.DescribeTrustedAdvisorCheckSummariesInput{
+		CheckIds: ids,
+	}, inSupportRegion)
+	if err != nil {
+		return fmt.Errorf("failed to describe Trusted Advisor check summaries: %w", supportError(err))
+	}
+
+	for _, summary := range summaries.Summaries {
+		check, ok := checks[stringValue(summary.CheckId)]
+		if !ok {
+			continue
+		}
+		check.Status = stringValue(summary.Status)
+		check.Refreshed = stringValue(summary.Timestamp)
+		if refreshed, err := time.Parse(time.RFC3339, check.Refreshed); err == nil {
+			check.Refreshed = refreshed.Format("2006-01-02 15:04:05")
+		}
+		if summary.ResourcesSummary != nil {
+			check.Flagged = summary.ResourcesSummary.ResourcesFlagged
+			check.Processed = summary.ResourcesSummary.ResourcesProcessed
+		}
+		if summary.CategorySpecificSummary != nil && summary.CategorySpecificSummary.CostOptimizing != nil {
+			check.Savings = summary.CategorySpecificSummary.CostOptimizing.EstimatedMonthlySavings
+		}
+	}
+
+	for _, id := range ids {
+		t.checks = append(t.checks, *checks[id])
+	}
+	sort.SliceStable(t.checks, func(i, j int) bool {
+		if t.checks[i].Status != t.checks[j].Status {
+			return advisorStatusOrder[t.checks[i].Status] < advisorStatusOrder[t.checks[j].Status]
+		}
+		if t.checks[i].Category != t.checks[j].Category {
+			return t.checks[i].Category < t.checks[j].Category
+		}
+		return t.checks[i].Name < t.checks[j].Name
+	})
+
+	return nil
+}
+
+// Rows returns the table data
+func (t *TrustedAdvisorChecks) Rows() [][]string {
+	rows := make([][]string, len(t.checks))
+	for i, check := range t.checks {
+		savings := ""
+		if check.Savings > 0 {
+			savings = fmt.Sprintf("$%.2f", check.Savings)
+		}
+		rows[i] = []string{
+			check.Category,
+			check.Name,
+			check.Status,
+			fmt.Sprintf("%d", check.Flagged),
+			fmt.Sprintf("%d", check.Processed),
+			savings,
+			check.Refreshed,
+		}
+	}
+	return rows
+}
+
+// GetID returns the check ID at the given index
+func (t *TrustedAdvisorChecks) GetID(index int) string {
+	if index >= 0 && index < len(t.checks) {
+		return t.checks[index].ID
+	}
+	return ""
+}
+
+// Highlight flags the checks in error
+func (t *TrustedAdvisorChecks) Highlight(index int) bool {
+	if index < 0 || index >= len(t.checks) {
+		return false
+	}
+	return t.checks[index].Status == "error"
+}
+
+// DrillDown returns the resources flagged by a check
+func (t *TrustedAdvisorChecks) DrillDown(checkID string) Resource {
+	name := checkID
+	for _, check := range t.checks {
+		if check.ID == checkID {
+			name = check.Name
+			break
+		}
+	}
+	return NewTrustedAdvisorFlaggedResources(checkID, name)
+}
+
+// QuickActions returns the available quick actions for Trusted Advisor checks
+func (t *TrustedAdvisorChecks) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:            'R',
+			Label:          "refresh check",
+			Description:    "Request a refresh of the check",
+			NeedsSelection: true,
+			Handler: func(ctx context.Context, c *client.Client, checkID string) error {
+				_, err := c.Support().RefreshTrustedAdvisorCheck(ctx, &support.RefreshTrustedAdvisorCheckInput{
+					CheckId: &checkID,
+				}, inSupportRegion)
+				if err != nil {
+					return fmt.Errorf("failed to refresh check %s: %w", checkID, supportError(err))
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// TrustedAdvisorFlaggedResource represents a resource flagged by a Trusted Advisor check
+type TrustedAdvisorFlaggedResource struct {
+	ID         string
+	Status     string
+	Region     string
+	Suppressed bool
+	Details    string
+}
+
+// TrustedAdvisorFlaggedResources implements Resource for the resources flagged by a check
+type TrustedAdvisorFlaggedResources struct {
+	checkID   string
+	checkName string
+	resources []TrustedAdvisorFlaggedResource
+}
+
+// NewTrustedAdvisorFlaggedResources creates a new TrustedAdvisorFlaggedResources resource
+func NewTrustedAdvisorFlaggedResources(checkID, checkName string) *TrustedAdvisorFlaggedResources {
+	return &TrustedAdvisorFlaggedResources{
+		checkID:   checkID,
+		checkName: checkName,
+		resources: make([]TrustedAdvisorFlaggedResource, 0),
+	}
+}
+
+// Name returns the display name
+func (t *TrustedAdvisorFlaggedResources) Name() string {
+	return fmt.Sprintf("Trusted Advisor (%s)", t.checkName)
+}
+
+// Columns returns the column definitions
+func (t *TrustedAdvisorFlaggedResources) Columns() []Column {
+	return []Column{
+		{Name: "Status", Width: 10},
+		{Name: "Region", Width: 15},
+		{Name: "Resource", Width: 30},
+		{Name: "Suppressed", Width: 10},
+		{Name: "Details", Width: 80},
+	}
+}
+
+// Fetch retrieves the resources flagged by the latest result of the check
+func (t *TrustedAdvisorFlaggedResources) Fetch(ctx context.Context, c *client.Client) error {
+	t.resources = make([]TrustedAdvisorFlaggedResource, 0)
+
+	output, err := c.Support().DescribeTrustedAdvisorCheckResult(ctx, &support.DescribeTrustedAdvisorCheckResultInput{
+		CheckId:  &t.checkID,
+		Language: aws.String("en"),
+	}, inSupportRegion)
+	if err != nil {
+		return fmt.Errorf("failed to describe result of check %s: %w", t.checkID, supportError(err))
+	}
+	if output.Result == nil {
+		return nil
+	}
+
+	for _, flagged := range output.Result.FlaggedResources {
+		details := make([]string, 0, len(flagged.Metadata))
+		for _, value := range flagged.Metadata {
+			if value != "" {
+				details = append(details, value)
+			}
+		}
+		t.resources = append(t.resources, TrustedAdvisorFlaggedResource{
+			ID:         stringValue(flagged.ResourceId),
+			Status:     stringValue(flagged.Status),
+			Region:     stringValue(flagged.Region),
+			Suppressed: flagged.IsSuppressed,
+			Details:    strings.Join(details, " | "),
+		})
+	}
+
+	sort.SliceStable(t.resources, func(i, j int) bool {
+		return advisorStatusOrder[t.resources[i].Status] < advisorStatusOrder[t.resources[j].Status]
+	})
+
+	return nil
+}
+
+// Rows returns the table data
+func (t *TrustedAdvisorFlaggedResources) Rows() [][]string {
+	rows := make([][]string, len(t.resources))
+	for i, res := range t.resources {
+		suppressed := "no"
+		if res.Suppressed {
+			suppressed = "yes"
+		}
+		rows[i] = []string{
+			res.Status,
+			res.Region,
+			res.ID,
+			suppressed,
+			res.Details,
+		}
+	}
+	return rows
+}
+
+// GetID returns the flagged resource ID at the given index
+func (t *TrustedAdvisorFlaggedResources) GetID(index int) string {
+	if index >= 0 && index < len(t.resources) {
+		return t.resources[index].ID
+	}
+	return ""
+}
+
+// Highlight flags the resources in error
+func (t *TrustedAdvisorFlaggedResources) Highlight(index int) bool {
+	if index < 0 || index >= len(t.resources) {
+		return false
+	}
+	return t.resources[index].Status == "error"
+}
+
+// QuickActions returns the available quick actions for flagged resources
+func (t *TrustedAdvisorFlaggedResources) QuickActions() []QuickAction {
+	return []QuickAction{}
+}
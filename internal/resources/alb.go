@@ -125,7 +125,22 @@ func (a *ALBs) GetID(index int) string {
 	return ""
 }
 
+// DrillDown opens the listeners of the load balancer
+func (a *ALBs) DrillDown(arn string) Resource {
+	return NewALBListeners(arn)
+}
+
 // QuickActions returns the available quick actions for ALBs
 func (a *ALBs) QuickActions() []QuickAction {
-	return []QuickAction{}
+	return []QuickAction{
+		{
+			Key:            't',
+			Label:          "target groups",
+			Description:    "Show the target groups with the health of their targets",
+			NeedsSelection: true,
+			View: func(arn string) Resource {
+				return NewALBTargetGroups(arn, "")
+			},
+		},
+	}
 }
@@ -0,0 +1,266 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbtypes "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+)
+
+// maxForwardWeight is the highest weight of a target group in a forward action
+const maxForwardWeight = 999
+
+// ALBListenerRules implements Resource for the rules of a load balancer listener
+type ALBListenerRules struct {
+	listenerARN string
+	rules       []elbtypes.Rule
+}
+
+// NewALBListenerRules creates a new ALBListenerRules resource
+func NewALBListenerRules(listenerARN string) *ALBListenerRules {
+	return &ALBListenerRules{
+		listenerARN: listenerARN,
+		rules:       make([]elbtypes.Rule, 0),
+	}
+}
+
+// Name returns the display name
+func (a *ALBListenerRules) Name() string {
+	return fmt.Sprintf("Listener Rules (%s)", elbResourceName(a.listenerARN))
+}
+
+// Columns returns the column definitions
+func (a *ALBListenerRules) Columns() []Column {
+	return []Column{
+		{Name: "Priority", Width: 9},
+		{Name: "Conditions", Width: 60},
+		{Name: "Actions", Width: 70},
+	}
+}
+
+// Fetch retrieves the rules of the listener by priority, the default rule last
+func (a *ALBListenerRules) Fetch(ctx context.Context, c *client.Client) error {
+	a.rules = make([]elbtypes.Rule, 0)
+
+	paginator := elasticloadbalancingv2.NewDescribeRulesPaginator(c.ELBv2(), &elasticloadbalancingv2.DescribeRulesInput{
+		ListenerArn: &a.listenerARN,
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe rules of %s: %w", elbResourceName(a.listenerARN), err)
+		}
+		a.rules = append(a.rules, output.Rules...)
+	}
+
+	sort.SliceStable(a.rules, func(i, j int) bool {
+		return rulePriority(a.rules[i]) < rulePriority(a.rules[j])
+	})
+
+	return nil
+}
+
+// rulePriority returns the priority of a rule as a number, the default rule coming last
+func rulePriority(rule elbtypes.Rule) int {
+	priority, err := strconv.Atoi(stringValue(rule.Priority))
+	if err != nil || ptrBoolValue(rule.IsDefault) {
+		return 1 << 30
+	}
+	return priority
+}
+
+// describeConditions summarizes the conditions of a rule on one line
+func describeConditions(conditions []elbtypes.RuleCondition) string {
+	descriptions := make([]string, 0, len(conditions))
+	for _, condition := range conditions {
+		field := stringValue(condition.Field)
+		values := condition.Values
+		switch {
+		case condition.HostHeaderConfig != nil:
+			values = condition.HostHeaderConfig.Values
+		case condition.PathPatternConfig != nil:
+			values = condition.PathPatternConfig.Values
+		case condition.HttpRequestMethodConfig != nil:
+			values = condition.HttpRequestMethodConfig.Values
+		case condition.SourceIpConfig != nil:
+			values = condition.SourceIpConfig.Values
+		case condition.HttpHeaderConfig != nil:
+			field = "header " + stringValue(condition.HttpHeaderConfig.HttpHeaderName)
+			values = condition.HttpHeaderConfig.Values
+		case condition.QueryStringConfig != nil:
+			values = make([]string, 0, len(condition.QueryStringConfig.Values))
+			for _, pair := range condition.QueryStringConfig.Values {
+				values = append(values, stringValue(pair.Key)+"="+stringValue(pair.Value))
+			}
+		}
+		descriptions = append(descriptions, fmt.Sprintf("%s %s", field, strings.Join(values, " | ")))
+	}
+	return strings.Join(descriptions, " and ")
+}
+
+// Rows returns the table data
+func (a *ALBListenerRules) Rows() [][]string {
+	rows := make([][]string, len(a.rules))
+	for i, rule := range a.rules {
+		conditions := describeConditions(rule.Conditions)
+		if ptrBoolValue(rule.IsDefault) {
+			conditions = "(no other rule matches)"
+		}
+		rows[i] = []string{
+			stringValue(rule.Priority),
+			conditions,
+			describeELBActions(rule.Actions),
+		}
+	}
+	return rows
+}
+
+// GetID returns the rule ARN at the given index
+func (a *ALBListenerRules) GetID(index int) string {
+	if index >= 0 && index < len(a.rules) {
+		return stringValue(a.rules[index].RuleArn)
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for listener rules
+func (a *ALBListenerRules) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:            'w',
+			Label:          "weights",
+			Description:    "Change the weights of the forwarded target groups",
+			NeedsSelection: true,
+			InputLabel:     "Weights (name=weight ...): ",
+			InputDefault: func(ruleARN string) string {
+				rule, ok := a.rule(ruleARN)
+				if !ok {
+					return ""
+				}
+				_, forward := weightedForward(rule.Actions)
+				if forward == nil {
+					return ""
+				}
+				weights := make([]string, 0, len(forward.TargetGroups))
+				for _, group := range forward.TargetGroups {
+					weights = append(weights, fmt.Sprintf("%s=%d", elbResourceName(stringValue(group.TargetGroupArn)), ptrInt32Value(group.Weight)))
+				}
+				return strings.Join(weights, " ")
+			},
+			InputPlan: a.planWeights,
+		},
+	}
+}
+
+// rule returns the listed rule with the given ARN
+func (a *ALBListenerRules) rule(ruleARN string) (elbtypes.Rule, bool) {
+	for _, rule := range a.rules {
+		if stringValue(rule.RuleArn) == ruleARN {
+			return rule, true
+		}
+	}
+	return elbtypes.Rule{}, false
+}
+
+// weightedForward returns the index and forward configuration of the forward action
+// splitting the traffic between several target groups, nil when there is none
+func weightedForward(actions []elbtypes.Action) (int, *elbtypes.ForwardActionConfig) {
+	for i, action := range actions {
+		if action.Type == elbtypes.ActionTypeEnumForward && action.ForwardConfig != nil && len(action.ForwardConfig.TargetGroups) > 1 {
+			return i, action.ForwardConfig
+		}
+	}
+	return -1, nil
+}
+
+// planWeights parses the new weights of the target groups of a rule and returns the change
+// as steps, the default rule being changed through its listener
+func (a *ALBListenerRules) planWeights(ctx context.Context, c *client.Client, ruleARN, input string) ([]Step, error) {
+	rule, ok := a.rule(ruleARN)
+	if !ok {
+		return nil, fmt.Errorf("rule %s is not listed", ruleARN)
+	}
+	index, forward := weightedForward(rule.Actions)
+	if forward == nil {
+		return nil, fmt.Errorf("the rule does not split its traffic between several target groups")
+	}
+
+	weights := make(map[string]int32)
+	for _, field := range strings.Fields(input) {
+		name, value, ok := strings.Cut(field, "=")
+		weight, err := strconv.Atoi(value)
+		if !ok || err != nil || weight < 0 || weight > maxForwardWeight {
+			return nil, fmt.Errorf("expected name=weight with a weight from 0 to %d, got %q", maxForwardWeight, field)
+		}
+		weights[name] = int32(weight)
+	}
+
+	groups := make([]elbtypes.TargetGroupTuple, 0, len(forward.TargetGroups))
+	changes := make([]string, 0, len(forward.TargetGroups))
+	var total int32
+	for _, group := range forward.TargetGroups {
+		name := elbResourceName(stringValue(group.TargetGroupArn))
+		weight, ok := weights[name]
+		if !ok {
+			return nil, fmt.Errorf("no weight given for %s", name)
+		}
+		delete(weights, name)
+		total += weight
+		if weight != ptrInt32Value(group.Weight) {
+			changes = append(changes, fmt.Sprintf("%s %d → %d", name, ptrInt32Value(group.Weight), weight))
+		}
+		groups = append(groups, elbtypes.TargetGroupTuple{TargetGroupArn: group.TargetGroupArn, Weight: aws.Int32(weight)})
+	}
+	if len(weights) > 0 {
+		unknown := make([]string, 0, len(weights))
+		for name := range weights {
+			unknown = append(unknown, name)
+		}
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("the rule does not forward to %s", strings.Join(unknown, ", "))
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("at least one target group needs a weight above 0")
+	}
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("the weights are unchanged")
+	}
+
+	// The forward action keeps its stickiness, only the weights change
+	actions := append([]elbtypes.Action(nil), rule.Actions...)
+	config := *forward
+	config.TargetGroups = groups
+	actions[index].ForwardConfig = &config
+	actions[index].TargetGroupArn = nil
+
+	return []Step{
+		{
+			Description: fmt.Sprintf("Shift the traffic of rule %s: %s", stringValue(rule.Priority), strings.Join(changes, ", ")),
+			Run: func(ctx context.Context, c *client.Client) error {
+				var err error
+				if ptrBoolValue(rule.IsDefault) {
+					_, err = c.ELBv2().ModifyListener(ctx, &elasticloadbalancingv2.ModifyListenerInput{
+						ListenerArn:    &a.listenerARN,
+						DefaultActions: actions,
+					})
+				} else {
+					_, err = c.ELBv2().ModifyRule(ctx, &elasticloadbalancingv2.ModifyRuleInput{
+						RuleArn: aws.String(ruleARN),
+						Actions: actions,
+					})
+				}
+				if err != nil {
+					return fmt.Errorf("failed to modify rule %s: %w", stringValue(rule.Priority), err)
+				}
+				return nil
+			},
+		},
+	}, nil
+}
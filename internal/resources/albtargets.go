@@ -0,0 +1,503 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbtypes "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+)
+
+// ALBListener represents a listener of a load balancer
+type ALBListener struct {
+	ARN            string
+	Protocol       string
+	Port           int32
+	DefaultActions string
+	Certificates   int
+	SSLPolicy      string
+}
+
+// ALBListeners implements Resource for the listeners of a load balancer
+type ALBListeners struct {
+	loadBalancerARN string
+	listeners       []ALBListener
+}
+
+// NewALBListeners creates a new ALBListeners resource
+func NewALBListeners(loadBalancerARN string) *ALBListeners {
+	return &ALBListeners{
+		loadBalancerARN: loadBalancerARN,
+		listeners:       make([]ALBListener, 0),
+	}
+}
+
+// Name returns the display name
+func (a *ALBListeners) Name() string {
+	return fmt.Sprintf("Listeners (%s)", elbResourceName(a.loadBalancerARN))
+}
+
+// Columns returns the column definitions
+func (a *ALBListeners) Columns() []Column {
+	return []Column{
+		{Name: "Protocol", Width: 10},
+		{Name: "Port", Width: 6},
+		{Name: "Default Action", Width: 60},
+		{Name: "Certificates", Width: 12},
+		{Name: "SSL Policy", Width: 35},
+	}
+}
+
+// Fetch retrieves the listeners of the load balancer, sorted by port
+func (a *ALBListeners) Fetch(ctx context.Context, c *client.Client) error {
+	a.listeners = make([]ALBListener, 0)
+
+	paginator := elasticloadbalancingv2.NewDescribeListenersPaginator(c.ELBv2(), &elasticloadbalancingv2.DescribeListenersInput{
+		LoadBalancerArn: &a.loadBalancerARN,
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe listeners: %w", err)
+		}
+		for _, listener := range output.Listeners {
+			a.listeners = append(a.listeners, ALBListener{
+				ARN:            stringValue(listener.ListenerArn),
+				Protocol:       string(listener.Protocol),
+				Port:           ptrInt32Value(listener.Port),
+				DefaultActions: describeELBActions(listener.DefaultActions),
+				Certificates:   len(listener.Certificates),
+				SSLPolicy:      stringValue(listener.SslPolicy),
+			})
+		}
+	}
+
+	sort.Slice(a.listeners, func(i, j int) bool {
+		return a.listeners[i].Port < a.listeners[j].Port
+	})
+
+	return nil
+}
+
+// Rows returns the table data
+func (a *ALBListeners) Rows() [][]string {
+	rows := make([][]string, len(a.listeners))
+	for i, listener := range a.listeners {
+		certificates := ""
+		if listener.Certificates > 0 {
+			certificates = fmt.Sprintf("%d", listener.Certificates)
+		}
+		rows[i] = []string{
+			listener.Protocol,
+			fmt.Sprintf("%d", listener.Port),
+			listener.DefaultActions,
+			certificates,
+			listener.SSLPolicy,
+		}
+	}
+	return rows
+}
+
+// GetID returns the listener ARN at the given index
+func (a *ALBListeners) GetID(index int) string {
+	if index >= 0 && index < len(a.listeners) {
+		return a.listeners[index].ARN
+	}
+	return ""
+}
+
+// DrillDown opens the target groups the listener forwards to
+func (a *ALBListeners) DrillDown(listenerARN string) Resource {
+	return NewALBTargetGroups(a.loadBalancerARN, listenerARN)
+}
+
+// QuickActions returns the available quick actions for listeners
+func (a *ALBListeners) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:         't',
+			Label:       "target groups",
+			Description: "Show all the target groups of the load balancer",
+			View: func(string) Resource {
+				return NewALBTargetGroups(a.loadBalancerARN, "")
+			},
+		},
+		{
+			Key:            'R',
+			Label:          "rules",
+			Description:    "Show the rules of the listener",
+			NeedsSelection: true,
+			View: func(listenerARN string) Resource {
+				return NewALBListenerRules(listenerARN)
+			},
+		},
+	}
+}
+
+// elbResourceName returns the name part of a load balancer, listener or target group ARN,
+// e.g. loadbalancer/app/<name>/<id> or targetgroup/<name>/<id>
+func elbResourceName(resourceARN string) string {
+	parts := strings.Split(resourceARN, "/")
+	switch {
+	case strings.Contains(resourceARN, ":targetgroup/") && len(parts) >= 2:
+		return parts[1]
+	case len(parts) >= 3:
+		return parts[2]
+	}
+	return resourceARN
+}
+
+// describeELBActions summarizes listener or rule actions on one line
+func describeELBActions(actions []elbtypes.Action) string {
+	descriptions := make([]string, 0, len(actions))
+	for _, action := range actions {
+		switch action.Type {
+		case elbtypes.ActionTypeEnumForward:
+			descriptions = append(descriptions, "forward "+describeForward(action))
+		case elbtypes.ActionTypeEnumRedirect:
+			if redirect := action.RedirectConfig; redirect != nil {
+				descriptions = append(descriptions, fmt.Sprintf("redirect %s %s://%s:%s%s", redirect.StatusCode,
+					stringValue(redirect.Protocol), stringValue(redirect.Host), stringValue(redirect.Port), stringValue(redirect.Path)))
+			}
+		case elbtypes.ActionTypeEnumFixedResponse:
+			if response := action.FixedResponseConfig; response != nil {
+				descriptions = append(descriptions, "fixed "+stringValue(response.StatusCode))
+			}
+		default:
+			descriptions = append(descriptions, string(action.Type))
+		}
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+// describeForward lists the target groups of a forward action, with their weights when
+// the traffic is split
+func describeForward(action elbtypes.Action) string {
+	if action.ForwardConfig == nil || len(action.ForwardConfig.TargetGroups) == 0 {
+		return elbResourceName(stringValue(action.TargetGroupArn))
+	}
+	groups := action.ForwardConfig.TargetGroups
+	if len(groups) == 1 {
+		return elbResourceName(stringValue(groups[0].TargetGroupArn))
+	}
+	names := make([]string, 0, len(groups))
+	for _, group := range groups {
+		names = append(names, fmt.Sprintf("%s (%d)", elbResourceName(stringValue(group.TargetGroupArn)), ptrInt32Value(group.Weight)))
+	}
+	return strings.Join(names, ", ")
+}
+
+// forwardedTargetGroups returns the ARNs of the target groups actions forward to
+func forwardedTargetGroups(actions []elbtypes.Action) []string {
+	arns := make([]string, 0)
+	for _, action := range actions {
+		if action.TargetGroupArn != nil {
+			arns = append(arns, *action.TargetGroupArn)
+		}
+		if action.ForwardConfig != nil {
+			for _, group := range action.ForwardConfig.TargetGroups {
+				arns = append(arns, stringValue(group.TargetGroupArn))
+			}
+		}
+	}
+	return arns
+}
+
+// ALBTargetGroup represents a target group with the health of its targets
+type ALBTargetGroup struct {
+	ARN         string
+	Name        string
+	Protocol    string
+	Port        int32
+	TargetType  string
+	HealthCheck string
+	Healthy     int
+	Unhealthy   int
+	Other       int
+}
+
+// ALBTargetGroups implements Resource for the target groups of a load balancer, or of
+// one of its listeners
+type ALBTargetGroups struct {
+	loadBalancerARN string
+	listenerARN     string
+	groups          []ALBTargetGroup
+}
+
+// NewALBTargetGroups creates a new ALBTargetGroups resource, listenerARN limits the target
+// groups to the ones its rules forward to when set
+func NewALBTargetGroups(loadBalancerARN, listenerARN string) *ALBTargetGroups {
+	return &ALBTargetGroups{
+		loadBalancerARN: loadBalancerARN,
+		listenerARN:     listenerARN,
+		groups:          make([]ALBTargetGroup, 0),
+	}
+}
+
+// Name returns the display name
+func (a *ALBTargetGroups) Name() string {
+	return fmt.Sprintf("Target Groups (%s)", elbResourceName(a.loadBalancerARN))
+}
+
+// Columns returns the column definitions
+func (a *ALBTargetGroups) Columns() []Column {
+	return []Column{
+		{Name: "Name", Width: 32},
+		{Name: "Protocol", Width: 9},
+		{Name: "Port", Width: 6},
+		{Name: "Target Type", Width: 11},
+		{Name: "Healthy", Width: 8},
+		{Name: "Unhealthy", Width: 10},
+		{Name: "Other", Width: 6},
+		{Name: "Health Check", Width: 30},
+	}
+}
+
+// Fetch retrieves the target groups and counts the health states of their targets
+func (a *ALBTargetGroups) Fetch(ctx context.Context, c *client.Client) error {
+	a.groups = make([]ALBTargetGroup, 0)
+
+	input := &elasticloadbalancingv2.DescribeTargetGroupsInput{LoadBalancerArn: &a.loadBalancerARN}
+	if a.listenerARN != "" {
+		arns, err := listenerTargetGroups(ctx, c, a.listenerARN)
+		if err != nil {
+			return err
+		}
+		if len(arns) == 0 {
+			return nil
+		}
+		input = &elasticloadbalancingv2.DescribeTargetGroupsInput{TargetGroupArns: arns}
+	}
+
+	paginator := elasticloadbalancingv2.NewDescribeTargetGroupsPaginator(c.ELBv2(), input)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe target groups: %w", err)
+		}
+		for _, group := range output.TargetGroups {
+			tg := ALBTargetGroup{
+				ARN:        stringValue(group.TargetGroupArn),
+				Name:       stringValue(group.TargetGroupName),
+				Protocol:   string(group.Protocol),
+				Port:       ptrInt32Value(group.Port),
+				TargetType: string(group.TargetType),
+				HealthCheck: strings.TrimSpace(fmt.Sprintf("%s %s %s", group.HealthCheckProtocol,
+					stringValue(group.HealthCheckPort), stringValue(group.HealthCheckPath))),
+			}
+
+			targets, err := describeTargetHealth(ctx, c, tg.ARN)
+			if err != nil {
+				return err
+			}
+			for _, target := range targets {
+				switch target.State {
+				case string(elbtypes.TargetHealthStateEnumHealthy):
+					tg.Healthy++
+				case string(elbtypes.TargetHealthStateEnumUnhealthy):
+					tg.Unhealthy++
+				default:
+					tg.Other++
+				}
+			}
+
+			a.groups = append(a.groups, tg)
+		}
+	}
+
+	return nil
+}
+
+// listenerTargetGroups returns the target groups the rules of a listener forward to,
+// its default action included
+func listenerTargetGroups(ctx context.Context, c *client.Client, listenerARN string) ([]string, error) {
+	seen := make(map[string]bool)
+	arns := make([]string, 0)
+
+	paginator := elasticloadbalancingv2.NewDescribeRulesPaginator(c.ELBv2(), &elasticloadbalancingv2.DescribeRulesInput{
+		ListenerArn: &listenerARN,
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe rules of %s: %w", elbResourceName(listenerARN), err)
+		}
+		for _, rule := range output.Rules {
+			for _, arn := range forwardedTargetGroups(rule.Actions) {
+				if !seen[arn] {
+					seen[arn] = true
+					arns = append(arns, arn)
+				}
+			}
+		}
+	}
+	return arns, nil
+}
+
+// Rows returns the table data
+func (a *ALBTargetGroups) Rows() [][]string {
+	rows := make([][]string, len(a.groups))
+	for i, tg := range a.groups {
+		port := ""
+		if tg.Port > 0 {
+			port = fmt.Sprintf("%d", tg.Port)
+		}
+		rows[i] = []string{
+			tg.Name,
+			tg.Protocol,
+			port,
+			tg.TargetType,
+			fmt.Sprintf("%d", tg.Healthy),
+			fmt.Sprintf("%d", tg.Unhealthy),
+			fmt.Sprintf("%d", tg.Other),
+			tg.HealthCheck,
+		}
+	}
+	return rows
+}
+
+// GetID returns the target group ARN at the given index
+func (a *ALBTargetGroups) GetID(index int) string {
+	if index >= 0 && index < len(a.groups) {
+		return a.groups[index].ARN
+	}
+	return ""
+}
+
+// Highlight flags the target groups with unhealthy targets
+func (a *ALBTargetGroups) Highlight(index int) bool {
+	return index >= 0 && index < len(a.groups) && a.groups[index].Unhealthy > 0
+}
+
+// DrillDown opens the targets of the target group with their health
+func (a *ALBTargetGroups) DrillDown(targetGroupARN string) Resource {
+	return NewALBTargets(targetGroupARN)
+}
+
+// QuickActions returns the available quick actions for target groups
+func (a *ALBTargetGroups) QuickActions() []QuickAction {
+	return []QuickAction{}
+}
+
+// ALBTarget represents a target of a target group with its health
+type ALBTarget struct {
+	ID          string
+	Port        int32
+	Zone        string
+	State       string
+	Reason      string
+	Description string
+}
+
+// describeTargetHealth returns the targets of a target group with their health
+func describeTargetHealth(ctx context.Context, c *client.Client, targetGroupARN string) ([]ALBTarget, error) {
+	output, err := c.ELBv2().DescribeTargetHealth(ctx, &elasticloadbalancingv2.DescribeTargetHealthInput{
+		TargetGroupArn: &targetGroupARN,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe targets of %s: %w", elbResourceName(targetGroupARN), err)
+	}
+
+	targets := make([]ALBTarget, 0, len(output.TargetHealthDescriptions))
+	for _, description := range output.TargetHealthDescriptions {
+		target := ALBTarget{}
+		if description.Target != nil {
+			target.ID = stringValue(description.Target.Id)
+			target.Port = ptrInt32Value(description.Target.Port)
+			target.Zone = stringValue(description.Target.AvailabilityZone)
+		}
+		if health := description.TargetHealth; health != nil {
+			target.State = string(health.State)
+			target.Reason = string(health.Reason)
+			target.Description = stringValue(health.Description)
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// ALBTargets implements Resource for the targets of a target group
+type ALBTargets struct {
+	targetGroupARN string
+	targets        []ALBTarget
+}
+
+// NewALBTargets creates a new ALBTargets resource
+func NewALBTargets(targetGroupARN string) *ALBTargets {
+	return &ALBTargets{
+		targetGroupARN: targetGroupARN,
+		targets:        make([]ALBTarget, 0),
+	}
+}
+
+// Name returns the display name
+func (a *ALBTargets) Name() string {
+	return fmt.Sprintf("Targets (%s)", elbResourceName(a.targetGroupARN))
+}
+
+// Columns returns the column definitions
+func (a *ALBTargets) Columns() []Column {
+	return []Column{
+		{Name: "Target", Width: 40},
+		{Name: "Port", Width: 6},
+		{Name: "AZ", Width: 15},
+		{Name: "State", Width: 12},
+		{Name: "Reason", Width: 32},
+		{Name: "Description", Width: 60},
+	}
+}
+
+// Fetch retrieves the targets of the target group with their health
+func (a *ALBTargets) Fetch(ctx context.Context, c *client.Client) error {
+	a.targets = make([]ALBTarget, 0)
+
+	targets, err := describeTargetHealth(ctx, c, a.targetGroupARN)
+	if err != nil {
+		return err
+	}
+	a.targets = targets
+
+	return nil
+}
+
+// Rows returns the table data
+func (a *ALBTargets) Rows() [][]string {
+	rows := make([][]string, len(a.targets))
+	for i, target := range a.targets {
+		port := ""
+		if target.Port > 0 {
+			port = fmt.Sprintf("%d", target.Port)
+		}
+		rows[i] = []string{
+			target.ID,
+			port,
+			target.Zone,
+			target.State,
+			target.Reason,
+			target.Description,
+		}
+	}
+	return rows
+}
+
+// GetID returns the target ID at the given index
+func (a *ALBTargets) GetID(index int) string {
+	if index >= 0 && index < len(a.targets) {
+		return a.targets[index].ID
+	}
+	return ""
+}
+
+// Highlight flags the targets which are not healthy
+func (a *ALBTargets) Highlight(index int) bool {
+	return index >= 0 && index < len(a.targets) && a.targets[index].State != string(elbtypes.TargetHealthStateEnumHealthy)
+}
+
+// QuickActions returns the available quick actions for targets
+func (a *ALBTargets) QuickActions() []QuickAction {
+	return []QuickAction{}
+}
@@ -3,6 +3,8 @@ package resources
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	"a9s/internal/client"
 
@@ -103,7 +105,22 @@ func (r *RestAPIs) GetID(index int) string {
 
 // QuickActions returns the available quick actions for REST APIs
 func (r *RestAPIs) QuickActions() []QuickAction {
-	return []QuickAction{}
+	return []QuickAction{
+		{
+			Key:            'o',
+			Label:          "resources",
+			Description:    "Show resources and methods",
+			NeedsSelection: true,
+			View: func(apiID string) Resource {
+				return NewRestAPIResources(apiID)
+			},
+		},
+	}
+}
+
+// DrillDown opens the stages of the REST API
+func (r *RestAPIs) DrillDown(apiID string) Resource {
+	return NewRestAPIStages(apiID)
 }
 
 // HttpAPI represents an HTTP API Gateway (v2)
@@ -208,5 +225,546 @@ func (h *HttpAPIs) GetID(index int) string {
 
 // QuickActions returns the available quick actions for HTTP APIs
 func (h *HttpAPIs) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:            'o',
+			Label:          "routes",
+			Description:    "Show routes",
+			NeedsSelection: true,
+			View: func(apiID string) Resource {
+				return NewHttpAPIRoutes(apiID)
+			},
+		},
+	}
+}
+
+// DrillDown opens the stages of the HTTP or WebSocket API
+func (h *HttpAPIs) DrillDown(apiID string) Resource {
+	for _, api := range h.apis {
+		if api.ID == apiID {
+			return NewHttpAPIStages(apiID, api.ProtocolType)
+		}
+	}
+	return NewHttpAPIStages(apiID, "HTTP")
+}
+
+// APIStage represents a deployment stage of an API Gateway API
+type APIStage struct {
+	Name        string
+	Deployment  string
+	Variables   string
+	Throttling  string
+	Logging     string
+	InvokeURL   string
+	LastUpdated string
+}
+
+// apiStageColumns are the columns shared by REST and HTTP API stages
+var apiStageColumns = []Column{
+	{Name: "Stage", Width: 20},
+	{Name: "Deployment", Width: 12},
+	{Name: "Variables", Width: 30},
+	{Name: "Throttling (rate/burst)", Width: 22},
+	{Name: "Logging", Width: 20},
+	{Name: "Invoke URL", Width: 60},
+	{Name: "Last Updated", Width: 20},
+}
+
+// apiStageRows converts stages to table rows
+func apiStageRows(stages []APIStage) [][]string {
+	rows := make([][]string, len(stages))
+	for i, stage := range stages {
+		rows[i] = []string{
+			stage.Name,
+			stage.Deployment,
+			stage.Variables,
+			stage.Throttling,
+			stage.Logging,
+			stage.InvokeURL,
+			stage.LastUpdated,
+		}
+	}
+	return rows
+}
+
+// apiStageInvokeURL returns the invoke URL of a stage, the $default stage has no path
+func apiStageInvokeURL(scheme, apiID, region, stage string) string {
+	url := fmt.Sprintf("%s://%s.execute-api.%s.amazonaws.com", scheme, apiID, region)
+	if stage != "$default" {
+		url += "/" + stage
+	}
+	return url
+}
+
+// formatStageVariables renders stage variables as sorted key=value pairs
+func formatStageVariables(variables map[string]string) string {
+	parts := make([]string, 0, len(variables))
+	for k, v := range variables {
+		parts = append(parts, k+"="+v)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+// copyStageURLAction returns the quick action copying the invoke URL of a stage
+func copyStageURLAction(stages func() []APIStage) QuickAction {
+	return QuickAction{
+		Key:            'y',
+		Label:          "copy url",
+		Description:    "Copy invoke URL",
+		NeedsSelection: true,
+		Clipboard:      true,
+		TextHandler: func(ctx context.Context, c *client.Client, stageName string) (string, error) {
+			for _, stage := range stages() {
+				if stage.Name == stageName {
+					return stage.InvokeURL, nil
+				}
+			}
+			return "", fmt.Errorf("unknown stage %s", stageName)
+		},
+	}
+}
+
+// RestAPIStages implements Resource for the stages of a REST API
+type RestAPIStages struct {
+	apiID  string
+	stages []APIStage
+}
+
+// NewRestAPIStages creates a new RestAPIStages resource
+func NewRestAPIStages(apiID string) *RestAPIStages {
+	return &RestAPIStages{
+		apiID:  apiID,
+		stages: make([]APIStage, 0),
+	}
+}
+
+// Name returns the display name
+func (r *RestAPIStages) Name() string {
+	return fmt.Sprintf("API Gateway Stages (%s)", r.apiID)
+}
+
+// Columns returns the column definitions
+func (r *RestAPIStages) Columns() []Column {
+	return apiStageColumns
+}
+
+// Fetch retrieves the stages of the REST API from AWS
+func (r *RestAPIStages) Fetch(ctx context.Context, c *client.Client) error {
+	r.stages = make([]APIStage, 0)
+
+	output, err := c.APIGateway().GetStages(ctx, &apigateway.GetStagesInput{
+		RestApiId: &r.apiID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get stages of %s: %w", r.apiID, err)
+	}
+
+	for _, s := range output.Item {
+		stage := APIStage{
+			Name:       stringValue(s.StageName),
+			Deployment: stringValue(s.DeploymentId),
+			Variables:  formatStageVariables(s.Variables),
+			Logging:    "off",
+		}
+		stage.InvokeURL = apiStageInvokeURL("https", r.apiID, c.Region(), stage.Name)
+
+		// Settings applied to all methods are stored under the "*/*" key
+		if settings, ok := s.MethodSettings["*/*"]; ok {
+			stage.Throttling = fmt.Sprintf("%.0f/%d", settings.ThrottlingRateLimit, settings.ThrottlingBurstLimit)
+			if level := stringValue(settings.LoggingLevel); level != "" {
+				stage.Logging = level
+			}
+		}
+		if s.AccessLogSettings != nil && s.AccessLogSettings.DestinationArn != nil {
+			stage.Logging += " +access"
+		}
+
+		if s.LastUpdatedDate != nil {
+			stage.LastUpdated = s.LastUpdatedDate.Format("2006-01-02 15:04:05")
+		}
+
+		r.stages = append(r.stages, stage)
+	}
+
+	return nil
+}
+
+// Rows returns the table data
+func (r *RestAPIStages) Rows() [][]string {
+	return apiStageRows(r.stages)
+}
+
+// GetID returns the stage name at the given index
+func (r *RestAPIStages) GetID(index int) string {
+	if index >= 0 && index < len(r.stages) {
+		return r.stages[index].Name
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for REST API stages
+func (r *RestAPIStages) QuickActions() []QuickAction {
+	return []QuickAction{
+		copyStageURLAction(func() []APIStage { return r.stages }),
+	}
+}
+
+// HttpAPIStages implements Resource for the stages of an HTTP or WebSocket API
+type HttpAPIStages struct {
+	apiID    string
+	protocol string
+	stages   []APIStage
+}
+
+// NewHttpAPIStages creates a new HttpAPIStages resource
+func NewHttpAPIStages(apiID, protocol string) *HttpAPIStages {
+	return &HttpAPIStages{
+		apiID:    apiID,
+		protocol: protocol,
+		stages:   make([]APIStage, 0),
+	}
+}
+
+// Name returns the display name
+func (h *HttpAPIStages) Name() string {
+	return fmt.Sprintf("API Gateway Stages (%s)", h.apiID)
+}
+
+// Columns returns the column definitions
+func (h *HttpAPIStages) Columns() []Column {
+	return apiStageColumns
+}
+
+// Fetch retrieves the stages of the HTTP API from AWS
+func (h *HttpAPIStages) Fetch(ctx context.Context, c *client.Client) error {
+	h.stages = make([]APIStage, 0)
+
+	scheme := "https"
+	if h.protocol == "WEBSOCKET" {
+		scheme = "wss"
+	}
+
+	var nextToken *string
+	for {
+		output, err := c.APIGatewayV2().GetStages(ctx, &apigatewayv2.GetStagesInput{
+			ApiId:     &h.apiID,
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get stages of %s: %w", h.apiID, err)
+		}
+
+		for _, s := range output.Items {
+			stage := APIStage{
+				Name:       stringValue(s.StageName),
+				Deployment: stringValue(s.DeploymentId),
+				Variables:  formatStageVariables(s.StageVariables),
+				Logging:    "off",
+			}
+			stage.InvokeURL = apiStageInvokeURL(scheme, h.apiID, c.Region(), stage.Name)
+
+			if settings := s.DefaultRouteSettings; settings != nil {
+				if settings.ThrottlingRateLimit != nil || settings.ThrottlingBurstLimit != nil {
+					rate := 0.0
+					if settings.ThrottlingRateLimit != nil {
+						rate = *settings.ThrottlingRateLimit
+					}
+					stage.Throttling = fmt.Sprintf("%.0f/%d", rate, ptrInt32Value(settings.ThrottlingBurstLimit))
+				}
+				if settings.LoggingLevel != "" {
+					stage.Logging = string(settings.LoggingLevel)
+				}
+			}
+			if s.AccessLogSettings != nil && s.AccessLogSettings.DestinationArn != nil {
+				stage.Logging += " +access"
+			}
+
+			if s.LastUpdatedDate != nil {
+				stage.LastUpdated = s.LastUpdatedDate.Format("2006-01-02 15:04:05")
+			}
+
+			h.stages = append(h.stages, stage)
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		nextToken = output.NextToken
+	}
+
+	return nil
+}
+
+// Rows returns the table data
+func (h *HttpAPIStages) Rows() [][]string {
+	return apiStageRows(h.stages)
+}
+
+// GetID returns the stage name at the given index
+func (h *HttpAPIStages) GetID(index int) string {
+	if index >= 0 && index < len(h.stages) {
+		return h.stages[index].Name
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for HTTP API stages
+func (h *HttpAPIStages) QuickActions() []QuickAction {
+	return []QuickAction{
+		copyStageURLAction(func() []APIStage { return h.stages }),
+	}
+}
+
+// APIRoute represents a route (v2) or a resource method (v1) of an API Gateway API
+type APIRoute struct {
+	Path          string
+	Method        string
+	Authorization string
+	Integration   string
+	Target        string
+}
+
+// apiRouteColumns are the columns shared by REST API resources and HTTP API routes
+var apiRouteColumns = []Column{
+	{Name: "Path", Width: 40},
+	{Name: "Method", Width: 10},
+	{Name: "Authorization", Width: 15},
+	{Name: "Integration", Width: 12},
+	{Name: "Target", Width: 80},
+}
+
+// apiRouteRows converts routes to table rows
+func apiRouteRows(routes []APIRoute) [][]string {
+	rows := make([][]string, len(routes))
+	for i, route := range routes {
+		rows[i] = []string{
+			route.Path,
+			route.Method,
+			route.Authorization,
+			route.Integration,
+			route.Target,
+		}
+	}
+	return rows
+}
+
+// apiRouteID returns the identifier of a route, e.g. "GET /pets"
+func apiRouteID(route APIRoute) string {
+	if route.Method == "" {
+		return route.Path
+	}
+	return route.Method + " " + route.Path
+}
+
+// integrationTarget extracts the Lambda function ARN from an integration URI,
+// other URIs (HTTP endpoints, AWS services) are returned as is
+func integrationTarget(uri string) string {
+	if i := strings.Index(uri, "functions/arn:"); i >= 0 {
+		return strings.TrimSuffix(uri[i+len("functions/"):], "/invocations")
+	}
+	return uri
+}
+
+// RestAPIResources implements Resource for the resources and methods of a REST API
+type RestAPIResources struct {
+	apiID  string
+	routes []APIRoute
+}
+
+// NewRestAPIResources creates a new RestAPIResources resource
+func NewRestAPIResources(apiID string) *RestAPIResources {
+	return &RestAPIResources{
+		apiID:  apiID,
+		routes: make([]APIRoute, 0),
+	}
+}
+
+// Name returns the display name
+func (r *RestAPIResources) Name() string {
+	return fmt.Sprintf("API Gateway Resources (%s)", r.apiID)
+}
+
+// Columns returns the column definitions
+func (r *RestAPIResources) Columns() []Column {
+	return apiRouteColumns
+}
+
+// Fetch retrieves the resources and methods of the REST API from AWS
+func (r *RestAPIResources) Fetch(ctx context.Context, c *client.Client) error {
+	r.routes = make([]APIRoute, 0)
+
+	paginator := apigateway.NewGetResourcesPaginator(c.APIGateway(), &apigateway.GetResourcesInput{
+		RestApiId: &r.apiID,
+		Embed:     []string{"methods"},
+	})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get resources of %s: %w", r.apiID, err)
+		}
+
+		for _, res := range output.Items {
+			path := stringValue(res.Path)
+			if len(res.ResourceMethods) == 0 {
+				r.routes = append(r.routes, APIRoute{Path: path})
+				continue
+			}
+
+			for httpMethod, method := range res.ResourceMethods {
+				route := APIRoute{
+					Path:          path,
+					Method:        httpMethod,
+					Authorization: stringValue(method.AuthorizationType),
+				}
+				if integration := method.MethodIntegration; integration != nil {
+					route.Integration = string(integration.Type)
+					route.Target = integrationTarget(stringValue(integration.Uri))
+				}
+				r.routes = append(r.routes, route)
+			}
+		}
+	}
+
+	sort.Slice(r.routes, func(i, j int) bool {
+		if r.routes[i].Path != r.routes[j].Path {
+			return r.routes[i].Path < r.routes[j].Path
+		}
+		return r.routes[i].Method < r.routes[j].Method
+	})
+
+	return nil
+}
+
+// Rows returns the table data
+func (r *RestAPIResources) Rows() [][]string {
+	return apiRouteRows(r.routes)
+}
+
+// GetID returns the method and path at the given index
+func (r *RestAPIResources) GetID(index int) string {
+	if index >= 0 && index < len(r.routes) {
+		return apiRouteID(r.routes[index])
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for REST API resources
+func (r *RestAPIResources) QuickActions() []QuickAction {
+	return []QuickAction{}
+}
+
+// HttpAPIRoutes implements Resource for the routes of an HTTP or WebSocket API
+type HttpAPIRoutes struct {
+	apiID  string
+	routes []APIRoute
+}
+
+// NewHttpAPIRoutes creates a new HttpAPIRoutes resource
+func NewHttpAPIRoutes(apiID string) *HttpAPIRoutes {
+	return &HttpAPIRoutes{
+		apiID:  apiID,
+		routes: make([]APIRoute, 0),
+	}
+}
+
+// Name returns the display name
+func (h *HttpAPIRoutes) Name() string {
+	return fmt.Sprintf("API Gateway Routes (%s)", h.apiID)
+}
+
+// Columns returns the column definitions
+func (h *HttpAPIRoutes) Columns() []Column {
+	return apiRouteColumns
+}
+
+// Fetch retrieves the routes of the HTTP API and their integrations from AWS
+func (h *HttpAPIRoutes) Fetch(ctx context.Context, c *client.Client) error {
+	h.routes = make([]APIRoute, 0)
+
+	// Routes reference their integration as "integrations/<id>"
+	integrations := make(map[string]APIRoute)
+	var nextToken *string
+	for {
+		output, err := c.APIGatewayV2().GetIntegrations(ctx, &apigatewayv2.GetIntegrationsInput{
+			ApiId:     &h.apiID,
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get integrations of %s: %w", h.apiID, err)
+		}
+
+		for _, integration := range output.Items {
+			integrations["integrations/"+stringValue(integration.IntegrationId)] = APIRoute{
+				Integration: string(integration.IntegrationType),
+				Target:      integrationTarget(stringValue(integration.IntegrationUri)),
+			}
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		nextToken = output.NextToken
+	}
+
+	nextToken = nil
+	for {
+		output, err := c.APIGatewayV2().GetRoutes(ctx, &apigatewayv2.GetRoutesInput{
+			ApiId:     &h.apiID,
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get routes of %s: %w", h.apiID, err)
+		}
+
+		for _, r := range output.Items {
+			// Route keys are "METHOD /path" for HTTP APIs, "$connect" and the like for WebSocket APIs
+			route := APIRoute{
+				Path:          stringValue(r.RouteKey),
+				Authorization: string(r.AuthorizationType),
+			}
+			if method, path, ok := strings.Cut(route.Path, " "); ok {
+				route.Method = method
+				route.Path = path
+			}
+			if integration, ok := integrations[stringValue(r.Target)]; ok {
+				route.Integration = integration.Integration
+				route.Target = integration.Target
+			}
+			h.routes = append(h.routes, route)
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		nextToken = output.NextToken
+	}
+
+	sort.Slice(h.routes, func(i, j int) bool {
+		if h.routes[i].Path != h.routes[j].Path {
+			return h.routes[i].Path < h.routes[j].Path
+		}
+		return h.routes[i].Method < h.routes[j].Method
+	})
+
+	return nil
+}
+
+// Rows returns the table data
+func (h *HttpAPIRoutes) Rows() [][]string {
+	return apiRouteRows(h.routes)
+}
+
+// GetID returns the route key at the given index
+func (h *HttpAPIRoutes) GetID(index int) string {
+	if index >= 0 && index < len(h.routes) {
+		return apiRouteID(h.routes[index])
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for HTTP API routes
+func (h *HttpAPIRoutes) QuickActions() []QuickAction {
 	return []QuickAction{}
 }
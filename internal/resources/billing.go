@@ -192,3 +192,175 @@ func (b *Billing) GetID(index int) string {
 func (b *Billing) QuickActions() []QuickAction {
 	return []QuickAction{}
 }
+
+// anomalyLookback is the period of the anomalies listed
+const anomalyLookback = 30 * 24 * time.Hour
+
+// CostAnomaly represents an anomaly found by Cost Anomaly Detection
+type CostAnomaly struct {
+	ID              string
+	StartDate       string
+	EndDate         string
+	Dimension       string
+	Impact          float64
+	ImpactPercent   float64
+	RootCause       string
+	Feedback        string
+	TotalRootCauses int
+}
+
+// ListCostAnomalies retrieves the anomalies detected since the given date, the most recent first
+func ListCostAnomalies(ctx context.Context, c *client.Client, since time.Time) ([]CostAnomaly, error) {
+	anomalies := make([]CostAnomaly, 0)
+
+	input := &costexplorer.GetAnomaliesInput{
+		DateInterval: &types.AnomalyDateInterval{
+			StartDate: aws.String(since.Format("2006-01-02")),
+		},
+	}
+	for {
+		output, err := c.CostExplorer().GetAnomalies(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cost anomalies: %w", err)
+		}
+
+		for _, anomaly := range output.Anomalies {
+			a := CostAnomaly{
+				ID:              aws.ToString(anomaly.AnomalyId),
+				StartDate:       aws.ToString(anomaly.AnomalyStartDate),
+				EndDate:         aws.ToString(anomaly.AnomalyEndDate),
+				Dimension:       aws.ToString(anomaly.DimensionValue),
+				Feedback:        string(anomaly.Feedback),
+				TotalRootCauses: len(anomaly.RootCauses),
+			}
+			if anomaly.Impact != nil {
+				a.Impact = anomaly.Impact.TotalImpact
+				if anomaly.Impact.TotalImpactPercentage != nil {
+					a.ImpactPercent = *anomaly.Impact.TotalImpactPercentage
+				}
+			}
+			if len(anomaly.RootCauses) > 0 {
+				a.RootCause = formatRootCause(anomaly.RootCauses[0])
+			}
+			anomalies = append(anomalies, a)
+		}
+
+		if output.NextPageToken == nil {
+			break
+		}
+		input.NextPageToken = output.NextPageToken
+	}
+
+	sort.Slice(anomalies, func(i, j int) bool {
+		return anomalies[i].StartDate > anomalies[j].StartDate
+	})
+	return anomalies, nil
+}
+
+// formatRootCause formats the dimensions of the main root cause of an anomaly
+func formatRootCause(cause types.RootCause) string {
+	parts := make([]string, 0, 4)
+	for _, value := range []*string{cause.Service, cause.Region, cause.UsageType, cause.LinkedAccountName} {
+		if v := aws.ToString(value); v != "" {
+			parts = append(parts, v)
+		}
+	}
+	if len(parts) == 0 {
+		return aws.ToString(cause.LinkedAccount)
+	}
+	return strings.Join(parts, " / ")
+}
+
+// BillingAnomalies implements Resource for the recent Cost Anomaly Detection anomalies
+type BillingAnomalies struct {
+	anomalies []CostAnomaly
+}
+
+// NewBillingAnomalies creates a new BillingAnomalies resource
+func NewBillingAnomalies() *BillingAnomalies {
+	return &BillingAnomalies{
+		anomalies: make([]CostAnomaly, 0),
+	}
+}
+
+// Name returns the display name
+func (b *BillingAnomalies) Name() string {
+	return "Cost Anomalies (Last 30 Days)"
+}
+
+// Columns returns the column definitions
+func (b *BillingAnomalies) Columns() []Column {
+	return []Column{
+		{Name: "Start", Width: 12},
+		{Name: "End", Width: 12},
+		{Name: "Service", Width: 30},
+		{Name: "Impact", Width: 12},
+		{Name: "Impact %", Width: 10},
+		{Name: "Root Cause", Width: 60},
+		{Name: "Feedback", Width: 12},
+	}
+}
+
+// Fetch retrieves the anomalies of the last 30 days
+func (b *BillingAnomalies) Fetch(ctx context.Context, c *client.Client) error {
+	anomalies, err := ListCostAnomalies(ctx, c, time.Now().Add(-anomalyLookback))
+	if err != nil {
+		return err
+	}
+	b.anomalies = anomalies
+	return nil
+}
+
+// Rows returns the table data
+func (b *BillingAnomalies) Rows() [][]string {
+	rows := make([][]string, len(b.anomalies))
+	for i, anomaly := range b.anomalies {
+		end := anomaly.EndDate
+		if end == "" {
+			end = "ongoing"
+		}
+		rootCause := anomaly.RootCause
+		if anomaly.TotalRootCauses > 1 {
+			rootCause += fmt.Sprintf(" (+%d)", anomaly.TotalRootCauses-1)
+		}
+		rows[i] = []string{
+			shortDate(anomaly.StartDate),
+			shortDate(end),
+			anomaly.Dimension,
+			fmt.Sprintf("%.2f", anomaly.Impact),
+			fmt.Sprintf("%.1f%%", anomaly.ImpactPercent),
+			rootCause,
+			anomaly.Feedback,
+		}
+	}
+	return rows
+}
+
+// shortDate keeps the day of a Cost Explorer date, which may carry a time
+func shortDate(date string) string {
+	if len(date) > 10 && date[4] == '-' {
+		return date[:10]
+	}
+	return date
+}
+
+// GetID returns the anomaly ID at the given index
+func (b *BillingAnomalies) GetID(index int) string {
+	if index >= 0 && index < len(b.anomalies) {
+		return b.anomalies[index].ID
+	}
+	return ""
+}
+
+// Highlight reports whether the anomaly is still ongoing
+func (b *BillingAnomalies) Highlight(index int) bool {
+	if index < 0 || index >= len(b.anomalies) {
+		return false
+	}
+	return b.anomalies[index].EndDate == ""
+}
+
+// QuickActions returns the available quick actions for cost anomalies
+func (b *BillingAnomalies) QuickActions() []QuickAction {
+	return []QuickAction{}
+}
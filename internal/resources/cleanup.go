@@ -0,0 +1,541 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbtypes "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+)
+
+// Kinds of orphaned resources found by the cleanup, in display order
+const (
+	cleanupVolume       = "volume"
+	cleanupAddress      = "elastic ip"
+	cleanupLoadBalancer = "load balancer"
+	cleanupDefaultVPC   = "default vpc"
+	cleanupAccessKey    = "access key"
+)
+
+// cleanupKinds orders the kinds of orphaned resources
+var cleanupKinds = map[string]int{
+	cleanupVolume:       0,
+	cleanupAddress:      1,
+	cleanupLoadBalancer: 2,
+	cleanupDefaultVPC:   3,
+	cleanupAccessKey:    4,
+}
+
+// CleanupItem represents an orphaned resource candidate for deletion
+type CleanupItem struct {
+	Kind   string
+	ID     string
+	Name   string
+	Reason string
+	Owner  string // User of an access key
+}
+
+// Cleanup implements Resource for the orphaned resources of the current region,
+// which the user ticks before deleting them as one reviewed plan
+type Cleanup struct {
+	maxAge time.Duration
+	region string
+	items  []CleanupItem
+	ticked map[string]bool
+}
+
+// NewCleanup creates a new Cleanup resource, access keys older than maxAge are candidates
+func NewCleanup(maxAge time.Duration) *Cleanup {
+	if maxAge <= 0 {
+		maxAge = DefaultKeyMaxAge
+	}
+	return &Cleanup{
+		maxAge: maxAge,
+		items:  make([]CleanupItem, 0),
+		ticked: make(map[string]bool),
+	}
+}
+
+// Name returns the display name
+func (r *Cleanup) Name() string {
+	return fmt.Sprintf("Cleanup (%s, %d ticked)", r.region, len(r.ticked))
+}
+
+// Columns returns the column definitions
+func (r *Cleanup) Columns() []Column {
+	return []Column{
+		{Name: "✓", Width: 3},
+		{Name: "Kind", Width: 15},
+		{Name: "ID", Width: 40},
+		{Name: "Name", Width: 30},
+		{Name: "Reason", Width: 45},
+	}
+}
+
+// Fetch looks for orphaned resources, the ticks of the ones still found are kept
+func (r *Cleanup) Fetch(ctx context.Context, c *client.Client) error {
+	r.items = make([]CleanupItem, 0)
+	r.region = c.Region()
+
+	finders := []func(context.Context, *client.Client) ([]CleanupItem, error){
+		unattachedVolumes,
+		unassociatedAddresses,
+		emptyLoadBalancers,
+		unusedDefaultVPCs,
+		r.oldAccessKeys,
+	}
+	for _, find := range finders {
+		items, err := find(ctx, c)
+		if err != nil {
+			return err
+		}
+		r.items = append(r.items, items...)
+	}
+
+	sort.SliceStable(r.items, func(i, j int) bool {
+		return cleanupKinds[r.items[i].Kind] < cleanupKinds[r.items[j].Kind]
+	})
+
+	found := make(map[string]bool)
+	for _, item := range r.items {
+		found[item.ID] = true
+	}
+	for id := range r.ticked {
+		if !found[id] {
+			delete(r.ticked, id)
+		}
+	}
+
+	return nil
+}
+
+// unattachedVolumes finds the EBS volumes attached to no instance
+func unattachedVolumes(ctx context.Context, c *client.Client) ([]CleanupItem, error) {
+	items := make([]CleanupItem, 0)
+	paginator := ec2.NewDescribeVolumesPaginator(c.EC2(), &ec2.DescribeVolumesInput{
+		Filters: []types.Filter{{Name: aws.String("status"), Values: []string{"available"}}},
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe volumes: %w", err)
+		}
+		for _, volume := range output.Volumes {
+			reason := fmt.Sprintf("unattached %d GiB %s", ptrInt32Value(volume.Size), volume.VolumeType)
+			if volume.CreateTime != nil {
+				reason += ", created " + volume.CreateTime.Format("2006-01-02")
+			}
+			name := ""
+			for _, tag := range volume.Tags {
+				if stringValue(tag.Key) == "Name" {
+					name = stringValue(tag.Value)
+					break
+				}
+			}
+			items = append(items, CleanupItem{
+				Kind:   cleanupVolume,
+				ID:     stringValue(volume.VolumeId),
+				Name:   name,
+				Reason: reason,
+			})
+		}
+	}
+	return items, nil
+}
+
+// unassociatedAddresses finds the Elastic IPs associated with nothing, which are billed
+func unassociatedAddresses(ctx context.Context, c *client.Client) ([]CleanupItem, error) {
+	output, err := c.EC2().DescribeAddresses(ctx, &ec2.DescribeAddressesInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe addresses: %w", err)
+	}
+
+	items := make([]CleanupItem, 0)
+	for _, address := range output.Addresses {
+		if address.AssociationId != nil {
+			continue
+		}
+		items = append(items, CleanupItem{
+			Kind:   cleanupAddress,
+			ID:     stringValue(address.AllocationId),
+			Name:   stringValue(address.PublicIp),
+			Reason: "not associated",
+		})
+	}
+	return items, nil
+}
+
+// emptyLoadBalancers finds the load balancers without any registered target
+func emptyLoadBalancers(ctx context.Context, c *client.Client) ([]CleanupItem, error) {
+	items := make([]CleanupItem, 0)
+	paginator := elasticloadbalancingv2.NewDescribeLoadBalancersPaginator(c.ELBv2(), &elasticloadbalancingv2.DescribeLoadBalancersInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe load balancers: %w", err)
+		}
+
+		for _, lb := range output.LoadBalancers {
+			groups, err := c.ELBv2().DescribeTargetGroups(ctx, &elasticloadbalancingv2.DescribeTargetGroupsInput{
+				LoadBalancerArn: lb.LoadBalancerArn,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to describe target groups of %s: %w", stringValue(lb.LoadBalancerName), err)
+			}
+
+			targets := 0
+			for _, group := range groups.TargetGroups {
+				health, err := c.ELBv2().DescribeTargetHealth(ctx, &elasticloadbalancingv2.DescribeTargetHealthInput{
+					TargetGroupArn: group.TargetGroupArn,
+				})
+				if err != nil {
+					return nil, fmt.Errorf("failed to describe targets of %s: %w", stringValue(group.TargetGroupName), err)
+				}
+				targets += len(health.TargetHealthDescriptions)
+			}
+			if targets > 0 {
+				continue
+			}
+			if lb.Type == elbtypes.LoadBalancerTypeEnumApplication {
+				redirecting, err := redirects(ctx, c, lb)
+				if err != nil {
+					return nil, err
+				}
+				if redirecting {
+					continue
+				}
+			}
+
+			reason := "no registered target"
+			if len(groups.TargetGroups) == 0 {
+				reason = "no target group"
+			}
+			items = append(items, CleanupItem{
+				Kind:   cleanupLoadBalancer,
+				ID:     stringValue(lb.LoadBalancerArn),
+				Name:   stringValue(lb.LoadBalancerName),
+				Reason: fmt.Sprintf("%s %s", lb.Type, reason),
+			})
+		}
+	}
+	return items, nil
+}
+
+// redirects reports whether a listener of an application load balancer, or one of its
+// rules, redirects, such a load balancer serves requests without any target
+func redirects(ctx context.Context, c *client.Client, lb elbtypes.LoadBalancer) (bool, error) {
+	listeners, err := c.ELBv2().DescribeListeners(ctx, &elasticloadbalancingv2.DescribeListenersInput{
+		LoadBalancerArn: lb.LoadBalancerArn,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to describe listeners of %s: %w", stringValue(lb.LoadBalancerName), err)
+	}
+
+	redirect := func(actions []elbtypes.Action) bool {
+		for _, action := range actions {
+			if action.Type == elbtypes.ActionTypeEnumRedirect {
+				return true
+			}
+		}
+		return false
+	}
+	for _, listener := range listeners.Listeners {
+		if redirect(listener.DefaultActions) {
+			return true, nil
+		}
+		rules, err := c.ELBv2().DescribeRules(ctx, &elasticloadbalancingv2.DescribeRulesInput{
+			ListenerArn: listener.ListenerArn,
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to describe rules of %s: %w", stringValue(listener.ListenerArn), err)
+		}
+		for _, rule := range rules.Rules {
+			if redirect(rule.Actions) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// unusedDefaultVPCs finds the default VPCs without any network interface
+func unusedDefaultVPCs(ctx context.Context, c *client.Client) ([]CleanupItem, error) {
+	output, err := c.EC2().DescribeVpcs(ctx, &ec2.DescribeVpcsInput{
+		Filters: []types.Filter{{Name: aws.String("is-default"), Values: []string{"true"}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe default VPCs: %w", err)
+	}
+
+	items := make([]CleanupItem, 0)
+	for _, vpc := range output.Vpcs {
+		interfaces, err := c.EC2().DescribeNetworkInterfaces(ctx, &ec2.DescribeNetworkInterfacesInput{
+			Filters: []types.Filter{{Name: aws.String("vpc-id"), Values: []string{stringValue(vpc.VpcId)}}},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe network interfaces of %s: %w", stringValue(vpc.VpcId), err)
+		}
+		if len(interfaces.NetworkInterfaces) > 0 {
+			continue
+		}
+		items = append(items, CleanupItem{
+			Kind:   cleanupDefaultVPC,
+			ID:     stringValue(vpc.VpcId),
+			Name:   stringValue(vpc.CidrBlock),
+			Reason: "default VPC without network interface",
+		})
+	}
+	return items, nil
+}
+
+// oldAccessKeys finds the access keys not used for longer than the maximum age, or never used
+// and created before, IAM being global they are listed whatever the region
+func (r *Cleanup) oldAccessKeys(ctx context.Context, c *client.Client) ([]CleanupItem, error) {
+	items := make([]CleanupItem, 0)
+	paginator := iam.NewListUsersPaginator(c.IAM(), &iam.ListUsersInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list IAM users: %w", err)
+		}
+
+		for _, user := range output.Users {
+			userName := stringValue(user.UserName)
+			keys, err := userAccessKeys(ctx, c, userName)
+			if err != nil {
+				return nil, err
+			}
+			for _, key := range keys {
+				if key.CreateDate == nil || time.Since(*key.CreateDate) <= r.maxAge {
+					continue
+				}
+				lastUsed, err := c.IAM().GetAccessKeyLastUsed(ctx, &iam.GetAccessKeyLastUsedInput{
+					AccessKeyId: key.AccessKeyId,
+				})
+				if err != nil {
+					return nil, fmt.Errorf("failed to get last use of access key %s: %w", stringValue(key.AccessKeyId), err)
+				}
+
+				reason := fmt.Sprintf("%s key never used, created %dd ago", key.Status, int(time.Since(*key.CreateDate).Hours()/24))
+				if used := lastUsed.AccessKeyLastUsed; used != nil && used.LastUsedDate != nil {
+					if time.Since(*used.LastUsedDate) <= r.maxAge {
+						continue
+					}
+					reason = fmt.Sprintf("%s key last used %dd ago", key.Status, int(time.Since(*used.LastUsedDate).Hours()/24))
+				}
+				items = append(items, CleanupItem{
+					Kind:   cleanupAccessKey,
+					ID:     stringValue(key.AccessKeyId),
+					Name:   userName,
+					Reason: reason,
+					Owner:  userName,
+				})
+			}
+		}
+	}
+	return items, nil
+}
+
+// Rows returns the table data
+func (r *Cleanup) Rows() [][]string {
+	rows := make([][]string, len(r.items))
+	for i, item := range r.items {
+		tick := ""
+		if r.ticked[item.ID] {
+			tick = "✓"
+		}
+		rows[i] = []string{
+			tick,
+			item.Kind,
+			item.ID,
+			item.Name,
+			item.Reason,
+		}
+	}
+	return rows
+}
+
+// GetID returns the resource ID at the given index
+func (r *Cleanup) GetID(index int) string {
+	if index >= 0 && index < len(r.items) {
+		return r.items[index].ID
+	}
+	return ""
+}
+
+// Highlight flags the ticked resources
+func (r *Cleanup) Highlight(index int) bool {
+	if index < 0 || index >= len(r.items) {
+		return false
+	}
+	return r.ticked[r.items[index].ID]
+}
+
+// QuickActions returns the available quick actions for the cleanup
+func (r *Cleanup) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:            't',
+			Label:          "tick",
+			Description:    "Tick or untick the resource",
+			NeedsSelection: true,
+			Mark:           r.tick,
+		},
+		{
+			Key:         'T',
+			Label:       "tick all",
+			Description: "Tick all resources, or untick them when all are ticked",
+			Mark:        r.tickAll,
+		},
+		{
+			Key:         'D',
+			Label:       "delete ticked",
+			Description: "Delete the ticked resources",
+			Plan:        r.planDelete,
+		},
+	}
+}
+
+// tick ticks or unticks a resource
+func (r *Cleanup) tick(id string) {
+	if r.ticked[id] {
+		delete(r.ticked, id)
+		return
+	}
+	r.ticked[id] = true
+}
+
+// tickAll ticks all resources, or unticks them when they all are
+func (r *Cleanup) tickAll(string) {
+	if len(r.ticked) == len(r.items) {
+		r.ticked = make(map[string]bool)
+		return
+	}
+	for _, item := range r.items {
+		r.ticked[item.ID] = true
+	}
+}
+
+// planDelete lists the steps deleting the ticked resources, default VPCs are
+// emptied of their internet gateways and subnets first
+func (r *Cleanup) planDelete(ctx context.Context, c *client.Client, _ string) ([]Step, error) {
+	items := make([]CleanupItem, 0)
+	for _, item := range r.items {
+		if r.ticked[item.ID] {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no resource ticked, press t to tick one")
+	}
+
+	steps := []Step{{Description: fmt.Sprintf("%d resources to delete in %s, this cannot be undone", len(items), r.region)}}
+	for _, item := range items {
+		switch item.Kind {
+		case cleanupVolume:
+			steps = append(steps, Step{
+				Description: fmt.Sprintf("Delete volume %s", item.ID),
+				Run: func(ctx context.Context, c *client.Client) error {
+					_, err := c.EC2().DeleteVolume(ctx, &ec2.DeleteVolumeInput{VolumeId: &item.ID})
+					return err
+				},
+			})
+		case cleanupAddress:
+			steps = append(steps, Step{
+				Description: fmt.Sprintf("Release Elastic IP %s (%s)", item.Name, item.ID),
+				Run: func(ctx context.Context, c *client.Client) error {
+					_, err := c.EC2().ReleaseAddress(ctx, &ec2.ReleaseAddressInput{AllocationId: &item.ID})
+					return err
+				},
+			})
+		case cleanupLoadBalancer:
+			steps = append(steps, Step{
+				Description: fmt.Sprintf("Delete load balancer %s", item.Name),
+				Run: func(ctx context.Context, c *client.Client) error {
+					_, err := c.ELBv2().DeleteLoadBalancer(ctx, &elasticloadbalancingv2.DeleteLoadBalancerInput{LoadBalancerArn: &item.ID})
+					return err
+				},
+			})
+		case cleanupDefaultVPC:
+			vpcSteps, err := planDeleteDefaultVPC(ctx, c, item.ID)
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, vpcSteps...)
+		case cleanupAccessKey:
+			steps = append(steps, Step{
+				Description: fmt.Sprintf("Delete access key %s of %s", item.ID, item.Owner),
+				Run: func(ctx context.Context, c *client.Client) error {
+					_, err := c.IAM().DeleteAccessKey(ctx, &iam.DeleteAccessKeyInput{UserName: &item.Owner, AccessKeyId: &item.ID})
+					return err
+				},
+			})
+		}
+	}
+	return steps, nil
+}
+
+// planDeleteDefaultVPC lists the steps deleting a default VPC with its internet gateways and subnets
+func planDeleteDefaultVPC(ctx context.Context, c *client.Client, vpcID string) ([]Step, error) {
+	steps := make([]Step, 0)
+
+	gateways, err := c.EC2().DescribeInternetGateways(ctx, &ec2.DescribeInternetGatewaysInput{
+		Filters: []types.Filter{{Name: aws.String("attachment.vpc-id"), Values: []string{vpcID}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe internet gateways of %s: %w", vpcID, err)
+	}
+	for _, gateway := range gateways.InternetGateways {
+		gatewayID := stringValue(gateway.InternetGatewayId)
+		steps = append(steps,
+			Step{
+				Description: fmt.Sprintf("Detach internet gateway %s from %s", gatewayID, vpcID),
+				Run: func(ctx context.Context, c *client.Client) error {
+					_, err := c.EC2().DetachInternetGateway(ctx, &ec2.DetachInternetGatewayInput{InternetGatewayId: &gatewayID, VpcId: &vpcID})
+					return err
+				},
+			},
+			Step{
+				Description: fmt.Sprintf("Delete internet gateway %s", gatewayID),
+				Run: func(ctx context.Context, c *client.Client) error {
+					_, err := c.EC2().DeleteInternetGateway(ctx, &ec2.DeleteInternetGatewayInput{InternetGatewayId: &gatewayID})
+					return err
+				},
+			},
+		)
+	}
+
+	subnets, err := c.EC2().DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{
+		Filters: []types.Filter{{Name: aws.String("vpc-id"), Values: []string{vpcID}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe subnets of %s: %w", vpcID, err)
+	}
+	for _, subnet := range subnets.Subnets {
+		subnetID := stringValue(subnet.SubnetId)
+		steps = append(steps, Step{
+			Description: fmt.Sprintf("Delete subnet %s (%s)", subnetID, stringValue(subnet.AvailabilityZone)),
+			Run: func(ctx context.Context, c *client.Client) error {
+				_, err := c.EC2().DeleteSubnet(ctx, &ec2.DeleteSubnetInput{SubnetId: &subnetID})
+				return err
+			},
+		})
+	}
+
+	steps = append(steps, Step{
+		Description: fmt.Sprintf("Delete default VPC %s, with its default route table, network ACL and security group", vpcID),
+		Run: func(ctx context.Context, c *client.Client) error {
+			_, err := c.EC2().DeleteVpc(ctx, &ec2.DeleteVpcInput{VpcId: &vpcID})
+			return err
+		},
+	})
+	return steps, nil
+}
@@ -0,0 +1,142 @@
+package resources
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbtypes "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// mockELBv2 serves application load balancers without targets, each with one listener
+// running the given default action
+type mockELBv2 struct {
+	client.ELBv2API
+	actions map[string]elbtypes.ActionTypeEnum
+}
+
+func (m *mockELBv2) DescribeLoadBalancers(ctx context.Context, params *elasticloadbalancingv2.DescribeLoadBalancersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error) {
+	output := &elasticloadbalancingv2.DescribeLoadBalancersOutput{}
+	for name := range m.actions {
+		output.LoadBalancers = append(output.LoadBalancers, elbtypes.LoadBalancer{
+			LoadBalancerArn:  aws.String("arn:lb/" + name),
+			LoadBalancerName: aws.String(name),
+			Type:             elbtypes.LoadBalancerTypeEnumApplication,
+		})
+	}
+	return output, nil
+}
+
+func (m *mockELBv2) DescribeTargetGroups(ctx context.Context, params *elasticloadbalancingv2.DescribeTargetGroupsInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetGroupsOutput, error) {
+	return &elasticloadbalancingv2.DescribeTargetGroupsOutput{}, nil
+}
+
+func (m *mockELBv2) DescribeListeners(ctx context.Context, params *elasticloadbalancingv2.DescribeListenersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeListenersOutput, error) {
+	name := strings.TrimPrefix(aws.ToString(params.LoadBalancerArn), "arn:lb/")
+	return &elasticloadbalancingv2.DescribeListenersOutput{
+		Listeners: []elbtypes.Listener{{
+			ListenerArn:    aws.String("arn:listener/" + name),
+			DefaultActions: []elbtypes.Action{{Type: m.actions[name]}},
+		}},
+	}, nil
+}
+
+func (m *mockELBv2) DescribeRules(ctx context.Context, params *elasticloadbalancingv2.DescribeRulesInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeRulesOutput, error) {
+	return &elasticloadbalancingv2.DescribeRulesOutput{}, nil
+}
+
+func TestEmptyLoadBalancers(t *testing.T) {
+	c := client.NewStatic(aws.Config{Region: "eu-west-1"}, "test", map[string]any{
+		"elasticloadbalancingv2": &mockELBv2{actions: map[string]elbtypes.ActionTypeEnum{
+			"unused":      elbtypes.ActionTypeEnumFixedResponse,
+			"https-redir": elbtypes.ActionTypeEnumRedirect,
+		}},
+	})
+
+	items, err := emptyLoadBalancers(context.Background(), c)
+	if err != nil {
+		t.Fatalf("emptyLoadBalancers: %v", err)
+	}
+	if len(items) != 1 || items[0].Name != "unused" {
+		t.Fatalf("got %+v, want only the unused load balancer", items)
+	}
+	if items[0].Reason != "application no target group" {
+		t.Errorf("got reason %q", items[0].Reason)
+	}
+}
+
+// mockIAM serves one user whose access keys are created and last used the given days ago,
+// a negative last use for a key never used
+type mockIAM struct {
+	client.IAMAPI
+	keys map[string][2]int
+}
+
+func daysAgo(days int) *time.Time {
+	return aws.Time(time.Now().Add(-time.Duration(days) * 24 * time.Hour))
+}
+
+func (m *mockIAM) ListUsers(ctx context.Context, params *iam.ListUsersInput, optFns ...func(*iam.Options)) (*iam.ListUsersOutput, error) {
+	return &iam.ListUsersOutput{Users: []iamtypes.User{{UserName: aws.String("ci")}}}, nil
+}
+
+func (m *mockIAM) ListAccessKeys(ctx context.Context, params *iam.ListAccessKeysInput, optFns ...func(*iam.Options)) (*iam.ListAccessKeysOutput, error) {
+	output := &iam.ListAccessKeysOutput{}
+	for id, days := range m.keys {
+		output.AccessKeyMetadata = append(output.AccessKeyMetadata, iamtypes.AccessKeyMetadata{
+			AccessKeyId: aws.String(id),
+			UserName:    params.UserName,
+			Status:      iamtypes.StatusTypeActive,
+			CreateDate:  daysAgo(days[0]),
+		})
+	}
+	return output, nil
+}
+
+func (m *mockIAM) GetAccessKeyLastUsed(ctx context.Context, params *iam.GetAccessKeyLastUsedInput, optFns ...func(*iam.Options)) (*iam.GetAccessKeyLastUsedOutput, error) {
+	used := &iamtypes.AccessKeyLastUsed{}
+	if days := m.keys[aws.ToString(params.AccessKeyId)][1]; days >= 0 {
+		used.LastUsedDate = daysAgo(days)
+	}
+	return &iam.GetAccessKeyLastUsedOutput{AccessKeyLastUsed: used}, nil
+}
+
+func TestOldAccessKeys(t *testing.T) {
+	c := client.NewStatic(aws.Config{Region: "eu-west-1"}, "test", map[string]any{
+		"iam": &mockIAM{keys: map[string][2]int{
+			"AKIAOLDBUTUSED": {400, 1},
+			"AKIASTALE":      {400, 200},
+			"AKIANEVERUSED":  {400, -1},
+			"AKIARECENT":     {10, -1},
+		}},
+	})
+
+	items, err := NewCleanup(90*24*time.Hour).oldAccessKeys(context.Background(), c)
+	if err != nil {
+		t.Fatalf("oldAccessKeys: %v", err)
+	}
+
+	reasons := make(map[string]string)
+	for _, item := range items {
+		reasons[item.ID] = item.Reason
+	}
+	want := map[string]string{
+		"AKIASTALE":     "Active key last used 200d ago",
+		"AKIANEVERUSED": "Active key never used, created 400d ago",
+	}
+	if len(reasons) != len(want) {
+		t.Fatalf("got %v, want %v", reasons, want)
+	}
+	for id, reason := range want {
+		if reasons[id] != reason {
+			t.Errorf("%s: got reason %q, want %q", id, reasons[id], reason)
+		}
+	}
+}
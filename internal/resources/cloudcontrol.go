@@ -0,0 +1,189 @@
+package resources
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol"
+)
+
+// maxCloudControlColumns is the number of property columns shown next to the identifier
+const maxCloudControlColumns = 4
+
+// CloudControlResource represents a resource listed through the Cloud Control API
+type CloudControlResource struct {
+	Identifier string
+	Properties map[string]any
+}
+
+// CloudControlResources implements Resource for any CloudFormation resource type
+// supported by the Cloud Control API, e.g. AWS::GameLift::Fleet
+type CloudControlResources struct {
+	typeName   string
+	resources  []CloudControlResource
+	properties []string
+}
+
+// NewCloudControlResources creates a new CloudControlResources resource
+func NewCloudControlResources(typeName string) *CloudControlResources {
+	return &CloudControlResources{
+		typeName:  typeName,
+		resources: make([]CloudControlResource, 0),
+	}
+}
+
+// Name returns the display name
+func (r *CloudControlResources) Name() string {
+	return r.typeName
+}
+
+// Columns returns the column definitions, the property columns depend on the fetched resources
+func (r *CloudControlResources) Columns() []Column {
+	columns := []Column{{Name: "Identifier", Width: 40}}
+	for _, property := range r.properties {
+		columns = append(columns, Column{Name: property, Width: 25})
+	}
+	return columns
+}
+
+// Fetch retrieves the resources of the type from the Cloud Control API
+func (r *CloudControlResources) Fetch(ctx context.Context, c *client.Client) error {
+	r.resources = make([]CloudControlResource, 0)
+
+	paginator := cloudcontrol.NewListResourcesPaginator(c.CloudControl(), &cloudcontrol.ListResourcesInput{
+		TypeName: &r.typeName,
+	})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list %s resources: %w", r.typeName, err)
+		}
+
+		for _, desc := range output.ResourceDescriptions {
+			properties := make(map[string]any)
+			if desc.Properties != nil {
+				if err := json.Unmarshal([]byte(*desc.Properties), &properties); err != nil {
+					return fmt.Errorf("failed to parse properties of %s: %w", stringValue(desc.Identifier), err)
+				}
+			}
+
+			r.resources = append(r.resources, CloudControlResource{
+				Identifier: stringValue(desc.Identifier),
+				Properties: properties,
+			})
+		}
+	}
+
+	r.properties = keyProperties(r.resources)
+
+	return nil
+}
+
+// keyProperties picks the scalar properties to show as columns, names and
+// states first, then the most common ones
+func keyProperties(resources []CloudControlResource) []string {
+	counts := make(map[string]int)
+	for _, res := range resources {
+		for key, value := range res.Properties {
+			switch value.(type) {
+			case string, float64, bool:
+				counts[key]++
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+
+	rank := func(key string) int {
+		lower := strings.ToLower(key)
+		switch {
+		case strings.HasSuffix(lower, "name"):
+			return 0
+		case strings.Contains(lower, "status") || strings.Contains(lower, "state"):
+			return 1
+		case strings.HasSuffix(lower, "arn"):
+			return 3
+		}
+		return 2
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if rank(keys[i]) != rank(keys[j]) {
+			return rank(keys[i]) < rank(keys[j])
+		}
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	if len(keys) > maxCloudControlColumns {
+		keys = keys[:maxCloudControlColumns]
+	}
+	return keys
+}
+
+// Rows returns the table data
+func (r *CloudControlResources) Rows() [][]string {
+	rows := make([][]string, len(r.resources))
+	for i, res := range r.resources {
+		row := []string{res.Identifier}
+		for _, property := range r.properties {
+			value, ok := res.Properties[property]
+			if !ok {
+				row = append(row, "")
+				continue
+			}
+			row = append(row, fmt.Sprint(value))
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+// GetID returns the resource identifier at the given index
+func (r *CloudControlResources) GetID(index int) string {
+	if index >= 0 && index < len(r.resources) {
+		return r.resources[index].Identifier
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for Cloud Control resources
+func (r *CloudControlResources) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:            'd',
+			Label:          "describe",
+			Description:    "Show properties",
+			NeedsSelection: true,
+			TextHandler: func(ctx context.Context, c *client.Client, identifier string) (string, error) {
+				output, err := c.CloudControl().GetResource(ctx, &cloudcontrol.GetResourceInput{
+					TypeName:   &r.typeName,
+					Identifier: &identifier,
+				})
+				if err != nil {
+					return "", fmt.Errorf("failed to get %s: %w", identifier, err)
+				}
+				if output.ResourceDescription == nil || output.ResourceDescription.Properties == nil {
+					return "", nil
+				}
+
+				var buf bytes.Buffer
+				if err := json.Indent(&buf, []byte(*output.ResourceDescription.Properties), "", "  "); err != nil {
+					return *output.ResourceDescription.Properties, nil
+				}
+				return buf.String(), nil
+			},
+		},
+	}
+}
@@ -0,0 +1,471 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cftypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+)
+
+// driftDetectionWait is how long the drift detection of a stack is waited for
+const driftDetectionWait = 5 * time.Minute
+
+// CloudFormationStacks implements Resource for CloudFormation stacks
+type CloudFormationStacks struct {
+	stacks []cftypes.Stack
+}
+
+// NewCloudFormationStacks creates a new CloudFormationStacks resource
+func NewCloudFormationStacks() *CloudFormationStacks {
+	return &CloudFormationStacks{
+		stacks: make([]cftypes.Stack, 0),
+	}
+}
+
+// Name returns the display name
+func (s *CloudFormationStacks) Name() string {
+	return "CloudFormation Stacks"
+}
+
+// Columns returns the column definitions
+func (s *CloudFormationStacks) Columns() []Column {
+	return []Column{
+		{Name: "Name", Width: 40},
+		{Name: "Status", Width: 30},
+		{Name: "Drift", Width: 12},
+		{Name: "Updated", Width: 20},
+		{Name: "Description", Width: 50},
+	}
+}
+
+// Fetch retrieves the stacks from AWS, deleted ones excluded
+func (s *CloudFormationStacks) Fetch(ctx context.Context, c *client.Client) error {
+	s.stacks = make([]cftypes.Stack, 0)
+
+	paginator := cloudformation.NewDescribeStacksPaginator(c.CloudFormation(), &cloudformation.DescribeStacksInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe stacks: %w", err)
+		}
+		s.stacks = append(s.stacks, output.Stacks...)
+	}
+
+	return nil
+}
+
+// Rows returns the table data
+func (s *CloudFormationStacks) Rows() [][]string {
+	rows := make([][]string, len(s.stacks))
+	for i, stack := range s.stacks {
+		updated := stack.LastUpdatedTime
+		if updated == nil {
+			updated = stack.CreationTime
+		}
+		updatedAt := ""
+		if updated != nil {
+			updatedAt = updated.Format("2006-01-02 15:04:05")
+		}
+		drift := ""
+		if stack.DriftInformation != nil {
+			drift = string(stack.DriftInformation.StackDriftStatus)
+		}
+		rows[i] = []string{
+			stringValue(stack.StackName),
+			string(stack.StackStatus),
+			drift,
+			updatedAt,
+			stringValue(stack.Description),
+		}
+	}
+	return rows
+}
+
+// GetID returns the stack name at the given index
+func (s *CloudFormationStacks) GetID(index int) string {
+	if index >= 0 && index < len(s.stacks) {
+		return stringValue(s.stacks[index].StackName)
+	}
+	return ""
+}
+
+// Highlight flags the stacks which failed, rolled back or drifted from their template
+func (s *CloudFormationStacks) Highlight(index int) bool {
+	if index < 0 || index >= len(s.stacks) {
+		return false
+	}
+	stack := s.stacks[index]
+	if stack.DriftInformation != nil && stack.DriftInformation.StackDriftStatus == cftypes.StackDriftStatusDrifted {
+		return true
+	}
+	status := string(stack.StackStatus)
+	return strings.HasSuffix(status, "FAILED") || strings.HasSuffix(status, "ROLLBACK_COMPLETE")
+}
+
+// DrillDown opens the events of the stack
+func (s *CloudFormationStacks) DrillDown(stackName string) Resource {
+	return NewStackEvents(s.stackID(stackName), stackName)
+}
+
+// QuickActions returns the available quick actions for stacks
+func (s *CloudFormationStacks) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:            'e',
+			Label:          "events",
+			Description:    "Show the events of the stack",
+			NeedsSelection: true,
+			View: func(stackName string) Resource {
+				return NewStackEvents(s.stackID(stackName), stackName)
+			},
+		},
+		{
+			Key:            'd',
+			Label:          "drift",
+			Description:    "Detect the drift of the stack resources",
+			NeedsSelection: true,
+			Handler:        detectStackDrift,
+			Follow: func(stackName string) Resource {
+				return NewStackDrifts(stackName)
+			},
+		},
+		{
+			Key:             'C',
+			Label:           "cancel update",
+			Description:     "Cancel the update in progress, the stack rolls back",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "[yellow]cancel the update[-] of stack [white]%s[-]? It rolls back to its previous configuration",
+			ConfirmCheck: func(ctx context.Context, c *client.Client, stackName string) (string, error) {
+				stack, err := describeStack(ctx, c, stackName)
+				if err != nil {
+					return "", err
+				}
+				if stack.StackStatus != cftypes.StackStatusUpdateInProgress {
+					return "", fmt.Errorf("stack %s is %s, only an update in progress can be cancelled", stackName, stack.StackStatus)
+				}
+				return "", nil
+			},
+			Handler: func(ctx context.Context, c *client.Client, stackName string) error {
+				_, err := c.CloudFormation().CancelUpdateStack(ctx, &cloudformation.CancelUpdateStackInput{StackName: aws.String(stackName)})
+				if err != nil {
+					return fmt.Errorf("failed to cancel the update of %s: %w", stackName, err)
+				}
+				return nil
+			},
+			Follow: func(stackName string) Resource {
+				return NewStackEvents(s.stackID(stackName), stackName)
+			},
+		},
+		{
+			Key:             'D',
+			Label:           "delete",
+			Description:     "Delete the stack and its resources",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			TypedConfirm:    true,
+			ConfirmTemplate: "[red]delete[-] stack [white]%s[-]? Its resources are deleted, except the retained ones",
+			ConfirmCheck:    checkStackDeletable,
+			Handler: func(ctx context.Context, c *client.Client, stackName string) error {
+				_, err := c.CloudFormation().DeleteStack(ctx, &cloudformation.DeleteStackInput{StackName: aws.String(stackName)})
+				if err != nil {
+					return fmt.Errorf("failed to delete stack %s: %w", stackName, err)
+				}
+				return nil
+			},
+			// The events are followed by stack ID, the name no longer resolves once deleted
+			Follow: func(stackName string) Resource {
+				return NewStackEvents(s.stackID(stackName), stackName)
+			},
+		},
+	}
+}
+
+// stackID returns the ID of the listed stack with the given name, the name itself otherwise
+func (s *CloudFormationStacks) stackID(stackName string) string {
+	for _, stack := range s.stacks {
+		if stringValue(stack.StackName) == stackName {
+			return stringValue(stack.StackId)
+		}
+	}
+	return stackName
+}
+
+// describeStack returns the current state of a stack, by name or ID
+func describeStack(ctx context.Context, c *client.Client, stack string) (cftypes.Stack, error) {
+	output, err := c.CloudFormation().DescribeStacks(ctx, &cloudformation.DescribeStacksInput{StackName: aws.String(stack)})
+	if err != nil {
+		return cftypes.Stack{}, fmt.Errorf("failed to describe stack %s: %w", stack, err)
+	}
+	if len(output.Stacks) == 0 {
+		return cftypes.Stack{}, fmt.Errorf("stack %s not found", stack)
+	}
+	return output.Stacks[0], nil
+}
+
+// checkStackDeletable refuses to delete protected, nested or busy stacks, and tells how many
+// resources the deletion removes
+func checkStackDeletable(ctx context.Context, c *client.Client, stackName string) (string, error) {
+	stack, err := describeStack(ctx, c, stackName)
+	if err != nil {
+		return "", err
+	}
+	if ptrBoolValue(stack.EnableTerminationProtection) {
+		return "", fmt.Errorf("termination protection is enabled on %s", stackName)
+	}
+	if stack.ParentId != nil {
+		return "", fmt.Errorf("%s is a nested stack, delete or update its parent stack instead", stackName)
+	}
+	if strings.HasSuffix(string(stack.StackStatus), "_IN_PROGRESS") {
+		return "", fmt.Errorf("stack %s is %s, wait for the operation to end", stackName, stack.StackStatus)
+	}
+
+	count := 0
+	paginator := cloudformation.NewListStackResourcesPaginator(c.CloudFormation(), &cloudformation.ListStackResourcesInput{
+		StackName: aws.String(stackName),
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to list resources of %s: %w", stackName, err)
+		}
+		count += len(output.StackResourceSummaries)
+	}
+	return fmt.Sprintf("Warning: the stack has %d resources", count), nil
+}
+
+// detectStackDrift runs a drift detection on a stack and waits for it, the drifted
+// resources are then listed by StackDrifts
+func detectStackDrift(ctx context.Context, c *client.Client, stackName string) error {
+	detect, err := c.CloudFormation().DetectStackDrift(ctx, &cloudformation.DetectStackDriftInput{StackName: aws.String(stackName)})
+	if err != nil {
+		return fmt.Errorf("failed to detect the drift of %s: %w", stackName, err)
+	}
+
+	reportProgress(ctx, "Detecting the drift of "+stackName+"...")
+	deadline := time.Now().Add(driftDetectionWait)
+	for {
+		status, err := c.CloudFormation().DescribeStackDriftDetectionStatus(ctx, &cloudformation.DescribeStackDriftDetectionStatusInput{
+			StackDriftDetectionId: detect.StackDriftDetectionId,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get the drift detection status of %s: %w", stackName, err)
+		}
+		switch status.DetectionStatus {
+		case cftypes.StackDriftDetectionStatusDetectionComplete:
+			return nil
+		case cftypes.StackDriftDetectionStatusDetectionFailed:
+			return fmt.Errorf("the drift detection of %s failed for some resources: %s", stackName, stringValue(status.DetectionStatusReason))
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("the drift detection of %s is still running, check the stack again later", stackName)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(3 * time.Second):
+		}
+	}
+}
+
+// StackDrifts implements Resource for the modified and deleted resources of a stack, as
+// found by its last drift detection
+type StackDrifts struct {
+	stackName string
+	status    cftypes.StackDriftStatus
+	drifts    []cftypes.StackResourceDrift
+}
+
+// NewStackDrifts creates a new StackDrifts resource
+func NewStackDrifts(stackName string) *StackDrifts {
+	return &StackDrifts{
+		stackName: stackName,
+		drifts:    make([]cftypes.StackResourceDrift, 0),
+	}
+}
+
+// Name returns the display name
+func (s *StackDrifts) Name() string {
+	if s.status == "" {
+		return fmt.Sprintf("Stack Drifts (%s)", s.stackName)
+	}
+	return fmt.Sprintf("Stack Drifts (%s, %s)", s.stackName, s.status)
+}
+
+// Columns returns the column definitions
+func (s *StackDrifts) Columns() []Column {
+	return []Column{
+		{Name: "Logical ID", Width: 35},
+		{Name: "Type", Width: 35},
+		{Name: "Drift", Width: 12},
+		{Name: "Differences", Width: 90},
+	}
+}
+
+// Fetch retrieves the drift status of the stack and its drifted resources
+func (s *StackDrifts) Fetch(ctx context.Context, c *client.Client) error {
+	s.drifts = make([]cftypes.StackResourceDrift, 0)
+
+	stack, err := describeStack(ctx, c, s.stackName)
+	if err != nil {
+		return err
+	}
+	s.status = ""
+	if stack.DriftInformation != nil {
+		s.status = stack.DriftInformation.StackDriftStatus
+	}
+
+	paginator := cloudformation.NewDescribeStackResourceDriftsPaginator(c.CloudFormation(), &cloudformation.DescribeStackResourceDriftsInput{
+		StackName: aws.String(s.stackName),
+		StackResourceDriftStatusFilters: []cftypes.StackResourceDriftStatus{
+			cftypes.StackResourceDriftStatusModified,
+			cftypes.StackResourceDriftStatusDeleted,
+		},
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe the resource drifts of %s: %w", s.stackName, err)
+		}
+		s.drifts = append(s.drifts, output.StackResourceDrifts...)
+	}
+	return nil
+}
+
+// Rows returns the table data
+func (s *StackDrifts) Rows() [][]string {
+	rows := make([][]string, len(s.drifts))
+	for i, drift := range s.drifts {
+		differences := make([]string, 0, len(drift.PropertyDifferences))
+		for _, difference := range drift.PropertyDifferences {
+			differences = append(differences, fmt.Sprintf("%s %s: %s → %s", difference.DifferenceType, stringValue(difference.PropertyPath),
+				stringValue(difference.ExpectedValue), stringValue(difference.ActualValue)))
+		}
+		rows[i] = []string{
+			stringValue(drift.LogicalResourceId),
+			stringValue(drift.ResourceType),
+			string(drift.StackResourceDriftStatus),
+			strings.Join(differences, "; "),
+		}
+	}
+	return rows
+}
+
+// GetID returns the logical ID of the drifted resource at the given index
+func (s *StackDrifts) GetID(index int) string {
+	if index >= 0 && index < len(s.drifts) {
+		return stringValue(s.drifts[index].LogicalResourceId)
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for stack drifts
+func (s *StackDrifts) QuickActions() []QuickAction {
+	return []QuickAction{}
+}
+
+// StackEvents implements Resource for the most recent events of a stack, refreshed while
+// an operation runs on it
+type StackEvents struct {
+	stackID   string
+	stackName string
+	status    cftypes.StackStatus
+	events    []cftypes.StackEvent
+}
+
+// NewStackEvents creates a new StackEvents resource
+func NewStackEvents(stackID, stackName string) *StackEvents {
+	return &StackEvents{
+		stackID:   stackID,
+		stackName: stackName,
+		events:    make([]cftypes.StackEvent, 0),
+	}
+}
+
+// Name returns the display name
+func (s *StackEvents) Name() string {
+	if s.status == "" {
+		return fmt.Sprintf("Stack Events (%s)", s.stackName)
+	}
+	return fmt.Sprintf("Stack Events (%s, %s)", s.stackName, s.status)
+}
+
+// Columns returns the column definitions
+func (s *StackEvents) Columns() []Column {
+	return []Column{
+		{Name: "Time", Width: 20},
+		{Name: "Logical ID", Width: 35},
+		{Name: "Type", Width: 35},
+		{Name: "Status", Width: 30},
+		{Name: "Reason", Width: 70},
+	}
+}
+
+// Fetch retrieves the status of the stack and its most recent events, newest first
+func (s *StackEvents) Fetch(ctx context.Context, c *client.Client) error {
+	s.events = make([]cftypes.StackEvent, 0)
+
+	stack, err := describeStack(ctx, c, s.stackID)
+	if err != nil {
+		return err
+	}
+	s.status = stack.StackStatus
+
+	output, err := c.CloudFormation().DescribeStackEvents(ctx, &cloudformation.DescribeStackEventsInput{
+		StackName: aws.String(s.stackID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe events of %s: %w", s.stackName, err)
+	}
+	s.events = output.StackEvents
+
+	return nil
+}
+
+// Rows returns the table data
+func (s *StackEvents) Rows() [][]string {
+	rows := make([][]string, len(s.events))
+	for i, event := range s.events {
+		timestamp := ""
+		if event.Timestamp != nil {
+			timestamp = event.Timestamp.Format("2006-01-02 15:04:05")
+		}
+		rows[i] = []string{
+			timestamp,
+			stringValue(event.LogicalResourceId),
+			stringValue(event.ResourceType),
+			string(event.ResourceStatus),
+			stringValue(event.ResourceStatusReason),
+		}
+	}
+	return rows
+}
+
+// GetID returns the event ID at the given index
+func (s *StackEvents) GetID(index int) string {
+	if index >= 0 && index < len(s.events) {
+		return stringValue(s.events[index].EventId)
+	}
+	return ""
+}
+
+// Highlight flags the events of failed operations
+func (s *StackEvents) Highlight(index int) bool {
+	return index >= 0 && index < len(s.events) && strings.HasSuffix(string(s.events[index].ResourceStatus), "FAILED")
+}
+
+// Streaming reports whether an operation runs on the stack, until the first fetch tells
+func (s *StackEvents) Streaming() bool {
+	return s.status == "" || strings.HasSuffix(string(s.status), "_IN_PROGRESS")
+}
+
+// QuickActions returns the available quick actions for stack events
+func (s *StackEvents) QuickActions() []QuickAction {
+	return []QuickAction{}
+}
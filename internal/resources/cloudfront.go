@@ -3,12 +3,19 @@ package resources
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"a9s/internal/client"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	cftypes "github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
 )
 
+// recentInvalidations is the number of invalidations listed for a distribution
+const recentInvalidations = 25
+
 // CloudFrontDistribution represents a CloudFront distribution
 type CloudFrontDistribution struct {
 	ID           string
@@ -133,5 +140,166 @@ func (c *CloudFrontDistributions) GetID(index int) string {
 
 // QuickActions returns the available quick actions for CloudFront distributions
 func (c *CloudFrontDistributions) QuickActions() []QuickAction {
-	return []QuickAction{}
+	return []QuickAction{
+		{
+			Key:            'i',
+			Label:          "invalidate",
+			Description:    "Invalidate paths",
+			NeedsSelection: true,
+			InputLabel:     "Paths: ",
+			InputDefault: func(string) string {
+				return "/*"
+			},
+			InputHandler: createInvalidation,
+		},
+	}
+}
+
+// DrillDown opens the recent invalidations of the distribution
+func (c *CloudFrontDistributions) DrillDown(distributionID string) Resource {
+	return NewCloudFrontInvalidations(distributionID)
+}
+
+// createInvalidation invalidates the space separated paths of a distribution
+func createInvalidation(ctx context.Context, c *client.Client, distributionID, paths string) error {
+	items := strings.Fields(paths)
+	for i, path := range items {
+		if !strings.HasPrefix(path, "/") {
+			items[i] = "/" + path
+		}
+	}
+
+	_, err := c.CloudFront().CreateInvalidation(ctx, &cloudfront.CreateInvalidationInput{
+		DistributionId: &distributionID,
+		InvalidationBatch: &cftypes.InvalidationBatch{
+			CallerReference: aws.String(fmt.Sprintf("a9s-%d", time.Now().UnixNano())),
+			Paths: &cftypes.Paths{
+				Quantity: aws.Int32(int32(len(items))),
+				Items:    items,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to invalidate %s: %w", distributionID, err)
+	}
+	return nil
+}
+
+// CloudFrontInvalidation represents an invalidation of a CloudFront distribution
+type CloudFrontInvalidation struct {
+	ID         string
+	Status     string
+	CreateTime string
+	Paths      string
+}
+
+// CloudFrontInvalidations implements Resource for the recent invalidations of a CloudFront distribution
+type CloudFrontInvalidations struct {
+	distributionID string
+	invalidations  []CloudFrontInvalidation
+}
+
+// NewCloudFrontInvalidations creates a new CloudFrontInvalidations resource
+func NewCloudFrontInvalidations(distributionID string) *CloudFrontInvalidations {
+	return &CloudFrontInvalidations{
+		distributionID: distributionID,
+		invalidations:  make([]CloudFrontInvalidation, 0),
+	}
+}
+
+// Name returns the display name
+func (c *CloudFrontInvalidations) Name() string {
+	return fmt.Sprintf("CloudFront Invalidations: %s", c.distributionID)
+}
+
+// Columns returns the column definitions
+func (c *CloudFrontInvalidations) Columns() []Column {
+	return []Column{
+		{Name: "ID", Width: 30},
+		{Name: "Status", Width: 12},
+		{Name: "Created", Width: 20},
+		{Name: "Paths", Width: 60},
+	}
+}
+
+// Fetch retrieves the most recent invalidations of the distribution with their paths
+func (c *CloudFrontInvalidations) Fetch(ctx context.Context, cl *client.Client) error {
+	c.invalidations = make([]CloudFrontInvalidation, 0)
+
+	output, err := cl.CloudFront().ListInvalidations(ctx, &cloudfront.ListInvalidationsInput{
+		DistributionId: &c.distributionID,
+		MaxItems:       aws.Int32(recentInvalidations),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list invalidations of %s: %w", c.distributionID, err)
+	}
+	if output.InvalidationList == nil {
+		return nil
+	}
+
+	for _, summary := range output.InvalidationList.Items {
+		invalidation := CloudFrontInvalidation{
+			ID:     stringValue(summary.Id),
+			Status: stringValue(summary.Status),
+		}
+		if summary.CreateTime != nil {
+			invalidation.CreateTime = summary.CreateTime.Format("2006-01-02 15:04:05")
+		}
+
+		// Summaries have no paths, they are in the invalidation itself
+		getOutput, err := cl.CloudFront().GetInvalidation(ctx, &cloudfront.GetInvalidationInput{
+			DistributionId: &c.distributionID,
+			Id:             summary.Id,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get invalidation %s: %w", invalidation.ID, err)
+		}
+		if inv := getOutput.Invalidation; inv != nil && inv.InvalidationBatch != nil && inv.InvalidationBatch.Paths != nil {
+			invalidation.Paths = strings.Join(inv.InvalidationBatch.Paths.Items, " ")
+		}
+
+		c.invalidations = append(c.invalidations, invalidation)
+	}
+
+	return nil
+}
+
+// Rows returns the table data
+func (c *CloudFrontInvalidations) Rows() [][]string {
+	rows := make([][]string, len(c.invalidations))
+	for i, invalidation := range c.invalidations {
+		rows[i] = []string{
+			invalidation.ID,
+			invalidation.Status,
+			invalidation.CreateTime,
+			invalidation.Paths,
+		}
+	}
+	return rows
+}
+
+// GetID returns the invalidation ID at the given index
+func (c *CloudFrontInvalidations) GetID(index int) string {
+	if index >= 0 && index < len(c.invalidations) {
+		return c.invalidations[index].ID
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for CloudFront invalidations
+func (c *CloudFrontInvalidations) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:         'c',
+			Label:       "create",
+			Description: "Invalidate paths",
+			InputLabel:  "Paths: ",
+			InputDefault: func(string) string {
+				return "/*"
+			},
+			InputHandler: func(ctx context.Context, cl *client.Client, _ string, paths string) error {
+				return createInvalidation(ctx, cl, c.distributionID, paths)
+			},
+		},
+	}
 }
@@ -2,11 +2,18 @@ package resources
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"a9s/internal/client"
 
 	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider"
+	cognitotypes "github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider/types"
 )
 
 // CognitoUserPool represents a Cognito User Pool
@@ -123,5 +130,538 @@ func (c *CognitoUserPools) GetID(index int) string {
 
 // QuickActions returns the available quick actions for Cognito user pools
 func (c *CognitoUserPools) QuickActions() []QuickAction {
-	return []QuickAction{}
+	return []QuickAction{
+		{
+			Key:            'e',
+			Label:          "export",
+			Description:    "Export users to a .csv or .json file",
+			NeedsSelection: true,
+			ReadOnly:       true,
+			InputLabel:     "File: ",
+			InputDefault: func(poolID string) string {
+				return fmt.Sprintf("%s-users.csv", poolID)
+			},
+			InputHandler: exportCognitoUsers,
+		},
+		{
+			Key:            'A',
+			Label:          "app clients",
+			Description:    "Show the app clients of the pool",
+			NeedsSelection: true,
+			View: func(poolID string) Resource {
+				return NewCognitoAppClients(poolID)
+			},
+		},
+	}
+}
+
+// DrillDown opens the users of the pool
+func (c *CognitoUserPools) DrillDown(poolID string) Resource {
+	return NewCognitoUsers(poolID)
+}
+
+// CognitoUser represents a user of a Cognito user pool, as exported
+type CognitoUser struct {
+	Username   string            `json:"username"`
+	Status     string            `json:"status"`
+	Enabled    bool              `json:"enabled"`
+	Created    string            `json:"created,omitempty"`
+	Modified   string            `json:"modified,omitempty"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+// exportCognitoUsers writes all the users of a pool to a file, as JSON when
+// its extension is .json and as CSV otherwise
+func exportCognitoUsers(ctx context.Context, cl *client.Client, poolID, path string) error {
+	users, err := listCognitoUsers(ctx, cl, poolID)
+	if err != nil {
+		return err
+	}
+
+	path, err = expandHome(path)
+	if err != nil {
+		return err
+	}
+
+	// The export holds personal data, keep it private to the user and never overwrite an existing file
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(users); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		return nil
+	}
+
+	// Users do not all have the same attributes, use the union as columns
+	seen := make(map[string]bool)
+	var attributes []string
+	for _, user := range users {
+		for name := range user.Attributes {
+			if !seen[name] {
+				seen[name] = true
+				attributes = append(attributes, name)
+			}
+		}
+	}
+	sort.Strings(attributes)
+
+	writer := csv.NewWriter(file)
+	header := append([]string{"username", "status", "enabled", "created", "modified"}, attributes...)
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	for _, user := range users {
+		record := []string{user.Username, user.Status, fmt.Sprintf("%t", user.Enabled), user.Created, user.Modified}
+		for _, name := range attributes {
+			record = append(record, user.Attributes[name])
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// listCognitoUsers retrieves all the users of a pool with their attributes
+func listCognitoUsers(ctx context.Context, cl *client.Client, poolID string) ([]CognitoUser, error) {
+	users := make([]CognitoUser, 0)
+
+	limit := int32(60)
+	paginator := cognitoidentityprovider.NewListUsersPaginator(cl.Cognito(), &cognitoidentityprovider.ListUsersInput{
+		UserPoolId: &poolID,
+		Limit:      &limit,
+	})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list users of %s: %w", poolID, err)
+		}
+
+		for _, u := range output.Users {
+			users = append(users, newCognitoUser(u))
+		}
+	}
+
+	return users, nil
+}
+
+// newCognitoUser converts a user returned by ListUsers
+func newCognitoUser(u cognitotypes.UserType) CognitoUser {
+	user := CognitoUser{
+		Username:   stringValue(u.Username),
+		Status:     string(u.UserStatus),
+		Enabled:    u.Enabled,
+		Attributes: make(map[string]string),
+	}
+	if u.UserCreateDate != nil {
+		user.Created = u.UserCreateDate.Format("2006-01-02 15:04:05")
+	}
+	if u.UserLastModifiedDate != nil {
+		user.Modified = u.UserLastModifiedDate.Format("2006-01-02 15:04:05")
+	}
+	for _, attr := range u.Attributes {
+		user.Attributes[stringValue(attr.Name)] = stringValue(attr.Value)
+	}
+	return user
+}
+
+// cognitoPageSize is the maximum number of users returned by ListUsers
+const cognitoPageSize int32 = 60
+
+// CognitoUsers implements Resource and Pageable for the users of a Cognito user pool
+type CognitoUsers struct {
+	poolID string
+	users  []CognitoUser
+	pages  int
+	token  *string
+}
+
+// NewCognitoUsers creates a new CognitoUsers resource
+func NewCognitoUsers(poolID string) *CognitoUsers {
+	return &CognitoUsers{
+		poolID: poolID,
+		users:  make([]CognitoUser, 0),
+	}
+}
+
+// Name returns the display name
+func (u *CognitoUsers) Name() string {
+	return fmt.Sprintf("Cognito Users: %s", u.poolID)
+}
+
+// Columns returns the column definitions
+func (u *CognitoUsers) Columns() []Column {
+	return []Column{
+		{Name: "Username", Width: 40},
+		{Name: "Status", Width: 22},
+		{Name: "Enabled", Width: 8},
+		{Name: "Email", Width: 40, Sensitive: true},
+		{Name: "Email Verified", Width: 14},
+		{Name: "Modified", Width: 20},
+	}
+}
+
+// Fetch retrieves the users of the pool, as many pages as already loaded
+func (u *CognitoUsers) Fetch(ctx context.Context, c *client.Client) error {
+	u.users = make([]CognitoUser, 0)
+	u.token = nil
+
+	pages := max(u.pages, 1)
+	u.pages = 0
+	for page := 0; page < pages; page++ {
+		if err := u.fetchPage(ctx, c); err != nil {
+			return err
+		}
+		if u.token == nil {
+			break
+		}
+	}
+
+	return nil
+}
+
+// HasMore reports whether more users can be loaded
+func (u *CognitoUsers) HasMore() bool {
+	return u.token != nil
+}
+
+// FetchMore retrieves the next page of users
+func (u *CognitoUsers) FetchMore(ctx context.Context, c *client.Client) error {
+	if u.token == nil {
+		return nil
+	}
+	return u.fetchPage(ctx, c)
+}
+
+// fetchPage retrieves the page of users starting at the pagination token
+func (u *CognitoUsers) fetchPage(ctx context.Context, c *client.Client) error {
+	limit := cognitoPageSize
+	output, err := c.Cognito().ListUsers(ctx, &cognitoidentityprovider.ListUsersInput{
+		UserPoolId:      &u.poolID,
+		Limit:           &limit,
+		PaginationToken: u.token,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list users of %s: %w", u.poolID, err)
+	}
+
+	for _, user := range output.Users {
+		u.users = append(u.users, newCognitoUser(user))
+	}
+
+	u.pages++
+	u.token = output.PaginationToken
+	return nil
+}
+
+// Rows returns the table data
+func (u *CognitoUsers) Rows() [][]string {
+	rows := make([][]string, len(u.users))
+	for i, user := range u.users {
+		rows[i] = []string{
+			user.Username,
+			user.Status,
+			fmt.Sprintf("%t", user.Enabled),
+			user.Attributes["email"],
+			user.Attributes["email_verified"],
+			user.Modified,
+		}
+	}
+	return rows
+}
+
+// GetID returns the username at the given index
+func (u *CognitoUsers) GetID(index int) string {
+	if index >= 0 && index < len(u.users) {
+		return u.users[index].Username
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for Cognito users
+func (u *CognitoUsers) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:             'X',
+			Label:           "disable",
+			Description:     "Disable user",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "Are you sure you want to disable user %s?",
+			Handler: func(ctx context.Context, c *client.Client, username string) error {
+				_, err := c.Cognito().AdminDisableUser(ctx, &cognitoidentityprovider.AdminDisableUserInput{
+					UserPoolId: &u.poolID,
+					Username:   &username,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to disable user %s: %w", username, err)
+				}
+				return nil
+			},
+		},
+		{
+			Key:            'E',
+			Label:          "enable",
+			Description:    "Enable user",
+			NeedsSelection: true,
+			Handler: func(ctx context.Context, c *client.Client, username string) error {
+				_, err := c.Cognito().AdminEnableUser(ctx, &cognitoidentityprovider.AdminEnableUserInput{
+					UserPoolId: &u.poolID,
+					Username:   &username,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to enable user %s: %w", username, err)
+				}
+				return nil
+			},
+		},
+		{
+			Key:             'R',
+			Label:           "reset password",
+			Description:     "Reset user password",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "Are you sure you want to reset the password of %s? The user must set a new one at next sign-in.",
+			Handler: func(ctx context.Context, c *client.Client, username string) error {
+				_, err := c.Cognito().AdminResetUserPassword(ctx, &cognitoidentityprovider.AdminResetUserPasswordInput{
+					UserPoolId: &u.poolID,
+					Username:   &username,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to reset password of %s: %w", username, err)
+				}
+				return nil
+			},
+		},
+		{
+			Key:             'D',
+			Label:           "delete",
+			Description:     "Delete user",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "Are you sure you want to delete user %s?",
+			Handler: func(ctx context.Context, c *client.Client, username string) error {
+				_, err := c.Cognito().AdminDeleteUser(ctx, &cognitoidentityprovider.AdminDeleteUserInput{
+					UserPoolId: &u.poolID,
+					Username:   &username,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to delete user %s: %w", username, err)
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// CognitoAppClient represents an app client of a Cognito user pool
+type CognitoAppClient struct {
+	ID            string
+	Name          string
+	OAuthFlows    string
+	Scopes        string
+	CallbackURLs  string
+	TokenValidity string
+	Secret        string
+}
+
+// CognitoAppClients implements Resource for the app clients of a Cognito user pool
+type CognitoAppClients struct {
+	poolID      string
+	clients     []CognitoAppClient
+	showSecrets bool
+}
+
+// NewCognitoAppClients creates a new CognitoAppClients resource
+func NewCognitoAppClients(poolID string) *CognitoAppClients {
+	return &CognitoAppClients{
+		poolID:  poolID,
+		clients: make([]CognitoAppClient, 0),
+	}
+}
+
+// Name returns the display name
+func (a *CognitoAppClients) Name() string {
+	return fmt.Sprintf("Cognito App Clients: %s", a.poolID)
+}
+
+// Columns returns the column definitions
+func (a *CognitoAppClients) Columns() []Column {
+	return []Column{
+		{Name: "Name", Width: 30},
+		{Name: "Client ID", Width: 28},
+		{Name: "OAuth Flows", Width: 25},
+		{Name: "Scopes", Width: 30},
+		{Name: "Callback URLs", Width: 45},
+		{Name: "Access/ID/Refresh", Width: 18},
+		{Name: "Secret", Width: 55},
+	}
+}
+
+// Fetch retrieves the app clients of the pool with their settings
+func (a *CognitoAppClients) Fetch(ctx context.Context, c *client.Client) error {
+	a.clients = make([]CognitoAppClient, 0)
+
+	maxResults := int32(60)
+	paginator := cognitoidentityprovider.NewListUserPoolClientsPaginator(c.Cognito(), &cognitoidentityprovider.ListUserPoolClientsInput{
+		UserPoolId: &a.poolID,
+		MaxResults: &maxResults,
+	})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list app clients of %s: %w", a.poolID, err)
+		}
+
+		for _, summary := range output.UserPoolClients {
+			describeOutput, err := c.Cognito().DescribeUserPoolClient(ctx, &cognitoidentityprovider.DescribeUserPoolClientInput{
+				UserPoolId: &a.poolID,
+				ClientId:   summary.ClientId,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to describe app client %s: %w", stringValue(summary.ClientId), err)
+			}
+			if describeOutput.UserPoolClient == nil {
+				continue
+			}
+
+			a.clients = append(a.clients, newCognitoAppClient(describeOutput.UserPoolClient))
+		}
+	}
+
+	return nil
+}
+
+// newCognitoAppClient flattens an app client description
+func newCognitoAppClient(appClient *cognitotypes.UserPoolClientType) CognitoAppClient {
+	flows := make([]string, 0, len(appClient.AllowedOAuthFlows))
+	for _, flow := range appClient.AllowedOAuthFlows {
+		flows = append(flows, string(flow))
+	}
+
+	var units cognitotypes.TokenValidityUnitsType
+	if appClient.TokenValidityUnits != nil {
+		units = *appClient.TokenValidityUnits
+	}
+
+	return CognitoAppClient{
+		ID:           stringValue(appClient.ClientId),
+		Name:         stringValue(appClient.ClientName),
+		OAuthFlows:   strings.Join(flows, ", "),
+		Scopes:       strings.Join(appClient.AllowedOAuthScopes, ", "),
+		CallbackURLs: strings.Join(appClient.CallbackURLs, ", "),
+		TokenValidity: strings.Join([]string{
+			formatTokenValidity(appClient.AccessTokenValidity, units.AccessToken, cognitotypes.TimeUnitsTypeHours),
+			formatTokenValidity(appClient.IdTokenValidity, units.IdToken, cognitotypes.TimeUnitsTypeHours),
+			formatTokenValidity(&appClient.RefreshTokenValidity, units.RefreshToken, cognitotypes.TimeUnitsTypeDays),
+		}, "/"),
+		Secret: stringValue(appClient.ClientSecret),
+	}
+}
+
+// formatTokenValidity formats a token validity with its unit, Cognito defaults to
+// 1 hour for access and ID tokens and 30 days for refresh tokens
+func formatTokenValidity(value *int32, unit, defaultUnit cognitotypes.TimeUnitsType) string {
+	if unit == "" {
+		unit = defaultUnit
+	}
+
+	validity := ptrInt32Value(value)
+	if validity == 0 {
+		if defaultUnit == cognitotypes.TimeUnitsTypeDays {
+			return "30d"
+		}
+		return "1h"
+	}
+
+	switch unit {
+	case cognitotypes.TimeUnitsTypeSeconds:
+		return fmt.Sprintf("%ds", validity)
+	case cognitotypes.TimeUnitsTypeMinutes:
+		return fmt.Sprintf("%dm", validity)
+	case cognitotypes.TimeUnitsTypeDays:
+		return fmt.Sprintf("%dd", validity)
+	default:
+		return fmt.Sprintf("%dh", validity)
+	}
+}
+
+// Rows returns the table data, secrets stay masked until toggled
+func (a *CognitoAppClients) Rows() [][]string {
+	rows := make([][]string, len(a.clients))
+	for i, appClient := range a.clients {
+		secret := appClient.Secret
+		switch {
+		case secret == "":
+			secret = "none"
+		case !a.showSecrets:
+			secret = strings.Repeat("•", 8)
+		}
+
+		rows[i] = []string{
+			appClient.Name,
+			appClient.ID,
+			appClient.OAuthFlows,
+			appClient.Scopes,
+			appClient.CallbackURLs,
+			appClient.TokenValidity,
+			secret,
+		}
+	}
+	return rows
+}
+
+// GetID returns the client ID at the given index
+func (a *CognitoAppClients) GetID(index int) string {
+	if index >= 0 && index < len(a.clients) {
+		return a.clients[index].ID
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for Cognito app clients
+func (a *CognitoAppClients) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:         's',
+			Label:       "secrets",
+			Description: "Toggle client secrets",
+			Toggle: func() {
+				a.showSecrets = !a.showSecrets
+			},
+		},
+		{
+			Key:            'y',
+			Label:          "copy secret",
+			Description:    "Copy client secret",
+			NeedsSelection: true,
+			Clipboard:      true,
+			TextHandler: func(ctx context.Context, c *client.Client, clientID string) (string, error) {
+				for _, appClient := range a.clients {
+					if appClient.ID != clientID {
+						continue
+					}
+					if appClient.Secret == "" {
+						return "", fmt.Errorf("app client %s has no secret", appClient.Name)
+					}
+					return appClient.Secret, nil
+				}
+				return "", fmt.Errorf("app client %s is not listed", clientID)
+			},
+		},
+	}
 }
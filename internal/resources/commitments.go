@@ -0,0 +1,277 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	cetypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/savingsplans"
+	sptypes "github.com/aws/aws-sdk-go-v2/service/savingsplans/types"
+)
+
+const (
+	// commitmentExpiryWarning is the time before expiry from which commitments are highlighted
+	commitmentExpiryWarning = 30 * 24 * time.Hour
+
+	// commitmentUsageWindow is the period of the utilization and coverage figures
+	commitmentUsageWindow = 30 * 24 * time.Hour
+)
+
+// Commitment represents an active Reserved Instance or Savings Plan
+type Commitment struct {
+	Kind        string
+	ID          string
+	ARN         string
+	Description string
+	Term        string
+	Payment     string
+	End         time.Time
+	Utilization string
+}
+
+// Commitments implements Resource for the active Reserved Instances and Savings Plans
+type Commitments struct {
+	commitments []Commitment
+	riCoverage  string
+	spCoverage  string
+}
+
+// NewCommitments creates a new Commitments resource
+func NewCommitments() *Commitments {
+	return &Commitments{
+		commitments: make([]Commitment, 0),
+	}
+}
+
+// Name returns the display name
+func (m *Commitments) Name() string {
+	return fmt.Sprintf("Commitments (RI coverage %s, SP coverage %s)", m.riCoverage, m.spCoverage)
+}
+
+// Columns returns the column definitions
+func (m *Commitments) Columns() []Column {
+	return []Column{
+		{Name: "Kind", Width: 6},
+		{Name: "ID", Width: 38},
+		{Name: "Description", Width: 30},
+		{Name: "Term", Width: 6},
+		{Name: "Payment", Width: 16},
+		{Name: "Expires", Width: 12},
+		{Name: "Days Left", Width: 10},
+		{Name: "Utilization", Width: 12},
+	}
+}
+
+// Fetch retrieves the active commitments, then their utilization and coverage
+// over the last 30 days from Cost Explorer
+func (m *Commitments) Fetch(ctx context.Context, c *client.Client) error {
+	m.commitments = make([]Commitment, 0)
+
+	riOutput, err := c.EC2().DescribeReservedInstances(ctx, &ec2.DescribeReservedInstancesInput{
+		Filters: []ec2types.Filter{{Name: aws.String("state"), Values: []string{"active"}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list reserved instances: %w", err)
+	}
+	for _, ri := range riOutput.ReservedInstances {
+		commitment := Commitment{
+			Kind:        "RI",
+			ID:          stringValue(ri.ReservedInstancesId),
+			Description: fmt.Sprintf("%d x %s", ptrInt32Value(ri.InstanceCount), ri.InstanceType),
+			Term:        formatTerm(ptrInt64Value(ri.Duration)),
+			Payment:     string(ri.OfferingType),
+		}
+		if ri.End != nil {
+			commitment.End = *ri.End
+		}
+		m.commitments = append(m.commitments, commitment)
+	}
+
+	spOutput, err := c.SavingsPlans().DescribeSavingsPlans(ctx, &savingsplans.DescribeSavingsPlansInput{
+		States: []sptypes.SavingsPlanState{sptypes.SavingsPlanStateActive},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list savings plans: %w", err)
+	}
+	for _, plan := range spOutput.SavingsPlans {
+		commitment := Commitment{
+			Kind:        "SP",
+			ID:          stringValue(plan.SavingsPlanId),
+			ARN:         stringValue(plan.SavingsPlanArn),
+			Description: fmt.Sprintf("%s %s/h", plan.SavingsPlanType, stringValue(plan.Commitment)),
+			Term:        formatTerm(plan.TermDurationInSeconds),
+			Payment:     string(plan.PaymentOption),
+		}
+		if end, err := time.Parse(time.RFC3339, stringValue(plan.End)); err == nil {
+			commitment.End = end
+		}
+		m.commitments = append(m.commitments, commitment)
+	}
+
+	sort.Slice(m.commitments, func(i, j int) bool {
+		return m.commitments[i].End.Before(m.commitments[j].End)
+	})
+
+	// Cost Explorer may be unavailable to the account, the commitments are still listed
+	now := time.Now()
+	period := &cetypes.DateInterval{
+		Start: aws.String(now.Add(-commitmentUsageWindow).Format("2006-01-02")),
+		End:   aws.String(now.Format("2006-01-02")),
+	}
+	utilizations := commitmentUtilizations(ctx, c, period)
+	for i := range m.commitments {
+		key := m.commitments[i].ID
+		if m.commitments[i].ARN != "" {
+			key = m.commitments[i].ARN
+		}
+		m.commitments[i].Utilization = "n/a"
+		if value, ok := utilizations[key]; ok {
+			m.commitments[i].Utilization = value + "%"
+		}
+	}
+	m.riCoverage, m.spCoverage = commitmentCoverage(ctx, c, period)
+
+	return nil
+}
+
+// commitmentUtilizations returns the utilization percentages of the reservations by ID
+// and of the savings plans by ARN, those Cost Explorer cannot provide are missing
+func commitmentUtilizations(ctx context.Context, c *client.Client, period *cetypes.DateInterval) map[string]string {
+	utilizations := make(map[string]string)
+
+	riOutput, err := c.CostExplorer().GetReservationUtilization(ctx, &costexplorer.GetReservationUtilizationInput{
+		TimePeriod: period,
+		GroupBy: []cetypes.GroupDefinition{{
+			Type: cetypes.GroupDefinitionTypeDimension,
+			Key:  aws.String("SUBSCRIPTION_ID"),
+		}},
+	})
+	if err == nil {
+		for _, byTime := range riOutput.UtilizationsByTime {
+			for _, group := range byTime.Groups {
+				if group.Utilization == nil {
+					continue
+				}
+				id := group.Attributes["leaseId"]
+				if id == "" {
+					id = aws.ToString(group.Value)
+				}
+				utilizations[id] = aws.ToString(group.Utilization.UtilizationPercentage)
+			}
+		}
+	}
+
+	spPaginator := costexplorer.NewGetSavingsPlansUtilizationDetailsPaginator(c.CostExplorer(), &costexplorer.GetSavingsPlansUtilizationDetailsInput{
+		TimePeriod: period,
+	})
+	for spPaginator.HasMorePages() {
+		output, err := spPaginator.NextPage(ctx)
+		if err != nil {
+			break
+		}
+		for _, detail := range output.SavingsPlansUtilizationDetails {
+			if detail.Utilization != nil {
+				utilizations[aws.ToString(detail.SavingsPlanArn)] = aws.ToString(detail.Utilization.UtilizationPercentage)
+			}
+		}
+	}
+
+	return utilizations
+}
+
+// commitmentCoverage returns the share of the eligible usage covered by reservations
+// and by savings plans, "n/a" when Cost Explorer cannot provide it
+func commitmentCoverage(ctx context.Context, c *client.Client, period *cetypes.DateInterval) (string, string) {
+	riCoverage, spCoverage := "n/a", "n/a"
+
+	riOutput, err := c.CostExplorer().GetReservationCoverage(ctx, &costexplorer.GetReservationCoverageInput{
+		TimePeriod: period,
+	})
+	if err == nil && riOutput.Total != nil && riOutput.Total.CoverageHours != nil {
+		riCoverage = aws.ToString(riOutput.Total.CoverageHours.CoverageHoursPercentage) + "%"
+	}
+
+	spOutput, err := c.CostExplorer().GetSavingsPlansCoverage(ctx, &costexplorer.GetSavingsPlansCoverageInput{
+		TimePeriod:  period,
+		Granularity: cetypes.GranularityMonthly,
+	})
+	if err == nil {
+		var covered, total float64
+		for _, coverage := range spOutput.SavingsPlansCoverages {
+			if coverage.Coverage == nil {
+				continue
+			}
+			spend, _ := strconv.ParseFloat(aws.ToString(coverage.Coverage.SpendCoveredBySavingsPlans), 64)
+			cost, _ := strconv.ParseFloat(aws.ToString(coverage.Coverage.TotalCost), 64)
+			covered += spend
+			total += cost
+		}
+		if total > 0 {
+			spCoverage = fmt.Sprintf("%.1f%%", covered/total*100)
+		}
+	}
+
+	return riCoverage, spCoverage
+}
+
+// formatTerm formats a commitment term in years when it is a whole number of them
+func formatTerm(seconds int64) string {
+	const year = 365 * 24 * 3600
+	if seconds > 0 && seconds%year == 0 {
+		return fmt.Sprintf("%dy", seconds/year)
+	}
+	return fmt.Sprintf("%dd", seconds/(24*3600))
+}
+
+// Rows returns the table data
+func (m *Commitments) Rows() [][]string {
+	rows := make([][]string, len(m.commitments))
+	for i, commitment := range m.commitments {
+		expires, daysLeft := "", ""
+		if !commitment.End.IsZero() {
+			expires = commitment.End.Format("2006-01-02")
+			daysLeft = fmt.Sprintf("%d", int(time.Until(commitment.End).Hours()/24))
+		}
+		rows[i] = []string{
+			commitment.Kind,
+			commitment.ID,
+			commitment.Description,
+			commitment.Term,
+			commitment.Payment,
+			expires,
+			daysLeft,
+			commitment.Utilization,
+		}
+	}
+	return rows
+}
+
+// GetID returns the reservation or savings plan ID at the given index
+func (m *Commitments) GetID(index int) string {
+	if index >= 0 && index < len(m.commitments) {
+		return m.commitments[index].ID
+	}
+	return ""
+}
+
+// Highlight reports whether the commitment expires within 30 days
+func (m *Commitments) Highlight(index int) bool {
+	if index < 0 || index >= len(m.commitments) || m.commitments[index].End.IsZero() {
+		return false
+	}
+	return time.Until(m.commitments[index].End) < commitmentExpiryWarning
+}
+
+// QuickActions returns the available quick actions for commitments
+func (m *Commitments) QuickActions() []QuickAction {
+	return []QuickAction{}
+}
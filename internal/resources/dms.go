@@ -0,0 +1,467 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	dms "github.com/aws/aws-sdk-go-v2/service/databasemigrationservice"
+	dmstypes "github.com/aws/aws-sdk-go-v2/service/databasemigrationservice/types"
+)
+
+// DMSReplicationInstance represents a DMS replication instance
+type DMSReplicationInstance struct {
+	ARN              string
+	ID               string
+	Class            string
+	Status           string
+	EngineVersion    string
+	Storage          int32
+	MultiAZ          bool
+	AvailabilityZone string
+	Created          string
+}
+
+// DMSReplicationInstances implements Resource for DMS replication instances
+type DMSReplicationInstances struct {
+	instances []DMSReplicationInstance
+}
+
+// NewDMSReplicationInstances creates a new DMSReplicationInstances resource
+func NewDMSReplicationInstances() *DMSReplicationInstances {
+	return &DMSReplicationInstances{
+		instances: make([]DMSReplicationInstance, 0),
+	}
+}
+
+// Name returns the display name
+func (d *DMSReplicationInstances) Name() string {
+	return "DMS Replication Instances"
+}
+
+// Columns returns the column definitions
+func (d *DMSReplicationInstances) Columns() []Column {
+	return []Column{
+		{Name: "Identifier", Width: 35},
+		{Name: "Class", Width: 18},
+		{Name: "Status", Width: 14},
+		{Name: "Engine", Width: 10},
+		{Name: "Storage", Width: 8},
+		{Name: "Multi-AZ", Width: 8},
+		{Name: "AZ", Width: 15},
+		{Name: "Created", Width: 20},
+	}
+}
+
+// Fetch retrieves the replication instances
+func (d *DMSReplicationInstances) Fetch(ctx context.Context, c *client.Client) error {
+	d.instances = make([]DMSReplicationInstance, 0)
+
+	paginator := dms.NewDescribeReplicationInstancesPaginator(c.DMS(), &dms.DescribeReplicationInstancesInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe replication instances: %w", err)
+		}
+
+		for _, instance := range output.ReplicationInstances {
+			inst := DMSReplicationInstance{
+				ARN:              stringValue(instance.ReplicationInstanceArn),
+				ID:               stringValue(instance.ReplicationInstanceIdentifier),
+				Class:            stringValue(instance.ReplicationInstanceClass),
+				Status:           stringValue(instance.ReplicationInstanceStatus),
+				EngineVersion:    stringValue(instance.EngineVersion),
+				Storage:          instance.AllocatedStorage,
+				MultiAZ:          instance.MultiAZ,
+				AvailabilityZone: stringValue(instance.AvailabilityZone),
+			}
+			if instance.InstanceCreateTime != nil {
+				inst.Created = instance.InstanceCreateTime.Format("2006-01-02 15:04:05")
+			}
+			d.instances = append(d.instances, inst)
+		}
+	}
+
+	return nil
+}
+
+// Rows returns the table data
+func (d *DMSReplicationInstances) Rows() [][]string {
+	rows := make([][]string, len(d.instances))
+	for i, inst := range d.instances {
+		multiAZ := "no"
+		if inst.MultiAZ {
+			multiAZ = "yes"
+		}
+		rows[i] = []string{
+			inst.ID,
+			inst.Class,
+			inst.Status,
+			inst.EngineVersion,
+			fmt.Sprintf("%d GiB", inst.Storage),
+			multiAZ,
+			inst.AvailabilityZone,
+			inst.Created,
+		}
+	}
+	return rows
+}
+
+// GetID returns the replication instance ARN at the given index
+func (d *DMSReplicationInstances) GetID(index int) string {
+	if index >= 0 && index < len(d.instances) {
+		return d.instances[index].ARN
+	}
+	return ""
+}
+
+// DrillDown returns the replication tasks running on an instance
+func (d *DMSReplicationInstances) DrillDown(instanceARN string) Resource {
+	name := instanceARN
+	for _, inst := range d.instances {
+		if inst.ARN == instanceARN {
+			name = inst.ID
+			break
+		}
+	}
+	return NewDMSReplicationTasks(instanceARN, name)
+}
+
+// QuickActions returns the available quick actions for replication instances
+func (d *DMSReplicationInstances) QuickActions() []QuickAction {
+	return []QuickAction{}
+}
+
+// DMSReplicationTask represents a DMS replication task with its statistics
+type DMSReplicationTask struct {
+	ARN           string
+	ID            string
+	Status        string
+	MigrationType string
+	Progress      int32
+	TablesLoaded  int32
+	TablesLoading int32
+	TablesQueued  int32
+	TablesErrored int32
+	Started       string
+	Reason        string
+}
+
+// DMSReplicationTasks implements Resource for the DMS replication tasks, all of
+// them or the ones of a replication instance
+type DMSReplicationTasks struct {
+	instanceARN  string
+	instanceName string
+	tasks        []DMSReplicationTask
+}
+
+// NewDMSReplicationTasks creates a new DMSReplicationTasks resource, an empty
+// instance ARN lists the tasks of all instances
+func NewDMSReplicationTasks(instanceARN, instanceName string) *DMSReplicationTasks {
+	return &DMSReplicationTasks{
+		instanceARN:  instanceARN,
+		instanceName: instanceName,
+		tasks:        make([]DMSReplicationTask, 0),
+	}
+}
+
+// Name returns the display name
+func (d *DMSReplicationTasks) Name() string {
+	if d.instanceARN == "" {
+		return "DMS Replication Tasks"
+	}
+	return fmt.Sprintf("DMS Replication Tasks (%s)", d.instanceName)
+}
+
+// Columns returns the column definitions
+func (d *DMSReplicationTasks) Columns() []Column {
+	return []Column{
+		{Name: "Identifier", Width: 35},
+		{Name: "Status", Width: 12},
+		{Name: "Type", Width: 18},
+		{Name: "Progress", Width: 9},
+		{Name: "Loaded", Width: 7},
+		{Name: "Loading", Width: 8},
+		{Name: "Queued", Width: 7},
+		{Name: "Errored", Width: 8},
+		{Name: "Started", Width: 20},
+		{Name: "Reason", Width: 50},
+	}
+}
+
+// Fetch retrieves the replication tasks with their statistics
+func (d *DMSReplicationTasks) Fetch(ctx context.Context, c *client.Client) error {
+	d.tasks = make([]DMSReplicationTask, 0)
+
+	input := &dms.DescribeReplicationTasksInput{
+		WithoutSettings: aws.Bool(true),
+	}
+	if d.instanceARN != "" {
+		input.Filters = []dmstypes.Filter{{
+			Name:   aws.String("replication-instance-arn"),
+			Values: []string{d.instanceARN},
+		}}
+	}
+
+	paginator := dms.NewDescribeReplicationTasksPaginator(c.DMS(), input)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe replication tasks: %w", err)
+		}
+
+		for _, task := range output.ReplicationTasks {
+			t := DMSReplicationTask{
+				ARN:           stringValue(task.ReplicationTaskArn),
+				ID:            stringValue(task.ReplicationTaskIdentifier),
+				Status:        stringValue(task.Status),
+				MigrationType: string(task.MigrationType),
+				Reason:        stringValue(task.LastFailureMessage),
+			}
+			if t.Reason == "" {
+				t.Reason = stringValue(task.StopReason)
+			}
+			if stats := task.ReplicationTaskStats; stats != nil {
+				t.Progress = stats.FullLoadProgressPercent
+				t.TablesLoaded = stats.TablesLoaded
+				t.TablesLoading = stats.TablesLoading
+				t.TablesQueued = stats.TablesQueued
+				t.TablesErrored = stats.TablesErrored
+				if stats.StartDate != nil {
+					t.Started = stats.StartDate.Format("2006-01-02 15:04:05")
+				}
+			}
+			d.tasks = append(d.tasks, t)
+		}
+	}
+
+	return nil
+}
+
+// Rows returns the table data
+func (d *DMSReplicationTasks) Rows() [][]string {
+	rows := make([][]string, len(d.tasks))
+	for i, task := range d.tasks {
+		rows[i] = []string{
+			task.ID,
+			task.Status,
+			task.MigrationType,
+			fmt.Sprintf("%d%%", task.Progress),
+			fmt.Sprintf("%d", task.TablesLoaded),
+			fmt.Sprintf("%d", task.TablesLoading),
+			fmt.Sprintf("%d", task.TablesQueued),
+			fmt.Sprintf("%d", task.TablesErrored),
+			task.Started,
+			task.Reason,
+		}
+	}
+	return rows
+}
+
+// GetID returns the replication task ARN at the given index
+func (d *DMSReplicationTasks) GetID(index int) string {
+	if index >= 0 && index < len(d.tasks) {
+		return d.tasks[index].ARN
+	}
+	return ""
+}
+
+// Highlight flags the failed tasks and the ones with tables in error
+func (d *DMSReplicationTasks) Highlight(index int) bool {
+	if index < 0 || index >= len(d.tasks) {
+		return false
+	}
+	return d.tasks[index].Status == "failed" || d.tasks[index].TablesErrored > 0
+}
+
+// DrillDown returns the table statistics of a task
+func (d *DMSReplicationTasks) DrillDown(taskARN string) Resource {
+	name := taskARN
+	for _, task := range d.tasks {
+		if task.ARN == taskARN {
+			name = task.ID
+			break
+		}
+	}
+	return NewDMSTableStatistics(taskARN, name)
+}
+
+// QuickActions returns the available quick actions for replication tasks
+func (d *DMSReplicationTasks) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:             'S',
+			Label:           "resume",
+			Description:     "Resume replication task",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "[green]resume[-] replication task [white]%s[-]?",
+			Handler:         d.ResumeTask,
+		},
+		{
+			Key:             's',
+			Label:           "stop",
+			Description:     "Stop replication task",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "[red]stop[-] replication task [white]%s[-]?",
+			Handler:         d.StopTask,
+		},
+	}
+}
+
+// ResumeTask resumes a stopped replication task where it left off
+func (d *DMSReplicationTasks) ResumeTask(ctx context.Context, c *client.Client, taskARN string) error {
+	_, err := c.DMS().StartReplicationTask(ctx, &dms.StartReplicationTaskInput{
+		ReplicationTaskArn:       &taskARN,
+		StartReplicationTaskType: dmstypes.StartReplicationTaskTypeValueResumeProcessing,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to resume replication task: %w", err)
+	}
+	return nil
+}
+
+// StopTask stops a running replication task
+func (d *DMSReplicationTasks) StopTask(ctx context.Context, c *client.Client, taskARN string) error {
+	_, err := c.DMS().StopReplicationTask(ctx, &dms.StopReplicationTaskInput{
+		ReplicationTaskArn: &taskARN,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stop replication task: %w", err)
+	}
+	return nil
+}
+
+// DMSTableStatistic represents the replication statistics of a table
+type DMSTableStatistic struct {
+	Schema       string
+	Table        string
+	State        string
+	FullLoadRows int64
+	ErrorRows    int64
+	Inserts      int64
+	Updates      int64
+	Deletes      int64
+	DDLs         int64
+	Validation   string
+	LastUpdated  string
+}
+
+// DMSTableStatistics implements Resource for the table statistics of a replication task
+type DMSTableStatistics struct {
+	taskARN  string
+	taskName string
+	tables   []DMSTableStatistic
+}
+
+// NewDMSTableStatistics creates a new DMSTableStatistics resource
+func NewDMSTableStatistics(taskARN, taskName string) *DMSTableStatistics {
+	return &DMSTableStatistics{
+		taskARN:  taskARN,
+		taskName: taskName,
+		tables:   make([]DMSTableStatistic, 0),
+	}
+}
+
+// Name returns the display name
+func (d *DMSTableStatistics) Name() string {
+	return fmt.Sprintf("DMS Table Statistics (%s)", d.taskName)
+}
+
+// Columns returns the column definitions
+func (d *DMSTableStatistics) Columns() []Column {
+	return []Column{
+		{Name: "Schema", Width: 20},
+		{Name: "Table", Width: 30},
+		{Name: "State", Width: 22},
+		{Name: "Full Load Rows", Width: 14},
+		{Name: "Error Rows", Width: 10},
+		{Name: "Inserts", Width: 10},
+		{Name: "Updates", Width: 10},
+		{Name: "Deletes", Width: 10},
+		{Name: "DDLs", Width: 6},
+		{Name: "Validation", Width: 14},
+		{Name: "Last Updated", Width: 20},
+	}
+}
+
+// Fetch retrieves the table statistics of the task
+func (d *DMSTableStatistics) Fetch(ctx context.Context, c *client.Client) error {
+	d.tables = make([]DMSTableStatistic, 0)
+
+	paginator := dms.NewDescribeTableStatisticsPaginator(c.DMS(), &dms.DescribeTableStatisticsInput{
+		ReplicationTaskArn: &d.taskARN,
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe table statistics: %w", err)
+		}
+
+		for _, table := range output.TableStatistics {
+			t := DMSTableStatistic{
+				Schema:       stringValue(table.SchemaName),
+				Table:        stringValue(table.TableName),
+				State:        stringValue(table.TableState),
+				FullLoadRows: table.FullLoadRows,
+				ErrorRows:    table.FullLoadErrorRows,
+				Inserts:      table.Inserts,
+				Updates:      table.Updates,
+				Deletes:      table.Deletes,
+				DDLs:         table.Ddls,
+				Validation:   stringValue(table.ValidationState),
+			}
+			if table.LastUpdateTime != nil {
+				t.LastUpdated = table.LastUpdateTime.Format("2006-01-02 15:04:05")
+			}
+			d.tables = append(d.tables, t)
+		}
+	}
+
+	return nil
+}
+
+// Rows returns the table data
+func (d *DMSTableStatistics) Rows() [][]string {
+	rows := make([][]string, len(d.tables))
+	for i, table := range d.tables {
+		rows[i] = []string{
+			table.Schema,
+			table.Table,
+			table.State,
+			fmt.Sprintf("%d", table.FullLoadRows),
+			fmt.Sprintf("%d", table.ErrorRows),
+			fmt.Sprintf("%d", table.Inserts),
+			fmt.Sprintf("%d", table.Updates),
+			fmt.Sprintf("%d", table.Deletes),
+			fmt.Sprintf("%d", table.DDLs),
+			table.Validation,
+			table.LastUpdated,
+		}
+	}
+	return rows
+}
+
+// GetID returns the schema qualified table name at the given index
+func (d *DMSTableStatistics) GetID(index int) string {
+	if index >= 0 && index < len(d.tables) {
+		return d.tables[index].Schema + "." + d.tables[index].Table
+	}
+	return ""
+}
+
+// Highlight flags the tables in error
+func (d *DMSTableStatistics) Highlight(index int) bool {
+	if index < 0 || index >= len(d.tables) {
+		return false
+	}
+	return d.tables[index].State == "Table error" || d.tables[index].ErrorRows > 0
+}
+
+// QuickActions returns the available quick actions for table statistics
+func (d *DMSTableStatistics) QuickActions() []QuickAction {
+	return []QuickAction{}
+}
@@ -0,0 +1,264 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	route53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+)
+
+// dnsLookupTypes are the record types resolved by a DNS lookup
+var dnsLookupTypes = []route53types.RRType{
+	route53types.RRTypeA,
+	route53types.RRTypeAaaa,
+	route53types.RRTypeCname,
+	route53types.RRTypeMx,
+	route53types.RRTypeTxt,
+}
+
+// DNSAnswer represents the local and Route53 answers for a record type
+type DNSAnswer struct {
+	Type    string
+	Local   []string
+	Route53 []string
+	Status  string
+}
+
+// DNSLookup implements Resource for the answers of a hostname from the local
+// resolver and from the Route53 hosted zone serving it
+type DNSLookup struct {
+	hostname string
+	zone     string
+	answers  []DNSAnswer
+}
+
+// NewDNSLookup creates a new DNSLookup resource
+func NewDNSLookup(hostname string) *DNSLookup {
+	return &DNSLookup{
+		hostname: strings.TrimSuffix(strings.ToLower(hostname), "."),
+		answers:  make([]DNSAnswer, 0),
+	}
+}
+
+// Name returns the display name
+func (d *DNSLookup) Name() string {
+	if d.zone == "" {
+		return fmt.Sprintf("DNS Lookup (%s)", d.hostname)
+	}
+	return fmt.Sprintf("DNS Lookup (%s in %s)", d.hostname, d.zone)
+}
+
+// Columns returns the column definitions
+func (d *DNSLookup) Columns() []Column {
+	return []Column{
+		{Name: "Type", Width: 8},
+		{Name: "Local", Width: 50},
+		{Name: "Route53", Width: 50},
+		{Name: "Status", Width: 14},
+	}
+}
+
+// Fetch resolves the hostname with the local resolver and asks Route53 for its answer
+func (d *DNSLookup) Fetch(ctx context.Context, c *client.Client) error {
+	d.answers = make([]DNSAnswer, 0)
+
+	zone, err := d.findZone(ctx, c)
+	if err != nil {
+		return err
+	}
+	d.zone = ""
+	if zone != nil {
+		d.zone = strings.TrimSuffix(stringValue(zone.Name), ".")
+	}
+
+	for _, recordType := range dnsLookupTypes {
+		answer := DNSAnswer{
+			Type:  string(recordType),
+			Local: lookupLocal(ctx, d.hostname, recordType),
+		}
+
+		if zone != nil {
+			answer.Route53, err = d.lookupRoute53(ctx, c, zone, recordType)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(answer.Local) == 0 && len(answer.Route53) == 0 {
+			continue
+		}
+
+		switch {
+		case zone == nil:
+			answer.Status = "not hosted"
+		case len(answer.Route53) == 0:
+			answer.Status = "local only"
+		case len(answer.Local) == 0:
+			answer.Status = "route53 only"
+		case strings.Join(answer.Local, ",") == strings.Join(answer.Route53, ","):
+			answer.Status = "match"
+		default:
+			answer.Status = "differs"
+		}
+
+		d.answers = append(d.answers, answer)
+	}
+
+	return nil
+}
+
+// findZone returns the most specific hosted zone of the account serving the hostname
+func (d *DNSLookup) findZone(ctx context.Context, c *client.Client) (*route53types.HostedZone, error) {
+	var found *route53types.HostedZone
+	foundName := ""
+
+	paginator := route53.NewListHostedZonesPaginator(c.Route53(), &route53.ListHostedZonesInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list Route53 hosted zones: %w", err)
+		}
+
+		for _, zone := range output.HostedZones {
+			name := strings.TrimSuffix(strings.ToLower(stringValue(zone.Name)), ".")
+			if d.hostname != name && !strings.HasSuffix(d.hostname, "."+name) {
+				continue
+			}
+			if found == nil || len(name) > len(foundName) {
+				found = &zone
+				foundName = name
+			}
+		}
+	}
+
+	return found, nil
+}
+
+// lookupRoute53 returns the Route53 answer for a record type, public zones are asked
+// what they would answer and private zones for their record values
+func (d *DNSLookup) lookupRoute53(ctx context.Context, c *client.Client, zone *route53types.HostedZone, recordType route53types.RRType) ([]string, error) {
+	if zone.Config != nil && zone.Config.PrivateZone {
+		output, err := c.Route53().ListResourceRecordSets(ctx, &route53.ListResourceRecordSetsInput{
+			HostedZoneId:    zone.Id,
+			StartRecordName: aws.String(d.hostname),
+			StartRecordType: recordType,
+			MaxItems:        aws.Int32(1),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list records of %s: %w", d.hostname, err)
+		}
+
+		values := make([]string, 0)
+		for _, record := range output.ResourceRecordSets {
+			if recordName(stringValue(record.Name)) != d.hostname+"." || record.Type != recordType {
+				continue
+			}
+			if record.AliasTarget != nil {
+				values = append(values, "alias "+stringValue(record.AliasTarget.DNSName))
+			}
+			for _, value := range record.ResourceRecords {
+				values = append(values, stringValue(value.Value))
+			}
+		}
+		return normalizeDNSValues(values), nil
+	}
+
+	output, err := c.Route53().TestDNSAnswer(ctx, &route53.TestDNSAnswerInput{
+		HostedZoneId: zone.Id,
+		RecordName:   aws.String(d.hostname),
+		RecordType:   recordType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to test the %s answer of %s: %w", recordType, d.hostname, err)
+	}
+	return normalizeDNSValues(output.RecordData), nil
+}
+
+// lookupLocal resolves a record type with the local resolver, failures are empty answers
+func lookupLocal(ctx context.Context, hostname string, recordType route53types.RRType) []string {
+	values := make([]string, 0)
+	resolver := net.DefaultResolver
+
+	switch recordType {
+	case route53types.RRTypeA, route53types.RRTypeAaaa:
+		network := "ip4"
+		if recordType == route53types.RRTypeAaaa {
+			network = "ip6"
+		}
+		ips, _ := resolver.LookupIP(ctx, network, hostname)
+		for _, ip := range ips {
+			values = append(values, ip.String())
+		}
+	case route53types.RRTypeCname:
+		cname, err := resolver.LookupCNAME(ctx, hostname)
+		if err == nil && !strings.EqualFold(strings.TrimSuffix(cname, "."), hostname) {
+			values = append(values, cname)
+		}
+	case route53types.RRTypeMx:
+		records, _ := resolver.LookupMX(ctx, hostname)
+		for _, mx := range records {
+			values = append(values, fmt.Sprintf("%d %s", mx.Pref, mx.Host))
+		}
+	case route53types.RRTypeTxt:
+		values, _ = resolver.LookupTXT(ctx, hostname)
+	}
+
+	return normalizeDNSValues(values)
+}
+
+// normalizeDNSValues makes answers comparable: lowercase hostnames without trailing
+// dots, unquoted texts, sorted
+func normalizeDNSValues(values []string) []string {
+	normalized := make([]string, 0, len(values))
+	for _, value := range values {
+		if strings.HasPrefix(value, `"`) {
+			normalized = append(normalized, strings.ReplaceAll(strings.Trim(value, `"`), `" "`, ""))
+			continue
+		}
+		normalized = append(normalized, strings.TrimSuffix(strings.ToLower(value), "."))
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// Rows returns the table data
+func (d *DNSLookup) Rows() [][]string {
+	rows := make([][]string, len(d.answers))
+	for i, answer := range d.answers {
+		rows[i] = []string{
+			answer.Type,
+			strings.Join(answer.Local, ", "),
+			strings.Join(answer.Route53, ", "),
+			answer.Status,
+		}
+	}
+	return rows
+}
+
+// GetID returns the record type at the given index
+func (d *DNSLookup) GetID(index int) string {
+	if index >= 0 && index < len(d.answers) {
+		return d.answers[index].Type
+	}
+	return ""
+}
+
+// Highlight reports whether the local and Route53 answers differ
+func (d *DNSLookup) Highlight(index int) bool {
+	if index < 0 || index >= len(d.answers) {
+		return false
+	}
+	return d.answers[index].Status == "differs"
+}
+
+// QuickActions returns the available quick actions for DNS lookups
+func (d *DNSLookup) QuickActions() []QuickAction {
+	return []QuickAction{}
+}
@@ -0,0 +1,98 @@
+package resources
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// envKey turns a parameter name or JSON key into an environment variable name
+func envKey(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	key := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, name)
+	if key != "" && key[0] >= '0' && key[0] <= '9' {
+		key = "_" + key
+	}
+	return key
+}
+
+// envVariables are the environment variables to export, with the names they are made from
+type envVariables struct {
+	values  map[string]string
+	sources map[string]string
+	clashes []string
+}
+
+// newEnvVariables returns an empty set of environment variables
+func newEnvVariables() *envVariables {
+	return &envVariables{values: make(map[string]string), sources: make(map[string]string)}
+}
+
+// set adds the variable made from a parameter name or JSON key, noting the names turned
+// into the same variable
+func (v *envVariables) set(name, value string) {
+	key := envKey(name)
+	if source, ok := v.sources[key]; ok {
+		v.clashes = append(v.clashes, fmt.Sprintf("%s and %s", source, name))
+		return
+	}
+	v.sources[key] = name
+	v.values[key] = value
+}
+
+// writeEnvFile writes variables to a new file, as shell exports when its extension
+// is .sh and in the dotenv format otherwise
+func writeEnvFile(path string, variables *envVariables) error {
+	if len(variables.clashes) > 0 {
+		sort.Strings(variables.clashes)
+		return fmt.Errorf("several names give the same variable: %s", strings.Join(variables.clashes, ", "))
+	}
+	path, err := expandHome(path)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(variables.values))
+	for key := range variables.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	shell := strings.EqualFold(filepath.Ext(path), ".sh")
+	dotenv := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+	var b strings.Builder
+	for _, key := range keys {
+		value := variables.values[key]
+		if shell {
+			// Single quotes keep the value literal for the shell
+			fmt.Fprintf(&b, "export %s='%s'\n", key, strings.ReplaceAll(value, "'", `'\''`))
+			continue
+		}
+		fmt.Fprintf(&b, "%s=\"%s\"\n", key, dotenv.Replace(value))
+	}
+
+	// The file holds secrets, keep it private to the user and never overwrite an existing one
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if _, err := f.WriteString(b.String()); err != nil {
+		f.Close()
+		os.Remove(path)
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return f.Close()
+}
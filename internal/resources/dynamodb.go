@@ -1,12 +1,25 @@
 package resources
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"a9s/internal/client"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/applicationautoscaling"
+	aastypes "github.com/aws/aws-sdk-go-v2/service/applicationautoscaling/types"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamotypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
 // DynamoDBTable represents a DynamoDB table
@@ -154,5 +167,959 @@ func (d *DynamoDBTables) GetID(index int) string {
 
 // QuickActions returns the available quick actions for DynamoDB tables
 func (d *DynamoDBTables) QuickActions() []QuickAction {
-	return []QuickAction{}
+	return []QuickAction{
+		{
+			Key:            'i',
+			Label:          "indexes",
+			Description:    "Show indexes and capacity",
+			NeedsSelection: true,
+			View: func(tableName string) Resource {
+				return NewDynamoDBIndexes(tableName)
+			},
+		},
+		{
+			Key:            'Q',
+			Label:          "query",
+			Description:    "Query items",
+			NeedsSelection: true,
+			InputLabel:     dynamoQueryLabel,
+			InputView: func(tableName, query string) Resource {
+				return NewDynamoDBItems(tableName, query)
+			},
+		},
+	}
+}
+
+// DrillDown scans the items of the table
+func (d *DynamoDBTables) DrillDown(tableName string) Resource {
+	return NewDynamoDBItems(tableName, "")
+}
+
+// dynamoQueryLabel describes the query syntax, the sort key condition is optional
+const dynamoQueryLabel = "Partition key [| =, <, <=, >, >=, begins_with or between ... and ... sort key]: "
+
+// DynamoDBItem represents an item of a DynamoDB table
+type DynamoDBItem struct {
+	Key          string // JSON of the key attributes, identifies the item
+	PartitionKey string
+	SortKey      string
+	Item         map[string]any
+	Raw          map[string]dynamotypes.AttributeValue // Typed attributes, for editing
+}
+
+// DynamoDBItems implements Resource and Pageable for the items of a table,
+// scanned or queried by key
+type DynamoDBItems struct {
+	table string
+	query string
+	items []DynamoDBItem
+
+	// Key schema, read from the table on first fetch
+	partitionKey string
+	sortKey      string
+	keyTypes     map[string]dynamotypes.ScalarAttributeType
+
+	pages   int
+	lastKey map[string]dynamotypes.AttributeValue
+}
+
+// NewDynamoDBItems creates a new DynamoDBItems resource, an empty query scans the table
+func NewDynamoDBItems(table, query string) *DynamoDBItems {
+	return &DynamoDBItems{
+		table: table,
+		query: strings.TrimSpace(query),
+		items: make([]DynamoDBItem, 0),
+	}
+}
+
+// Name returns the display name
+func (d *DynamoDBItems) Name() string {
+	if d.query != "" {
+		return fmt.Sprintf("DynamoDB Items (%s: %s)", d.table, d.query)
+	}
+	return fmt.Sprintf("DynamoDB Items (%s)", d.table)
+}
+
+// Columns returns the column definitions
+func (d *DynamoDBItems) Columns() []Column {
+	return []Column{
+		{Name: "Partition Key", Width: 30},
+		{Name: "Sort Key", Width: 30},
+		{Name: "Item", Width: 100},
+	}
+}
+
+// Fetch retrieves the items from AWS, as many pages as already loaded
+func (d *DynamoDBItems) Fetch(ctx context.Context, c *client.Client) error {
+	d.items = make([]DynamoDBItem, 0)
+	d.lastKey = nil
+
+	if d.keyTypes == nil {
+		if err := d.describe(ctx, c); err != nil {
+			return err
+		}
+	}
+
+	pages := max(d.pages, 1)
+	d.pages = 0
+	for page := 0; page < pages; page++ {
+		if err := d.fetchPage(ctx, c); err != nil {
+			return err
+		}
+		if d.lastKey == nil {
+			break
+		}
+	}
+
+	return nil
+}
+
+// describe reads the key schema of the table
+func (d *DynamoDBItems) describe(ctx context.Context, c *client.Client) error {
+	output, err := c.DynamoDB().DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: &d.table,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe table %s: %w", d.table, err)
+	}
+
+	keyTypes := make(map[string]dynamotypes.ScalarAttributeType)
+	for _, def := range output.Table.AttributeDefinitions {
+		keyTypes[stringValue(def.AttributeName)] = def.AttributeType
+	}
+	d.partitionKey, d.sortKey = keySchemaNames(output.Table.KeySchema)
+	d.keyTypes = keyTypes
+	return nil
+}
+
+// HasMore reports whether more items can be loaded
+func (d *DynamoDBItems) HasMore() bool {
+	return d.lastKey != nil
+}
+
+// FetchMore retrieves the next page of items
+func (d *DynamoDBItems) FetchMore(ctx context.Context, c *client.Client) error {
+	if d.lastKey == nil {
+		return nil
+	}
+	return d.fetchPage(ctx, c)
+}
+
+// fetchPage scans or queries the page of items starting after the last evaluated key
+func (d *DynamoDBItems) fetchPage(ctx context.Context, c *client.Client) error {
+	limit := pageSize
+
+	var items []map[string]dynamotypes.AttributeValue
+	var lastKey map[string]dynamotypes.AttributeValue
+	if d.query == "" {
+		output, err := c.DynamoDB().Scan(ctx, &dynamodb.ScanInput{
+			TableName:         &d.table,
+			Limit:             &limit,
+			ExclusiveStartKey: d.lastKey,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan table %s: %w", d.table, err)
+		}
+		items, lastKey = output.Items, output.LastEvaluatedKey
+	} else {
+		input, err := d.queryInput()
+		if err != nil {
+			return err
+		}
+		input.Limit = &limit
+		input.ExclusiveStartKey = d.lastKey
+
+		output, err := c.DynamoDB().Query(ctx, input)
+		if err != nil {
+			return fmt.Errorf("failed to query table %s: %w", d.table, err)
+		}
+		items, lastKey = output.Items, output.LastEvaluatedKey
+	}
+
+	for _, item := range items {
+		plain := attributeMapValue(item)
+
+		key := map[string]any{d.partitionKey: plain[d.partitionKey]}
+		if d.sortKey != "" {
+			key[d.sortKey] = plain[d.sortKey]
+		}
+		keyJSON, _ := json.Marshal(key)
+
+		i := DynamoDBItem{
+			Key:          string(keyJSON),
+			PartitionKey: fmt.Sprint(plain[d.partitionKey]),
+			Item:         plain,
+			Raw:          item,
+		}
+		if d.sortKey != "" {
+			i.SortKey = fmt.Sprint(plain[d.sortKey])
+		}
+		d.items = append(d.items, i)
+	}
+
+	d.pages++
+	d.lastKey = nil
+	if len(lastKey) > 0 {
+		d.lastKey = lastKey
+	}
+	return nil
+}
+
+// queryInput builds the key condition of the query, e.g. "user#42 | begins_with order#"
+func (d *DynamoDBItems) queryInput() (*dynamodb.QueryInput, error) {
+	partition, condition, _ := strings.Cut(d.query, "|")
+	partition = strings.TrimSpace(partition)
+	condition = strings.TrimSpace(condition)
+	if partition == "" {
+		return nil, fmt.Errorf("invalid query %q: missing partition key value", d.query)
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 &d.table,
+		KeyConditionExpression:    aws.String("#pk = :pk"),
+		ExpressionAttributeNames:  map[string]string{"#pk": d.partitionKey},
+		ExpressionAttributeValues: map[string]dynamotypes.AttributeValue{":pk": d.keyValue(d.partitionKey, partition)},
+	}
+	if condition == "" {
+		return input, nil
+	}
+	if d.sortKey == "" {
+		return nil, fmt.Errorf("invalid query %q: table %s has no sort key", d.query, d.table)
+	}
+
+	op, value, _ := strings.Cut(condition, " ")
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, fmt.Errorf("invalid query %q: missing sort key value", d.query)
+	}
+
+	input.ExpressionAttributeNames["#sk"] = d.sortKey
+	switch op {
+	case "=", "<", "<=", ">", ">=":
+		input.KeyConditionExpression = aws.String("#pk = :pk AND #sk " + op + " :sk")
+		input.ExpressionAttributeValues[":sk"] = d.keyValue(d.sortKey, value)
+	case "begins_with":
+		input.KeyConditionExpression = aws.String("#pk = :pk AND begins_with(#sk, :sk)")
+		input.ExpressionAttributeValues[":sk"] = d.keyValue(d.sortKey, value)
+	case "between":
+		low, high, ok := strings.Cut(value, " and ")
+		if !ok {
+			return nil, fmt.Errorf("invalid query %q: expected between <low> and <high>", d.query)
+		}
+		input.KeyConditionExpression = aws.String("#pk = :pk AND #sk BETWEEN :low AND :high")
+		input.ExpressionAttributeValues[":low"] = d.keyValue(d.sortKey, strings.TrimSpace(low))
+		input.ExpressionAttributeValues[":high"] = d.keyValue(d.sortKey, strings.TrimSpace(high))
+	default:
+		return nil, fmt.Errorf("invalid query %q: unknown operator %s", d.query, op)
+	}
+
+	return input, nil
+}
+
+// keyValue converts a typed value to the attribute type of a key
+func (d *DynamoDBItems) keyValue(attribute, value string) dynamotypes.AttributeValue {
+	switch d.keyTypes[attribute] {
+	case dynamotypes.ScalarAttributeTypeN:
+		return &dynamotypes.AttributeValueMemberN{Value: value}
+	case dynamotypes.ScalarAttributeTypeB:
+		return &dynamotypes.AttributeValueMemberB{Value: []byte(value)}
+	default:
+		return &dynamotypes.AttributeValueMemberS{Value: value}
+	}
+}
+
+// attributeMapValue converts a DynamoDB item to plain values which render as JSON
+func attributeMapValue(item map[string]dynamotypes.AttributeValue) map[string]any {
+	plain := make(map[string]any, len(item))
+	for k, v := range item {
+		plain[k] = attributeValue(v)
+	}
+	return plain
+}
+
+// attributeValue converts a DynamoDB attribute to a plain value, numbers are kept exact
+func attributeValue(av dynamotypes.AttributeValue) any {
+	switch v := av.(type) {
+	case *dynamotypes.AttributeValueMemberS:
+		return v.Value
+	case *dynamotypes.AttributeValueMemberN:
+		return json.Number(v.Value)
+	case *dynamotypes.AttributeValueMemberBOOL:
+		return v.Value
+	case *dynamotypes.AttributeValueMemberNULL:
+		return nil
+	case *dynamotypes.AttributeValueMemberB:
+		return base64.StdEncoding.EncodeToString(v.Value)
+	case *dynamotypes.AttributeValueMemberSS:
+		return v.Value
+	case *dynamotypes.AttributeValueMemberNS:
+		numbers := make([]json.Number, len(v.Value))
+		for i, n := range v.Value {
+			numbers[i] = json.Number(n)
+		}
+		return numbers
+	case *dynamotypes.AttributeValueMemberBS:
+		values := make([]string, len(v.Value))
+		for i, b := range v.Value {
+			values[i] = base64.StdEncoding.EncodeToString(b)
+		}
+		return values
+	case *dynamotypes.AttributeValueMemberL:
+		values := make([]any, len(v.Value))
+		for i, elem := range v.Value {
+			values[i] = attributeValue(elem)
+		}
+		return values
+	case *dynamotypes.AttributeValueMemberM:
+		return attributeMapValue(v.Value)
+	}
+	return nil
+}
+
+// Rows returns the table data
+func (d *DynamoDBItems) Rows() [][]string {
+	rows := make([][]string, len(d.items))
+	for i, item := range d.items {
+		itemJSON, _ := json.Marshal(item.Item)
+		rows[i] = []string{
+			item.PartitionKey,
+			item.SortKey,
+			string(itemJSON),
+		}
+	}
+	return rows
+}
+
+// GetID returns the key of the item at the given index
+func (d *DynamoDBItems) GetID(index int) string {
+	if index >= 0 && index < len(d.items) {
+		return d.items[index].Key
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for DynamoDB items
+func (d *DynamoDBItems) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:            'd',
+			Label:          "detail",
+			Description:    "Show item",
+			NeedsSelection: true,
+			TextHandler: func(ctx context.Context, c *client.Client, key string) (string, error) {
+				for _, item := range d.items {
+					if item.Key != key {
+						continue
+					}
+
+					var buf bytes.Buffer
+					encoder := json.NewEncoder(&buf)
+					encoder.SetIndent("", "  ")
+					if err := encoder.Encode(item.Item); err != nil {
+						return "", fmt.Errorf("failed to render item: %w", err)
+					}
+					return buf.String(), nil
+				}
+				return "", fmt.Errorf("unknown item %s", key)
+			},
+		},
+		{
+			Key:            'Q',
+			Label:          "query",
+			Description:    "Query items",
+			NeedsSelection: false,
+			InputLabel:     dynamoQueryLabel,
+			InputView: func(_, query string) Resource {
+				return NewDynamoDBItems(d.table, query)
+			},
+		},
+		{
+			Key:          'c',
+			Label:        "create",
+			Description:  "Create an item",
+			EditTemplate: d.newItemTemplate,
+			EditPlan: func(ctx context.Context, c *client.Client, _, text string) ([]Step, error) {
+				return d.planPutItem(nil, text)
+			},
+		},
+		{
+			Key:            'e',
+			Label:          "edit",
+			Description:    "Edit the item",
+			NeedsSelection: true,
+			EditTemplate: func(key string) string {
+				item, ok := d.item(key)
+				if !ok {
+					return ""
+				}
+				data, _ := json.MarshalIndent(typedAttributeMap(item.Raw), "", "  ")
+				return string(data) + "\n"
+			},
+			EditPlan: func(ctx context.Context, c *client.Client, key, text string) ([]Step, error) {
+				item, ok := d.item(key)
+				if !ok {
+					return nil, fmt.Errorf("unknown item %s", key)
+				}
+				return d.planPutItem(item.Raw, text)
+			},
+		},
+		{
+			Key:            'D',
+			Label:          "delete",
+			Description:    "Delete the item",
+			NeedsSelection: true,
+			Plan:           d.planDeleteItem,
+		},
+	}
+}
+
+// item returns the loaded item with the given key
+func (d *DynamoDBItems) item(key string) (DynamoDBItem, bool) {
+	for _, item := range d.items {
+		if item.Key == key {
+			return item, true
+		}
+	}
+	return DynamoDBItem{}, false
+}
+
+// itemKey returns the key attributes of an item
+func (d *DynamoDBItems) itemKey(item map[string]dynamotypes.AttributeValue) map[string]dynamotypes.AttributeValue {
+	key := map[string]dynamotypes.AttributeValue{d.partitionKey: item[d.partitionKey]}
+	if d.sortKey != "" {
+		key[d.sortKey] = item[d.sortKey]
+	}
+	return key
+}
+
+// newItemTemplate returns the DynamoDB JSON of an item with empty key attributes
+func (d *DynamoDBItems) newItemTemplate(string) string {
+	template := make(map[string]any)
+	for _, name := range []string{d.partitionKey, d.sortKey} {
+		if name == "" {
+			continue
+		}
+		kind := string(d.keyTypes[name])
+		if kind == "" {
+			kind = "S"
+		}
+		template[name] = map[string]any{kind: ""}
+	}
+	data, _ := json.MarshalIndent(template, "", "  ")
+	return string(data) + "\n"
+}
+
+// validateKey checks that an item has the key attributes of the table with their types
+func (d *DynamoDBItems) validateKey(item map[string]dynamotypes.AttributeValue) error {
+	for _, name := range []string{d.partitionKey, d.sortKey} {
+		if name == "" {
+			continue
+		}
+		av, ok := item[name]
+		if !ok {
+			return fmt.Errorf("key attribute %s is missing", name)
+		}
+		if kind := attributeKind(av); kind != string(d.keyTypes[name]) {
+			return fmt.Errorf("key attribute %s must be of type %s, got %s", name, d.keyTypes[name], kind)
+		}
+	}
+	return nil
+}
+
+// planPutItem validates an edited item and returns its diff with the previous version,
+// nil when the item is created, followed by the PutItem step
+func (d *DynamoDBItems) planPutItem(before map[string]dynamotypes.AttributeValue, text string) ([]Step, error) {
+	after, err := parseTypedItem([]byte(text))
+	if err != nil {
+		return nil, err
+	}
+	if err := d.validateKey(after); err != nil {
+		return nil, err
+	}
+
+	input := &dynamodb.PutItemInput{
+		TableName: &d.table,
+		Item:      after,
+	}
+	description := fmt.Sprintf("Put the item in %s", d.table)
+	if before == nil {
+		// A new item must not overwrite an existing one with the same key
+		input.ConditionExpression = aws.String("attribute_not_exists(#pk)")
+		input.ExpressionAttributeNames = map[string]string{"#pk": d.partitionKey}
+		description = fmt.Sprintf("Create the item in %s", d.table)
+	} else {
+		beforeKey, _ := json.Marshal(typedAttributeMap(d.itemKey(before)))
+		afterKey, _ := json.Marshal(typedAttributeMap(d.itemKey(after)))
+		if string(beforeKey) != string(afterKey) {
+			return nil, fmt.Errorf("the key attributes cannot be edited, create a new item instead")
+		}
+	}
+
+	steps := make([]Step, 0)
+	for _, line := range typedItemDiff(before, after) {
+		steps = append(steps, Step{Description: line})
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("the item is unchanged")
+	}
+
+	steps = append(steps, Step{
+		Description: description,
+		Run: func(ctx context.Context, c *client.Client) error {
+			if _, err := c.DynamoDB().PutItem(ctx, input); err != nil {
+				return fmt.Errorf("failed to put item in %s: %w", d.table, err)
+			}
+			return nil
+		},
+	})
+	return steps, nil
+}
+
+// planDeleteItem returns the attributes of the item as notes, followed by the DeleteItem step
+func (d *DynamoDBItems) planDeleteItem(ctx context.Context, c *client.Client, key string) ([]Step, error) {
+	item, ok := d.item(key)
+	if !ok {
+		return nil, fmt.Errorf("unknown item %s", key)
+	}
+
+	steps := make([]Step, 0)
+	for _, line := range typedItemDiff(item.Raw, nil) {
+		steps = append(steps, Step{Description: line})
+	}
+	steps = append(steps, Step{
+		Description: fmt.Sprintf("Delete the item %s from %s", key, d.table),
+		Run: func(ctx context.Context, c *client.Client) error {
+			_, err := c.DynamoDB().DeleteItem(ctx, &dynamodb.DeleteItemInput{
+				TableName: &d.table,
+				Key:       d.itemKey(item.Raw),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to delete item %s: %w", key, err)
+			}
+			return nil
+		},
+	})
+	return steps, nil
+}
+
+// DynamoDBIndex represents a table, or one of its secondary indexes, with its capacity
+type DynamoDBIndex struct {
+	Name         string
+	Type         string
+	PartitionKey string
+	SortKey      string
+	Projection   string
+	Status       string
+	Provisioned  string
+	Consumed     string
+	AutoScaling  string
+
+	// Application Auto Scaling resource ID, e.g. "table/orders/index/by-customer"
+	resourceID string
+}
+
+// DynamoDBIndexes implements Resource for the indexes and capacity of a table
+type DynamoDBIndexes struct {
+	table    string
+	onDemand bool
+	indexes  []DynamoDBIndex
+}
+
+// NewDynamoDBIndexes creates a new DynamoDBIndexes resource
+func NewDynamoDBIndexes(table string) *DynamoDBIndexes {
+	return &DynamoDBIndexes{
+		table:   table,
+		indexes: make([]DynamoDBIndex, 0),
+	}
+}
+
+// Name returns the display name
+func (d *DynamoDBIndexes) Name() string {
+	return fmt.Sprintf("DynamoDB Indexes (%s)", d.table)
+}
+
+// Columns returns the column definitions
+func (d *DynamoDBIndexes) Columns() []Column {
+	return []Column{
+		{Name: "Index", Width: 30},
+		{Name: "Type", Width: 6},
+		{Name: "Partition Key", Width: 20},
+		{Name: "Sort Key", Width: 20},
+		{Name: "Projection", Width: 30},
+		{Name: "Status", Width: 10},
+		{Name: "Provisioned (R/W)", Width: 18},
+		{Name: "Consumed 5m (R/W)", Width: 18},
+		{Name: "Auto Scaling", Width: 40},
+	}
+}
+
+// Fetch retrieves the indexes of the table, their consumed capacity and auto scaling settings
+func (d *DynamoDBIndexes) Fetch(ctx context.Context, c *client.Client) error {
+	d.indexes = make([]DynamoDBIndex, 0)
+
+	output, err := c.DynamoDB().DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: &d.table,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe table %s: %w", d.table, err)
+	}
+	table := output.Table
+
+	onDemand := table.BillingModeSummary != nil && table.BillingModeSummary.BillingMode == dynamotypes.BillingModePayPerRequest
+	d.onDemand = onDemand
+
+	base := DynamoDBIndex{
+		Name:        d.table,
+		Type:        "TABLE",
+		Projection:  "ALL",
+		Status:      string(table.TableStatus),
+		Provisioned: formatThroughput(table.ProvisionedThroughput, onDemand),
+		resourceID:  "table/" + d.table,
+	}
+	base.PartitionKey, base.SortKey = keySchemaNames(table.KeySchema)
+	d.indexes = append(d.indexes, base)
+
+	for _, gsi := range table.GlobalSecondaryIndexes {
+		index := DynamoDBIndex{
+			Name:        stringValue(gsi.IndexName),
+			Type:        "GSI",
+			Projection:  formatProjection(gsi.Projection),
+			Status:      string(gsi.IndexStatus),
+			Provisioned: formatThroughput(gsi.ProvisionedThroughput, onDemand),
+			resourceID:  "table/" + d.table + "/index/" + stringValue(gsi.IndexName),
+		}
+		index.PartitionKey, index.SortKey = keySchemaNames(gsi.KeySchema)
+		d.indexes = append(d.indexes, index)
+	}
+
+	// Local indexes share the capacity of the table
+	for _, lsi := range table.LocalSecondaryIndexes {
+		index := DynamoDBIndex{
+			Name:        stringValue(lsi.IndexName),
+			Type:        "LSI",
+			Projection:  formatProjection(lsi.Projection),
+			Status:      string(table.TableStatus),
+			Provisioned: "table",
+		}
+		index.PartitionKey, index.SortKey = keySchemaNames(lsi.KeySchema)
+		d.indexes = append(d.indexes, index)
+	}
+
+	// Capacity details are best effort, they need CloudWatch and Application Auto Scaling permissions
+	d.fetchConsumed(ctx, c)
+	if !onDemand {
+		d.fetchAutoScaling(ctx, c)
+	}
+
+	return nil
+}
+
+// fetchConsumed sets the average consumed capacity per second over the last 5 minutes
+func (d *DynamoDBIndexes) fetchConsumed(ctx context.Context, c *client.Client) {
+	const period = 300
+
+	queries := make([]cwtypes.MetricDataQuery, 0)
+	for i, index := range d.indexes {
+		if index.Type == "LSI" {
+			continue
+		}
+
+		dimensions := []cwtypes.Dimension{{Name: aws.String("TableName"), Value: aws.String(d.table)}}
+		if index.Type == "GSI" {
+			dimensions = append(dimensions, cwtypes.Dimension{Name: aws.String("GlobalSecondaryIndexName"), Value: aws.String(index.Name)})
+		}
+
+		// Query IDs are "r<index>" and "w<index>"
+		for prefix, metric := range map[string]string{"r": "ConsumedReadCapacityUnits", "w": "ConsumedWriteCapacityUnits"} {
+			queries = append(queries, cwtypes.MetricDataQuery{
+				Id: aws.String(fmt.Sprintf("%s%d", prefix, i)),
+				MetricStat: &cwtypes.MetricStat{
+					Metric: &cwtypes.Metric{
+						Namespace:  aws.String("AWS/DynamoDB"),
+						MetricName: aws.String(metric),
+						Dimensions: dimensions,
+					},
+					Period: aws.Int32(period),
+					Stat:   aws.String("Sum"),
+				},
+			})
+		}
+	}
+
+	end := time.Now()
+	output, err := c.CloudWatch().GetMetricData(ctx, &cloudwatch.GetMetricDataInput{
+		MetricDataQueries: queries,
+		StartTime:         aws.Time(end.Add(-period * time.Second)),
+		EndTime:           aws.Time(end),
+	})
+	if err != nil {
+		return
+	}
+
+	consumed := make(map[string]float64)
+	for _, result := range output.MetricDataResults {
+		if len(result.Values) > 0 {
+			consumed[stringValue(result.Id)] = result.Values[0] / period
+		}
+	}
+	for i := range d.indexes {
+		if d.indexes[i].Type == "LSI" {
+			continue
+		}
+		d.indexes[i].Consumed = fmt.Sprintf("%.1f/%.1f", consumed[fmt.Sprintf("r%d", i)], consumed[fmt.Sprintf("w%d", i)])
+	}
+}
+
+// fetchAutoScaling sets the auto scaling range and target of the table and its global indexes
+func (d *DynamoDBIndexes) fetchAutoScaling(ctx context.Context, c *client.Client) {
+	for i, index := range d.indexes {
+		if index.resourceID == "" {
+			continue
+		}
+
+		targets, err := c.ApplicationAutoScaling().DescribeScalableTargets(ctx, &applicationautoscaling.DescribeScalableTargetsInput{
+			ServiceNamespace: aastypes.ServiceNamespaceDynamodb,
+			ResourceIds:      []string{index.resourceID},
+		})
+		if err != nil {
+			return
+		}
+		if len(targets.ScalableTargets) == 0 {
+			d.indexes[i].AutoScaling = "off"
+			continue
+		}
+
+		policies, err := c.ApplicationAutoScaling().DescribeScalingPolicies(ctx, &applicationautoscaling.DescribeScalingPoliciesInput{
+			ServiceNamespace: aastypes.ServiceNamespaceDynamodb,
+			ResourceId:       &index.resourceID,
+		})
+		if err != nil {
+			return
+		}
+
+		targetValues := make(map[aastypes.ScalableDimension]float64)
+		for _, policy := range policies.ScalingPolicies {
+			if config := policy.TargetTrackingScalingPolicyConfiguration; config != nil && config.TargetValue != nil {
+				targetValues[policy.ScalableDimension] = *config.TargetValue
+			}
+		}
+
+		parts := make([]string, 0, len(targets.ScalableTargets))
+		for _, target := range targets.ScalableTargets {
+			kind := "W"
+			if strings.HasSuffix(string(target.ScalableDimension), "ReadCapacityUnits") {
+				kind = "R"
+			}
+			part := fmt.Sprintf("%s %d-%d", kind, ptrInt32Value(target.MinCapacity), ptrInt32Value(target.MaxCapacity))
+			if value, ok := targetValues[target.ScalableDimension]; ok {
+				part += fmt.Sprintf(" @%.0f%%", value)
+			}
+			parts = append(parts, part)
+		}
+		sort.Strings(parts)
+		d.indexes[i].AutoScaling = strings.Join(parts, ", ")
+	}
+}
+
+// keySchemaNames returns the partition and sort key names of a key schema
+func keySchemaNames(schema []dynamotypes.KeySchemaElement) (string, string) {
+	var partitionKey, sortKey string
+	for _, key := range schema {
+		switch key.KeyType {
+		case dynamotypes.KeyTypeHash:
+			partitionKey = stringValue(key.AttributeName)
+		case dynamotypes.KeyTypeRange:
+			sortKey = stringValue(key.AttributeName)
+		}
+	}
+	return partitionKey, sortKey
+}
+
+// formatProjection renders the projected attributes of an index
+func formatProjection(projection *dynamotypes.Projection) string {
+	if projection == nil {
+		return ""
+	}
+	if len(projection.NonKeyAttributes) > 0 {
+		return fmt.Sprintf("%s (%s)", projection.ProjectionType, strings.Join(projection.NonKeyAttributes, ","))
+	}
+	return string(projection.ProjectionType)
+}
+
+// formatThroughput renders the provisioned read/write capacity units
+func formatThroughput(throughput *dynamotypes.ProvisionedThroughputDescription, onDemand bool) string {
+	if onDemand {
+		return "on-demand"
+	}
+	if throughput == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", ptrInt64Value(throughput.ReadCapacityUnits), ptrInt64Value(throughput.WriteCapacityUnits))
+}
+
+// Rows returns the table data
+func (d *DynamoDBIndexes) Rows() [][]string {
+	rows := make([][]string, len(d.indexes))
+	for i, index := range d.indexes {
+		rows[i] = []string{
+			index.Name,
+			index.Type,
+			index.PartitionKey,
+			index.SortKey,
+			index.Projection,
+			index.Status,
+			index.Provisioned,
+			index.Consumed,
+			index.AutoScaling,
+		}
+	}
+	return rows
+}
+
+// GetID returns the index name at the given index
+func (d *DynamoDBIndexes) GetID(index int) string {
+	if index >= 0 && index < len(d.indexes) {
+		return d.indexes[index].Name
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for DynamoDB indexes
+func (d *DynamoDBIndexes) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:             'C',
+			Label:           "capacity",
+			Description:     "Set on-demand or provisioned capacity",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "[yellow]change the capacity[-] of [white]%s[-]?",
+			ConfirmCheck:    d.capacityContext,
+			InputLabel:      "on-demand or RCU/WCU: ",
+			InputDefault: func(name string) string {
+				if index, ok := d.index(name); ok {
+					return index.Provisioned
+				}
+				return ""
+			},
+			InputHandler: d.SetCapacity,
+		},
+	}
+}
+
+// dynamoMaxCapacityUnits is the default quota of read or write capacity units of a table
+const dynamoMaxCapacityUnits = 40000
+
+// index returns the table or index row with the given name
+func (d *DynamoDBIndexes) index(name string) (DynamoDBIndex, bool) {
+	for _, index := range d.indexes {
+		if index.Name == name {
+			return index, true
+		}
+	}
+	return DynamoDBIndex{}, false
+}
+
+// capacityContext shows the current and consumed capacity in the confirmation, and
+// refuses the local indexes which share the capacity of the table
+func (d *DynamoDBIndexes) capacityContext(ctx context.Context, c *client.Client, name string) (string, error) {
+	index, ok := d.index(name)
+	if !ok {
+		return "", fmt.Errorf("index %s not found", name)
+	}
+	if index.Type == "LSI" {
+		return "", fmt.Errorf("local index %s uses the capacity of the table", name)
+	}
+
+	consumed := index.Consumed
+	if consumed == "" {
+		consumed = "unknown"
+	}
+	warning := fmt.Sprintf("Current: %s, consumed over the last 5 minutes: %s per second (R/W)", index.Provisioned, consumed)
+	if index.AutoScaling != "" && index.AutoScaling != "off" {
+		warning += fmt.Sprintf("\nAuto scaling (%s) may override the provisioned capacity", index.AutoScaling)
+	}
+	if index.Type == "TABLE" {
+		warning += "\nThe billing mode of a table can be switched to on-demand a limited number of times per 24 hours"
+	}
+	return warning, nil
+}
+
+// SetCapacity switches a table to on-demand, or sets the provisioned capacity of a table or
+// global index from "RCU/WCU", switching the table and its global indexes to provisioned if needed
+func (d *DynamoDBIndexes) SetCapacity(ctx context.Context, c *client.Client, name, input string) error {
+	index, ok := d.index(name)
+	if !ok {
+		return fmt.Errorf("index %s not found", name)
+	}
+
+	update := &dynamodb.UpdateTableInput{TableName: &d.table}
+	if input == "on-demand" {
+		if index.Type != "TABLE" {
+			return fmt.Errorf("the billing mode is set on the table, not on index %s", name)
+		}
+		if d.onDemand {
+			return fmt.Errorf("table %s is already on-demand", d.table)
+		}
+		update.BillingMode = dynamotypes.BillingModePayPerRequest
+	} else {
+		throughput, err := parseThroughput(input)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case index.Type == "GSI" && d.onDemand:
+			return fmt.Errorf("table %s is on-demand, set its provisioned capacity first", d.table)
+		case index.Type == "GSI":
+			update.GlobalSecondaryIndexUpdates = []dynamotypes.GlobalSecondaryIndexUpdate{{
+				Update: &dynamotypes.UpdateGlobalSecondaryIndexAction{
+					IndexName:             &name,
+					ProvisionedThroughput: throughput,
+				},
+			}}
+		default:
+			update.ProvisionedThroughput = throughput
+			if d.onDemand {
+				// Provisioned tables need the capacity of every global index too
+				update.BillingMode = dynamotypes.BillingModeProvisioned
+				for _, gsi := range d.indexes {
+					if gsi.Type != "GSI" {
+						continue
+					}
+					update.GlobalSecondaryIndexUpdates = append(update.GlobalSecondaryIndexUpdates, dynamotypes.GlobalSecondaryIndexUpdate{
+						Update: &dynamotypes.UpdateGlobalSecondaryIndexAction{
+							IndexName:             aws.String(gsi.Name),
+							ProvisionedThroughput: throughput,
+						},
+					})
+				}
+			}
+		}
+	}
+
+	if _, err := c.DynamoDB().UpdateTable(ctx, update); err != nil {
+		return fmt.Errorf("failed to update capacity of %s: %w", name, err)
+	}
+	return nil
+}
+
+// parseThroughput parses "RCU/WCU" into a provisioned throughput within the default quota
+func parseThroughput(input string) (*dynamotypes.ProvisionedThroughput, error) {
+	read, write, ok := strings.Cut(input, "/")
+	if !ok {
+		return nil, fmt.Errorf("expected on-demand or RCU/WCU, got %q", input)
+	}
+	units := make([]int64, 0, 2)
+	for _, value := range []string{read, write} {
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil || n < 1 || n > dynamoMaxCapacityUnits {
+			return nil, fmt.Errorf("capacity units must be between 1 and %d, got %q", dynamoMaxCapacityUnits, value)
+		}
+		units = append(units, n)
+	}
+	return &dynamotypes.ProvisionedThroughput{
+		ReadCapacityUnits:  aws.Int64(units[0]),
+		WriteCapacityUnits: aws.Int64(units[1]),
+	}, nil
 }
@@ -0,0 +1,224 @@
+package resources
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	dynamotypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// typedAttributeMap converts a DynamoDB item to DynamoDB JSON, which keeps the attribute
+// types when the item is edited, e.g. {"id": {"S": "42"}}
+func typedAttributeMap(item map[string]dynamotypes.AttributeValue) map[string]any {
+	typed := make(map[string]any, len(item))
+	for k, v := range item {
+		typed[k] = typedAttributeValue(v)
+	}
+	return typed
+}
+
+// typedAttributeValue converts a DynamoDB attribute to DynamoDB JSON
+func typedAttributeValue(av dynamotypes.AttributeValue) any {
+	switch v := av.(type) {
+	case *dynamotypes.AttributeValueMemberS:
+		return map[string]any{"S": v.Value}
+	case *dynamotypes.AttributeValueMemberN:
+		return map[string]any{"N": v.Value}
+	case *dynamotypes.AttributeValueMemberBOOL:
+		return map[string]any{"BOOL": v.Value}
+	case *dynamotypes.AttributeValueMemberNULL:
+		return map[string]any{"NULL": true}
+	case *dynamotypes.AttributeValueMemberB:
+		return map[string]any{"B": base64.StdEncoding.EncodeToString(v.Value)}
+	case *dynamotypes.AttributeValueMemberSS:
+		return map[string]any{"SS": v.Value}
+	case *dynamotypes.AttributeValueMemberNS:
+		return map[string]any{"NS": v.Value}
+	case *dynamotypes.AttributeValueMemberBS:
+		values := make([]string, len(v.Value))
+		for i, b := range v.Value {
+			values[i] = base64.StdEncoding.EncodeToString(b)
+		}
+		return map[string]any{"BS": values}
+	case *dynamotypes.AttributeValueMemberL:
+		values := make([]any, len(v.Value))
+		for i, elem := range v.Value {
+			values[i] = typedAttributeValue(elem)
+		}
+		return map[string]any{"L": values}
+	case *dynamotypes.AttributeValueMemberM:
+		return map[string]any{"M": typedAttributeMap(v.Value)}
+	}
+	return nil
+}
+
+// parseTypedItem parses an item written in DynamoDB JSON
+func parseTypedItem(data []byte) (map[string]dynamotypes.AttributeValue, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("expected a JSON object of attributes")
+	}
+	return parseTypedMap(raw)
+}
+
+// parseTypedMap parses the attributes of an item or of a map attribute
+func parseTypedMap(raw map[string]json.RawMessage) (map[string]dynamotypes.AttributeValue, error) {
+	item := make(map[string]dynamotypes.AttributeValue, len(raw))
+	for name, value := range raw {
+		av, err := parseTypedAttribute(value)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %s: %w", name, err)
+		}
+		item[name] = av
+	}
+	return item, nil
+}
+
+// parseTypedAttribute parses an attribute written in DynamoDB JSON, e.g. {"N": "42"}
+func parseTypedAttribute(data json.RawMessage) (dynamotypes.AttributeValue, error) {
+	var typed map[string]json.RawMessage
+	if err := json.Unmarshal(data, &typed); err != nil || len(typed) != 1 {
+		return nil, fmt.Errorf(`expected a single type, e.g. {"S": "text"}, got %s`, data)
+	}
+
+	for kind, value := range typed {
+		switch kind {
+		case "S":
+			var s string
+			if err := json.Unmarshal(value, &s); err != nil {
+				return nil, fmt.Errorf("S expects a string")
+			}
+			return &dynamotypes.AttributeValueMemberS{Value: s}, nil
+		case "N":
+			var n json.Number
+			if err := json.Unmarshal(value, &n); err != nil {
+				return nil, fmt.Errorf("N expects a number")
+			}
+			return &dynamotypes.AttributeValueMemberN{Value: n.String()}, nil
+		case "BOOL":
+			var b bool
+			if err := json.Unmarshal(value, &b); err != nil {
+				return nil, fmt.Errorf("BOOL expects true or false")
+			}
+			return &dynamotypes.AttributeValueMemberBOOL{Value: b}, nil
+		case "NULL":
+			return &dynamotypes.AttributeValueMemberNULL{Value: true}, nil
+		case "B":
+			var s string
+			if err := json.Unmarshal(value, &s); err != nil {
+				return nil, fmt.Errorf("B expects a base64 string")
+			}
+			b, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return nil, fmt.Errorf("B expects a base64 string: %w", err)
+			}
+			return &dynamotypes.AttributeValueMemberB{Value: b}, nil
+		case "SS":
+			var values []string
+			if err := json.Unmarshal(value, &values); err != nil {
+				return nil, fmt.Errorf("SS expects a list of strings")
+			}
+			return &dynamotypes.AttributeValueMemberSS{Value: values}, nil
+		case "NS":
+			var numbers []json.Number
+			if err := json.Unmarshal(value, &numbers); err != nil {
+				return nil, fmt.Errorf("NS expects a list of numbers")
+			}
+			values := make([]string, len(numbers))
+			for i, n := range numbers {
+				values[i] = n.String()
+			}
+			return &dynamotypes.AttributeValueMemberNS{Value: values}, nil
+		case "BS":
+			var encoded []string
+			if err := json.Unmarshal(value, &encoded); err != nil {
+				return nil, fmt.Errorf("BS expects a list of base64 strings")
+			}
+			values := make([][]byte, len(encoded))
+			for i, s := range encoded {
+				b, err := base64.StdEncoding.DecodeString(s)
+				if err != nil {
+					return nil, fmt.Errorf("BS expects a list of base64 strings: %w", err)
+				}
+				values[i] = b
+			}
+			return &dynamotypes.AttributeValueMemberBS{Value: values}, nil
+		case "L":
+			var elems []json.RawMessage
+			if err := json.Unmarshal(value, &elems); err != nil {
+				return nil, fmt.Errorf("L expects a list of attributes")
+			}
+			values := make([]dynamotypes.AttributeValue, len(elems))
+			for i, elem := range elems {
+				av, err := parseTypedAttribute(elem)
+				if err != nil {
+					return nil, fmt.Errorf("element %d: %w", i, err)
+				}
+				values[i] = av
+			}
+			return &dynamotypes.AttributeValueMemberL{Value: values}, nil
+		case "M":
+			var raw map[string]json.RawMessage
+			if err := json.Unmarshal(value, &raw); err != nil {
+				return nil, fmt.Errorf("M expects an object of attributes")
+			}
+			values, err := parseTypedMap(raw)
+			if err != nil {
+				return nil, err
+			}
+			return &dynamotypes.AttributeValueMemberM{Value: values}, nil
+		default:
+			return nil, fmt.Errorf("unknown type %s", kind)
+		}
+	}
+	return nil, nil
+}
+
+// attributeKind returns the DynamoDB JSON type of an attribute, e.g. S
+func attributeKind(av dynamotypes.AttributeValue) string {
+	if typed, ok := typedAttributeValue(av).(map[string]any); ok {
+		for kind := range typed {
+			return kind
+		}
+	}
+	return ""
+}
+
+// typedItemDiff describes the attribute changes between two items, one line per attribute
+func typedItemDiff(before, after map[string]dynamotypes.AttributeValue) []string {
+	names := make([]string, 0, len(before)+len(after))
+	for name := range before {
+		names = append(names, name)
+	}
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	render := func(av dynamotypes.AttributeValue) string {
+		data, _ := json.Marshal(typedAttributeValue(av))
+		return string(data)
+	}
+
+	lines := make([]string, 0)
+	for _, name := range names {
+		old, existed := before[name]
+		value, exists := after[name]
+		switch {
+		case !existed:
+			lines = append(lines, fmt.Sprintf("+ %s: %s", name, render(value)))
+		case !exists:
+			lines = append(lines, fmt.Sprintf("- %s: %s", name, render(old)))
+		case render(old) != render(value):
+			lines = append(lines, fmt.Sprintf("~ %s: %s → %s", name, render(old), render(value)))
+		}
+	}
+	return lines
+}
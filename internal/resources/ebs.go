@@ -0,0 +1,443 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+)
+
+// volumeModificationCooldown is the time EBS requires between two modifications of a volume
+const volumeModificationCooldown = 6 * time.Hour
+
+// EBSVolume represents an EBS volume
+type EBSVolume struct {
+	VolumeID         string
+	Name             string
+	State            string
+	Size             int32
+	Type             string
+	IOPS             int32
+	Throughput       int32
+	InstanceID       string
+	Device           string
+	AvailabilityZone string
+	CreateTime       string
+}
+
+// EBSVolumes implements Resource for EBS volumes
+type EBSVolumes struct {
+	volumes []EBSVolume
+}
+
+// NewEBSVolumes creates a new EBSVolumes resource
+func NewEBSVolumes() *EBSVolumes {
+	return &EBSVolumes{
+		volumes: make([]EBSVolume, 0),
+	}
+}
+
+// Name returns the display name
+func (e *EBSVolumes) Name() string {
+	return "EBS Volumes"
+}
+
+// Columns returns the column definitions
+func (e *EBSVolumes) Columns() []Column {
+	return []Column{
+		{Name: "ID", Width: 22},
+		{Name: "Name", Width: 30},
+		{Name: "State", Width: 10},
+		{Name: "Size", Width: 9},
+		{Name: "Type", Width: 8},
+		{Name: "IOPS", Width: 7},
+		{Name: "Throughput", Width: 11},
+		{Name: "Attached To", Width: 32},
+		{Name: "AZ", Width: 15},
+		{Name: "Created", Width: 20},
+	}
+}
+
+// Fetch retrieves EBS volumes from AWS
+func (e *EBSVolumes) Fetch(ctx context.Context, c *client.Client) error {
+	e.volumes = make([]EBSVolume, 0)
+
+	paginator := ec2.NewDescribeVolumesPaginator(c.EC2(), &ec2.DescribeVolumesInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe volumes: %w", err)
+		}
+		for _, volume := range output.Volumes {
+			e.volumes = append(e.volumes, parseVolume(volume))
+		}
+	}
+
+	return nil
+}
+
+// parseVolume converts an AWS EBS volume to our model
+func parseVolume(volume types.Volume) EBSVolume {
+	vol := EBSVolume{
+		VolumeID:         stringValue(volume.VolumeId),
+		State:            string(volume.State),
+		Size:             ptrInt32Value(volume.Size),
+		Type:             string(volume.VolumeType),
+		IOPS:             ptrInt32Value(volume.Iops),
+		Throughput:       ptrInt32Value(volume.Throughput),
+		AvailabilityZone: stringValue(volume.AvailabilityZone),
+	}
+	for _, tag := range volume.Tags {
+		if stringValue(tag.Key) == "Name" {
+			vol.Name = stringValue(tag.Value)
+			break
+		}
+	}
+	// Multi-Attach volumes can have several attachments, the first one is shown
+	if len(volume.Attachments) > 0 {
+		vol.InstanceID = stringValue(volume.Attachments[0].InstanceId)
+		vol.Device = stringValue(volume.Attachments[0].Device)
+	}
+	if volume.CreateTime != nil {
+		vol.CreateTime = volume.CreateTime.Format("2006-01-02 15:04:05")
+	}
+	return vol
+}
+
+// Rows returns the table data
+func (e *EBSVolumes) Rows() [][]string {
+	rows := make([][]string, len(e.volumes))
+	for i, vol := range e.volumes {
+		attachment := ""
+		if vol.InstanceID != "" {
+			attachment = vol.InstanceID + " " + vol.Device
+		}
+		iops, throughput := "", ""
+		if vol.IOPS > 0 {
+			iops = strconv.Itoa(int(vol.IOPS))
+		}
+		if vol.Throughput > 0 {
+			throughput = fmt.Sprintf("%d MiB/s", vol.Throughput)
+		}
+		rows[i] = []string{
+			vol.VolumeID,
+			vol.Name,
+			vol.State,
+			fmt.Sprintf("%d GiB", vol.Size),
+			vol.Type,
+			iops,
+			throughput,
+			attachment,
+			vol.AvailabilityZone,
+			vol.CreateTime,
+		}
+	}
+	return rows
+}
+
+// GetID returns the volume ID at the given index
+func (e *EBSVolumes) GetID(index int) string {
+	if index >= 0 && index < len(e.volumes) {
+		return e.volumes[index].VolumeID
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for EBS volumes
+func (e *EBSVolumes) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:             's',
+			Label:           "snapshot",
+			Description:     "Create a snapshot of the volume",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "[yellow]snapshot[-] volume [white]%s[-]? The snapshot is billed until deleted",
+			InputLabel:      "Snapshot description: ",
+			InputDefault: func(volumeID string) string {
+				return fmt.Sprintf("%s %s", e.volume(volumeID).displayName(), time.Now().Format("2006-01-02 15:04"))
+			},
+			InputHandler: e.createSnapshot,
+		},
+		{
+			Key:             'X',
+			Label:           "detach",
+			Description:     "Detach the volume from its instance",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "[yellow]detach[-] volume [white]%s[-]? Unmount its file systems first to avoid losing data",
+			ConfirmCheck:    e.checkDetach,
+			Handler:         e.detachVolume,
+		},
+		{
+			Key:            'm',
+			Label:          "modify",
+			Description:    "Modify the size, type, IOPS or throughput in the editor",
+			NeedsSelection: true,
+			EditTemplate: func(volumeID string) string {
+				return volumeTemplate(e.volume(volumeID))
+			},
+			EditPlan: func(ctx context.Context, c *client.Client, volumeID, text string) ([]Step, error) {
+				return planModifyVolume(ctx, c, e.volume(volumeID), text)
+			},
+		},
+		{
+			Key:             'D',
+			Label:           "delete",
+			Description:     "Delete the unattached volume",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			TypedConfirm:    true,
+			ConfirmTemplate: "[red]delete[-] volume [white]%s[-]? Its data is lost unless it was snapshotted",
+			ConfirmCheck: func(ctx context.Context, c *client.Client, volumeID string) (string, error) {
+				if vol := e.volume(volumeID); vol.State != string(types.VolumeStateAvailable) {
+					return "", fmt.Errorf("%s is %s, only unattached volumes can be deleted", volumeID, vol.State)
+				}
+				return "", nil
+			},
+			Handler: func(ctx context.Context, c *client.Client, volumeID string) error {
+				_, err := c.EC2().DeleteVolume(ctx, &ec2.DeleteVolumeInput{VolumeId: aws.String(volumeID)})
+				if err != nil {
+					return fmt.Errorf("failed to delete volume %s: %w", volumeID, err)
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// volume returns the listed volume with the given ID, the ID only when it is not listed
+func (e *EBSVolumes) volume(volumeID string) EBSVolume {
+	for _, vol := range e.volumes {
+		if vol.VolumeID == volumeID {
+			return vol
+		}
+	}
+	return EBSVolume{VolumeID: volumeID}
+}
+
+// displayName returns the Name tag of the volume, or its ID
+func (v EBSVolume) displayName() string {
+	if v.Name != "" {
+		return v.Name
+	}
+	return v.VolumeID
+}
+
+// createSnapshot snapshots a volume, the snapshot gets the Name tag of the volume
+func (e *EBSVolumes) createSnapshot(ctx context.Context, c *client.Client, volumeID, description string) error {
+	input := &ec2.CreateSnapshotInput{
+		VolumeId:    aws.String(volumeID),
+		Description: aws.String(strings.TrimSpace(description)),
+	}
+	if name := e.volume(volumeID).Name; name != "" {
+		input.TagSpecifications = []types.TagSpecification{{
+			ResourceType: types.ResourceTypeSnapshot,
+			Tags:         []types.Tag{{Key: aws.String("Name"), Value: aws.String(name)}},
+		}}
+	}
+	if _, err := c.EC2().CreateSnapshot(ctx, input); err != nil {
+		return fmt.Errorf("failed to create snapshot of %s: %w", volumeID, err)
+	}
+	return nil
+}
+
+// checkDetach refuses to detach an unattached volume or the root volume of a running
+// instance, and names the instance and device in the confirmation
+func (e *EBSVolumes) checkDetach(ctx context.Context, c *client.Client, volumeID string) (string, error) {
+	vol := e.volume(volumeID)
+	if vol.InstanceID == "" {
+		return "", fmt.Errorf("%s is not attached", volumeID)
+	}
+
+	output, err := c.EC2().DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: []string{vol.InstanceID},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe instance %s: %w", vol.InstanceID, err)
+	}
+	for _, reservation := range output.Reservations {
+		for _, instance := range reservation.Instances {
+			state := types.InstanceStateNameStopped
+			if instance.State != nil {
+				state = instance.State.Name
+			}
+			if stringValue(instance.RootDeviceName) == vol.Device && state != types.InstanceStateNameStopped {
+				return "", fmt.Errorf("%s is the root volume of %s which is %s, stop the instance first", volumeID, vol.InstanceID, state)
+			}
+			return fmt.Sprintf("It is attached to %s (%s) as %s", vol.InstanceID, state, vol.Device), nil
+		}
+	}
+	return fmt.Sprintf("It is attached to %s as %s", vol.InstanceID, vol.Device), nil
+}
+
+// detachVolume detaches a volume from its instance
+func (e *EBSVolumes) detachVolume(ctx context.Context, c *client.Client, volumeID string) error {
+	_, err := c.EC2().DetachVolume(ctx, &ec2.DetachVolumeInput{VolumeId: aws.String(volumeID)})
+	if err != nil {
+		return fmt.Errorf("failed to detach volume %s: %w", volumeID, err)
+	}
+	return nil
+}
+
+// volumeTemplate returns the editor template of the modifiable settings of a volume
+func volumeTemplate(vol EBSVolume) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Settings of %s, lines starting with # are ignored.\n", vol.displayName())
+	b.WriteString("# Size is in GiB and can only grow. Type is gp2, gp3, io1, io2, st1, sc1 or standard.\n")
+	b.WriteString("# IOPS apply to gp3, io1 and io2, the throughput in MiB/s to gp3, empty for the default.\n")
+	b.WriteString("# A volume can be modified once every 6 hours.\n")
+	fmt.Fprintf(&b, "Size: %d\n", vol.Size)
+	fmt.Fprintf(&b, "Type: %s\n", vol.Type)
+	if vol.IOPS > 0 && volumeHasIOPS(vol.Type) {
+		fmt.Fprintf(&b, "IOPS: %d\n", vol.IOPS)
+	} else {
+		b.WriteString("IOPS: \n")
+	}
+	if vol.Throughput > 0 {
+		fmt.Fprintf(&b, "Throughput: %d\n", vol.Throughput)
+	} else {
+		b.WriteString("Throughput: \n")
+	}
+	return b.String()
+}
+
+// volumeHasIOPS reports whether the IOPS of a volume type can be set
+func volumeHasIOPS(volumeType string) bool {
+	return volumeType == "gp3" || volumeType == "io1" || volumeType == "io2"
+}
+
+// parseVolumeTemplate parses and validates an edited volume template, unset IOPS and throughput are 0
+func parseVolumeTemplate(vol EBSVolume, text string) (EBSVolume, error) {
+	fields := make(map[string]string)
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+		field, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return vol, fmt.Errorf("expected a Field: value line, got %q", line)
+		}
+		fields[strings.ToLower(strings.TrimSpace(field))] = strings.TrimSpace(value)
+	}
+
+	after := vol
+	size, err := strconv.Atoi(fields["size"])
+	if err != nil || size <= 0 {
+		return vol, fmt.Errorf("size must be a number of GiB, got %q", fields["size"])
+	}
+	if int32(size) < vol.Size {
+		return vol, fmt.Errorf("a volume cannot shrink, %s is %d GiB", vol.VolumeID, vol.Size)
+	}
+	after.Size = int32(size)
+
+	after.Type = strings.ToLower(fields["type"])
+	switch after.Type {
+	case "gp2", "gp3", "io1", "io2", "st1", "sc1", "standard":
+	default:
+		return vol, fmt.Errorf("type must be gp2, gp3, io1, io2, st1, sc1 or standard, got %q", fields["type"])
+	}
+
+	after.IOPS, after.Throughput = 0, 0
+	if value := fields["iops"]; value != "" {
+		iops, err := strconv.Atoi(value)
+		if err != nil || iops <= 0 {
+			return vol, fmt.Errorf("IOPS must be a number, got %q", value)
+		}
+		if !volumeHasIOPS(after.Type) {
+			return vol, fmt.Errorf("the IOPS of %s volumes cannot be set", after.Type)
+		}
+		after.IOPS = int32(iops)
+	} else if after.Type == "io1" || after.Type == "io2" {
+		return vol, fmt.Errorf("%s volumes require IOPS", after.Type)
+	}
+	if value := fields["throughput"]; value != "" {
+		throughput, err := strconv.Atoi(value)
+		if err != nil || throughput <= 0 {
+			return vol, fmt.Errorf("throughput must be a number of MiB/s, got %q", value)
+		}
+		if after.Type != "gp3" {
+			return vol, fmt.Errorf("only the throughput of gp3 volumes can be set")
+		}
+		after.Throughput = int32(throughput)
+	}
+
+	return after, nil
+}
+
+// planModifyVolume validates an edited volume template and returns the modification as
+// steps, refusing it while the previous modification is younger than the cooldown
+func planModifyVolume(ctx context.Context, c *client.Client, before EBSVolume, text string) ([]Step, error) {
+	after, err := parseVolumeTemplate(before, text)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &ec2.ModifyVolumeInput{VolumeId: aws.String(before.VolumeID)}
+	changes := make([]string, 0)
+	if after.Size != before.Size {
+		input.Size = aws.Int32(after.Size)
+		changes = append(changes, fmt.Sprintf("size %d → %d GiB", before.Size, after.Size))
+	}
+	if after.Type != before.Type {
+		input.VolumeType = types.VolumeType(after.Type)
+		changes = append(changes, fmt.Sprintf("type %s → %s", before.Type, after.Type))
+	}
+	if after.IOPS != 0 && after.IOPS != before.IOPS {
+		input.Iops = aws.Int32(after.IOPS)
+		changes = append(changes, fmt.Sprintf("IOPS %d → %d", before.IOPS, after.IOPS))
+	}
+	if after.Throughput != 0 && after.Throughput != before.Throughput {
+		input.Throughput = aws.Int32(after.Throughput)
+		changes = append(changes, fmt.Sprintf("throughput %d → %d MiB/s", before.Throughput, after.Throughput))
+	}
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("the volume is unchanged")
+	}
+
+	output, err := c.EC2().DescribeVolumesModifications(ctx, &ec2.DescribeVolumesModificationsInput{
+		VolumeIds: []string{before.VolumeID},
+	})
+	var apiErr smithy.APIError
+	switch {
+	case errors.As(err, &apiErr) && apiErr.ErrorCode() == "InvalidVolumeModification.NotFound":
+		// The volume was never modified
+		output = &ec2.DescribeVolumesModificationsOutput{}
+	case err != nil:
+		return nil, fmt.Errorf("failed to describe modifications of %s: %w", before.VolumeID, err)
+	}
+	for _, modification := range output.VolumesModifications {
+		if modification.StartTime != nil && time.Since(*modification.StartTime) < volumeModificationCooldown {
+			return nil, fmt.Errorf("%s was modified at %s, it can be modified again from %s", before.VolumeID,
+				modification.StartTime.Local().Format("2006-01-02 15:04:05"),
+				modification.StartTime.Add(volumeModificationCooldown).Local().Format("2006-01-02 15:04:05"))
+		}
+	}
+
+	steps := []Step{
+		{
+			Description: fmt.Sprintf("Modify %s: %s", before.displayName(), strings.Join(changes, ", ")),
+			Run: func(ctx context.Context, c *client.Client) error {
+				if _, err := c.EC2().ModifyVolume(ctx, input); err != nil {
+					return fmt.Errorf("failed to modify volume %s: %w", before.VolumeID, err)
+				}
+				return nil
+			},
+		},
+	}
+	if after.Size != before.Size && before.InstanceID != "" {
+		steps = append(steps, Step{
+			Description: fmt.Sprintf("Then extend the partition and file system of %s on %s, e.g. with growpart and resize2fs or xfs_growfs", before.Device, before.InstanceID),
+		})
+	}
+	return steps, nil
+}
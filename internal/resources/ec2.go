@@ -3,9 +3,13 @@ package resources
 import (
 	"context"
 	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
 
 	"a9s/internal/client"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 )
@@ -20,17 +24,21 @@ type EC2Instance struct {
 	PublicIP         string
 	AvailabilityZone string
 	LaunchTime       string
+	Tags             map[string]string
 }
 
 // EC2Instances implements Resource for EC2 instances
 type EC2Instances struct {
 	instances []EC2Instance
+	sshRules  []SSHRule
 }
 
-// NewEC2Instances creates a new EC2Instances resource
-func NewEC2Instances() *EC2Instances {
+// NewEC2Instances creates a new EC2Instances resource, the SSH rules set the user
+// and key used to ssh into the instances
+func NewEC2Instances(sshRules []SSHRule) *EC2Instances {
 	return &EC2Instances{
 		instances: make([]EC2Instance, 0),
+		sshRules:  sshRules,
 	}
 }
 
@@ -85,13 +93,12 @@ func (e *EC2Instances) parseInstance(instance types.Instance) EC2Instance {
 		PublicIP:   stringValue(instance.PublicIpAddress),
 	}
 
-	// Get the Name tag
+	// Keep the tags, the Name one being displayed
+	inst.Tags = make(map[string]string, len(instance.Tags))
 	for _, tag := range instance.Tags {
-		if stringValue(tag.Key) == "Name" {
-			inst.Name = stringValue(tag.Value)
-			break
-		}
+		inst.Tags[stringValue(tag.Key)] = stringValue(tag.Value)
 	}
+	inst.Name = inst.Tags["Name"]
 
 	if instance.Placement != nil {
 		inst.AvailabilityZone = stringValue(instance.Placement.AvailabilityZone)
@@ -160,6 +167,65 @@ func (e *EC2Instances) QuickActions() []QuickAction {
 			ConfirmTemplate: "[yellow]restart[-] instance [white]%s[-]?",
 			Handler:         e.RestartInstance,
 		},
+		{
+			Key:             'X',
+			Label:           "terminate",
+			Description:     "Terminate instance",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "[red]terminate[-] instance [white]%s[-]? Its instance store and the volumes deleted on termination are lost",
+			ConfirmCheck:    e.checkTermination,
+			TypedConfirm:    true,
+			Handler:         e.TerminateInstance,
+		},
+		{
+			Key:            'T',
+			Label:          "resize",
+			Description:    "Change instance type",
+			NeedsSelection: true,
+			InputLabel:     "Type filter (* for all): ",
+			InputDefault:   e.typeFamily,
+			InputView: func(instanceID, filter string) Resource {
+				return NewEC2InstanceTypeChoices(instanceID, filter)
+			},
+		},
+		{
+			Key:            'h',
+			Label:          "ssh",
+			Description:    "Open an SSH session",
+			NeedsSelection: true,
+			Command: func(ctx context.Context, c *client.Client, instanceID string) (*exec.Cmd, error) {
+				for _, inst := range e.instances {
+					if inst.InstanceID == instanceID {
+						return sshCommand(inst, e.sshRules)
+					}
+				}
+				return nil, fmt.Errorf("instance %s not found", instanceID)
+			},
+		},
+		{
+			Key:            'x',
+			Label:          "shell",
+			Description:    "Open an SSM session",
+			NeedsSelection: true,
+			Command:        startSessionCommand,
+		},
+		{
+			Key:            'F',
+			Label:          "forward",
+			Description:    "Forward a port through SSM",
+			NeedsSelection: true,
+			InputLabel:     "Ports (local:remote or local:host:remote): ",
+			InputDefault:   func(string) string { return "2222:22" },
+			InputTunnel: func(ctx context.Context, c *client.Client, instanceID, input string) (*exec.Cmd, error) {
+				localPort, host, remotePort, err := parsePortForward(input)
+				if err != nil {
+					return nil, err
+				}
+				return portForwardCommand(ctx, c, instanceID, localPort, host, remotePort)
+			},
+		},
+		tagsAction(e),
 	}
 }
 
@@ -185,6 +251,110 @@ func (e *EC2Instances) StartInstance(ctx context.Context, c *client.Client, inst
 	return nil
 }
 
+// Tags returns the tags of an instance
+func (e *EC2Instances) Tags(ctx context.Context, c *client.Client, instanceID string) (map[string]string, error) {
+	tags := make(map[string]string)
+	paginator := ec2.NewDescribeTagsPaginator(c.EC2(), &ec2.DescribeTagsInput{
+		Filters: []types.Filter{{Name: aws.String("resource-id"), Values: []string{instanceID}}},
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe tags of %s: %w", instanceID, err)
+		}
+		for _, tag := range output.Tags {
+			tags[stringValue(tag.Key)] = stringValue(tag.Value)
+		}
+	}
+	return tags, nil
+}
+
+// SetTags adds or updates tags of an instance
+func (e *EC2Instances) SetTags(ctx context.Context, c *client.Client, instanceID string, tags map[string]string) error {
+	ec2Tags := make([]types.Tag, 0, len(tags))
+	for key, value := range tags {
+		ec2Tags = append(ec2Tags, types.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	_, err := c.EC2().CreateTags(ctx, &ec2.CreateTagsInput{
+		Resources: []string{instanceID},
+		Tags:      ec2Tags,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to tag %s: %w", instanceID, err)
+	}
+	return nil
+}
+
+// RemoveTags removes tags from an instance
+func (e *EC2Instances) RemoveTags(ctx context.Context, c *client.Client, instanceID string, keys []string) error {
+	ec2Tags := make([]types.Tag, 0, len(keys))
+	for _, key := range keys {
+		ec2Tags = append(ec2Tags, types.Tag{Key: aws.String(key)})
+	}
+	_, err := c.EC2().DeleteTags(ctx, &ec2.DeleteTagsInput{
+		Resources: []string{instanceID},
+		Tags:      ec2Tags,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to untag %s: %w", instanceID, err)
+	}
+	return nil
+}
+
+// typeFamily returns the family of the type of an instance, e.g. "m5." for m5.large
+func (e *EC2Instances) typeFamily(instanceID string) string {
+	for _, inst := range e.instances {
+		if inst.InstanceID == instanceID {
+			family, _, _ := strings.Cut(inst.Type, ".")
+			return family + "."
+		}
+	}
+	return "*"
+}
+
+// TerminateInstance terminates an EC2 instance
+func (e *EC2Instances) TerminateInstance(ctx context.Context, c *client.Client, instanceID string) error {
+	_, err := c.EC2().TerminateInstances(ctx, &ec2.TerminateInstancesInput{
+		InstanceIds: []string{instanceID},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to terminate instance %s: %w", instanceID, err)
+	}
+	return nil
+}
+
+// checkTermination refuses to terminate an instance with termination protection and
+// warns when it belongs to an Auto Scaling group, which would launch a replacement
+func (e *EC2Instances) checkTermination(ctx context.Context, c *client.Client, instanceID string) (string, error) {
+	attribute, err := c.EC2().DescribeInstanceAttribute(ctx, &ec2.DescribeInstanceAttributeInput{
+		InstanceId: &instanceID,
+		Attribute:  types.InstanceAttributeNameDisableApiTermination,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to check termination protection of %s: %w", instanceID, err)
+	}
+	if attribute.DisableApiTermination != nil && ptrBoolValue(attribute.DisableApiTermination.Value) {
+		return "", fmt.Errorf("termination protection is enabled on %s (disableApiTermination), disable it first", instanceID)
+	}
+
+	output, err := c.EC2().DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: []string{instanceID},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe instance %s: %w", instanceID, err)
+	}
+	for _, reservation := range output.Reservations {
+		for _, instance := range reservation.Instances {
+			for _, tag := range instance.Tags {
+				if stringValue(tag.Key) == "aws:autoscaling:groupName" {
+					return fmt.Sprintf("Warning: %s belongs to the Auto Scaling group %s, which will launch a replacement", instanceID, stringValue(tag.Value)), nil
+				}
+			}
+		}
+	}
+	return "", nil
+}
+
 // RestartInstance restarts (reboots) an EC2 instance
 func (e *EC2Instances) RestartInstance(ctx context.Context, c *client.Client, instanceID string) error {
 	_, err := c.EC2().RebootInstances(ctx, &ec2.RebootInstancesInput{
@@ -195,3 +365,237 @@ func (e *EC2Instances) RestartInstance(ctx context.Context, c *client.Client, in
 	}
 	return nil
 }
+
+// describeTypesBatch is the number of instance types described per call
+const describeTypesBatch = 100
+
+// EC2InstanceTypeChoice represents an instance type an instance can be resized to
+type EC2InstanceTypeChoice struct {
+	Type      string
+	VCPUs     int32
+	MemoryMiB int64
+	Network   string
+	Current   bool
+}
+
+// EC2InstanceTypeChoices implements Resource for the instance types offered in the
+// availability zone of an instance with the same architecture as its current type
+type EC2InstanceTypeChoices struct {
+	instanceID  string
+	filter      string
+	currentType string
+	zone        string
+	choices     []EC2InstanceTypeChoice
+}
+
+// NewEC2InstanceTypeChoices creates a new EC2InstanceTypeChoices resource, only the
+// types containing the filter are listed, "*" lists them all
+func NewEC2InstanceTypeChoices(instanceID, filter string) *EC2InstanceTypeChoices {
+	return &EC2InstanceTypeChoices{
+		instanceID: instanceID,
+		filter:     strings.ToLower(filter),
+		choices:    make([]EC2InstanceTypeChoice, 0),
+	}
+}
+
+// Name returns the display name
+func (e *EC2InstanceTypeChoices) Name() string {
+	return fmt.Sprintf("Instance Types (%s is %s in %s)", e.instanceID, e.currentType, e.zone)
+}
+
+// Columns returns the column definitions
+func (e *EC2InstanceTypeChoices) Columns() []Column {
+	return []Column{
+		{Name: "Type", Width: 20},
+		{Name: "vCPUs", Width: 6},
+		{Name: "Memory", Width: 10},
+		{Name: "Network", Width: 25},
+	}
+}
+
+// Fetch retrieves the types offered in the availability zone of the instance and
+// keeps the ones sharing an architecture with the current type
+func (e *EC2InstanceTypeChoices) Fetch(ctx context.Context, c *client.Client) error {
+	e.choices = make([]EC2InstanceTypeChoice, 0)
+
+	output, err := c.EC2().DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: []string{e.instanceID},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe instance %s: %w", e.instanceID, err)
+	}
+	if len(output.Reservations) == 0 || len(output.Reservations[0].Instances) == 0 {
+		return fmt.Errorf("instance %s not found", e.instanceID)
+	}
+	instance := output.Reservations[0].Instances[0]
+	e.currentType = string(instance.InstanceType)
+	if instance.Placement != nil {
+		e.zone = stringValue(instance.Placement.AvailabilityZone)
+	}
+
+	current, err := describeInstanceTypes(ctx, c, []types.InstanceType{instance.InstanceType})
+	if err != nil {
+		return err
+	}
+	architectures := make(map[types.ArchitectureType]bool)
+	for _, info := range current {
+		if info.ProcessorInfo != nil {
+			for _, arch := range info.ProcessorInfo.SupportedArchitectures {
+				architectures[arch] = true
+			}
+		}
+	}
+
+	offered := make([]types.InstanceType, 0)
+	paginator := ec2.NewDescribeInstanceTypeOfferingsPaginator(c.EC2(), &ec2.DescribeInstanceTypeOfferingsInput{
+		LocationType: types.LocationTypeAvailabilityZone,
+		Filters:      []types.Filter{{Name: aws.String("location"), Values: []string{e.zone}}},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe instance type offerings in %s: %w", e.zone, err)
+		}
+		for _, offering := range page.InstanceTypeOfferings {
+			if e.filter == "*" || strings.Contains(string(offering.InstanceType), e.filter) {
+				offered = append(offered, offering.InstanceType)
+			}
+		}
+	}
+
+	infos, err := describeInstanceTypes(ctx, c, offered)
+	if err != nil {
+		return err
+	}
+	for _, info := range infos {
+		compatible := false
+		if info.ProcessorInfo != nil {
+			for _, arch := range info.ProcessorInfo.SupportedArchitectures {
+				compatible = compatible || architectures[arch]
+			}
+		}
+		if !compatible {
+			continue
+		}
+
+		choice := EC2InstanceTypeChoice{
+			Type:    string(info.InstanceType),
+			Current: info.InstanceType == instance.InstanceType,
+		}
+		if info.VCpuInfo != nil {
+			choice.VCPUs = ptrInt32Value(info.VCpuInfo.DefaultVCpus)
+		}
+		if info.MemoryInfo != nil {
+			choice.MemoryMiB = ptrInt64Value(info.MemoryInfo.SizeInMiB)
+		}
+		if info.NetworkInfo != nil {
+			choice.Network = stringValue(info.NetworkInfo.NetworkPerformance)
+		}
+		e.choices = append(e.choices, choice)
+	}
+
+	sort.Slice(e.choices, func(i, j int) bool {
+		if e.choices[i].VCPUs != e.choices[j].VCPUs {
+			return e.choices[i].VCPUs < e.choices[j].VCPUs
+		}
+		if e.choices[i].MemoryMiB != e.choices[j].MemoryMiB {
+			return e.choices[i].MemoryMiB < e.choices[j].MemoryMiB
+		}
+		return e.choices[i].Type < e.choices[j].Type
+	})
+
+	return nil
+}
+
+// describeInstanceTypes describes instance types, in batches of the maximum allowed per call
+func describeInstanceTypes(ctx context.Context, c *client.Client, instanceTypes []types.InstanceType) ([]types.InstanceTypeInfo, error) {
+	infos := make([]types.InstanceTypeInfo, 0, len(instanceTypes))
+	for start := 0; start < len(instanceTypes); start += describeTypesBatch {
+		end := min(start+describeTypesBatch, len(instanceTypes))
+		output, err := c.EC2().DescribeInstanceTypes(ctx, &ec2.DescribeInstanceTypesInput{
+			InstanceTypes: instanceTypes[start:end],
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe instance types: %w", err)
+		}
+		infos = append(infos, output.InstanceTypes...)
+	}
+	return infos, nil
+}
+
+// Rows returns the table data
+func (e *EC2InstanceTypeChoices) Rows() [][]string {
+	rows := make([][]string, len(e.choices))
+	for i, choice := range e.choices {
+		rows[i] = []string{
+			choice.Type,
+			fmt.Sprintf("%d", choice.VCPUs),
+			fmt.Sprintf("%.1f GiB", float64(choice.MemoryMiB)/1024),
+			choice.Network,
+		}
+	}
+	return rows
+}
+
+// GetID returns the instance type at the given index
+func (e *EC2InstanceTypeChoices) GetID(index int) string {
+	if index >= 0 && index < len(e.choices) {
+		return e.choices[index].Type
+	}
+	return ""
+}
+
+// Highlight flags the current type of the instance
+func (e *EC2InstanceTypeChoices) Highlight(index int) bool {
+	return index >= 0 && index < len(e.choices) && e.choices[index].Current
+}
+
+// QuickActions returns the available quick actions for instance type choices
+func (e *EC2InstanceTypeChoices) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:             'R',
+			Label:           "resize",
+			Description:     "Change instance type",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: fmt.Sprintf("[yellow]resize[-] instance [white]%s[-] to [white]%%s[-]?", e.instanceID),
+			ConfirmCheck:    e.checkStopped,
+			Handler:         e.resize,
+		},
+	}
+}
+
+// checkStopped refuses to resize an instance which is not stopped
+func (e *EC2InstanceTypeChoices) checkStopped(ctx context.Context, c *client.Client, instanceType string) (string, error) {
+	if instanceType == e.currentType {
+		return "", fmt.Errorf("%s is already %s", e.instanceID, instanceType)
+	}
+
+	output, err := c.EC2().DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: []string{e.instanceID},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe instance %s: %w", e.instanceID, err)
+	}
+	for _, reservation := range output.Reservations {
+		for _, instance := range reservation.Instances {
+			if instance.State != nil && instance.State.Name != types.InstanceStateNameStopped {
+				return "", fmt.Errorf("%s is %s, stop it first", e.instanceID, instance.State.Name)
+			}
+		}
+	}
+	return "", nil
+}
+
+// resize changes the type of the stopped instance
+func (e *EC2InstanceTypeChoices) resize(ctx context.Context, c *client.Client, instanceType string) error {
+	_, err := c.EC2().ModifyInstanceAttribute(ctx, &ec2.ModifyInstanceAttributeInput{
+		InstanceId:   &e.instanceID,
+		InstanceType: &types.AttributeValue{Value: &instanceType},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to change the type of %s: %w", e.instanceID, err)
+	}
+	return nil
+}
@@ -3,10 +3,14 @@ package resources
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"a9s/internal/client"
 
 	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
 )
 
 // ECRRepository represents an ECR repository
@@ -125,5 +129,353 @@ func (e *ECRRepositories) GetID(index int) string {
 
 // QuickActions returns the available quick actions for ECR repositories
 func (e *ECRRepositories) QuickActions() []QuickAction {
-	return []QuickAction{}
+	return []QuickAction{
+		{
+			Key:             'U',
+			Label:           "delete untagged",
+			Description:     "Delete the untagged images of the repository",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "[red]Delete[-] the untagged images of [white]%s[-]?",
+			ConfirmCheck: func(ctx context.Context, c *client.Client, name string) (string, error) {
+				images, err := untaggedImages(ctx, c, name)
+				if err != nil {
+					return "", err
+				}
+				if len(images) == 0 {
+					return "", fmt.Errorf("%s has no untagged images", name)
+				}
+				var size int64
+				for _, image := range images {
+					size += ptrInt64Value(image.ImageSizeInBytes)
+				}
+				return fmt.Sprintf("%d untagged images, %s, will be deleted.", len(images), formatSize(size)), nil
+			},
+			Handler: deleteUntaggedImages,
+		},
+	}
+}
+
+// ecrDeleteBatchSize is the maximum number of images of a BatchDeleteImage call
+const ecrDeleteBatchSize = 100
+
+// untaggedImages lists the untagged images of a repository
+func untaggedImages(ctx context.Context, c *client.Client, repository string) ([]ecrtypes.ImageDetail, error) {
+	images := make([]ecrtypes.ImageDetail, 0)
+
+	paginator := ecr.NewDescribeImagesPaginator(c.ECR(), &ecr.DescribeImagesInput{
+		RepositoryName: &repository,
+		Filter:         &ecrtypes.DescribeImagesFilter{TagStatus: ecrtypes.TagStatusUntagged},
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe images of %s: %w", repository, err)
+		}
+		images = append(images, output.ImageDetails...)
+	}
+
+	return images, nil
+}
+
+// deleteUntaggedImages deletes the untagged images of a repository, listed again so that
+// an image tagged since the confirmation is kept
+func deleteUntaggedImages(ctx context.Context, c *client.Client, repository string) error {
+	images, err := untaggedImages(ctx, c, repository)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]ecrtypes.ImageIdentifier, 0, len(images))
+	for _, image := range images {
+		ids = append(ids, ecrtypes.ImageIdentifier{ImageDigest: image.ImageDigest})
+	}
+
+	failed := 0
+	for start := 0; start < len(ids); start += ecrDeleteBatchSize {
+		batch := ids[start:min(start+ecrDeleteBatchSize, len(ids))]
+		output, err := c.ECR().BatchDeleteImage(ctx, &ecr.BatchDeleteImageInput{
+			RepositoryName: &repository,
+			ImageIds:       batch,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete images of %s: %w", repository, err)
+		}
+		failed += len(output.Failures)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d of the %d untagged images of %s", failed, len(ids), repository)
+	}
+	return nil
+}
+
+// DrillDown opens the images of the repository
+func (e *ECRRepositories) DrillDown(name string) Resource {
+	return NewECRImages(name)
+}
+
+// ECRImage represents an image of an ECR repository
+type ECRImage struct {
+	Digest   string
+	Tags     string
+	Size     int64
+	PushedAt time.Time
+	Critical int32
+	High     int32
+	Scan     string
+}
+
+// ECRImages implements Resource for the images of an ECR repository, newest first
+type ECRImages struct {
+	repository string
+	images     []ECRImage
+}
+
+// NewECRImages creates a new ECRImages resource
+func NewECRImages(repository string) *ECRImages {
+	return &ECRImages{
+		repository: repository,
+		images:     make([]ECRImage, 0),
+	}
+}
+
+// Name returns the display name
+func (e *ECRImages) Name() string {
+	return fmt.Sprintf("ECR Images: %s", e.repository)
+}
+
+// Columns returns the column definitions
+func (e *ECRImages) Columns() []Column {
+	return []Column{
+		{Name: "Tags", Width: 30},
+		{Name: "Digest", Width: 75},
+		{Name: "Size", Width: 10},
+		{Name: "Pushed At", Width: 20},
+		{Name: "Critical", Width: 8},
+		{Name: "High", Width: 8},
+		{Name: "Scan", Width: 12},
+	}
+}
+
+// Fetch retrieves the images of the repository with their scan findings summary
+func (e *ECRImages) Fetch(ctx context.Context, c *client.Client) error {
+	e.images = make([]ECRImage, 0)
+
+	paginator := ecr.NewDescribeImagesPaginator(c.ECR(), &ecr.DescribeImagesInput{
+		RepositoryName: &e.repository,
+	})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe images of %s: %w", e.repository, err)
+		}
+
+		for _, detail := range output.ImageDetails {
+			image := ECRImage{
+				Digest: stringValue(detail.ImageDigest),
+				Tags:   strings.Join(detail.ImageTags, ","),
+				Size:   ptrInt64Value(detail.ImageSizeInBytes),
+				Scan:   "-",
+			}
+			if image.Tags == "" {
+				image.Tags = "<untagged>"
+			}
+			if detail.ImagePushedAt != nil {
+				image.PushedAt = *detail.ImagePushedAt
+			}
+			if detail.ImageScanStatus != nil {
+				image.Scan = string(detail.ImageScanStatus.Status)
+			}
+			if detail.ImageScanFindingsSummary != nil {
+				counts := detail.ImageScanFindingsSummary.FindingSeverityCounts
+				image.Critical = counts[string(ecrtypes.FindingSeverityCritical)]
+				image.High = counts[string(ecrtypes.FindingSeverityHigh)]
+			}
+
+			e.images = append(e.images, image)
+		}
+	}
+
+	sort.Slice(e.images, func(i, j int) bool {
+		return e.images[i].PushedAt.After(e.images[j].PushedAt)
+	})
+
+	return nil
+}
+
+// Rows returns the table data
+func (e *ECRImages) Rows() [][]string {
+	rows := make([][]string, len(e.images))
+	for i, image := range e.images {
+		pushedAt := ""
+		if !image.PushedAt.IsZero() {
+			pushedAt = image.PushedAt.Format("2006-01-02 15:04:05")
+		}
+		rows[i] = []string{
+			image.Tags,
+			image.Digest,
+			formatSize(image.Size),
+			pushedAt,
+			fmt.Sprintf("%d", image.Critical),
+			fmt.Sprintf("%d", image.High),
+			image.Scan,
+		}
+	}
+	return rows
+}
+
+// GetID returns the image digest at the given index
+func (e *ECRImages) GetID(index int) string {
+	if index >= 0 && index < len(e.images) {
+		return e.images[index].Digest
+	}
+	return ""
+}
+
+// Highlight flags the images with critical findings
+func (e *ECRImages) Highlight(index int) bool {
+	return index >= 0 && index < len(e.images) && e.images[index].Critical > 0
+}
+
+// QuickActions returns the available quick actions for ECR images
+func (e *ECRImages) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:             'D',
+			Label:           "delete",
+			Description:     "Delete image",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "Are you sure you want to delete image %s and all its tags?",
+			Handler: func(ctx context.Context, c *client.Client, digest string) error {
+				output, err := c.ECR().BatchDeleteImage(ctx, &ecr.BatchDeleteImageInput{
+					RepositoryName: &e.repository,
+					ImageIds:       []ecrtypes.ImageIdentifier{{ImageDigest: &digest}},
+				})
+				if err != nil {
+					return fmt.Errorf("failed to delete image %s: %w", digest, err)
+				}
+				if len(output.Failures) > 0 {
+					return fmt.Errorf("failed to delete image %s: %s", digest, stringValue(output.Failures[0].FailureReason))
+				}
+				return nil
+			},
+		},
+		{
+			Key:            'S',
+			Label:          "scan",
+			Description:    "Start image scan",
+			NeedsSelection: true,
+			Handler: func(ctx context.Context, c *client.Client, digest string) error {
+				_, err := c.ECR().StartImageScan(ctx, &ecr.StartImageScanInput{
+					RepositoryName: &e.repository,
+					ImageId:        &ecrtypes.ImageIdentifier{ImageDigest: &digest},
+				})
+				if err != nil {
+					return fmt.Errorf("failed to start scan of %s: %w", digest, err)
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// ecrImage is a container image reference to an ECR repository
+type ecrImage struct {
+	RegistryID string
+	Region     string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// parseECRImage parses an image URI such as
+// 123456789012.dkr.ecr.eu-west-1.amazonaws.com/app:1.2 or .../app@sha256:...
+func parseECRImage(uri string) (ecrImage, bool) {
+	host, path, ok := strings.Cut(uri, "/")
+	if !ok {
+		return ecrImage{}, false
+	}
+	parts := strings.Split(host, ".")
+	if len(parts) < 6 || parts[1] != "dkr" || parts[2] != "ecr" {
+		return ecrImage{}, false
+	}
+
+	image := ecrImage{RegistryID: parts[0], Region: parts[3], Repository: path, Tag: "latest"}
+	if repository, digest, ok := strings.Cut(path, "@"); ok {
+		image.Repository, image.Tag, image.Digest = repository, "", digest
+	} else if i := strings.LastIndex(path, ":"); i >= 0 {
+		image.Repository, image.Tag = path[:i], path[i+1:]
+	}
+	return image, true
+}
+
+// formatFindings renders scan finding counts from the most severe, e.g. "CRITICAL: 1, HIGH: 3"
+func formatFindings(counts map[string]int32) string {
+	order := map[string]int{"CRITICAL": 0, "HIGH": 1, "MEDIUM": 2, "LOW": 3, "INFORMATIONAL": 4, "UNDEFINED": 5}
+	severities := make([]string, 0, len(counts))
+	for severity := range counts {
+		severities = append(severities, severity)
+	}
+	sort.Slice(severities, func(i, j int) bool {
+		return order[severities[i]] < order[severities[j]]
+	})
+
+	parts := make([]string, 0, len(severities))
+	for _, severity := range severities {
+		parts = append(parts, fmt.Sprintf("%s: %d", severity, counts[severity]))
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// imageProvenance resolves an image reference to its ECR digest, push date and
+// scan findings, the registry may be in another region than the current one
+func imageProvenance(ctx context.Context, c *client.Client, uri string) (string, error) {
+	image, ok := parseECRImage(uri)
+	if !ok {
+		return fmt.Sprintf("%s\n  not an ECR image\n", uri), nil
+	}
+
+	id := ecrtypes.ImageIdentifier{}
+	if image.Digest != "" {
+		id.ImageDigest = &image.Digest
+	} else {
+		id.ImageTag = &image.Tag
+	}
+	output, err := c.ECR().DescribeImages(ctx, &ecr.DescribeImagesInput{
+		RegistryId:     &image.RegistryID,
+		RepositoryName: &image.Repository,
+		ImageIds:       []ecrtypes.ImageIdentifier{id},
+	}, func(o *ecr.Options) {
+		o.Region = image.Region
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe image %s: %w", uri, err)
+	}
+	if len(output.ImageDetails) == 0 {
+		return fmt.Sprintf("%s\n  not found in %s\n", uri, image.Repository), nil
+	}
+
+	detail := output.ImageDetails[0]
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", uri)
+	fmt.Fprintf(&b, "  Repository: %s (%s, %s)\n", image.Repository, image.RegistryID, image.Region)
+	fmt.Fprintf(&b, "  Digest:     %s\n", stringValue(detail.ImageDigest))
+	fmt.Fprintf(&b, "  Tags:       %s\n", strings.Join(detail.ImageTags, ", "))
+	if detail.ImagePushedAt != nil {
+		fmt.Fprintf(&b, "  Pushed at:  %s\n", detail.ImagePushedAt.Format("2006-01-02 15:04:05"))
+	}
+	switch {
+	case detail.ImageScanFindingsSummary != nil:
+		fmt.Fprintf(&b, "  Findings:   %s\n", formatFindings(detail.ImageScanFindingsSummary.FindingSeverityCounts))
+	case detail.ImageScanStatus != nil:
+		fmt.Fprintf(&b, "  Scan:       %s\n", detail.ImageScanStatus.Status)
+	default:
+		fmt.Fprintf(&b, "  Scan:       never scanned\n")
+	}
+	return b.String(), nil
 }
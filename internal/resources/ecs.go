@@ -3,10 +3,14 @@ package resources
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"a9s/internal/client"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
 )
 
 // ECSCluster represents an ECS cluster
@@ -112,3 +116,306 @@ func (e *ECSClusters) GetID(index int) string {
 func (e *ECSClusters) QuickActions() []QuickAction {
 	return []QuickAction{}
 }
+
+// DrillDown returns the services of a cluster
+func (e *ECSClusters) DrillDown(clusterName string) Resource {
+	return NewECSServices(clusterName)
+}
+
+// ecsDescribeServicesBatch is the number of services DescribeServices accepts at once
+const ecsDescribeServicesBatch = 10
+
+// ECSService represents an ECS service with the progress of its deployments
+type ECSService struct {
+	Name           string
+	Status         string
+	DesiredCount   int32
+	RunningCount   int32
+	PendingCount   int32
+	Rollout        string
+	Deployments    int
+	TaskDefinition string
+}
+
+// ECSServices implements Resource for the services of an ECS cluster
+type ECSServices struct {
+	cluster  string
+	services []ECSService
+}
+
+// NewECSServices creates a new ECSServices resource
+func NewECSServices(cluster string) *ECSServices {
+	return &ECSServices{
+		cluster:  cluster,
+		services: make([]ECSService, 0),
+	}
+}
+
+// Name returns the display name
+func (e *ECSServices) Name() string {
+	return fmt.Sprintf("ECS Services (%s)", e.cluster)
+}
+
+// Columns returns the column definitions
+func (e *ECSServices) Columns() []Column {
+	return []Column{
+		{Name: "Service", Width: 35},
+		{Name: "Status", Width: 10},
+		{Name: "Running/Desired", Width: 16},
+		{Name: "Pending", Width: 8},
+		{Name: "Rollout", Width: 12},
+		{Name: "Deployments", Width: 12},
+		{Name: "Task Definition", Width: 40},
+	}
+}
+
+// Fetch retrieves the services of the cluster
+func (e *ECSServices) Fetch(ctx context.Context, c *client.Client) error {
+	e.services = make([]ECSService, 0)
+
+	arns := make([]string, 0)
+	paginator := ecs.NewListServicesPaginator(c.ECS(), &ecs.ListServicesInput{
+		Cluster: &e.cluster,
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list services of %s: %w", e.cluster, err)
+		}
+		arns = append(arns, output.ServiceArns...)
+	}
+
+	for start := 0; start < len(arns); start += ecsDescribeServicesBatch {
+		end := min(start+ecsDescribeServicesBatch, len(arns))
+		output, err := c.ECS().DescribeServices(ctx, &ecs.DescribeServicesInput{
+			Cluster:  &e.cluster,
+			Services: arns[start:end],
+		})
+		if err != nil {
+			return fmt.Errorf("failed to describe services of %s: %w", e.cluster, err)
+		}
+
+		for _, service := range output.Services {
+			svc := ECSService{
+				Name:           stringValue(service.ServiceName),
+				Status:         stringValue(service.Status),
+				DesiredCount:   service.DesiredCount,
+				RunningCount:   service.RunningCount,
+				PendingCount:   service.PendingCount,
+				Deployments:    len(service.Deployments),
+				TaskDefinition: taskDefinitionName(stringValue(service.TaskDefinition)),
+			}
+			for _, deployment := range service.Deployments {
+				if stringValue(deployment.Status) == "PRIMARY" {
+					svc.Rollout = string(deployment.RolloutState)
+				}
+			}
+			e.services = append(e.services, svc)
+		}
+	}
+
+	return nil
+}
+
+// taskDefinitionName returns the family:revision of a task definition ARN
+func taskDefinitionName(arn string) string {
+	if i := strings.LastIndex(arn, "/"); i >= 0 {
+		return arn[i+1:]
+	}
+	return arn
+}
+
+// Rows returns the table data
+func (e *ECSServices) Rows() [][]string {
+	rows := make([][]string, len(e.services))
+	for i, service := range e.services {
+		rows[i] = []string{
+			service.Name,
+			service.Status,
+			fmt.Sprintf("%d/%d", service.RunningCount, service.DesiredCount),
+			fmt.Sprintf("%d", service.PendingCount),
+			service.Rollout,
+			fmt.Sprintf("%d", service.Deployments),
+			service.TaskDefinition,
+		}
+	}
+	return rows
+}
+
+// GetID returns the service name at the given index
+func (e *ECSServices) GetID(index int) string {
+	if index >= 0 && index < len(e.services) {
+		return e.services[index].Name
+	}
+	return ""
+}
+
+// Highlight flags the services not running their desired count, e.g. while scaling or deploying
+func (e *ECSServices) Highlight(index int) bool {
+	if index < 0 || index >= len(e.services) {
+		return false
+	}
+	return e.services[index].RunningCount != e.services[index].DesiredCount || e.services[index].Deployments > 1
+}
+
+// QuickActions returns the available quick actions for ECS services
+func (e *ECSServices) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:             's',
+			Label:           "scale",
+			Description:     "Set the desired count",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "[yellow]scale[-] service [white]%s[-]?",
+			InputLabel:      "Desired count: ",
+			InputDefault: func(name string) string {
+				for _, service := range e.services {
+					if service.Name == name {
+						return fmt.Sprintf("%d", service.DesiredCount)
+					}
+				}
+				return ""
+			},
+			InputHandler: e.ScaleService,
+		},
+	}
+}
+
+// ScaleService sets the desired count of a service, its progress shows on the next refreshes
+func (e *ECSServices) ScaleService(ctx context.Context, c *client.Client, name, input string) error {
+	count, err := strconv.Atoi(input)
+	if err != nil || count < 0 {
+		return fmt.Errorf("invalid desired count %q", input)
+	}
+
+	_, err = c.ECS().UpdateService(ctx, &ecs.UpdateServiceInput{
+		Cluster:      &e.cluster,
+		Service:      &name,
+		DesiredCount: aws.Int32(int32(count)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scale service %s: %w", name, err)
+	}
+	return nil
+}
+
+// ECSTaskDefinition represents the latest active revision of a task definition family
+type ECSTaskDefinition struct {
+	Family   string
+	Revision string
+	ARN      string
+}
+
+// ECSTaskDefinitions implements Resource for ECS task definitions
+type ECSTaskDefinitions struct {
+	definitions []ECSTaskDefinition
+}
+
+// NewECSTaskDefinitions creates a new ECSTaskDefinitions resource
+func NewECSTaskDefinitions() *ECSTaskDefinitions {
+	return &ECSTaskDefinitions{
+		definitions: make([]ECSTaskDefinition, 0),
+	}
+}
+
+// Name returns the display name
+func (e *ECSTaskDefinitions) Name() string {
+	return "ECS Task Definitions"
+}
+
+// Columns returns the column definitions
+func (e *ECSTaskDefinitions) Columns() []Column {
+	return []Column{
+		{Name: "Family", Width: 40},
+		{Name: "Revision", Width: 10},
+		{Name: "ARN", Width: 80},
+	}
+}
+
+// Fetch retrieves the latest active revision of each task definition family
+func (e *ECSTaskDefinitions) Fetch(ctx context.Context, c *client.Client) error {
+	e.definitions = make([]ECSTaskDefinition, 0)
+
+	paginator := ecs.NewListTaskDefinitionsPaginator(c.ECS(), &ecs.ListTaskDefinitionsInput{
+		Status: ecstypes.TaskDefinitionStatusActive,
+		Sort:   ecstypes.SortOrderDesc,
+	})
+
+	seen := make(map[string]bool)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list ECS task definitions: %w", err)
+		}
+
+		// ARN format: arn:aws:ecs:region:account-id:task-definition/family:revision
+		for _, arn := range output.TaskDefinitionArns {
+			name := arn[strings.LastIndex(arn, "/")+1:]
+			family, revision, _ := strings.Cut(name, ":")
+			if seen[family] {
+				continue
+			}
+			seen[family] = true
+
+			e.definitions = append(e.definitions, ECSTaskDefinition{
+				Family:   family,
+				Revision: revision,
+				ARN:      arn,
+			})
+		}
+	}
+
+	return nil
+}
+
+// Rows returns the table data
+func (e *ECSTaskDefinitions) Rows() [][]string {
+	rows := make([][]string, len(e.definitions))
+	for i, definition := range e.definitions {
+		rows[i] = []string{
+			definition.Family,
+			definition.Revision,
+			definition.ARN,
+		}
+	}
+	return rows
+}
+
+// GetID returns the task definition ARN at the given index
+func (e *ECSTaskDefinitions) GetID(index int) string {
+	if index >= 0 && index < len(e.definitions) {
+		return e.definitions[index].ARN
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for ECS task definitions
+func (e *ECSTaskDefinitions) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:            'P',
+			Label:          "provenance",
+			Description:    "Show container image provenance",
+			NeedsSelection: true,
+			TextHandler: func(ctx context.Context, c *client.Client, arn string) (string, error) {
+				output, err := c.ECS().DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{
+					TaskDefinition: &arn,
+				})
+				if err != nil {
+					return "", fmt.Errorf("failed to describe task definition %s: %w", arn, err)
+				}
+
+				var b strings.Builder
+				for _, container := range output.TaskDefinition.ContainerDefinitions {
+					provenance, err := imageProvenance(ctx, c, stringValue(container.Image))
+					if err != nil {
+						return "", err
+					}
+					fmt.Fprintf(&b, "[%s] %s\n", stringValue(container.Name), provenance)
+				}
+				return b.String(), nil
+			},
+		},
+	}
+}
@@ -0,0 +1,321 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// ElasticIP represents an Elastic IP address
+type ElasticIP struct {
+	AllocationID     string
+	PublicIP         string
+	Name             string
+	InstanceID       string
+	NetworkInterface string
+	PrivateIP        string
+	AssociationID    string
+}
+
+// ElasticIPs implements Resource for Elastic IP addresses
+type ElasticIPs struct {
+	addresses []ElasticIP
+}
+
+// NewElasticIPs creates a new ElasticIPs resource
+func NewElasticIPs() *ElasticIPs {
+	return &ElasticIPs{
+		addresses: make([]ElasticIP, 0),
+	}
+}
+
+// Name returns the display name
+func (e *ElasticIPs) Name() string {
+	return "Elastic IPs"
+}
+
+// Columns returns the column definitions
+func (e *ElasticIPs) Columns() []Column {
+	return []Column{
+		{Name: "Public IP", Width: 16},
+		{Name: "Allocation ID", Width: 28},
+		{Name: "Name", Width: 30},
+		{Name: "Instance", Width: 20},
+		{Name: "Network Interface", Width: 22},
+		{Name: "Private IP", Width: 16},
+	}
+}
+
+// Fetch retrieves Elastic IP addresses from AWS
+func (e *ElasticIPs) Fetch(ctx context.Context, c *client.Client) error {
+	e.addresses = make([]ElasticIP, 0)
+
+	output, err := c.EC2().DescribeAddresses(ctx, &ec2.DescribeAddressesInput{})
+	if err != nil {
+		return fmt.Errorf("failed to describe addresses: %w", err)
+	}
+
+	for _, address := range output.Addresses {
+		eip := ElasticIP{
+			AllocationID:     stringValue(address.AllocationId),
+			PublicIP:         stringValue(address.PublicIp),
+			InstanceID:       stringValue(address.InstanceId),
+			NetworkInterface: stringValue(address.NetworkInterfaceId),
+			PrivateIP:        stringValue(address.PrivateIpAddress),
+			AssociationID:    stringValue(address.AssociationId),
+		}
+		for _, tag := range address.Tags {
+			if stringValue(tag.Key) == "Name" {
+				eip.Name = stringValue(tag.Value)
+				break
+			}
+		}
+		e.addresses = append(e.addresses, eip)
+	}
+
+	return nil
+}
+
+// Rows returns the table data
+func (e *ElasticIPs) Rows() [][]string {
+	rows := make([][]string, len(e.addresses))
+	for i, eip := range e.addresses {
+		rows[i] = []string{
+			eip.PublicIP,
+			eip.AllocationID,
+			eip.Name,
+			eip.InstanceID,
+			eip.NetworkInterface,
+			eip.PrivateIP,
+		}
+	}
+	return rows
+}
+
+// GetID returns the allocation ID at the given index
+func (e *ElasticIPs) GetID(index int) string {
+	if index >= 0 && index < len(e.addresses) {
+		return e.addresses[index].AllocationID
+	}
+	return ""
+}
+
+// Highlight flags the addresses associated with nothing, which are billed
+func (e *ElasticIPs) Highlight(index int) bool {
+	return index >= 0 && index < len(e.addresses) && e.addresses[index].AssociationID == ""
+}
+
+// QuickActions returns the available quick actions for Elastic IPs
+func (e *ElasticIPs) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:            'A',
+			Label:          "associate",
+			Description:    "Associate the address with an instance",
+			NeedsSelection: true,
+			View: func(allocationID string) Resource {
+				return NewEIPInstancePicker(e.address(allocationID))
+			},
+		},
+		{
+			Key:             'x',
+			Label:           "disassociate",
+			Description:     "Disassociate the address",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "[yellow]disassociate[-] address [white]%s[-]?",
+			ConfirmCheck: func(ctx context.Context, c *client.Client, allocationID string) (string, error) {
+				eip := e.address(allocationID)
+				if eip.AssociationID == "" {
+					return "", fmt.Errorf("%s is not associated", eip.PublicIP)
+				}
+				return fmt.Sprintf("%s is associated with %s, which loses it", eip.PublicIP, eip.target()), nil
+			},
+			Handler: func(ctx context.Context, c *client.Client, allocationID string) error {
+				return disassociateAddress(ctx, c, e.address(allocationID))
+			},
+		},
+		{
+			Key:             'D',
+			Label:           "release",
+			Description:     "Release the address",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			TypedConfirm:    true,
+			ConfirmTemplate: "[red]release[-] address [white]%s[-]? The IP may never be allocated again",
+			ConfirmCheck: func(ctx context.Context, c *client.Client, allocationID string) (string, error) {
+				eip := e.address(allocationID)
+				if eip.AssociationID == "" {
+					return "", nil
+				}
+				return fmt.Sprintf("Warning: %s is in use by %s, it is disassociated first", eip.PublicIP, eip.target()), nil
+			},
+			Handler: func(ctx context.Context, c *client.Client, allocationID string) error {
+				eip := e.address(allocationID)
+				if eip.AssociationID != "" {
+					if err := disassociateAddress(ctx, c, eip); err != nil {
+						return err
+					}
+				}
+				_, err := c.EC2().ReleaseAddress(ctx, &ec2.ReleaseAddressInput{AllocationId: aws.String(allocationID)})
+				if err != nil {
+					return fmt.Errorf("failed to release address %s: %w", eip.PublicIP, err)
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// address returns the listed address with the given allocation ID
+func (e *ElasticIPs) address(allocationID string) ElasticIP {
+	for _, eip := range e.addresses {
+		if eip.AllocationID == allocationID {
+			return eip
+		}
+	}
+	return ElasticIP{AllocationID: allocationID, PublicIP: allocationID}
+}
+
+// target returns the instance or network interface the address is associated with
+func (e ElasticIP) target() string {
+	if e.InstanceID != "" {
+		return e.InstanceID
+	}
+	return e.NetworkInterface
+}
+
+// disassociateAddress removes the association of an address
+func disassociateAddress(ctx context.Context, c *client.Client, eip ElasticIP) error {
+	_, err := c.EC2().DisassociateAddress(ctx, &ec2.DisassociateAddressInput{AssociationId: aws.String(eip.AssociationID)})
+	if err != nil {
+		return fmt.Errorf("failed to disassociate address %s: %w", eip.PublicIP, err)
+	}
+	return nil
+}
+
+// EIPInstancePicker implements Resource for the instances an Elastic IP can be associated with
+type EIPInstancePicker struct {
+	address   ElasticIP
+	instances []EC2Instance
+}
+
+// NewEIPInstancePicker creates a new EIPInstancePicker resource
+func NewEIPInstancePicker(address ElasticIP) *EIPInstancePicker {
+	return &EIPInstancePicker{
+		address:   address,
+		instances: make([]EC2Instance, 0),
+	}
+}
+
+// Name returns the display name
+func (p *EIPInstancePicker) Name() string {
+	return fmt.Sprintf("Associate %s", p.address.PublicIP)
+}
+
+// Columns returns the column definitions
+func (p *EIPInstancePicker) Columns() []Column {
+	return []Column{
+		{Name: "ID", Width: 20},
+		{Name: "Name", Width: 30},
+		{Name: "State", Width: 12},
+		{Name: "Private IP", Width: 16},
+		{Name: "Public IP", Width: 16},
+		{Name: "AZ", Width: 15},
+	}
+}
+
+// Fetch retrieves the running and stopped instances
+func (p *EIPInstancePicker) Fetch(ctx context.Context, c *client.Client) error {
+	p.instances = make([]EC2Instance, 0)
+
+	paginator := ec2.NewDescribeInstancesPaginator(c.EC2(), &ec2.DescribeInstancesInput{
+		Filters: []types.Filter{{Name: aws.String("instance-state-name"), Values: []string{"running", "stopped"}}},
+	})
+	parser := NewEC2Instances(nil)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe EC2 instances: %w", err)
+		}
+		for _, reservation := range output.Reservations {
+			for _, instance := range reservation.Instances {
+				p.instances = append(p.instances, parser.parseInstance(instance))
+			}
+		}
+	}
+
+	return nil
+}
+
+// Rows returns the table data
+func (p *EIPInstancePicker) Rows() [][]string {
+	rows := make([][]string, len(p.instances))
+	for i, inst := range p.instances {
+		rows[i] = []string{
+			inst.InstanceID,
+			inst.Name,
+			inst.State,
+			inst.PrivateIP,
+			inst.PublicIP,
+			inst.AvailabilityZone,
+		}
+	}
+	return rows
+}
+
+// GetID returns the instance ID at the given index
+func (p *EIPInstancePicker) GetID(index int) string {
+	if index >= 0 && index < len(p.instances) {
+		return p.instances[index].InstanceID
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for the instance picker
+func (p *EIPInstancePicker) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:             'A',
+			Label:           "associate",
+			Description:     "Associate the address with the instance",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: fmt.Sprintf("[green]associate[-] %s with [white]%%s[-]?", p.address.PublicIP),
+			ConfirmCheck:    p.checkAssociate,
+			Handler: func(ctx context.Context, c *client.Client, instanceID string) error {
+				_, err := c.EC2().AssociateAddress(ctx, &ec2.AssociateAddressInput{
+					AllocationId:       aws.String(p.address.AllocationID),
+					InstanceId:         aws.String(instanceID),
+					AllowReassociation: aws.Bool(true),
+				})
+				if err != nil {
+					return fmt.Errorf("failed to associate %s with %s: %w", p.address.PublicIP, instanceID, err)
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// checkAssociate warns when the address moves from another instance, or when the
+// instance loses its current public IP
+func (p *EIPInstancePicker) checkAssociate(ctx context.Context, c *client.Client, instanceID string) (string, error) {
+	if p.address.InstanceID == instanceID {
+		return "", fmt.Errorf("%s is already associated with %s", p.address.PublicIP, instanceID)
+	}
+	if p.address.AssociationID != "" {
+		return fmt.Sprintf("Warning: %s is moved away from %s", p.address.PublicIP, p.address.target()), nil
+	}
+	for _, inst := range p.instances {
+		if inst.InstanceID == instanceID && inst.PublicIP != "" {
+			return fmt.Sprintf("It replaces the public IP %s of the instance", inst.PublicIP), nil
+		}
+	}
+	return "", nil
+}
@@ -2,11 +2,18 @@ package resources
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 
 	"a9s/internal/client"
 
 	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
 )
 
 // EKSCluster represents an EKS cluster
@@ -118,5 +125,604 @@ func (e *EKSClusters) GetID(index int) string {
 
 // QuickActions returns the available quick actions for EKS clusters
 func (e *EKSClusters) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:            'F',
+			Label:          "fargate",
+			Description:    "Show Fargate profiles",
+			NeedsSelection: true,
+			View: func(clusterName string) Resource {
+				return NewEKSFargateProfiles(clusterName)
+			},
+		},
+		{
+			Key:            'I',
+			Label:          "identities",
+			Description:    "Show pod identity associations and IRSA roles",
+			NeedsSelection: true,
+			View: func(clusterName string) Resource {
+				return NewEKSIdentities(clusterName)
+			},
+		},
+		{
+			Key:             'K',
+			Label:           "kubeconfig",
+			Description:     "Add the cluster to the kubeconfig and make it current",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ReadOnly:        true,
+			ConfirmTemplate: "add cluster [white]%s[-] to the kubeconfig and [yellow]make it the current context[-]? kubectl then targets it",
+			ConfirmCheck: func(ctx context.Context, c *client.Client, clusterName string) (string, error) {
+				if err := checkKubeconfigRole(c); err != nil {
+					return "", err
+				}
+				current, err := currentKubeContext()
+				if err != nil || current == "" {
+					return "", err
+				}
+				return "Current context: " + current, nil
+			},
+			Handler: func(ctx context.Context, c *client.Client, clusterName string) error {
+				_, err := updateKubeconfig(ctx, c, clusterName, true)
+				return err
+			},
+		},
+		{
+			Key:            'k',
+			Label:          "k9s",
+			Description:    "Browse the cluster with k9s",
+			NeedsSelection: true,
+			Command:        k9sCommand,
+		},
+	}
+}
+
+// DrillDown opens the node groups of the cluster
+func (e *EKSClusters) DrillDown(clusterName string) Resource {
+	return NewEKSNodeGroups(clusterName)
+}
+
+// EKSNodeGroup represents a managed node group of an EKS cluster
+type EKSNodeGroup struct {
+	Name           string
+	Status         string
+	InstanceTypes  string
+	CapacityType   string
+	DesiredSize    int32
+	MinSize        int32
+	MaxSize        int32
+	AMIType        string
+	ReleaseVersion string
+	Version        string
+}
+
+// EKSNodeGroups implements Resource for the node groups of an EKS cluster
+type EKSNodeGroups struct {
+	clusterName string
+	nodegroups  []EKSNodeGroup
+}
+
+// NewEKSNodeGroups creates a new EKSNodeGroups resource
+func NewEKSNodeGroups(clusterName string) *EKSNodeGroups {
+	return &EKSNodeGroups{
+		clusterName: clusterName,
+		nodegroups:  make([]EKSNodeGroup, 0),
+	}
+}
+
+// Name returns the display name
+func (e *EKSNodeGroups) Name() string {
+	return fmt.Sprintf("EKS Node Groups (%s)", e.clusterName)
+}
+
+// Columns returns the column definitions
+func (e *EKSNodeGroups) Columns() []Column {
+	return []Column{
+		{Name: "Name", Width: 30},
+		{Name: "Status", Width: 15},
+		{Name: "Instance Types", Width: 25},
+		{Name: "Capacity", Width: 10},
+		{Name: "Desired", Width: 8},
+		{Name: "Min", Width: 6},
+		{Name: "Max", Width: 6},
+		{Name: "AMI Type", Width: 18},
+		{Name: "Release", Width: 25},
+		{Name: "Version", Width: 8},
+	}
+}
+
+// Fetch retrieves the node groups of the cluster from AWS
+func (e *EKSNodeGroups) Fetch(ctx context.Context, c *client.Client) error {
+	e.nodegroups = make([]EKSNodeGroup, 0)
+
+	paginator := eks.NewListNodegroupsPaginator(c.EKS(), &eks.ListNodegroupsInput{
+		ClusterName: &e.clusterName,
+	})
+
+	var names []string
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list node groups of %s: %w", e.clusterName, err)
+		}
+		names = append(names, output.Nodegroups...)
+	}
+
+	for _, name := range names {
+		output, err := c.EKS().DescribeNodegroup(ctx, &eks.DescribeNodegroupInput{
+			ClusterName:   &e.clusterName,
+			NodegroupName: &name,
+		})
+		if err != nil {
+			continue // Skip node groups we can't describe
+		}
+
+		ng := output.Nodegroup
+		nodegroup := EKSNodeGroup{
+			Name:           stringValue(ng.NodegroupName),
+			Status:         string(ng.Status),
+			InstanceTypes:  strings.Join(ng.InstanceTypes, ","),
+			CapacityType:   string(ng.CapacityType),
+			AMIType:        string(ng.AmiType),
+			ReleaseVersion: stringValue(ng.ReleaseVersion),
+			Version:        stringValue(ng.Version),
+		}
+
+		if ng.ScalingConfig != nil {
+			nodegroup.DesiredSize = ptrInt32Value(ng.ScalingConfig.DesiredSize)
+			nodegroup.MinSize = ptrInt32Value(ng.ScalingConfig.MinSize)
+			nodegroup.MaxSize = ptrInt32Value(ng.ScalingConfig.MaxSize)
+		}
+
+		e.nodegroups = append(e.nodegroups, nodegroup)
+	}
+
+	return nil
+}
+
+// Rows returns the table data
+func (e *EKSNodeGroups) Rows() [][]string {
+	rows := make([][]string, len(e.nodegroups))
+	for i, ng := range e.nodegroups {
+		rows[i] = []string{
+			ng.Name,
+			ng.Status,
+			ng.InstanceTypes,
+			ng.CapacityType,
+			fmt.Sprintf("%d", ng.DesiredSize),
+			fmt.Sprintf("%d", ng.MinSize),
+			fmt.Sprintf("%d", ng.MaxSize),
+			ng.AMIType,
+			ng.ReleaseVersion,
+			ng.Version,
+		}
+	}
+	return rows
+}
+
+// GetID returns the node group name at the given index
+func (e *EKSNodeGroups) GetID(index int) string {
+	if index >= 0 && index < len(e.nodegroups) {
+		return e.nodegroups[index].Name
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for EKS node groups
+func (e *EKSNodeGroups) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:             's',
+			Label:           "scale",
+			Description:     "Scale node group",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "[yellow]scale[-] node group [white]%s[-]?",
+			InputLabel:      "Desired (or min/desired/max): ",
+			InputHandler:    e.ScaleNodeGroup,
+		},
+	}
+}
+
+// ScaleNodeGroup updates the scaling configuration of a node group, the input
+// is either the desired size or a "min/desired/max" triplet
+func (e *EKSNodeGroups) ScaleNodeGroup(ctx context.Context, c *client.Client, nodegroupName, input string) error {
+	var current *EKSNodeGroup
+	for i := range e.nodegroups {
+		if e.nodegroups[i].Name == nodegroupName {
+			current = &e.nodegroups[i]
+			break
+		}
+	}
+	if current == nil {
+		return fmt.Errorf("unknown node group %s", nodegroupName)
+	}
+
+	minSize, desiredSize, maxSize := current.MinSize, current.DesiredSize, current.MaxSize
+
+	parts := strings.Split(strings.TrimSpace(input), "/")
+	values := make([]int32, len(parts))
+	for i, part := range parts {
+		v, err := strconv.ParseInt(strings.TrimSpace(part), 10, 32)
+		if err != nil || v < 0 {
+			return fmt.Errorf("invalid size %q", part)
+		}
+		values[i] = int32(v)
+	}
+
+	switch len(values) {
+	case 1:
+		desiredSize = values[0]
+	case 3:
+		minSize, desiredSize, maxSize = values[0], values[1], values[2]
+	default:
+		return fmt.Errorf("expected desired or min/desired/max, got %q", input)
+	}
+
+	if desiredSize < minSize || desiredSize > maxSize {
+		return fmt.Errorf("desired size %d is outside of [%d, %d]", desiredSize, minSize, maxSize)
+	}
+
+	_, err := c.EKS().UpdateNodegroupConfig(ctx, &eks.UpdateNodegroupConfigInput{
+		ClusterName:   &e.clusterName,
+		NodegroupName: &nodegroupName,
+		ScalingConfig: &ekstypes.NodegroupScalingConfig{
+			MinSize:     &minSize,
+			DesiredSize: &desiredSize,
+			MaxSize:     &maxSize,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scale node group %s: %w", nodegroupName, err)
+	}
+	return nil
+}
+
+// EKSFargateProfile represents a Fargate profile of an EKS cluster
+type EKSFargateProfile struct {
+	Name             string
+	Status           string
+	Selectors        string
+	PodExecutionRole string
+	Subnets          string
+	CreatedAt        string
+}
+
+// EKSFargateProfiles implements Resource for the Fargate profiles of an EKS cluster
+type EKSFargateProfiles struct {
+	clusterName string
+	profiles    []EKSFargateProfile
+}
+
+// NewEKSFargateProfiles creates a new EKSFargateProfiles resource
+func NewEKSFargateProfiles(clusterName string) *EKSFargateProfiles {
+	return &EKSFargateProfiles{
+		clusterName: clusterName,
+		profiles:    make([]EKSFargateProfile, 0),
+	}
+}
+
+// Name returns the display name
+func (e *EKSFargateProfiles) Name() string {
+	return fmt.Sprintf("EKS Fargate Profiles (%s)", e.clusterName)
+}
+
+// Columns returns the column definitions
+func (e *EKSFargateProfiles) Columns() []Column {
+	return []Column{
+		{Name: "Name", Width: 30},
+		{Name: "Status", Width: 15},
+		{Name: "Selectors", Width: 40},
+		{Name: "Pod Execution Role", Width: 50},
+		{Name: "Subnets", Width: 20},
+		{Name: "Created At", Width: 20},
+	}
+}
+
+// Fetch retrieves the Fargate profiles of the cluster from AWS
+func (e *EKSFargateProfiles) Fetch(ctx context.Context, c *client.Client) error {
+	e.profiles = make([]EKSFargateProfile, 0)
+
+	paginator := eks.NewListFargateProfilesPaginator(c.EKS(), &eks.ListFargateProfilesInput{
+		ClusterName: &e.clusterName,
+	})
+
+	var names []string
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list Fargate profiles of %s: %w", e.clusterName, err)
+		}
+		names = append(names, output.FargateProfileNames...)
+	}
+
+	for _, name := range names {
+		output, err := c.EKS().DescribeFargateProfile(ctx, &eks.DescribeFargateProfileInput{
+			ClusterName:        &e.clusterName,
+			FargateProfileName: &name,
+		})
+		if err != nil {
+			continue // Skip profiles we can't describe
+		}
+
+		fp := output.FargateProfile
+		profile := EKSFargateProfile{
+			Name:             stringValue(fp.FargateProfileName),
+			Status:           string(fp.Status),
+			Selectors:        formatFargateSelectors(fp.Selectors),
+			PodExecutionRole: stringValue(fp.PodExecutionRoleArn),
+			Subnets:          fmt.Sprintf("%d", len(fp.Subnets)),
+		}
+
+		if fp.CreatedAt != nil {
+			profile.CreatedAt = fp.CreatedAt.Format("2006-01-02 15:04:05")
+		}
+
+		e.profiles = append(e.profiles, profile)
+	}
+
+	return nil
+}
+
+// formatFargateSelectors renders selectors as "namespace{key=value}" entries
+func formatFargateSelectors(selectors []ekstypes.FargateProfileSelector) string {
+	parts := make([]string, 0, len(selectors))
+	for _, selector := range selectors {
+		part := stringValue(selector.Namespace)
+		if len(selector.Labels) > 0 {
+			labels := make([]string, 0, len(selector.Labels))
+			for k, v := range selector.Labels {
+				labels = append(labels, k+"="+v)
+			}
+			sort.Strings(labels)
+			part += "{" + strings.Join(labels, ",") + "}"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Rows returns the table data
+func (e *EKSFargateProfiles) Rows() [][]string {
+	rows := make([][]string, len(e.profiles))
+	for i, profile := range e.profiles {
+		rows[i] = []string{
+			profile.Name,
+			profile.Status,
+			profile.Selectors,
+			profile.PodExecutionRole,
+			profile.Subnets,
+			profile.CreatedAt,
+		}
+	}
+	return rows
+}
+
+// GetID returns the Fargate profile name at the given index
+func (e *EKSFargateProfiles) GetID(index int) string {
+	if index >= 0 && index < len(e.profiles) {
+		return e.profiles[index].Name
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for Fargate profiles
+func (e *EKSFargateProfiles) QuickActions() []QuickAction {
+	return []QuickAction{}
+}
+
+// EKSIdentity maps a Kubernetes service account to an IAM role
+type EKSIdentity struct {
+	ID             string
+	Type           string // "pod-identity" or "irsa"
+	Namespace      string
+	ServiceAccount string
+	RoleArn        string
+}
+
+// EKSIdentities implements Resource for the pod identity associations of an EKS
+// cluster and the IAM roles trusting its OIDC provider (IRSA)
+type EKSIdentities struct {
+	clusterName string
+	identities  []EKSIdentity
+}
+
+// NewEKSIdentities creates a new EKSIdentities resource
+func NewEKSIdentities(clusterName string) *EKSIdentities {
+	return &EKSIdentities{
+		clusterName: clusterName,
+		identities:  make([]EKSIdentity, 0),
+	}
+}
+
+// Name returns the display name
+func (e *EKSIdentities) Name() string {
+	return fmt.Sprintf("EKS Identities (%s)", e.clusterName)
+}
+
+// Columns returns the column definitions
+func (e *EKSIdentities) Columns() []Column {
+	return []Column{
+		{Name: "Type", Width: 14},
+		{Name: "Namespace", Width: 25},
+		{Name: "Service Account", Width: 35},
+		{Name: "Role", Width: 70},
+	}
+}
+
+// Fetch retrieves the pod identity associations and the IRSA roles of the cluster
+func (e *EKSIdentities) Fetch(ctx context.Context, c *client.Client) error {
+	e.identities = make([]EKSIdentity, 0)
+
+	paginator := eks.NewListPodIdentityAssociationsPaginator(c.EKS(), &eks.ListPodIdentityAssociationsInput{
+		ClusterName: &e.clusterName,
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list pod identity associations of %s: %w", e.clusterName, err)
+		}
+
+		for _, summary := range output.Associations {
+			identity := EKSIdentity{
+				ID:             stringValue(summary.AssociationId),
+				Type:           "pod-identity",
+				Namespace:      stringValue(summary.Namespace),
+				ServiceAccount: stringValue(summary.ServiceAccount),
+			}
+
+			// The role is only part of the association details
+			association, err := c.EKS().DescribePodIdentityAssociation(ctx, &eks.DescribePodIdentityAssociationInput{
+				ClusterName:   &e.clusterName,
+				AssociationId: summary.AssociationId,
+			})
+			if err == nil && association.Association != nil {
+				identity.RoleArn = stringValue(association.Association.RoleArn)
+			}
+
+			e.identities = append(e.identities, identity)
+		}
+	}
+
+	cluster, err := c.EKS().DescribeCluster(ctx, &eks.DescribeClusterInput{Name: &e.clusterName})
+	if err != nil {
+		return fmt.Errorf("failed to describe %s: %w", e.clusterName, err)
+	}
+	if cluster.Cluster.Identity == nil || cluster.Cluster.Identity.Oidc == nil || cluster.Cluster.Identity.Oidc.Issuer == nil {
+		return nil
+	}
+	issuer := strings.TrimPrefix(*cluster.Cluster.Identity.Oidc.Issuer, "https://")
+
+	roles := iam.NewListRolesPaginator(c.IAM(), &iam.ListRolesInput{})
+	for roles.HasMorePages() {
+		output, err := roles.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list IAM roles: %w", err)
+		}
+
+		for _, role := range output.Roles {
+			subjects, err := irsaSubjects(stringValue(role.AssumeRolePolicyDocument), issuer)
+			if err != nil {
+				continue
+			}
+			for _, subject := range subjects {
+				namespace, serviceAccount, _ := strings.Cut(subject, ":")
+				e.identities = append(e.identities, EKSIdentity{
+					ID:             stringValue(role.RoleName) + "/" + subject,
+					Type:           "irsa",
+					Namespace:      namespace,
+					ServiceAccount: serviceAccount,
+					RoleArn:        stringValue(role.Arn),
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(e.identities, func(i, j int) bool {
+		if e.identities[i].Namespace != e.identities[j].Namespace {
+			return e.identities[i].Namespace < e.identities[j].Namespace
+		}
+		return e.identities[i].ServiceAccount < e.identities[j].ServiceAccount
+	})
+
+	return nil
+}
+
+// irsaSubjects returns the "namespace:service-account" subjects allowed to assume
+// a role through the OIDC provider of the issuer, "*:*" when any subject is
+func irsaSubjects(document, issuer string) ([]string, error) {
+	decoded, err := url.QueryUnescape(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode trust policy: %w", err)
+	}
+
+	var policy struct {
+		Statement []struct {
+			Effect    string
+			Principal json.RawMessage // "*" or a map of a type to principals
+			Condition map[string]map[string]json.RawMessage
+		}
+	}
+	if err := json.Unmarshal([]byte(decoded), &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse trust policy: %w", err)
+	}
+
+	subjects := make([]string, 0)
+	for _, statement := range policy.Statement {
+		if statement.Effect != "Allow" {
+			continue
+		}
+
+		var byType map[string]json.RawMessage
+		if json.Unmarshal(statement.Principal, &byType) != nil {
+			continue
+		}
+		federated := false
+		for _, principal := range jsonStrings(byType["Federated"]) {
+			if strings.HasSuffix(principal, ":oidc-provider/"+issuer) {
+				federated = true
+			}
+		}
+		if !federated {
+			continue
+		}
+
+		// Subjects are "system:serviceaccount:<namespace>:<name>", possibly with wildcards
+		restricted := false
+		for _, conditions := range statement.Condition {
+			for key, raw := range conditions {
+				if key != issuer+":sub" {
+					continue
+				}
+				restricted = true
+				for _, subject := range jsonStrings(raw) {
+					subjects = append(subjects, strings.TrimPrefix(subject, "system:serviceaccount:"))
+				}
+			}
+		}
+		if !restricted {
+			subjects = append(subjects, "*:*")
+		}
+	}
+	return subjects, nil
+}
+
+// jsonStrings decodes a JSON value holding either a string or a list of strings
+func jsonStrings(raw json.RawMessage) []string {
+	var single string
+	if json.Unmarshal(raw, &single) == nil {
+		return []string{single}
+	}
+	var values []string
+	if json.Unmarshal(raw, &values) == nil {
+		return values
+	}
+	return nil
+}
+
+// Rows returns the table data
+func (e *EKSIdentities) Rows() [][]string {
+	rows := make([][]string, len(e.identities))
+	for i, identity := range e.identities {
+		rows[i] = []string{
+			identity.Type,
+			identity.Namespace,
+			identity.ServiceAccount,
+			identity.RoleArn,
+		}
+	}
+	return rows
+}
+
+// GetID returns the identity ID at the given index
+func (e *EKSIdentities) GetID(index int) string {
+	if index >= 0 && index < len(e.identities) {
+		return e.identities[index].ID
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for EKS identities
+func (e *EKSIdentities) QuickActions() []QuickAction {
 	return []QuickAction{}
 }
@@ -3,12 +3,157 @@ package resources
 import (
 	"context"
 	"fmt"
+	"os/exec"
+	"strings"
 
 	"a9s/internal/client"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	ectypes "github.com/aws/aws-sdk-go-v2/service/elasticache/types"
 )
 
+// cacheConnection holds how to reach an ElastiCache cluster or replication group
+type cacheConnection struct {
+	Engine            string
+	Address           string
+	Port              int32
+	TransitEncryption bool
+	AtRestEncryption  bool
+	AuthToken         bool
+	ClusterMode       bool
+}
+
+// endpoint returns the host:port of the connection
+func (cc cacheConnection) endpoint() string {
+	if cc.Address == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", cc.Address, cc.Port)
+}
+
+// detail describes the connection and its security settings
+func (cc cacheConnection) detail() string {
+	yesNo := func(b bool) string {
+		if b {
+			return "yes"
+		}
+		return "no"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Engine:                %s\n", cc.Engine)
+	fmt.Fprintf(&b, "Endpoint:              %s\n", cc.endpoint())
+	fmt.Fprintf(&b, "Cluster mode:          %s\n", yesNo(cc.ClusterMode))
+	fmt.Fprintf(&b, "In-transit encryption: %s\n", yesNo(cc.TransitEncryption))
+	fmt.Fprintf(&b, "At-rest encryption:    %s\n", yesNo(cc.AtRestEncryption))
+	fmt.Fprintf(&b, "Auth token required:   %s\n", yesNo(cc.AuthToken))
+	return b.String()
+}
+
+// cliCommand returns the redis-cli, or valkey-cli, command connecting to the endpoint
+func (cc cacheConnection) cliCommand() (*exec.Cmd, error) {
+	if cc.Engine == "memcached" {
+		return nil, fmt.Errorf("no command line client for memcached")
+	}
+	if cc.Address == "" {
+		return nil, fmt.Errorf("no endpoint available yet")
+	}
+
+	names := []string{"redis-cli"}
+	if cc.Engine == "valkey" {
+		names = []string{"valkey-cli", "redis-cli"}
+	}
+	path := ""
+	for _, name := range names {
+		if p, err := exec.LookPath(name); err == nil {
+			path = p
+			break
+		}
+	}
+	if path == "" {
+		return nil, fmt.Errorf("%s not found in PATH", names[0])
+	}
+
+	args := []string{"-h", cc.Address, "-p", fmt.Sprintf("%d", cc.Port)}
+	if cc.TransitEncryption {
+		args = append(args, "--tls")
+	}
+	if cc.AuthToken {
+		args = append(args, "--askpass")
+	}
+	if cc.ClusterMode {
+		args = append(args, "-c")
+	}
+	return exec.Command(path, args...), nil
+}
+
+// cacheQuickActions returns the actions shared by clusters and replication groups
+func cacheQuickActions(connection func(id string) (cacheConnection, bool)) []QuickAction {
+	lookup := func(id string) (cacheConnection, error) {
+		cc, ok := connection(id)
+		if !ok {
+			return cacheConnection{}, fmt.Errorf("%s not found", id)
+		}
+		return cc, nil
+	}
+
+	return []QuickAction{
+		{
+			Key:            'y',
+			Label:          "copy",
+			Description:    "Copy endpoint",
+			NeedsSelection: true,
+			Clipboard:      true,
+			TextHandler: func(ctx context.Context, c *client.Client, id string) (string, error) {
+				cc, err := lookup(id)
+				if err != nil {
+					return "", err
+				}
+				if cc.Address == "" {
+					return "", fmt.Errorf("%s has no endpoint yet", id)
+				}
+				return cc.endpoint(), nil
+			},
+		},
+		{
+			Key:            's',
+			Label:          "cli",
+			Description:    "Open redis-cli",
+			NeedsSelection: true,
+			Command: func(ctx context.Context, c *client.Client, id string) (*exec.Cmd, error) {
+				cc, err := lookup(id)
+				if err != nil {
+					return nil, err
+				}
+				return cc.cliCommand()
+			},
+		},
+		{
+			Key:            'd',
+			Label:          "detail",
+			Description:    "Show endpoint and encryption",
+			NeedsSelection: true,
+			TextHandler: func(ctx context.Context, c *client.Client, id string) (string, error) {
+				cc, err := lookup(id)
+				if err != nil {
+					return "", err
+				}
+				return cc.detail(), nil
+			},
+		},
+	}
+}
+
+// endpointConnection sets the address and port of a connection from an endpoint
+func endpointConnection(cc *cacheConnection, endpoint *ectypes.Endpoint) {
+	if endpoint == nil {
+		return
+	}
+	cc.Address = stringValue(endpoint.Address)
+	cc.Port = ptrInt32Value(endpoint.Port)
+}
+
 // ElastiCacheCluster represents an ElastiCache cluster
 type ElastiCacheCluster struct {
 	ClusterID     string
@@ -18,6 +163,7 @@ type ElastiCacheCluster struct {
 	NumCacheNodes string
 	Status        string
 	PreferredAZ   string
+	Connection    cacheConnection
 }
 
 // ElastiCacheClusters implements Resource for ElastiCache clusters
@@ -54,7 +200,9 @@ func (e *ElastiCacheClusters) Columns() []Column {
 func (e *ElastiCacheClusters) Fetch(ctx context.Context, c *client.Client) error {
 	e.clusters = make([]ElastiCacheCluster, 0)
 
-	paginator := elasticache.NewDescribeCacheClustersPaginator(c.ElastiCache(), &elasticache.DescribeCacheClustersInput{})
+	paginator := elasticache.NewDescribeCacheClustersPaginator(c.ElastiCache(), &elasticache.DescribeCacheClustersInput{
+		ShowCacheNodeInfo: aws.Bool(true),
+	})
 
 	for paginator.HasMorePages() {
 		output, err := paginator.NextPage(ctx)
@@ -63,6 +211,19 @@ func (e *ElastiCacheClusters) Fetch(ctx context.Context, c *client.Client) error
 		}
 
 		for _, cluster := range output.CacheClusters {
+			connection := cacheConnection{
+				Engine:            stringValue(cluster.Engine),
+				TransitEncryption: ptrBoolValue(cluster.TransitEncryptionEnabled),
+				AtRestEncryption:  ptrBoolValue(cluster.AtRestEncryptionEnabled),
+				AuthToken:         ptrBoolValue(cluster.AuthTokenEnabled),
+			}
+			// Memcached has a configuration endpoint, Redis nodes their own
+			if cluster.ConfigurationEndpoint != nil {
+				endpointConnection(&connection, cluster.ConfigurationEndpoint)
+			} else if len(cluster.CacheNodes) > 0 {
+				endpointConnection(&connection, cluster.CacheNodes[0].Endpoint)
+			}
+
 			e.clusters = append(e.clusters, ElastiCacheCluster{
 				ClusterID:     stringValue(cluster.CacheClusterId),
 				Engine:        stringValue(cluster.Engine),
@@ -71,6 +232,7 @@ func (e *ElastiCacheClusters) Fetch(ctx context.Context, c *client.Client) error
 				NumCacheNodes: fmt.Sprintf("%d", ptrInt32Value(cluster.NumCacheNodes)),
 				Status:        stringValue(cluster.CacheClusterStatus),
 				PreferredAZ:   stringValue(cluster.PreferredAvailabilityZone),
+				Connection:    connection,
 			})
 		}
 	}
@@ -105,7 +267,14 @@ func (e *ElastiCacheClusters) GetID(index int) string {
 
 // QuickActions returns the available quick actions for ElastiCache clusters
 func (e *ElastiCacheClusters) QuickActions() []QuickAction {
-	return []QuickAction{}
+	return cacheQuickActions(func(id string) (cacheConnection, bool) {
+		for _, cluster := range e.clusters {
+			if cluster.ClusterID == id {
+				return cluster.Connection, true
+			}
+		}
+		return cacheConnection{}, false
+	})
 }
 
 // ElastiCacheReplicationGroup represents an ElastiCache replication group
@@ -116,6 +285,7 @@ type ElastiCacheReplicationGroup struct {
 	ClusterEnabled     string
 	NodeType           string
 	NumNodeGroups      string
+	Connection         cacheConnection
 }
 
 // ElastiCacheReplicationGroups implements Resource for ElastiCache replication groups
@@ -176,6 +346,20 @@ func (e *ElastiCacheReplicationGroups) Fetch(ctx context.Context, c *client.Clie
 				nodeType = stringValue(rg.CacheNodeType)
 			}
 
+			connection := cacheConnection{
+				Engine:            stringValue(rg.Engine),
+				TransitEncryption: ptrBoolValue(rg.TransitEncryptionEnabled),
+				AtRestEncryption:  ptrBoolValue(rg.AtRestEncryptionEnabled),
+				AuthToken:         ptrBoolValue(rg.AuthTokenEnabled),
+				ClusterMode:       ptrBoolValue(rg.ClusterEnabled),
+			}
+			// Cluster mode has a configuration endpoint, otherwise use the primary
+			if rg.ConfigurationEndpoint != nil {
+				endpointConnection(&connection, rg.ConfigurationEndpoint)
+			} else if len(rg.NodeGroups) > 0 {
+				endpointConnection(&connection, rg.NodeGroups[0].PrimaryEndpoint)
+			}
+
 			e.groups = append(e.groups, ElastiCacheReplicationGroup{
 				ReplicationGroupID: stringValue(rg.ReplicationGroupId),
 				Description:        stringValue(rg.Description),
@@ -183,6 +367,7 @@ func (e *ElastiCacheReplicationGroups) Fetch(ctx context.Context, c *client.Clie
 				ClusterEnabled:     clusterEnabled,
 				NodeType:           nodeType,
 				NumNodeGroups:      numNodeGroups,
+				Connection:         connection,
 			})
 		}
 	}
@@ -216,5 +401,158 @@ func (e *ElastiCacheReplicationGroups) GetID(index int) string {
 
 // QuickActions returns the available quick actions for ElastiCache replication groups
 func (e *ElastiCacheReplicationGroups) QuickActions() []QuickAction {
-	return []QuickAction{}
+	return cacheQuickActions(func(id string) (cacheConnection, bool) {
+		for _, rg := range e.groups {
+			if rg.ReplicationGroupID == id {
+				return rg.Connection, true
+			}
+		}
+		return cacheConnection{}, false
+	})
+}
+
+// ElastiCacheServerlessCache represents an ElastiCache serverless cache
+type ElastiCacheServerlessCache struct {
+	Name          string
+	Engine        string
+	EngineVersion string
+	Status        string
+	DataStorage   string
+	ECPU          string
+	CreatedAt     string
+	Connection    cacheConnection
+}
+
+// ElastiCacheServerlessCaches implements Resource for ElastiCache serverless caches
+type ElastiCacheServerlessCaches struct {
+	caches []ElastiCacheServerlessCache
+}
+
+// NewElastiCacheServerlessCaches creates a new ElastiCacheServerlessCaches resource
+func NewElastiCacheServerlessCaches() *ElastiCacheServerlessCaches {
+	return &ElastiCacheServerlessCaches{
+		caches: make([]ElastiCacheServerlessCache, 0),
+	}
+}
+
+// Name returns the display name
+func (e *ElastiCacheServerlessCaches) Name() string {
+	return "ElastiCache Serverless Caches"
+}
+
+// Columns returns the column definitions
+func (e *ElastiCacheServerlessCaches) Columns() []Column {
+	return []Column{
+		{Name: "Name", Width: 30},
+		{Name: "Engine", Width: 10},
+		{Name: "Version", Width: 10},
+		{Name: "Status", Width: 12},
+		{Name: "Storage Limit", Width: 16},
+		{Name: "ECPU/s Limit", Width: 20},
+		{Name: "Endpoint", Width: 60},
+		{Name: "Created At", Width: 20},
+	}
+}
+
+// Fetch retrieves ElastiCache serverless caches from AWS
+func (e *ElastiCacheServerlessCaches) Fetch(ctx context.Context, c *client.Client) error {
+	e.caches = make([]ElastiCacheServerlessCache, 0)
+
+	paginator := elasticache.NewDescribeServerlessCachesPaginator(c.ElastiCache(), &elasticache.DescribeServerlessCachesInput{})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe ElastiCache serverless caches: %w", err)
+		}
+
+		for _, sc := range output.ServerlessCaches {
+			// Serverless caches always encrypt, users come from the user group if any
+			connection := cacheConnection{
+				Engine:            stringValue(sc.Engine),
+				TransitEncryption: true,
+				AtRestEncryption:  true,
+				AuthToken:         stringValue(sc.UserGroupId) != "",
+			}
+			endpointConnection(&connection, sc.Endpoint)
+
+			cache := ElastiCacheServerlessCache{
+				Name:          stringValue(sc.ServerlessCacheName),
+				Engine:        stringValue(sc.Engine),
+				EngineVersion: stringValue(sc.FullEngineVersion),
+				Status:        stringValue(sc.Status),
+				DataStorage:   "-",
+				ECPU:          "-",
+				Connection:    connection,
+			}
+
+			if limits := sc.CacheUsageLimits; limits != nil {
+				if limits.DataStorage != nil {
+					cache.DataStorage = formatUsageLimit(limits.DataStorage.Minimum, limits.DataStorage.Maximum, " "+string(limits.DataStorage.Unit))
+				}
+				if limits.ECPUPerSecond != nil {
+					cache.ECPU = formatUsageLimit(limits.ECPUPerSecond.Minimum, limits.ECPUPerSecond.Maximum, "")
+				}
+			}
+
+			if sc.CreateTime != nil {
+				cache.CreatedAt = sc.CreateTime.Format("2006-01-02 15:04:05")
+			}
+
+			e.caches = append(e.caches, cache)
+		}
+	}
+
+	return nil
+}
+
+// formatUsageLimit renders a usage limit range, e.g. "1-10 GB"
+func formatUsageLimit(minimum, maximum *int32, unit string) string {
+	switch {
+	case minimum == nil && maximum == nil:
+		return "-"
+	case minimum == nil:
+		return fmt.Sprintf("max %d%s", *maximum, unit)
+	case maximum == nil:
+		return fmt.Sprintf("min %d%s", *minimum, unit)
+	}
+	return fmt.Sprintf("%d-%d%s", *minimum, *maximum, unit)
+}
+
+// Rows returns the table data
+func (e *ElastiCacheServerlessCaches) Rows() [][]string {
+	rows := make([][]string, len(e.caches))
+	for i, cache := range e.caches {
+		rows[i] = []string{
+			cache.Name,
+			cache.Engine,
+			cache.EngineVersion,
+			cache.Status,
+			cache.DataStorage,
+			cache.ECPU,
+			cache.Connection.endpoint(),
+			cache.CreatedAt,
+		}
+	}
+	return rows
+}
+
+// GetID returns the serverless cache name at the given index
+func (e *ElastiCacheServerlessCaches) GetID(index int) string {
+	if index >= 0 && index < len(e.caches) {
+		return e.caches[index].Name
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for ElastiCache serverless caches
+func (e *ElastiCacheServerlessCaches) QuickActions() []QuickAction {
+	return cacheQuickActions(func(id string) (cacheConnection, bool) {
+		for _, cache := range e.caches {
+			if cache.Name == id {
+				return cache.Connection, true
+			}
+		}
+		return cacheConnection{}, false
+	})
 }
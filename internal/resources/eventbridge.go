@@ -0,0 +1,394 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	eventbridgetypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// samplePatternValue is the value put in a test event where the pattern accepts any string
+const samplePatternValue = "example"
+
+// EventRule represents an EventBridge rule
+type EventRule struct {
+	ARN         string
+	Name        string
+	Bus         string
+	State       string
+	Schedule    string
+	Pattern     string
+	Description string
+}
+
+// EventRules implements Resource for the EventBridge rules of all the buses
+type EventRules struct {
+	rules []EventRule
+}
+
+// NewEventRules creates a new EventRules resource
+func NewEventRules() *EventRules {
+	return &EventRules{
+		rules: make([]EventRule, 0),
+	}
+}
+
+// Name returns the display name
+func (e *EventRules) Name() string {
+	return "EventBridge Rules"
+}
+
+// Columns returns the column definitions
+func (e *EventRules) Columns() []Column {
+	return []Column{
+		{Name: "Bus", Width: 20},
+		{Name: "Name", Width: 40},
+		{Name: "State", Width: 10},
+		{Name: "Pattern / Schedule", Width: 70},
+		{Name: "Description", Width: 40},
+	}
+}
+
+// Fetch retrieves the rules of every event bus
+func (e *EventRules) Fetch(ctx context.Context, c *client.Client) error {
+	e.rules = make([]EventRule, 0)
+
+	buses := make([]string, 0)
+	input := &eventbridge.ListEventBusesInput{}
+	for {
+		output, err := c.EventBridge().ListEventBuses(ctx, input)
+		if err != nil {
+			return fmt.Errorf("failed to list event buses: %w", err)
+		}
+		for _, bus := range output.EventBuses {
+			buses = append(buses, stringValue(bus.Name))
+		}
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	for _, bus := range buses {
+		input := &eventbridge.ListRulesInput{EventBusName: aws.String(bus)}
+		for {
+			output, err := c.EventBridge().ListRules(ctx, input)
+			if err != nil {
+				return fmt.Errorf("failed to list rules of %s: %w", bus, err)
+			}
+			for _, rule := range output.Rules {
+				e.rules = append(e.rules, EventRule{
+					ARN:         stringValue(rule.Arn),
+					Name:        stringValue(rule.Name),
+					Bus:         bus,
+					State:       string(rule.State),
+					Schedule:    stringValue(rule.ScheduleExpression),
+					Pattern:     stringValue(rule.EventPattern),
+					Description: stringValue(rule.Description),
+				})
+			}
+			if output.NextToken == nil {
+				break
+			}
+			input.NextToken = output.NextToken
+		}
+	}
+
+	return nil
+}
+
+// Rows returns the table data
+func (e *EventRules) Rows() [][]string {
+	rows := make([][]string, len(e.rules))
+	for i, rule := range e.rules {
+		trigger := rule.Schedule
+		if rule.Pattern != "" {
+			trigger = compactJSON(rule.Pattern)
+		}
+		rows[i] = []string{
+			rule.Bus,
+			rule.Name,
+			rule.State,
+			trigger,
+			rule.Description,
+		}
+	}
+	return rows
+}
+
+// GetID returns the rule ARN at the given index
+func (e *EventRules) GetID(index int) string {
+	if index >= 0 && index < len(e.rules) {
+		return e.rules[index].ARN
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for EventBridge rules
+func (e *EventRules) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:            't',
+			Label:          "test event",
+			Description:    "Put a test event matching the pattern on the bus",
+			NeedsSelection: true,
+			EditTemplate: func(ruleARN string) string {
+				rule, ok := e.rule(ruleARN)
+				if !ok {
+					return "# Rule " + ruleARN + " is not listed\n"
+				}
+				template, err := testEventTemplate(rule)
+				if err != nil {
+					return "# " + err.Error() + "\n"
+				}
+				return template
+			},
+			EditPlan: func(ctx context.Context, c *client.Client, ruleARN, text string) ([]Step, error) {
+				rule, ok := e.rule(ruleARN)
+				if !ok {
+					return nil, fmt.Errorf("rule %s is not listed", ruleARN)
+				}
+				return planTestEvent(ctx, c, rule, text)
+			},
+		},
+	}
+}
+
+// rule returns the listed rule with the given ARN
+func (e *EventRules) rule(ruleARN string) (EventRule, bool) {
+	for _, rule := range e.rules {
+		if rule.ARN == ruleARN {
+			return rule, true
+		}
+	}
+	return EventRule{}, false
+}
+
+// compactJSON returns a JSON document on one line, or the text itself when it is not JSON
+func compactJSON(text string) string {
+	var value any
+	if err := json.Unmarshal([]byte(text), &value); err != nil {
+		return text
+	}
+	compact, err := json.Marshal(value)
+	if err != nil {
+		return text
+	}
+	return string(compact)
+}
+
+// testEvent is the part of an event the sender of PutEvents sets
+type testEvent struct {
+	Source     string          `json:"source"`
+	DetailType string          `json:"detail-type"`
+	Resources  []string        `json:"resources"`
+	Detail     json.RawMessage `json:"detail"`
+}
+
+// testEventTemplate returns the editor template of an event matching the pattern of a rule
+func testEventTemplate(rule EventRule) (string, error) {
+	if rule.Pattern == "" {
+		return "", fmt.Errorf("rule %s is triggered by a schedule, it has no event pattern", rule.Name)
+	}
+	var pattern map[string]any
+	if err := json.Unmarshal([]byte(rule.Pattern), &pattern); err != nil {
+		return "", fmt.Errorf("failed to parse the pattern of %s: %w", rule.Name, err)
+	}
+	sample := samplePattern(pattern)
+
+	event := testEvent{Source: samplePatternValue, DetailType: samplePatternValue, Resources: []string{}, Detail: json.RawMessage("{}")}
+	if source, ok := sample["source"].(string); ok {
+		event.Source = source
+	}
+	if detailType, ok := sample["detail-type"].(string); ok {
+		event.DetailType = detailType
+	}
+	if resources, ok := sample["resources"].(string); ok {
+		event.Resources = []string{resources}
+	}
+	if detail, ok := sample["detail"]; ok {
+		encoded, err := json.Marshal(detail)
+		if err != nil {
+			return "", err
+		}
+		event.Detail = encoded
+	}
+	body, err := json.MarshalIndent(event, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Test event put on the bus %s for the rule %s, lines starting with # are ignored.\n", rule.Bus, rule.Name)
+	b.WriteString("# The values were taken from the pattern, the event is checked against it before being sent:\n")
+	fmt.Fprintf(&b, "#   %s\n", compactJSON(rule.Pattern))
+	b.Write(body)
+	b.WriteString("\n")
+	return b.String(), nil
+}
+
+// samplePattern returns the fields of an event matching a pattern, fields the pattern
+// requires to be absent are left out
+func samplePattern(pattern map[string]any) map[string]any {
+	sample := make(map[string]any, len(pattern))
+	for key, value := range pattern {
+		switch value := value.(type) {
+		case map[string]any:
+			sample[key] = samplePattern(value)
+		case []any:
+			if len(value) == 0 {
+				continue
+			}
+			if matched, ok := sampleMatch(value[0]); ok {
+				sample[key] = matched
+			}
+		}
+	}
+	return sample
+}
+
+// sampleMatch returns a value accepted by one of the matches of a pattern field, an exact
+// value or a content filter like prefix or numeric, false when the field must be absent
+func sampleMatch(match any) (any, bool) {
+	filter, ok := match.(map[string]any)
+	if !ok {
+		return match, true
+	}
+	for operator, operand := range filter {
+		switch operator {
+		case "prefix":
+			if text, ok := operand.(string); ok {
+				return text + samplePatternValue, true
+			}
+		case "suffix":
+			if text, ok := operand.(string); ok {
+				return samplePatternValue + text, true
+			}
+		case "equals-ignore-case":
+			return operand, true
+		case "wildcard":
+			if text, ok := operand.(string); ok {
+				return strings.ReplaceAll(text, "*", samplePatternValue), true
+			}
+		case "exists":
+			if exists, ok := operand.(bool); ok && !exists {
+				return nil, false
+			}
+		case "cidr":
+			if text, ok := operand.(string); ok {
+				if _, network, err := net.ParseCIDR(text); err == nil {
+					return network.IP.String(), true
+				}
+			}
+		case "numeric":
+			if conditions, ok := operand.([]any); ok {
+				return sampleNumeric(conditions), true
+			}
+		}
+	}
+	return samplePatternValue, true
+}
+
+// sampleNumeric returns a number satisfying the first condition of a numeric filter, e.g. [">", 0, "<=", 5]
+func sampleNumeric(conditions []any) float64 {
+	if len(conditions) < 2 {
+		return 0
+	}
+	operator, _ := conditions[0].(string)
+	bound, _ := conditions[1].(float64)
+	switch operator {
+	case ">":
+		return bound + 1
+	case "<":
+		return bound - 1
+	}
+	return bound
+}
+
+// planTestEvent parses an edited test event, checks it against the pattern of the rule
+// and returns the steps putting it on the bus
+func planTestEvent(ctx context.Context, c *client.Client, rule EventRule, text string) ([]Step, error) {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(text, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "#") {
+			lines = append(lines, line)
+		}
+	}
+	var event testEvent
+	if err := json.Unmarshal([]byte(strings.Join(lines, "\n")), &event); err != nil {
+		return nil, fmt.Errorf("the event is not valid JSON: %w", err)
+	}
+	if event.Source == "" || event.DetailType == "" {
+		return nil, fmt.Errorf("the source and detail-type of the event are required")
+	}
+	if len(event.Detail) == 0 || !strings.HasPrefix(strings.TrimSpace(string(event.Detail)), "{") {
+		return nil, fmt.Errorf("the detail of the event must be a JSON object")
+	}
+
+	identity, err := c.STS().GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get caller identity: %w", err)
+	}
+	full, err := json.Marshal(map[string]any{
+		"id":          "00000000-0000-0000-0000-000000000000",
+		"version":     "0",
+		"account":     stringValue(identity.Account),
+		"region":      c.Region(),
+		"time":        time.Now().UTC().Format(time.RFC3339),
+		"source":      event.Source,
+		"detail-type": event.DetailType,
+		"resources":   event.Resources,
+		"detail":      event.Detail,
+	})
+	if err != nil {
+		return nil, err
+	}
+	test, err := c.EventBridge().TestEventPattern(ctx, &eventbridge.TestEventPatternInput{
+		EventPattern: aws.String(rule.Pattern),
+		Event:        aws.String(string(full)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to test the event against the pattern: %w", err)
+	}
+
+	steps := make([]Step, 0, 2)
+	if test.Result {
+		steps = append(steps, Step{Description: fmt.Sprintf("The event matches the pattern of %s", rule.Name)})
+	} else {
+		steps = append(steps, Step{Description: fmt.Sprintf("Warning: the event does not match the pattern of %s", rule.Name)})
+	}
+	if rule.State != string(eventbridgetypes.RuleStateEnabled) {
+		steps = append(steps, Step{Description: fmt.Sprintf("Warning: %s is %s, its targets are not invoked", rule.Name, rule.State)})
+	}
+
+	return append(steps, Step{
+		Description: fmt.Sprintf("Put a %s event from %s on %s", event.DetailType, event.Source, rule.Bus),
+		Run: func(ctx context.Context, c *client.Client) error {
+			output, err := c.EventBridge().PutEvents(ctx, &eventbridge.PutEventsInput{
+				Entries: []eventbridgetypes.PutEventsRequestEntry{{
+					EventBusName: aws.String(rule.Bus),
+					Source:       aws.String(event.Source),
+					DetailType:   aws.String(event.DetailType),
+					Resources:    event.Resources,
+					Detail:       aws.String(string(event.Detail)),
+				}},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to put event: %w", err)
+			}
+			if output.FailedEntryCount > 0 && len(output.Entries) > 0 {
+				return fmt.Errorf("failed to put event: %s", stringValue(output.Entries[0].ErrorMessage))
+			}
+			return nil
+		},
+	}), nil
+}
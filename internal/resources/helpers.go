@@ -1,5 +1,12 @@
 package resources
 
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
 // stringValue safely dereferences a string pointer
 func stringValue(s *string) string {
 	if s == nil {
@@ -23,3 +30,15 @@ func ptrInt64Value(i *int64) int64 {
 	}
 	return *i
 }
+
+// expandHome expands a leading ~/ in a path to the home directory
+func expandHome(path string) (string, error) {
+	if !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, path[2:]), nil
+}
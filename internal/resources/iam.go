@@ -2,12 +2,22 @@ package resources
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
+	"sort"
 	"strings"
+	"time"
 
+	"a9s/internal/audit"
 	"a9s/internal/client"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
 )
 
 // IAMUser represents an IAM user
@@ -18,9 +28,11 @@ type IAMUser struct {
 	ARN        string
 }
 
-// IAMUsers implements Resource for IAM users
+// IAMUsers implements Resource and Pageable for IAM users
 type IAMUsers struct {
-	users []IAMUser
+	users  []IAMUser
+	pages  int
+	marker *string
 }
 
 // NewIAMUsers creates a new IAMUsers resource
@@ -39,39 +51,74 @@ func (i *IAMUsers) Name() string {
 func (i *IAMUsers) Columns() []Column {
 	return []Column{
 		{Name: "User Name", Width: 30},
-		{Name: "User ID", Width: 25},
+		{Name: "User ID", Width: 25, Sensitive: true},
 		{Name: "Created", Width: 20},
 		{Name: "ARN", Width: 60},
 	}
 }
 
-// Fetch retrieves IAM users from AWS
+// Fetch retrieves IAM users from AWS, as many pages as already loaded
 func (i *IAMUsers) Fetch(ctx context.Context, c *client.Client) error {
 	i.users = make([]IAMUser, 0)
+	i.marker = nil
 
-	paginator := iam.NewListUsersPaginator(c.IAM(), &iam.ListUsersInput{})
-
-	for paginator.HasMorePages() {
-		output, err := paginator.NextPage(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to list IAM users: %w", err)
+	pages := max(i.pages, 1)
+	i.pages = 0
+	for page := 0; page < pages; page++ {
+		if err := i.fetchPage(ctx, c); err != nil {
+			return err
 		}
+		if i.marker == nil {
+			break
+		}
+	}
 
-		for _, user := range output.Users {
-			createDate := ""
-			if user.CreateDate != nil {
-				createDate = user.CreateDate.Format("2006-01-02 15:04:05")
-			}
+	return nil
+}
 
-			i.users = append(i.users, IAMUser{
-				UserName:   stringValue(user.UserName),
-				UserID:     stringValue(user.UserId),
-				CreateDate: createDate,
-				ARN:        stringValue(user.Arn),
-			})
+// HasMore reports whether more IAM users can be loaded
+func (i *IAMUsers) HasMore() bool {
+	return i.marker != nil
+}
+
+// FetchMore retrieves the next page of IAM users
+func (i *IAMUsers) FetchMore(ctx context.Context, c *client.Client) error {
+	if i.marker == nil {
+		return nil
+	}
+	return i.fetchPage(ctx, c)
+}
+
+// fetchPage retrieves the page of IAM users starting at the marker
+func (i *IAMUsers) fetchPage(ctx context.Context, c *client.Client) error {
+	maxItems := pageSize
+	output, err := c.IAM().ListUsers(ctx, &iam.ListUsersInput{
+		Marker:   i.marker,
+		MaxItems: &maxItems,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list IAM users: %w", err)
+	}
+
+	for _, user := range output.Users {
+		createDate := ""
+		if user.CreateDate != nil {
+			createDate = user.CreateDate.Format("2006-01-02 15:04:05")
 		}
+
+		i.users = append(i.users, IAMUser{
+			UserName:   stringValue(user.UserName),
+			UserID:     stringValue(user.UserId),
+			CreateDate: createDate,
+			ARN:        stringValue(user.Arn),
+		})
 	}
 
+	i.pages++
+	i.marker = nil
+	if output.IsTruncated {
+		i.marker = output.Marker
+	}
 	return nil
 }
 
@@ -99,7 +146,38 @@ func (i *IAMUsers) GetID(index int) string {
 
 // QuickActions returns the available quick actions for IAM users
 func (i *IAMUsers) QuickActions() []QuickAction {
-	return []QuickAction{}
+	return []QuickAction{
+		{
+			Key:            'D',
+			Label:          "delete",
+			Description:    "Delete user",
+			NeedsSelection: true,
+			Plan:           planDeleteUser,
+		},
+		{
+			Key:            'X',
+			Label:          "disable",
+			Description:    "Disable user",
+			NeedsSelection: true,
+			Plan:           planDisableUser,
+		},
+		{
+			Key:            'R',
+			Label:          "rotate key",
+			Description:    "Rotate access key",
+			NeedsSelection: true,
+			Plan:           planRotateAccessKey,
+		},
+		{
+			Key:            'P',
+			Label:          "policies",
+			Description:    "Attach or detach managed policies",
+			NeedsSelection: true,
+			View: func(userName string) Resource {
+				return NewIAMAttachedPolicies(iamUser, userName)
+			},
+		},
+	}
 }
 
 // IAMRole represents an IAM role
@@ -110,9 +188,11 @@ type IAMRole struct {
 	ARN        string
 }
 
-// IAMRoles implements Resource for IAM roles
+// IAMRoles implements Resource and Pageable for IAM roles
 type IAMRoles struct {
-	roles []IAMRole
+	roles  []IAMRole
+	pages  int
+	marker *string
 }
 
 // NewIAMRoles creates a new IAMRoles resource
@@ -131,39 +211,74 @@ func (i *IAMRoles) Name() string {
 func (i *IAMRoles) Columns() []Column {
 	return []Column{
 		{Name: "Role Name", Width: 40},
-		{Name: "Role ID", Width: 25},
+		{Name: "Role ID", Width: 25, Sensitive: true},
 		{Name: "Created", Width: 20},
 		{Name: "ARN", Width: 60},
 	}
 }
 
-// Fetch retrieves IAM roles from AWS
+// Fetch retrieves IAM roles from AWS, as many pages as already loaded
 func (i *IAMRoles) Fetch(ctx context.Context, c *client.Client) error {
 	i.roles = make([]IAMRole, 0)
+	i.marker = nil
 
-	paginator := iam.NewListRolesPaginator(c.IAM(), &iam.ListRolesInput{})
-
-	for paginator.HasMorePages() {
-		output, err := paginator.NextPage(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to list IAM roles: %w", err)
+	pages := max(i.pages, 1)
+	i.pages = 0
+	for page := 0; page < pages; page++ {
+		if err := i.fetchPage(ctx, c); err != nil {
+			return err
 		}
+		if i.marker == nil {
+			break
+		}
+	}
 
-		for _, role := range output.Roles {
-			createDate := ""
-			if role.CreateDate != nil {
-				createDate = role.CreateDate.Format("2006-01-02 15:04:05")
-			}
+	return nil
+}
 
-			i.roles = append(i.roles, IAMRole{
-				RoleName:   stringValue(role.RoleName),
-				RoleID:     stringValue(role.RoleId),
-				CreateDate: createDate,
-				ARN:        stringValue(role.Arn),
-			})
+// HasMore reports whether more IAM roles can be loaded
+func (i *IAMRoles) HasMore() bool {
+	return i.marker != nil
+}
+
+// FetchMore retrieves the next page of IAM roles
+func (i *IAMRoles) FetchMore(ctx context.Context, c *client.Client) error {
+	if i.marker == nil {
+		return nil
+	}
+	return i.fetchPage(ctx, c)
+}
+
+// fetchPage retrieves the page of IAM roles starting at the marker
+func (i *IAMRoles) fetchPage(ctx context.Context, c *client.Client) error {
+	maxItems := pageSize
+	output, err := c.IAM().ListRoles(ctx, &iam.ListRolesInput{
+		Marker:   i.marker,
+		MaxItems: &maxItems,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list IAM roles: %w", err)
+	}
+
+	for _, role := range output.Roles {
+		createDate := ""
+		if role.CreateDate != nil {
+			createDate = role.CreateDate.Format("2006-01-02 15:04:05")
 		}
+
+		i.roles = append(i.roles, IAMRole{
+			RoleName:   stringValue(role.RoleName),
+			RoleID:     stringValue(role.RoleId),
+			CreateDate: createDate,
+			ARN:        stringValue(role.Arn),
+		})
 	}
 
+	i.pages++
+	i.marker = nil
+	if output.IsTruncated {
+		i.marker = output.Marker
+	}
 	return nil
 }
 
@@ -191,7 +306,151 @@ func (i *IAMRoles) GetID(index int) string {
 
 // QuickActions returns the available quick actions for IAM roles
 func (i *IAMRoles) QuickActions() []QuickAction {
-	return []QuickAction{}
+	return []QuickAction{
+		{
+			Key:            'D',
+			Label:          "delete",
+			Description:    "Delete role",
+			NeedsSelection: true,
+			Plan:           planDeleteRole,
+		},
+		{
+			Key:            'X',
+			Label:          "disable",
+			Description:    "Disable role",
+			NeedsSelection: true,
+			Plan:           planDisableRole,
+		},
+		{
+			Key:            'P',
+			Label:          "policies",
+			Description:    "Attach or detach managed policies",
+			NeedsSelection: true,
+			View: func(roleName string) Resource {
+				return NewIAMAttachedPolicies(iamRole, roleName)
+			},
+		},
+		{
+			Key:            'A',
+			Label:          "assume",
+			Description:    "Assume role, Ctrl+U drops it",
+			NeedsSelection: true,
+			ReadOnly:       true,
+			InputLabel:     "MFA code (empty if not required): ",
+			InputOptional:  true,
+			InputHandler:   assumeRole,
+		},
+	}
+}
+
+// assumeRole assumes a role, the clients then use its temporary credentials
+func assumeRole(ctx context.Context, c *client.Client, roleName, mfaCode string) error {
+	output, err := c.IAM().GetRole(ctx, &iam.GetRoleInput{RoleName: aws.String(roleName)})
+	if err != nil {
+		return fmt.Errorf("failed to get role: %w", err)
+	}
+	return c.AssumeRole(ctx, aws.ToString(output.Role.Arn), strings.TrimSpace(mfaCode))
+}
+
+// IAMGroup represents an IAM group
+type IAMGroup struct {
+	GroupName  string
+	GroupID    string
+	CreateDate string
+	ARN        string
+}
+
+// IAMGroups implements Resource for IAM groups
+type IAMGroups struct {
+	groups []IAMGroup
+}
+
+// NewIAMGroups creates a new IAMGroups resource
+func NewIAMGroups() *IAMGroups {
+	return &IAMGroups{
+		groups: make([]IAMGroup, 0),
+	}
+}
+
+// Name returns the display name
+func (i *IAMGroups) Name() string {
+	return "IAM Groups"
+}
+
+// Columns returns the column definitions
+func (i *IAMGroups) Columns() []Column {
+	return []Column{
+		{Name: "Group Name", Width: 40},
+		{Name: "Group ID", Width: 25, Sensitive: true},
+		{Name: "Created", Width: 20},
+		{Name: "ARN", Width: 60},
+	}
+}
+
+// Fetch retrieves IAM groups from AWS
+func (i *IAMGroups) Fetch(ctx context.Context, c *client.Client) error {
+	i.groups = make([]IAMGroup, 0)
+
+	paginator := iam.NewListGroupsPaginator(c.IAM(), &iam.ListGroupsInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list IAM groups: %w", err)
+		}
+
+		for _, group := range output.Groups {
+			createDate := ""
+			if group.CreateDate != nil {
+				createDate = group.CreateDate.Format("2006-01-02 15:04:05")
+			}
+
+			i.groups = append(i.groups, IAMGroup{
+				GroupName:  stringValue(group.GroupName),
+				GroupID:    stringValue(group.GroupId),
+				CreateDate: createDate,
+				ARN:        stringValue(group.Arn),
+			})
+		}
+	}
+
+	return nil
+}
+
+// Rows returns the table data
+func (i *IAMGroups) Rows() [][]string {
+	rows := make([][]string, len(i.groups))
+	for idx, group := range i.groups {
+		rows[idx] = []string{
+			group.GroupName,
+			group.GroupID,
+			group.CreateDate,
+			group.ARN,
+		}
+	}
+	return rows
+}
+
+// GetID returns the group name at the given index
+func (i *IAMGroups) GetID(index int) string {
+	if index >= 0 && index < len(i.groups) {
+		return i.groups[index].GroupName
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for IAM groups
+func (i *IAMGroups) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:            'P',
+			Label:          "policies",
+			Description:    "Attach or detach managed policies",
+			NeedsSelection: true,
+			View: func(groupName string) Resource {
+				return NewIAMAttachedPolicies(iamGroup, groupName)
+			},
+		},
+	}
 }
 
 // IAMPolicy represents an IAM policy
@@ -297,3 +556,822 @@ func (i *IAMPolicies) GetID(index int) string {
 func (i *IAMPolicies) QuickActions() []QuickAction {
 	return []QuickAction{}
 }
+
+// DrillDown opens the users, roles and groups the policy is attached to
+func (i *IAMPolicies) DrillDown(policyName string) Resource {
+	for _, policy := range i.policies {
+		if policyNameFromARN(policy.ARN) == policyName {
+			return NewIAMPolicyAttachments(policy.ARN)
+		}
+	}
+	return nil
+}
+
+// DefaultKeyMaxAge is the age above which access keys are highlighted by default
+const DefaultKeyMaxAge = 90 * 24 * time.Hour
+
+// IAMAccessKey represents an access key of an IAM user
+type IAMAccessKey struct {
+	UserName    string
+	ID          string
+	Status      string
+	Created     time.Time
+	LastUsed    string
+	LastService string
+	LastRegion  string
+}
+
+// IAMAccessKeys implements Resource for the access keys of all IAM users
+type IAMAccessKeys struct {
+	maxAge time.Duration
+	keys   []IAMAccessKey
+}
+
+// NewIAMAccessKeys creates a new IAMAccessKeys resource, keys older than maxAge are highlighted
+func NewIAMAccessKeys(maxAge time.Duration) *IAMAccessKeys {
+	if maxAge <= 0 {
+		maxAge = DefaultKeyMaxAge
+	}
+	return &IAMAccessKeys{
+		maxAge: maxAge,
+		keys:   make([]IAMAccessKey, 0),
+	}
+}
+
+// Name returns the display name
+func (i *IAMAccessKeys) Name() string {
+	return "IAM Access Keys"
+}
+
+// Columns returns the column definitions
+func (i *IAMAccessKeys) Columns() []Column {
+	return []Column{
+		{Name: "User", Width: 30},
+		{Name: "Access Key ID", Width: 22},
+		{Name: "Status", Width: 10},
+		{Name: "Age", Width: 8},
+		{Name: "Created", Width: 20},
+		{Name: "Last Used", Width: 20},
+		{Name: "Service", Width: 20},
+		{Name: "Region", Width: 15},
+	}
+}
+
+// Fetch retrieves the access keys of all IAM users with their last use
+func (i *IAMAccessKeys) Fetch(ctx context.Context, c *client.Client) error {
+	i.keys = make([]IAMAccessKey, 0)
+
+	paginator := iam.NewListUsersPaginator(c.IAM(), &iam.ListUsersInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list IAM users: %w", err)
+		}
+
+		for _, user := range output.Users {
+			userName := stringValue(user.UserName)
+			keys, err := userAccessKeys(ctx, c, userName)
+			if err != nil {
+				return err
+			}
+
+			for _, key := range keys {
+				k := IAMAccessKey{
+					UserName: userName,
+					ID:       stringValue(key.AccessKeyId),
+					Status:   string(key.Status),
+					LastUsed: "never",
+				}
+				if key.CreateDate != nil {
+					k.Created = *key.CreateDate
+				}
+
+				lastUsed, err := c.IAM().GetAccessKeyLastUsed(ctx, &iam.GetAccessKeyLastUsedInput{
+					AccessKeyId: key.AccessKeyId,
+				})
+				if err == nil && lastUsed.AccessKeyLastUsed != nil {
+					if lastUsed.AccessKeyLastUsed.LastUsedDate != nil {
+						k.LastUsed = lastUsed.AccessKeyLastUsed.LastUsedDate.Format("2006-01-02 15:04:05")
+					}
+					k.LastService = stringValue(lastUsed.AccessKeyLastUsed.ServiceName)
+					k.LastRegion = stringValue(lastUsed.AccessKeyLastUsed.Region)
+				}
+
+				i.keys = append(i.keys, k)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Rows returns the table data
+func (i *IAMAccessKeys) Rows() [][]string {
+	rows := make([][]string, len(i.keys))
+	for j, key := range i.keys {
+		created := ""
+		age := ""
+		if !key.Created.IsZero() {
+			created = key.Created.Format("2006-01-02 15:04:05")
+			age = fmt.Sprintf("%dd", int(time.Since(key.Created).Hours()/24))
+		}
+		rows[j] = []string{
+			key.UserName,
+			key.ID,
+			key.Status,
+			age,
+			created,
+			key.LastUsed,
+			key.LastService,
+			key.LastRegion,
+		}
+	}
+	return rows
+}
+
+// GetID returns the access key ID at the given index
+func (i *IAMAccessKeys) GetID(index int) string {
+	if index >= 0 && index < len(i.keys) {
+		return i.keys[index].ID
+	}
+	return ""
+}
+
+// Highlight flags the keys older than the maximum age
+func (i *IAMAccessKeys) Highlight(index int) bool {
+	if index < 0 || index >= len(i.keys) || i.keys[index].Created.IsZero() {
+		return false
+	}
+	return time.Since(i.keys[index].Created) > i.maxAge
+}
+
+// QuickActions returns the available quick actions for IAM access keys
+func (i *IAMAccessKeys) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:             'X',
+			Label:           "deactivate",
+			Description:     "Deactivate access key",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "[yellow]deactivate[-] access key [white]%s[-]? The applications using it will be denied.",
+			ConfirmCheck: func(ctx context.Context, c *client.Client, keyID string) (string, error) {
+				key, err := i.key(keyID)
+				if err != nil {
+					return "", err
+				}
+				if key.Status != string(iamtypes.StatusTypeActive) {
+					return "", fmt.Errorf("access key %s is already inactive", keyID)
+				}
+				return fmt.Sprintf("Last used: %s %s", key.LastUsed, key.LastService), nil
+			},
+			Handler: func(ctx context.Context, c *client.Client, keyID string) error {
+				return i.setStatus(ctx, c, keyID, iamtypes.StatusTypeInactive)
+			},
+		},
+		{
+			Key:             'A',
+			Label:           "activate",
+			Description:     "Activate access key",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "[yellow]activate[-] access key [white]%s[-]? It can be used again, make sure it has not leaked.",
+			ConfirmCheck: func(ctx context.Context, c *client.Client, keyID string) (string, error) {
+				key, err := i.key(keyID)
+				if err != nil {
+					return "", err
+				}
+				if key.Status == string(iamtypes.StatusTypeActive) {
+					return "", fmt.Errorf("access key %s is already active", keyID)
+				}
+				return fmt.Sprintf("Last used: %s %s", key.LastUsed, key.LastService), nil
+			},
+			Handler: func(ctx context.Context, c *client.Client, keyID string) error {
+				return i.setStatus(ctx, c, keyID, iamtypes.StatusTypeActive)
+			},
+		},
+		{
+			Key:             'D',
+			Label:           "delete",
+			Description:     "Delete access key",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			TypedConfirm:    true,
+			ConfirmTemplate: "[red]delete[-] access key [white]%s[-]? This cannot be undone.",
+			ConfirmCheck: func(ctx context.Context, c *client.Client, keyID string) (string, error) {
+				key, err := i.key(keyID)
+				if err != nil {
+					return "", err
+				}
+				// Deactivating first shows what breaks while the key can still be restored
+				if key.Status == string(iamtypes.StatusTypeActive) {
+					return "", fmt.Errorf("access key %s is active, deactivate it first", keyID)
+				}
+				return fmt.Sprintf("Last used: %s %s", key.LastUsed, key.LastService), nil
+			},
+			Handler: func(ctx context.Context, c *client.Client, keyID string) error {
+				key, err := i.key(keyID)
+				if err != nil {
+					return err
+				}
+				_, err = c.IAM().DeleteAccessKey(ctx, &iam.DeleteAccessKeyInput{UserName: &key.UserName, AccessKeyId: &keyID})
+				if err != nil {
+					return fmt.Errorf("failed to delete access key %s: %w", keyID, err)
+				}
+				return nil
+			},
+		},
+		{
+			Key:            'c',
+			Label:          "create",
+			Description:    "Create a new access key for the user of the key",
+			NeedsSelection: true,
+			Plan: func(ctx context.Context, c *client.Client, keyID string) ([]Step, error) {
+				key, err := i.key(keyID)
+				if err != nil {
+					return nil, err
+				}
+				return planCreateAccessKey(ctx, c, key.UserName)
+			},
+		},
+	}
+}
+
+// key returns the listed access key with the given ID
+func (i *IAMAccessKeys) key(keyID string) (IAMAccessKey, error) {
+	for _, key := range i.keys {
+		if key.ID == keyID {
+			return key, nil
+		}
+	}
+	return IAMAccessKey{}, fmt.Errorf("access key %s is not listed", keyID)
+}
+
+// setStatus activates or deactivates an access key
+func (i *IAMAccessKeys) setStatus(ctx context.Context, c *client.Client, keyID string, status iamtypes.StatusType) error {
+	key, err := i.key(keyID)
+	if err != nil {
+		return err
+	}
+	_, err = c.IAM().UpdateAccessKey(ctx, &iam.UpdateAccessKeyInput{
+		UserName:    &key.UserName,
+		AccessKeyId: &keyID,
+		Status:      status,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set access key %s %s: %w", keyID, status, err)
+	}
+	return nil
+}
+
+// planCreateAccessKey creates an access key for a user, its secret is only shown in the
+// plan output, never stored
+func planCreateAccessKey(ctx context.Context, c *client.Client, userName string) ([]Step, error) {
+	keys, err := userAccessKeys(ctx, c, userName)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) > 1 {
+		return nil, fmt.Errorf("%s already has 2 access keys, delete one first", userName)
+	}
+
+	return []Step{
+		{Description: "The secret access key is shown once, copy it with y before closing"},
+		{
+			Description: fmt.Sprintf("Create new access key for %s", userName),
+			RunText:     createAccessKeyText(userName),
+		},
+	}, nil
+}
+
+// createAccessKeyText returns a step creating an access key and returning it as environment variables
+func createAccessKeyText(userName string) func(ctx context.Context, c *client.Client) (string, error) {
+	return func(ctx context.Context, c *client.Client) (string, error) {
+		output, err := c.IAM().CreateAccessKey(ctx, &iam.CreateAccessKeyInput{UserName: &userName})
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("AWS_ACCESS_KEY_ID=%s\nAWS_SECRET_ACCESS_KEY=%s",
+			stringValue(output.AccessKey.AccessKeyId), stringValue(output.AccessKey.SecretAccessKey)), nil
+	}
+}
+
+// denyAllPolicyName is the inline policy attached to disable a role
+const denyAllPolicyName = "a9s-deny-all"
+
+// denyAllPolicy denies every action, including for the sessions already issued
+const denyAllPolicy = `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":"*","Resource":"*"}]}`
+
+// userAccessKeys lists the access keys of a user
+func userAccessKeys(ctx context.Context, c *client.Client, userName string) ([]iamtypes.AccessKeyMetadata, error) {
+	keys := make([]iamtypes.AccessKeyMetadata, 0)
+	paginator := iam.NewListAccessKeysPaginator(c.IAM(), &iam.ListAccessKeysInput{UserName: &userName})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list access keys of %s: %w", userName, err)
+		}
+		keys = append(keys, output.AccessKeyMetadata...)
+	}
+	return keys, nil
+}
+
+// hasLoginProfile reports whether a user has a console password
+func hasLoginProfile(ctx context.Context, c *client.Client, userName string) (bool, error) {
+	_, err := c.IAM().GetLoginProfile(ctx, &iam.GetLoginProfileInput{UserName: &userName})
+	if err != nil {
+		var notFound *iamtypes.NoSuchEntityException
+		if errors.As(err, &notFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get login profile of %s: %w", userName, err)
+	}
+	return true, nil
+}
+
+// rotationGracePeriod is the delay after which a rotated access key should be deleted
+const rotationGracePeriod = 7 * 24 * time.Hour
+
+// planRotateAccessKey creates a new access key for a user and records a reminder to
+// deactivate and delete the old one in the audit log, which is left to the access keys view
+// once the applications use the new key
+func planRotateAccessKey(ctx context.Context, c *client.Client, userName string) ([]Step, error) {
+	keys, err := userAccessKeys(ctx, c, userName)
+	if err != nil {
+		return nil, err
+	}
+
+	var active, inactive []string
+	for _, key := range keys {
+		if key.Status == iamtypes.StatusTypeActive {
+			active = append(active, stringValue(key.AccessKeyId))
+		} else {
+			inactive = append(inactive, stringValue(key.AccessKeyId))
+		}
+	}
+	if len(active) > 1 {
+		return nil, fmt.Errorf("%s already has 2 active access keys, deactivate one first", userName)
+	}
+
+	steps := make([]Step, 0)
+
+	// Users have at most 2 access keys, make room for the new one
+	if len(keys) > 1 {
+		keyID := inactive[0]
+		steps = append(steps, Step{
+			Description: fmt.Sprintf("Delete inactive access key %s", keyID),
+			Run: func(ctx context.Context, c *client.Client) error {
+				_, err := c.IAM().DeleteAccessKey(ctx, &iam.DeleteAccessKeyInput{UserName: &userName, AccessKeyId: &keyID})
+				return err
+			},
+		})
+	}
+
+	steps = append(steps, Step{
+		Description: "Create new access key",
+		RunText:     createAccessKeyText(userName),
+	})
+
+	if len(active) == 0 {
+		return steps, nil
+	}
+
+	oldKeyID := active[0]
+	return append(steps,
+		Step{
+			Description: fmt.Sprintf("Record a reminder to deactivate and delete %s in %d days", oldKeyID, int(rotationGracePeriod.Hours()/24)),
+			Run: func(ctx context.Context, c *client.Client) error {
+				due := time.Now().Add(rotationGracePeriod)
+				audit.Record(audit.Entry{
+					Profile:  c.Profile(),
+					Region:   c.Region(),
+					Resource: "IAM Users",
+					Action:   "reminder: deactivate and delete access key",
+					Target:   userName + "/" + oldKeyID,
+					Ticket:   client.TicketFromContext(ctx),
+					Due:      &due,
+				})
+				return nil
+			},
+		},
+		Step{Description: fmt.Sprintf("Update the applications using %s with the new key, then deactivate it from the access keys of %s (X)", oldKeyID, userName)},
+	), nil
+}
+
+// planDisableUser deactivates the access keys of a user and removes its console password
+func planDisableUser(ctx context.Context, c *client.Client, userName string) ([]Step, error) {
+	steps := make([]Step, 0)
+
+	keys, err := userAccessKeys(ctx, c, userName)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		keyID := stringValue(key.AccessKeyId)
+		if key.Status == iamtypes.StatusTypeInactive {
+			continue
+		}
+		steps = append(steps, Step{
+			Description: fmt.Sprintf("Deactivate access key %s", keyID),
+			Run: func(ctx context.Context, c *client.Client) error {
+				_, err := c.IAM().UpdateAccessKey(ctx, &iam.UpdateAccessKeyInput{
+					UserName:    &userName,
+					AccessKeyId: &keyID,
+					Status:      iamtypes.StatusTypeInactive,
+				})
+				return err
+			},
+		})
+	}
+
+	hasPassword, err := hasLoginProfile(ctx, c, userName)
+	if err != nil {
+		return nil, err
+	}
+	if hasPassword {
+		steps = append(steps, Step{
+			Description: "Delete console password",
+			Run: func(ctx context.Context, c *client.Client) error {
+				_, err := c.IAM().DeleteLoginProfile(ctx, &iam.DeleteLoginProfileInput{UserName: &userName})
+				return err
+			},
+		})
+	}
+
+	if len(steps) == 0 {
+		steps = append(steps, Step{Description: "User has no active access key nor console password"})
+	}
+	return steps, nil
+}
+
+// planDeleteUser removes everything attached to a user, then deletes it
+func planDeleteUser(ctx context.Context, c *client.Client, userName string) ([]Step, error) {
+	steps := make([]Step, 0)
+
+	hasPassword, err := hasLoginProfile(ctx, c, userName)
+	if err != nil {
+		return nil, err
+	}
+	if hasPassword {
+		steps = append(steps, Step{
+			Description: "Delete console password",
+			Run: func(ctx context.Context, c *client.Client) error {
+				_, err := c.IAM().DeleteLoginProfile(ctx, &iam.DeleteLoginProfileInput{UserName: &userName})
+				return err
+			},
+		})
+	}
+
+	keys, err := userAccessKeys(ctx, c, userName)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		keyID := stringValue(key.AccessKeyId)
+		steps = append(steps, Step{
+			Description: fmt.Sprintf("Delete access key %s (%s)", keyID, key.Status),
+			Run: func(ctx context.Context, c *client.Client) error {
+				_, err := c.IAM().DeleteAccessKey(ctx, &iam.DeleteAccessKeyInput{UserName: &userName, AccessKeyId: &keyID})
+				return err
+			},
+		})
+	}
+
+	mfaPaginator := iam.NewListMFADevicesPaginator(c.IAM(), &iam.ListMFADevicesInput{UserName: &userName})
+	for mfaPaginator.HasMorePages() {
+		output, err := mfaPaginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list MFA devices of %s: %w", userName, err)
+		}
+		for _, device := range output.MFADevices {
+			serial := stringValue(device.SerialNumber)
+			steps = append(steps, Step{
+				Description: fmt.Sprintf("Deactivate MFA device %s", serial),
+				Run: func(ctx context.Context, c *client.Client) error {
+					_, err := c.IAM().DeactivateMFADevice(ctx, &iam.DeactivateMFADeviceInput{UserName: &userName, SerialNumber: &serial})
+					return err
+				},
+			})
+		}
+	}
+
+	certPaginator := iam.NewListSigningCertificatesPaginator(c.IAM(), &iam.ListSigningCertificatesInput{UserName: &userName})
+	for certPaginator.HasMorePages() {
+		output, err := certPaginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list signing certificates of %s: %w", userName, err)
+		}
+		for _, cert := range output.Certificates {
+			certID := stringValue(cert.CertificateId)
+			steps = append(steps, Step{
+				Description: fmt.Sprintf("Delete signing certificate %s", certID),
+				Run: func(ctx context.Context, c *client.Client) error {
+					_, err := c.IAM().DeleteSigningCertificate(ctx, &iam.DeleteSigningCertificateInput{UserName: &userName, CertificateId: &certID})
+					return err
+				},
+			})
+		}
+	}
+
+	sshPaginator := iam.NewListSSHPublicKeysPaginator(c.IAM(), &iam.ListSSHPublicKeysInput{UserName: &userName})
+	for sshPaginator.HasMorePages() {
+		output, err := sshPaginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list SSH public keys of %s: %w", userName, err)
+		}
+		for _, key := range output.SSHPublicKeys {
+			keyID := stringValue(key.SSHPublicKeyId)
+			steps = append(steps, Step{
+				Description: fmt.Sprintf("Delete SSH public key %s", keyID),
+				Run: func(ctx context.Context, c *client.Client) error {
+					_, err := c.IAM().DeleteSSHPublicKey(ctx, &iam.DeleteSSHPublicKeyInput{UserName: &userName, SSHPublicKeyId: &keyID})
+					return err
+				},
+			})
+		}
+	}
+
+	credentials, err := c.IAM().ListServiceSpecificCredentials(ctx, &iam.ListServiceSpecificCredentialsInput{UserName: &userName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service credentials of %s: %w", userName, err)
+	}
+	for _, credential := range credentials.ServiceSpecificCredentials {
+		credentialID := stringValue(credential.ServiceSpecificCredentialId)
+		steps = append(steps, Step{
+			Description: fmt.Sprintf("Delete %s credential %s", stringValue(credential.ServiceName), credentialID),
+			Run: func(ctx context.Context, c *client.Client) error {
+				_, err := c.IAM().DeleteServiceSpecificCredential(ctx, &iam.DeleteServiceSpecificCredentialInput{
+					UserName:                    &userName,
+					ServiceSpecificCredentialId: &credentialID,
+				})
+				return err
+			},
+		})
+	}
+
+	groupPaginator := iam.NewListGroupsForUserPaginator(c.IAM(), &iam.ListGroupsForUserInput{UserName: &userName})
+	for groupPaginator.HasMorePages() {
+		output, err := groupPaginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list groups of %s: %w", userName, err)
+		}
+		for _, group := range output.Groups {
+			groupName := stringValue(group.GroupName)
+			steps = append(steps, Step{
+				Description: fmt.Sprintf("Remove from group %s", groupName),
+				Run: func(ctx context.Context, c *client.Client) error {
+					_, err := c.IAM().RemoveUserFromGroup(ctx, &iam.RemoveUserFromGroupInput{UserName: &userName, GroupName: &groupName})
+					return err
+				},
+			})
+		}
+	}
+
+	attachedPaginator := iam.NewListAttachedUserPoliciesPaginator(c.IAM(), &iam.ListAttachedUserPoliciesInput{UserName: &userName})
+	for attachedPaginator.HasMorePages() {
+		output, err := attachedPaginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list attached policies of %s: %w", userName, err)
+		}
+		for _, policy := range output.AttachedPolicies {
+			policyArn := stringValue(policy.PolicyArn)
+			steps = append(steps, Step{
+				Description: fmt.Sprintf("Detach policy %s", stringValue(policy.PolicyName)),
+				Run: func(ctx context.Context, c *client.Client) error {
+					_, err := c.IAM().DetachUserPolicy(ctx, &iam.DetachUserPolicyInput{UserName: &userName, PolicyArn: &policyArn})
+					return err
+				},
+			})
+		}
+	}
+
+	inlinePaginator := iam.NewListUserPoliciesPaginator(c.IAM(), &iam.ListUserPoliciesInput{UserName: &userName})
+	for inlinePaginator.HasMorePages() {
+		output, err := inlinePaginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list inline policies of %s: %w", userName, err)
+		}
+		for _, policyName := range output.PolicyNames {
+			steps = append(steps, Step{
+				Description: fmt.Sprintf("Delete inline policy %s", policyName),
+				Run: func(ctx context.Context, c *client.Client) error {
+					_, err := c.IAM().DeleteUserPolicy(ctx, &iam.DeleteUserPolicyInput{UserName: &userName, PolicyName: &policyName})
+					return err
+				},
+			})
+		}
+	}
+
+	steps = append(steps, Step{
+		Description: fmt.Sprintf("Delete user %s", userName),
+		Run: func(ctx context.Context, c *client.Client) error {
+			_, err := c.IAM().DeleteUser(ctx, &iam.DeleteUserInput{UserName: &userName})
+			return err
+		},
+	})
+	return steps, nil
+}
+
+// planDisableRole attaches an inline policy denying everything, which also
+// applies to the sessions already issued
+func planDisableRole(ctx context.Context, c *client.Client, roleName string) ([]Step, error) {
+	notes, err := roleTrustNotes(ctx, c, roleName)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(notes,
+		Step{
+			Description: fmt.Sprintf("Attach inline policy %s denying all actions", denyAllPolicyName),
+			Run: func(ctx context.Context, c *client.Client) error {
+				_, err := c.IAM().PutRolePolicy(ctx, &iam.PutRolePolicyInput{
+					RoleName:       &roleName,
+					PolicyName:     aws.String(denyAllPolicyName),
+					PolicyDocument: aws.String(denyAllPolicy),
+				})
+				return err
+			},
+		},
+		Step{Description: fmt.Sprintf("Delete the %s inline policy to enable the role again", denyAllPolicyName)},
+	), nil
+}
+
+// planDeleteRole removes the role from its instance profiles and its policies, then deletes it
+func planDeleteRole(ctx context.Context, c *client.Client, roleName string) ([]Step, error) {
+	steps, err := roleTrustNotes(ctx, c, roleName)
+	if err != nil {
+		return nil, err
+	}
+
+	profilePaginator := iam.NewListInstanceProfilesForRolePaginator(c.IAM(), &iam.ListInstanceProfilesForRoleInput{RoleName: &roleName})
+	for profilePaginator.HasMorePages() {
+		output, err := profilePaginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list instance profiles of %s: %w", roleName, err)
+		}
+		for _, profile := range output.InstanceProfiles {
+			profileName := stringValue(profile.InstanceProfileName)
+
+			// Instances keep the profile, but lose the role credentials
+			instances, err := instancesUsingProfile(ctx, c, stringValue(profile.Arn))
+			if err != nil {
+				return nil, err
+			}
+			if len(instances) > 0 {
+				steps = append(steps, Step{
+					Description: fmt.Sprintf("Instance profile %s is used by %s", profileName, strings.Join(instances, ", ")),
+				})
+			}
+
+			steps = append(steps, Step{
+				Description: fmt.Sprintf("Remove from instance profile %s", profileName),
+				Run: func(ctx context.Context, c *client.Client) error {
+					_, err := c.IAM().RemoveRoleFromInstanceProfile(ctx, &iam.RemoveRoleFromInstanceProfileInput{
+						RoleName:            &roleName,
+						InstanceProfileName: &profileName,
+					})
+					return err
+				},
+			})
+		}
+	}
+
+	attachedPaginator := iam.NewListAttachedRolePoliciesPaginator(c.IAM(), &iam.ListAttachedRolePoliciesInput{RoleName: &roleName})
+	for attachedPaginator.HasMorePages() {
+		output, err := attachedPaginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list attached policies of %s: %w", roleName, err)
+		}
+		for _, policy := range output.AttachedPolicies {
+			policyArn := stringValue(policy.PolicyArn)
+			steps = append(steps, Step{
+				Description: fmt.Sprintf("Detach policy %s", stringValue(policy.PolicyName)),
+				Run: func(ctx context.Context, c *client.Client) error {
+					_, err := c.IAM().DetachRolePolicy(ctx, &iam.DetachRolePolicyInput{RoleName: &roleName, PolicyArn: &policyArn})
+					return err
+				},
+			})
+		}
+	}
+
+	inlinePaginator := iam.NewListRolePoliciesPaginator(c.IAM(), &iam.ListRolePoliciesInput{RoleName: &roleName})
+	for inlinePaginator.HasMorePages() {
+		output, err := inlinePaginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list inline policies of %s: %w", roleName, err)
+		}
+		for _, policyName := range output.PolicyNames {
+			steps = append(steps, Step{
+				Description: fmt.Sprintf("Delete inline policy %s", policyName),
+				Run: func(ctx context.Context, c *client.Client) error {
+					_, err := c.IAM().DeleteRolePolicy(ctx, &iam.DeleteRolePolicyInput{RoleName: &roleName, PolicyName: &policyName})
+					return err
+				},
+			})
+		}
+	}
+
+	steps = append(steps, Step{
+		Description: fmt.Sprintf("Delete role %s", roleName),
+		Run: func(ctx context.Context, c *client.Client) error {
+			_, err := c.IAM().DeleteRole(ctx, &iam.DeleteRoleInput{RoleName: &roleName})
+			return err
+		},
+	})
+	return steps, nil
+}
+
+// roleTrustNotes returns notes listing who can assume the role, so that the
+// user knows what breaks when it goes away
+func roleTrustNotes(ctx context.Context, c *client.Client, roleName string) ([]Step, error) {
+	output, err := c.IAM().GetRole(ctx, &iam.GetRoleInput{RoleName: &roleName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role %s: %w", roleName, err)
+	}
+
+	notes := make([]Step, 0)
+	if strings.HasPrefix(stringValue(output.Role.Path), "/aws-service-role/") {
+		notes = append(notes, Step{Description: "Service-linked role, it can only be deleted through its service"})
+	}
+
+	principals, err := trustedPrincipals(stringValue(output.Role.AssumeRolePolicyDocument))
+	if err != nil {
+		return nil, err
+	}
+	for _, principal := range principals {
+		notes = append(notes, Step{Description: fmt.Sprintf("Trusted by %s", principal)})
+	}
+	return notes, nil
+}
+
+// trustedPrincipals parses the URL encoded trust policy of a role into "type:principal" entries
+func trustedPrincipals(document string) ([]string, error) {
+	decoded, err := url.QueryUnescape(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode trust policy: %w", err)
+	}
+
+	var policy struct {
+		Statement []struct {
+			Effect    string
+			Principal json.RawMessage
+		}
+	}
+	if err := json.Unmarshal([]byte(decoded), &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse trust policy: %w", err)
+	}
+
+	principals := make([]string, 0)
+	for _, statement := range policy.Statement {
+		if statement.Effect != "Allow" {
+			continue
+		}
+
+		// Principal is either "*" or a map of a type to one or several principals
+		var wildcard string
+		if json.Unmarshal(statement.Principal, &wildcard) == nil {
+			principals = append(principals, wildcard)
+			continue
+		}
+		var byType map[string]json.RawMessage
+		if err := json.Unmarshal(statement.Principal, &byType); err != nil {
+			continue
+		}
+		for principalType, raw := range byType {
+			var values []string
+			var single string
+			if json.Unmarshal(raw, &single) == nil {
+				values = []string{single}
+			} else if err := json.Unmarshal(raw, &values); err != nil {
+				continue
+			}
+			for _, value := range values {
+				principals = append(principals, principalType+":"+value)
+			}
+		}
+	}
+	sort.Strings(principals)
+	return principals, nil
+}
+
+// instancesUsingProfile returns the IDs of the EC2 instances of the region using an instance profile
+func instancesUsingProfile(ctx context.Context, c *client.Client, profileArn string) ([]string, error) {
+	instances := make([]string, 0)
+	paginator := ec2.NewDescribeInstancesPaginator(c.EC2(), &ec2.DescribeInstancesInput{
+		Filters: []ec2types.Filter{
+			{Name: aws.String("iam-instance-profile.arn"), Values: []string{profileArn}},
+		},
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list instances using %s: %w", profileArn, err)
+		}
+		for _, reservation := range output.Reservations {
+			for _, instance := range reservation.Instances {
+				instances = append(instances, stringValue(instance.InstanceId))
+			}
+		}
+	}
+	return instances, nil
+}
@@ -0,0 +1,397 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// Kinds of the IAM identities managed policies are attached to
+const (
+	iamUser  = "user"
+	iamRole  = "role"
+	iamGroup = "group"
+)
+
+// attachPolicy attaches a managed policy to a user, role or group
+func attachPolicy(ctx context.Context, c *client.Client, kind, name, policyARN string) error {
+	var err error
+	switch kind {
+	case iamUser:
+		_, err = c.IAM().AttachUserPolicy(ctx, &iam.AttachUserPolicyInput{UserName: &name, PolicyArn: &policyARN})
+	case iamRole:
+		_, err = c.IAM().AttachRolePolicy(ctx, &iam.AttachRolePolicyInput{RoleName: &name, PolicyArn: &policyARN})
+	case iamGroup:
+		_, err = c.IAM().AttachGroupPolicy(ctx, &iam.AttachGroupPolicyInput{GroupName: &name, PolicyArn: &policyARN})
+	default:
+		return fmt.Errorf("policies cannot be attached to a %s", kind)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to attach %s to %s %s: %w", policyARN, kind, name, err)
+	}
+	return nil
+}
+
+// detachPolicy detaches a managed policy from a user, role or group
+func detachPolicy(ctx context.Context, c *client.Client, kind, name, policyARN string) error {
+	var err error
+	switch kind {
+	case iamUser:
+		_, err = c.IAM().DetachUserPolicy(ctx, &iam.DetachUserPolicyInput{UserName: &name, PolicyArn: &policyARN})
+	case iamRole:
+		_, err = c.IAM().DetachRolePolicy(ctx, &iam.DetachRolePolicyInput{RoleName: &name, PolicyArn: &policyARN})
+	case iamGroup:
+		_, err = c.IAM().DetachGroupPolicy(ctx, &iam.DetachGroupPolicyInput{GroupName: &name, PolicyArn: &policyARN})
+	default:
+		return fmt.Errorf("policies cannot be detached from a %s", kind)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to detach %s from %s %s: %w", policyARN, kind, name, err)
+	}
+	return nil
+}
+
+// policyNameFromARN returns the name of a managed policy, the last part of its ARN
+func policyNameFromARN(arn string) string {
+	return arn[strings.LastIndex(arn, "/")+1:]
+}
+
+// IAMAttachedPolicies implements Resource for the managed policies attached to a user, role or group
+type IAMAttachedPolicies struct {
+	kind     string
+	name     string
+	policies []iamtypes.AttachedPolicy
+}
+
+// NewIAMAttachedPolicies creates a new IAMAttachedPolicies resource, kind is user, role or group
+func NewIAMAttachedPolicies(kind, name string) *IAMAttachedPolicies {
+	return &IAMAttachedPolicies{
+		kind:     kind,
+		name:     name,
+		policies: make([]iamtypes.AttachedPolicy, 0),
+	}
+}
+
+// Name returns the display name
+func (i *IAMAttachedPolicies) Name() string {
+	return fmt.Sprintf("IAM Attached Policies (%s %s)", i.kind, i.name)
+}
+
+// Columns returns the column definitions
+func (i *IAMAttachedPolicies) Columns() []Column {
+	return []Column{
+		{Name: "Policy Name", Width: 45},
+		{Name: "ARN", Width: 80},
+	}
+}
+
+// Fetch retrieves the managed policies attached to the identity
+func (i *IAMAttachedPolicies) Fetch(ctx context.Context, c *client.Client) error {
+	i.policies = make([]iamtypes.AttachedPolicy, 0)
+
+	var err error
+	switch i.kind {
+	case iamUser:
+		paginator := iam.NewListAttachedUserPoliciesPaginator(c.IAM(), &iam.ListAttachedUserPoliciesInput{UserName: &i.name})
+		for paginator.HasMorePages() && err == nil {
+			var output *iam.ListAttachedUserPoliciesOutput
+			if output, err = paginator.NextPage(ctx); err == nil {
+				i.policies = append(i.policies, output.AttachedPolicies...)
+			}
+		}
+	case iamRole:
+		paginator := iam.NewListAttachedRolePoliciesPaginator(c.IAM(), &iam.ListAttachedRolePoliciesInput{RoleName: &i.name})
+		for paginator.HasMorePages() && err == nil {
+			var output *iam.ListAttachedRolePoliciesOutput
+			if output, err = paginator.NextPage(ctx); err == nil {
+				i.policies = append(i.policies, output.AttachedPolicies...)
+			}
+		}
+	case iamGroup:
+		paginator := iam.NewListAttachedGroupPoliciesPaginator(c.IAM(), &iam.ListAttachedGroupPoliciesInput{GroupName: &i.name})
+		for paginator.HasMorePages() && err == nil {
+			var output *iam.ListAttachedGroupPoliciesOutput
+			if output, err = paginator.NextPage(ctx); err == nil {
+				i.policies = append(i.policies, output.AttachedPolicies...)
+			}
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to list policies of %s %s: %w", i.kind, i.name, err)
+	}
+
+	sort.Slice(i.policies, func(a, b int) bool {
+		return stringValue(i.policies[a].PolicyName) < stringValue(i.policies[b].PolicyName)
+	})
+
+	return nil
+}
+
+// Rows returns the table data
+func (i *IAMAttachedPolicies) Rows() [][]string {
+	rows := make([][]string, len(i.policies))
+	for idx, policy := range i.policies {
+		rows[idx] = []string{
+			stringValue(policy.PolicyName),
+			stringValue(policy.PolicyArn),
+		}
+	}
+	return rows
+}
+
+// GetID returns the policy ARN at the given index
+func (i *IAMAttachedPolicies) GetID(index int) string {
+	if index >= 0 && index < len(i.policies) {
+		return stringValue(i.policies[index].PolicyArn)
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for attached policies
+func (i *IAMAttachedPolicies) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:         'c',
+			Label:       "attach",
+			Description: "Search a managed policy to attach",
+			InputLabel:  "Search policies: ",
+			InputView: func(_, search string) Resource {
+				return NewIAMPolicyPicker(i.kind, i.name, search)
+			},
+		},
+		{
+			Key:             'x',
+			Label:           "detach",
+			Description:     "Detach the policy",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: fmt.Sprintf("[yellow]detach[-] [white]%%s[-] from %s [white]%s[-]?", i.kind, i.name),
+			Handler: func(ctx context.Context, c *client.Client, policyARN string) error {
+				return detachPolicy(ctx, c, i.kind, i.name, policyARN)
+			},
+		},
+	}
+}
+
+// IAMPolicyPicker implements Resource for the managed policies, customer and AWS ones,
+// matching a search, to attach one to a user, role or group
+type IAMPolicyPicker struct {
+	kind     string
+	name     string
+	search   string
+	policies []iamtypes.Policy
+}
+
+// NewIAMPolicyPicker creates a new IAMPolicyPicker resource
+func NewIAMPolicyPicker(kind, name, search string) *IAMPolicyPicker {
+	return &IAMPolicyPicker{
+		kind:     kind,
+		name:     name,
+		search:   strings.TrimSpace(search),
+		policies: make([]iamtypes.Policy, 0),
+	}
+}
+
+// Name returns the display name
+func (p *IAMPolicyPicker) Name() string {
+	return fmt.Sprintf("Attach Policy to %s %s (%s)", p.kind, p.name, p.search)
+}
+
+// Columns returns the column definitions
+func (p *IAMPolicyPicker) Columns() []Column {
+	return []Column{
+		{Name: "Policy Name", Width: 45},
+		{Name: "Scope", Width: 8},
+		{Name: "Attachments", Width: 12},
+		{Name: "ARN", Width: 80},
+	}
+}
+
+// Fetch retrieves the managed policies whose name contains the search, case insensitively
+func (p *IAMPolicyPicker) Fetch(ctx context.Context, c *client.Client) error {
+	p.policies = make([]iamtypes.Policy, 0)
+	search := strings.ToLower(p.search)
+
+	paginator := iam.NewListPoliciesPaginator(c.IAM(), &iam.ListPoliciesInput{
+		Scope: iamtypes.PolicyScopeTypeAll,
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list IAM policies: %w", err)
+		}
+		for _, policy := range output.Policies {
+			if strings.Contains(strings.ToLower(stringValue(policy.PolicyName)), search) {
+				p.policies = append(p.policies, policy)
+			}
+		}
+	}
+
+	sort.Slice(p.policies, func(i, j int) bool {
+		return stringValue(p.policies[i].PolicyName) < stringValue(p.policies[j].PolicyName)
+	})
+
+	return nil
+}
+
+// Rows returns the table data
+func (p *IAMPolicyPicker) Rows() [][]string {
+	rows := make([][]string, len(p.policies))
+	for i, policy := range p.policies {
+		scope := "Local"
+		if strings.HasPrefix(stringValue(policy.Arn), "arn:aws:iam::aws:") {
+			scope = "AWS"
+		}
+		rows[i] = []string{
+			stringValue(policy.PolicyName),
+			scope,
+			fmt.Sprintf("%d", ptrInt32Value(policy.AttachmentCount)),
+			stringValue(policy.Arn),
+		}
+	}
+	return rows
+}
+
+// GetID returns the policy ARN at the given index
+func (p *IAMPolicyPicker) GetID(index int) string {
+	if index >= 0 && index < len(p.policies) {
+		return stringValue(p.policies[index].Arn)
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for the policy picker
+func (p *IAMPolicyPicker) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:             'A',
+			Label:           "attach",
+			Description:     "Attach the policy",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: fmt.Sprintf("[yellow]attach[-] [white]%%s[-] to %s [white]%s[-]?", p.kind, p.name),
+			Handler: func(ctx context.Context, c *client.Client, policyARN string) error {
+				return attachPolicy(ctx, c, p.kind, p.name, policyARN)
+			},
+		},
+	}
+}
+
+// IAMPolicyAttachment represents a user, role or group a managed policy is attached to
+type IAMPolicyAttachment struct {
+	Kind string
+	Name string
+}
+
+// IAMPolicyAttachments implements Resource for the users, roles and groups a managed policy is attached to
+type IAMPolicyAttachments struct {
+	policyARN   string
+	attachments []IAMPolicyAttachment
+}
+
+// NewIAMPolicyAttachments creates a new IAMPolicyAttachments resource
+func NewIAMPolicyAttachments(policyARN string) *IAMPolicyAttachments {
+	return &IAMPolicyAttachments{
+		policyARN:   policyARN,
+		attachments: make([]IAMPolicyAttachment, 0),
+	}
+}
+
+// Name returns the display name
+func (p *IAMPolicyAttachments) Name() string {
+	return fmt.Sprintf("IAM Policy Attachments (%s)", policyNameFromARN(p.policyARN))
+}
+
+// Columns returns the column definitions
+func (p *IAMPolicyAttachments) Columns() []Column {
+	return []Column{
+		{Name: "Kind", Width: 8},
+		{Name: "Name", Width: 60},
+	}
+}
+
+// Fetch retrieves the users, roles and groups the policy is attached to
+func (p *IAMPolicyAttachments) Fetch(ctx context.Context, c *client.Client) error {
+	p.attachments = make([]IAMPolicyAttachment, 0)
+
+	paginator := iam.NewListEntitiesForPolicyPaginator(c.IAM(), &iam.ListEntitiesForPolicyInput{
+		PolicyArn: &p.policyARN,
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list entities of %s: %w", p.policyARN, err)
+		}
+		for _, user := range output.PolicyUsers {
+			p.attachments = append(p.attachments, IAMPolicyAttachment{Kind: iamUser, Name: stringValue(user.UserName)})
+		}
+		for _, role := range output.PolicyRoles {
+			p.attachments = append(p.attachments, IAMPolicyAttachment{Kind: iamRole, Name: stringValue(role.RoleName)})
+		}
+		for _, group := range output.PolicyGroups {
+			p.attachments = append(p.attachments, IAMPolicyAttachment{Kind: iamGroup, Name: stringValue(group.GroupName)})
+		}
+	}
+
+	return nil
+}
+
+// Rows returns the table data
+func (p *IAMPolicyAttachments) Rows() [][]string {
+	rows := make([][]string, len(p.attachments))
+	for i, attachment := range p.attachments {
+		rows[i] = []string{attachment.Kind, attachment.Name}
+	}
+	return rows
+}
+
+// GetID returns the attachment at the given index, as kind/name
+func (p *IAMPolicyAttachments) GetID(index int) string {
+	if index >= 0 && index < len(p.attachments) {
+		return p.attachments[index].Kind + "/" + p.attachments[index].Name
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for policy attachments
+func (p *IAMPolicyAttachments) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:         'c',
+			Label:       "attach",
+			Description: "Attach the policy to a user, role or group",
+			InputLabel:  "user/NAME, role/NAME or group/NAME: ",
+			// The attach is a reviewed step, the policy may grant anything to whoever is typed
+			InputPlan: func(ctx context.Context, c *client.Client, _, input string) ([]Step, error) {
+				kind, name, ok := strings.Cut(strings.TrimSpace(input), "/")
+				if !ok || name == "" {
+					return nil, fmt.Errorf("expected user/NAME, role/NAME or group/NAME, got %q", input)
+				}
+				return []Step{{
+					Description: fmt.Sprintf("Attach %s to %s %s", policyNameFromARN(p.policyARN), kind, name),
+					Run: func(ctx context.Context, c *client.Client) error {
+						return attachPolicy(ctx, c, kind, name, p.policyARN)
+					},
+				}}, nil
+			},
+		},
+		{
+			Key:             'x',
+			Label:           "detach",
+			Description:     "Detach the policy",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: fmt.Sprintf("[yellow]detach[-] %s from [white]%%s[-]?", policyNameFromARN(p.policyARN)),
+			Handler: func(ctx context.Context, c *client.Client, id string) error {
+				kind, name, _ := strings.Cut(id, "/")
+				return detachPolicy(ctx, c, kind, name, p.policyARN)
+			},
+		},
+	}
+}
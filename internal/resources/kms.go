@@ -1,12 +1,18 @@
 package resources
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 
 	"a9s/internal/client"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
 )
 
 // KMSKey represents a KMS key
@@ -40,7 +46,7 @@ func (k *KMSKeys) Name() string {
 // Columns returns the column definitions
 func (k *KMSKeys) Columns() []Column {
 	return []Column{
-		{Name: "Key ID", Width: 40},
+		{Name: "Key ID", Width: 40, Sensitive: true},
 		{Name: "Alias", Width: 30},
 		{Name: "Description", Width: 30},
 		{Name: "State", Width: 12},
@@ -135,5 +141,172 @@ func (k *KMSKeys) GetID(index int) string {
 
 // QuickActions returns the available quick actions for KMS keys
 func (k *KMSKeys) QuickActions() []QuickAction {
-	return []QuickAction{}
+	return []QuickAction{
+		{
+			Key:            'P',
+			Label:          "policy",
+			Description:    "Show key policy",
+			NeedsSelection: true,
+			TextHandler:    keyPolicy,
+		},
+	}
+}
+
+// DrillDown opens the grants of the key
+func (k *KMSKeys) DrillDown(keyID string) Resource {
+	return NewKMSGrants(keyID)
+}
+
+// keyPolicy returns the indented default policy of a key
+func keyPolicy(ctx context.Context, c *client.Client, keyID string) (string, error) {
+	output, err := c.KMS().GetKeyPolicy(ctx, &kms.GetKeyPolicyInput{
+		KeyId:      &keyID,
+		PolicyName: aws.String("default"),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get policy of key %s: %w", keyID, err)
+	}
+
+	policy := stringValue(output.Policy)
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(policy), "", "  "); err != nil {
+		return policy, nil
+	}
+	return buf.String(), nil
+}
+
+// KMSGrant represents a grant of a KMS key
+type KMSGrant struct {
+	ID          string
+	Name        string
+	Grantee     string
+	Retiring    string
+	Operations  string
+	Constraints string
+	CreatedAt   string
+}
+
+// KMSGrants implements Resource for the grants of a KMS key
+type KMSGrants struct {
+	keyID  string
+	grants []KMSGrant
+}
+
+// NewKMSGrants creates a new KMSGrants resource
+func NewKMSGrants(keyID string) *KMSGrants {
+	return &KMSGrants{
+		keyID:  keyID,
+		grants: make([]KMSGrant, 0),
+	}
+}
+
+// Name returns the display name
+func (k *KMSGrants) Name() string {
+	return fmt.Sprintf("KMS Grants: %s", k.keyID)
+}
+
+// Columns returns the column definitions
+func (k *KMSGrants) Columns() []Column {
+	return []Column{
+		{Name: "Name", Width: 25},
+		{Name: "Grantee", Width: 60},
+		{Name: "Operations", Width: 45},
+		{Name: "Constraints", Width: 30},
+		{Name: "Retiring", Width: 30},
+		{Name: "Created", Width: 20},
+	}
+}
+
+// Fetch retrieves the grants of the key
+func (k *KMSGrants) Fetch(ctx context.Context, c *client.Client) error {
+	k.grants = make([]KMSGrant, 0)
+
+	paginator := kms.NewListGrantsPaginator(c.KMS(), &kms.ListGrantsInput{
+		KeyId: &k.keyID,
+	})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list grants of key %s: %w", k.keyID, err)
+		}
+
+		for _, grant := range output.Grants {
+			operations := make([]string, 0, len(grant.Operations))
+			for _, operation := range grant.Operations {
+				operations = append(operations, string(operation))
+			}
+
+			g := KMSGrant{
+				ID:          stringValue(grant.GrantId),
+				Name:        stringValue(grant.Name),
+				Grantee:     stringValue(grant.GranteePrincipal),
+				Retiring:    stringValue(grant.RetiringPrincipal),
+				Operations:  strings.Join(operations, ", "),
+				Constraints: formatGrantConstraints(grant.Constraints),
+			}
+			if grant.CreationDate != nil {
+				g.CreatedAt = grant.CreationDate.Format("2006-01-02 15:04:05")
+			}
+
+			k.grants = append(k.grants, g)
+		}
+	}
+
+	return nil
+}
+
+// formatGrantConstraints formats the encryption context a grant is restricted to
+func formatGrantConstraints(constraints *kmstypes.GrantConstraints) string {
+	if constraints == nil {
+		return ""
+	}
+
+	parts := make([]string, 0)
+	for key, value := range constraints.EncryptionContextEquals {
+		parts = append(parts, fmt.Sprintf("%s=%s", key, value))
+	}
+	for key, value := range constraints.EncryptionContextSubset {
+		parts = append(parts, fmt.Sprintf("%s⊇%s", key, value))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+// Rows returns the table data
+func (k *KMSGrants) Rows() [][]string {
+	rows := make([][]string, len(k.grants))
+	for i, grant := range k.grants {
+		rows[i] = []string{
+			grant.Name,
+			grant.Grantee,
+			grant.Operations,
+			grant.Constraints,
+			grant.Retiring,
+			grant.CreatedAt,
+		}
+	}
+	return rows
+}
+
+// GetID returns the grant ID at the given index
+func (k *KMSGrants) GetID(index int) string {
+	if index >= 0 && index < len(k.grants) {
+		return k.grants[index].ID
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for KMS grants
+func (k *KMSGrants) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:         'P',
+			Label:       "policy",
+			Description: "Show key policy",
+			TextHandler: func(ctx context.Context, c *client.Client, _ string) (string, error) {
+				return keyPolicy(ctx, c, k.keyID)
+			},
+		},
+	}
 }
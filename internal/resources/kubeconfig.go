@@ -0,0 +1,225 @@
+package resources
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"go.yaml.in/yaml/v3"
+)
+
+// kubeconfigPath returns the kubeconfig file kubectl writes to, the first one of KUBECONFIG
+func kubeconfigPath() (string, error) {
+	if paths := filepath.SplitList(os.Getenv("KUBECONFIG")); len(paths) > 0 && paths[0] != "" {
+		return paths[0], nil
+	}
+	return expandHome("~/.kube/config")
+}
+
+// checkKubeconfigRole refuses a role assumed with MFA, the token command written in the
+// kubeconfig assumes it again and cannot ask for the code
+func checkKubeconfigRole(c *client.Client) error {
+	if role, _ := c.AssumedRole(); role != "" && c.AssumedWithMFA() {
+		return fmt.Errorf("role %s was assumed with MFA, which aws eks get-token cannot ask for, drop it (Ctrl+U) or use a profile assuming it", role)
+	}
+	return nil
+}
+
+// currentKubeContext returns the current context of the kubeconfig, empty without one
+func currentKubeContext() (string, error) {
+	path, err := kubeconfigPath()
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var config struct {
+		CurrentContext string `yaml:"current-context"`
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return config.CurrentContext, nil
+}
+
+// updateKubeconfig adds or updates the cluster, user and context of an EKS cluster in the
+// kubeconfig, like aws eks update-kubeconfig, makes it current when asked and returns the
+// context name
+func updateKubeconfig(ctx context.Context, c *client.Client, clusterName string, current bool) (string, error) {
+	if err := checkKubeconfigRole(c); err != nil {
+		return "", err
+	}
+	output, err := c.EKS().DescribeCluster(ctx, &eks.DescribeClusterInput{Name: &clusterName})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe cluster %s: %w", clusterName, err)
+	}
+	cluster := output.Cluster
+	if cluster.Endpoint == nil || cluster.CertificateAuthority == nil {
+		return "", fmt.Errorf("cluster %s has no endpoint yet", clusterName)
+	}
+	arn := stringValue(cluster.Arn)
+
+	path, err := kubeconfigPath()
+	if err != nil {
+		return "", err
+	}
+
+	var config yaml.Node
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(config.Content) == 0 {
+		config = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+		setKubeconfigValue(config.Content[0], "apiVersion", scalarNode("v1"))
+		setKubeconfigValue(config.Content[0], "kind", scalarNode("Config"))
+	}
+	root := config.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return "", fmt.Errorf("failed to parse %s: not a kubeconfig", path)
+	}
+
+	// The token is got as the assumed role, from the credentials of the profile it was assumed from
+	args := []string{"--region", c.Region(), "eks", "get-token", "--cluster-name", clusterName, "--output", "json"}
+	command := map[string]any{
+		"apiVersion": "client.authentication.k8s.io/v1beta1",
+		"command":    "aws",
+	}
+	if role, _ := c.AssumedRole(); role != "" {
+		args = append(args, "--role-arn", role)
+		if c.Profile() != "" {
+			args = append(args, "--profile", c.Profile())
+		}
+	} else if c.Profile() != "" {
+		command["env"] = []map[string]string{{"name": "AWS_PROFILE", "value": c.Profile()}}
+	}
+	command["args"] = args
+
+	for _, entry := range []struct {
+		section, key string
+		value        any
+	}{
+		{"clusters", "cluster", map[string]any{
+			"server":                     stringValue(cluster.Endpoint),
+			"certificate-authority-data": stringValue(cluster.CertificateAuthority.Data),
+		}},
+		{"users", "user", map[string]any{"exec": command}},
+		{"contexts", "context", map[string]any{"cluster": arn, "user": arn}},
+	} {
+		var value yaml.Node
+		if err := value.Encode(entry.value); err != nil {
+			return "", err
+		}
+		if err := upsertKubeconfigEntry(root, entry.section, arn, entry.key, &value); err != nil {
+			return "", fmt.Errorf("failed to update %s: %w", path, err)
+		}
+	}
+	if current {
+		setKubeconfigValue(root, "current-context", scalarNode(arn))
+	}
+
+	// The document is edited in place, keeping the comments and the order of the entries
+	var b bytes.Buffer
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&config); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, b.Bytes(), 0600); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return arn, nil
+}
+
+// scalarNode returns a YAML string node
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// kubeconfigValue returns the value of a key of a YAML mapping, nil without that key
+func kubeconfigValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setKubeconfigValue replaces the value of a key of a YAML mapping, or appends the key
+func setKubeconfigValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content, scalarNode(key), value)
+}
+
+// upsertKubeconfigEntry replaces the value of the named entry of a kubeconfig list, or appends
+// the entry, the other fields of an existing entry are kept
+func upsertKubeconfigEntry(config *yaml.Node, section, name, key string, value *yaml.Node) error {
+	entries := kubeconfigValue(config, section)
+	if entries == nil || entries.Tag == "!!null" {
+		entries = &yaml.Node{Kind: yaml.SequenceNode}
+		setKubeconfigValue(config, section, entries)
+	}
+	if entries.Kind != yaml.SequenceNode {
+		return fmt.Errorf("%s is not a list", section)
+	}
+
+	for _, entry := range entries.Content {
+		if entry.Kind != yaml.MappingNode {
+			continue
+		}
+		if named := kubeconfigValue(entry, "name"); named != nil && named.Value == name {
+			setKubeconfigValue(entry, key, value)
+			return nil
+		}
+	}
+	if len(entries.Content) == 0 {
+		// An empty list written [] grows as a block list
+		entries.Style = 0
+	}
+	entry := &yaml.Node{Kind: yaml.MappingNode}
+	setKubeconfigValue(entry, "name", scalarNode(name))
+	setKubeconfigValue(entry, key, value)
+	entries.Content = append(entries.Content, entry)
+	return nil
+}
+
+// k9sCommand adds an EKS cluster to the kubeconfig and returns the k9s command browsing it,
+// the current context is left as is
+func k9sCommand(ctx context.Context, c *client.Client, clusterName string) (*exec.Cmd, error) {
+	k9s, err := exec.LookPath("k9s")
+	if err != nil {
+		return nil, errors.New("k9s not found in PATH")
+	}
+	contextName, err := updateKubeconfig(ctx, c, clusterName, false)
+	if err != nil {
+		return nil, err
+	}
+	return exec.Command(k9s, "--context", contextName), nil
+}
@@ -3,14 +3,25 @@ package resources
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"a9s/internal/client"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
 )
 
 // LambdaFunction represents a Lambda function
 type LambdaFunction struct {
+	ARN          string
 	FunctionName string
 	Runtime      string
 	Handler      string
@@ -18,11 +29,32 @@ type LambdaFunction struct {
 	Timeout      string
 	LastModified string
 	Description  string
+	LogGroup     string
+
+	// Metrics over the last 24 hours, with metrics on
+	Invocations float64
+	Errors      float64
+	P95Duration float64 // milliseconds
+	Throttles   float64
 }
 
+// lambdaMetricsWindow is the span of the Lambda metric columns
+const lambdaMetricsWindow = 24 * time.Hour
+
+// Service limits of the memory size in MB and the timeout in seconds of a function
+const (
+	lambdaMinMemory  = 128
+	lambdaMaxMemory  = 10240
+	lambdaMaxTimeout = 900
+)
+
+// lambdaErrorRateThreshold is the error rate above which a function is highlighted
+const lambdaErrorRateThreshold = 0.05
+
 // LambdaFunctions implements Resource for Lambda functions
 type LambdaFunctions struct {
 	functions []LambdaFunction
+	metrics   bool
 }
 
 // NewLambdaFunctions creates a new LambdaFunctions resource
@@ -39,14 +71,28 @@ func (l *LambdaFunctions) Name() string {
 
 // Columns returns the column definitions
 func (l *LambdaFunctions) Columns() []Column {
-	return []Column{
+	columns := []Column{
 		{Name: "Function Name", Width: 40},
 		{Name: "Runtime", Width: 15},
 		{Name: "Handler", Width: 30},
 		{Name: "Memory (MB)", Width: 12},
 		{Name: "Timeout (s)", Width: 12},
-		{Name: "Last Modified", Width: 25},
 	}
+	if l.metrics {
+		columns = append(columns,
+			Column{Name: "Invocations (24h)", Width: 18},
+			Column{Name: "Error Rate", Width: 10},
+			Column{Name: "p95 (ms)", Width: 10},
+			Column{Name: "Throttles", Width: 10},
+		)
+	}
+	return append(columns, Column{Name: "Last Modified", Width: 25})
+}
+
+// ToggleMetrics switches the CloudWatch metric columns on or off
+func (l *LambdaFunctions) ToggleMetrics() bool {
+	l.metrics = !l.metrics
+	return l.metrics
 }
 
 // Fetch retrieves Lambda functions from AWS
@@ -63,6 +109,7 @@ func (l *LambdaFunctions) Fetch(ctx context.Context, c *client.Client) error {
 
 		for _, fn := range output.Functions {
 			l.functions = append(l.functions, LambdaFunction{
+				ARN:          stringValue(fn.FunctionArn),
 				FunctionName: stringValue(fn.FunctionName),
 				Runtime:      string(fn.Runtime),
 				Handler:      stringValue(fn.Handler),
@@ -70,29 +117,108 @@ func (l *LambdaFunctions) Fetch(ctx context.Context, c *client.Client) error {
 				Timeout:      fmt.Sprintf("%d", ptrInt32Value(fn.Timeout)),
 				LastModified: stringValue(fn.LastModified),
 				Description:  stringValue(fn.Description),
+				LogGroup:     lambdaLogGroup(fn),
 			})
 		}
 	}
 
+	if l.metrics {
+		return l.fetchMetrics(ctx, c)
+	}
+
 	return nil
 }
 
+// fetchMetrics sets the invocations, errors, p95 duration and throttles of the last 24 hours
+func (l *LambdaFunctions) fetchMetrics(ctx context.Context, c *client.Client) error {
+	const period = int32(lambdaMetricsWindow / time.Second)
+
+	queries := make([]cwtypes.MetricDataQuery, 0, 4*len(l.functions))
+	for i, fn := range l.functions {
+		dimension := cwtypes.Dimension{Name: aws.String("FunctionName"), Value: aws.String(fn.FunctionName)}
+		queries = append(queries,
+			metricQuery(fmt.Sprintf("i%d", i), "AWS/Lambda", "Invocations", "Sum", period, dimension),
+			metricQuery(fmt.Sprintf("e%d", i), "AWS/Lambda", "Errors", "Sum", period, dimension),
+			metricQuery(fmt.Sprintf("d%d", i), "AWS/Lambda", "Duration", "p95", period, dimension),
+			metricQuery(fmt.Sprintf("t%d", i), "AWS/Lambda", "Throttles", "Sum", period, dimension),
+		)
+	}
+
+	series, err := metricSeries(ctx, c, queries, lambdaMetricsWindow)
+	if err != nil {
+		return err
+	}
+
+	// The window may span two periods, sum them, or keep the worst p95
+	sum := func(values []float64) float64 {
+		total := 0.0
+		for _, value := range values {
+			total += value
+		}
+		return total
+	}
+	for i := range l.functions {
+		fn := &l.functions[i]
+		fn.Invocations = sum(series[fmt.Sprintf("i%d", i)])
+		fn.Errors = sum(series[fmt.Sprintf("e%d", i)])
+		fn.Throttles = sum(series[fmt.Sprintf("t%d", i)])
+		for _, value := range series[fmt.Sprintf("d%d", i)] {
+			fn.P95Duration = max(fn.P95Duration, value)
+		}
+	}
+
+	return nil
+}
+
+// lambdaLogGroup returns the log group of a function, /aws/lambda/<name> unless configured otherwise
+func lambdaLogGroup(fn lambdatypes.FunctionConfiguration) string {
+	if fn.LoggingConfig != nil && fn.LoggingConfig.LogGroup != nil {
+		return *fn.LoggingConfig.LogGroup
+	}
+	return fmt.Sprintf("/aws/lambda/%s", stringValue(fn.FunctionName))
+}
+
+// errorRate returns the ratio of failed invocations
+func (fn LambdaFunction) errorRate() float64 {
+	if fn.Invocations == 0 {
+		return 0
+	}
+	return fn.Errors / fn.Invocations
+}
+
 // Rows returns the table data
 func (l *LambdaFunctions) Rows() [][]string {
 	rows := make([][]string, len(l.functions))
 	for i, fn := range l.functions {
-		rows[i] = []string{
+		row := []string{
 			fn.FunctionName,
 			fn.Runtime,
 			fn.Handler,
 			fn.MemorySize,
 			fn.Timeout,
-			fn.LastModified,
 		}
+		if l.metrics {
+			row = append(row,
+				fmt.Sprintf("%.0f", fn.Invocations),
+				fmt.Sprintf("%.1f%%", fn.errorRate()*100),
+				fmt.Sprintf("%.0f", fn.P95Duration),
+				fmt.Sprintf("%.0f", fn.Throttles),
+			)
+		}
+		rows[i] = append(row, fn.LastModified)
 	}
 	return rows
 }
 
+// Highlight flags the functions with a high error rate or throttled invocations, with metrics on
+func (l *LambdaFunctions) Highlight(index int) bool {
+	if !l.metrics || index < 0 || index >= len(l.functions) {
+		return false
+	}
+	fn := l.functions[index]
+	return fn.errorRate() > lambdaErrorRateThreshold || fn.Throttles > 0
+}
+
 // GetID returns the function name at the given index
 func (l *LambdaFunctions) GetID(index int) string {
 	if index >= 0 && index < len(l.functions) {
@@ -103,5 +229,447 @@ func (l *LambdaFunctions) GetID(index int) string {
 
 // QuickActions returns the available quick actions for Lambda functions
 func (l *LambdaFunctions) QuickActions() []QuickAction {
-	return []QuickAction{}
+	return []QuickAction{
+		{
+			Key:            'l',
+			Label:          "logs",
+			Description:    "Tail the latest invocations",
+			NeedsSelection: true,
+			View: func(functionName string) Resource {
+				for _, fn := range l.functions {
+					if fn.FunctionName == functionName {
+						return NewLatestLogEvents(fn.LogGroup)
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Key:            'E',
+			Label:          "errors",
+			Description:    "Tail the function errors",
+			NeedsSelection: true,
+			View: func(functionName string) Resource {
+				for _, fn := range l.functions {
+					if fn.FunctionName == functionName {
+						return NewLogEvents(fn.LogGroup, true)
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Key:            'v',
+			Label:          "env",
+			Description:    "Edit environment variables",
+			NeedsSelection: true,
+			View: func(functionName string) Resource {
+				return NewLambdaEnvironment(functionName)
+			},
+		},
+		{
+			Key:            'M',
+			Label:          "tune",
+			Description:    "Set memory size and timeout",
+			NeedsSelection: true,
+			InputLabel:     "Memory (MB):timeout (s): ",
+			InputDefault: func(functionName string) string {
+				fn := l.function(functionName)
+				return fn.MemorySize + ":" + fn.Timeout
+			},
+			InputPlan: func(ctx context.Context, c *client.Client, functionName, input string) ([]Step, error) {
+				return planTuneFunction(l.function(functionName), input)
+			},
+		},
+		{
+			Key:            'C',
+			Label:          "concurrency",
+			Description:    "View and set reserved and provisioned concurrency",
+			NeedsSelection: true,
+			View: func(functionName string) Resource {
+				return NewLambdaConcurrency(functionName)
+			},
+		},
+		{
+			Key:            'D',
+			Label:          "download",
+			Description:    "Download the deployment package",
+			NeedsSelection: true,
+			ReadOnly:       true,
+			InputLabel:     "File: ",
+			InputDefault: func(functionName string) string {
+				return functionName + ".zip"
+			},
+			InputHandler: downloadFunctionCode,
+		},
+		{
+			Key:            'P',
+			Label:          "provenance",
+			Description:    "Show container image provenance",
+			NeedsSelection: true,
+			TextHandler: func(ctx context.Context, c *client.Client, functionName string) (string, error) {
+				output, err := c.Lambda().GetFunction(ctx, &lambda.GetFunctionInput{
+					FunctionName: &functionName,
+				})
+				if err != nil {
+					return "", fmt.Errorf("failed to get function %s: %w", functionName, err)
+				}
+				if output.Code == nil || output.Code.ImageUri == nil {
+					return "", fmt.Errorf("%s is not a container image function", functionName)
+				}
+
+				provenance, err := imageProvenance(ctx, c, *output.Code.ImageUri)
+				if err != nil {
+					return "", err
+				}
+				// The tag may have moved since the function was deployed
+				return fmt.Sprintf("%s  Deployed:   %s\n", provenance, stringValue(output.Code.ResolvedImageUri)), nil
+			},
+		},
+		tagsAction(l),
+	}
+}
+
+// arn returns the ARN of a function, which the tagging API expects
+func (l *LambdaFunctions) arn(functionName string) (string, error) {
+	for _, fn := range l.functions {
+		if fn.FunctionName == functionName {
+			return fn.ARN, nil
+		}
+	}
+	return "", fmt.Errorf("function %s not found", functionName)
+}
+
+// Tags returns the tags of a function
+func (l *LambdaFunctions) Tags(ctx context.Context, c *client.Client, functionName string) (map[string]string, error) {
+	arn, err := l.arn(functionName)
+	if err != nil {
+		return nil, err
+	}
+	output, err := c.Lambda().ListTags(ctx, &lambda.ListTagsInput{Resource: &arn})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags of %s: %w", functionName, err)
+	}
+	return output.Tags, nil
+}
+
+// SetTags adds or updates tags of a function
+func (l *LambdaFunctions) SetTags(ctx context.Context, c *client.Client, functionName string, tags map[string]string) error {
+	arn, err := l.arn(functionName)
+	if err != nil {
+		return err
+	}
+	_, err = c.Lambda().TagResource(ctx, &lambda.TagResourceInput{Resource: &arn, Tags: tags})
+	if err != nil {
+		return fmt.Errorf("failed to tag %s: %w", functionName, err)
+	}
+	return nil
+}
+
+// RemoveTags removes tags from a function
+func (l *LambdaFunctions) RemoveTags(ctx context.Context, c *client.Client, functionName string, keys []string) error {
+	arn, err := l.arn(functionName)
+	if err != nil {
+		return err
+	}
+	_, err = c.Lambda().UntagResource(ctx, &lambda.UntagResourceInput{Resource: &arn, TagKeys: keys})
+	if err != nil {
+		return fmt.Errorf("failed to untag %s: %w", functionName, err)
+	}
+	return nil
+}
+
+// function returns the listed function with the given name, an empty one when not listed
+func (l *LambdaFunctions) function(functionName string) LambdaFunction {
+	for _, fn := range l.functions {
+		if fn.FunctionName == functionName {
+			return fn
+		}
+	}
+	return LambdaFunction{FunctionName: functionName}
+}
+
+// planTuneFunction plans setting the memory size and the timeout of a function from
+// "memory:timeout", an empty part keeps the current value
+func planTuneFunction(fn LambdaFunction, input string) ([]Step, error) {
+	memory, timeout, _ := strings.Cut(input, ":")
+	memory, timeout = strings.TrimSpace(memory), strings.TrimSpace(timeout)
+
+	update := &lambda.UpdateFunctionConfigurationInput{FunctionName: aws.String(fn.FunctionName)}
+	changes := make([]string, 0, 2)
+	if memory != "" {
+		size, err := strconv.Atoi(memory)
+		if err != nil || size < lambdaMinMemory || size > lambdaMaxMemory {
+			return nil, fmt.Errorf("memory must be between %d and %d MB, got %q", lambdaMinMemory, lambdaMaxMemory, memory)
+		}
+		update.MemorySize = aws.Int32(int32(size))
+		changes = append(changes, fmt.Sprintf("memory %s → %d MB", fn.MemorySize, size))
+	}
+	if timeout != "" {
+		seconds, err := strconv.Atoi(timeout)
+		if err != nil || seconds < 1 || seconds > lambdaMaxTimeout {
+			return nil, fmt.Errorf("timeout must be between 1 and %d seconds, got %q", lambdaMaxTimeout, timeout)
+		}
+		update.Timeout = aws.Int32(int32(seconds))
+		changes = append(changes, fmt.Sprintf("timeout %s → %d s", fn.Timeout, seconds))
+	}
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("expected memory:timeout, got %q", input)
+	}
+
+	return []Step{{
+		Description: fmt.Sprintf("Set the %s of %s", strings.Join(changes, " and "), fn.FunctionName),
+		Run: func(ctx context.Context, c *client.Client) error {
+			if _, err := c.Lambda().UpdateFunctionConfiguration(ctx, update); err != nil {
+				return fmt.Errorf("failed to update configuration of %s: %w", fn.FunctionName, err)
+			}
+			return nil
+		},
+	}}, nil
+}
+
+// downloadFunctionCode saves the deployment package of a function from the presigned URL
+// returned by GetFunction, which is valid for ten minutes
+func downloadFunctionCode(ctx context.Context, c *client.Client, functionName, path string) error {
+	output, err := c.Lambda().GetFunction(ctx, &lambda.GetFunctionInput{
+		FunctionName: &functionName,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get function %s: %w", functionName, err)
+	}
+	if output.Code == nil || output.Code.Location == nil {
+		if output.Code != nil && output.Code.ImageUri != nil {
+			return fmt.Errorf("%s is a container image function, pull %s instead", functionName, *output.Code.ImageUri)
+		}
+		return fmt.Errorf("%s has no code location", functionName)
+	}
+
+	path, err = expandHome(strings.TrimSpace(path))
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, *output.Code.Location, nil)
+	if err != nil {
+		return err
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to download code of %s: %w", functionName, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download code of %s: %s", functionName, response.Status)
+	}
+
+	// An existing file is never overwritten
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, response.Body); err != nil {
+		file.Close()
+		os.Remove(path)
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return file.Close()
+}
+
+// DrillDown opens the versions and aliases of the function
+func (l *LambdaFunctions) DrillDown(functionName string) Resource {
+	return NewLambdaVersions(functionName)
+}
+
+// LambdaVersion represents a published version or an alias of a Lambda function
+type LambdaVersion struct {
+	Kind         string // "alias" or "version"
+	Name         string
+	Version      string
+	Routing      string
+	Description  string
+	LastModified string
+}
+
+// LambdaVersions implements Resource for the versions and aliases of a Lambda function
+type LambdaVersions struct {
+	functionName string
+	versions     []LambdaVersion
+}
+
+// NewLambdaVersions creates a new LambdaVersions resource
+func NewLambdaVersions(functionName string) *LambdaVersions {
+	return &LambdaVersions{
+		functionName: functionName,
+		versions:     make([]LambdaVersion, 0),
+	}
+}
+
+// Name returns the display name
+func (l *LambdaVersions) Name() string {
+	return fmt.Sprintf("Lambda Versions & Aliases (%s)", l.functionName)
+}
+
+// Columns returns the column definitions
+func (l *LambdaVersions) Columns() []Column {
+	return []Column{
+		{Name: "Type", Width: 8},
+		{Name: "Name", Width: 30},
+		{Name: "Version", Width: 10},
+		{Name: "Traffic", Width: 30},
+		{Name: "Description", Width: 40},
+		{Name: "Last Modified", Width: 25},
+	}
+}
+
+// Fetch retrieves the aliases and versions of the function from AWS
+func (l *LambdaVersions) Fetch(ctx context.Context, c *client.Client) error {
+	l.versions = make([]LambdaVersion, 0)
+
+	aliases := lambda.NewListAliasesPaginator(c.Lambda(), &lambda.ListAliasesInput{
+		FunctionName: &l.functionName,
+	})
+
+	for aliases.HasMorePages() {
+		output, err := aliases.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list aliases of %s: %w", l.functionName, err)
+		}
+
+		for _, alias := range output.Aliases {
+			l.versions = append(l.versions, LambdaVersion{
+				Kind:        "alias",
+				Name:        stringValue(alias.Name),
+				Version:     stringValue(alias.FunctionVersion),
+				Routing:     formatAliasRouting(stringValue(alias.FunctionVersion), alias.RoutingConfig),
+				Description: stringValue(alias.Description),
+			})
+		}
+	}
+
+	versions := lambda.NewListVersionsByFunctionPaginator(c.Lambda(), &lambda.ListVersionsByFunctionInput{
+		FunctionName: &l.functionName,
+	})
+
+	for versions.HasMorePages() {
+		output, err := versions.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list versions of %s: %w", l.functionName, err)
+		}
+
+		for _, fn := range output.Versions {
+			l.versions = append(l.versions, LambdaVersion{
+				Kind:         "version",
+				Name:         stringValue(fn.Version),
+				Version:      stringValue(fn.Version),
+				Description:  stringValue(fn.Description),
+				LastModified: stringValue(fn.LastModified),
+			})
+		}
+	}
+
+	return nil
+}
+
+// formatAliasRouting renders the traffic weights of an alias, e.g. "3: 90% / 4: 10%"
+func formatAliasRouting(primary string, routing *lambdatypes.AliasRoutingConfiguration) string {
+	if routing == nil || len(routing.AdditionalVersionWeights) == 0 {
+		return primary + ": 100%"
+	}
+
+	remaining := 1.0
+	var parts []string
+	for version, weight := range routing.AdditionalVersionWeights {
+		remaining -= weight
+		parts = append(parts, fmt.Sprintf("%s: %.0f%%", version, weight*100))
+	}
+	sort.Strings(parts)
+
+	return strings.Join(append([]string{fmt.Sprintf("%s: %.0f%%", primary, remaining*100)}, parts...), " / ")
+}
+
+// Rows returns the table data
+func (l *LambdaVersions) Rows() [][]string {
+	rows := make([][]string, len(l.versions))
+	for i, v := range l.versions {
+		rows[i] = []string{
+			v.Kind,
+			v.Name,
+			v.Version,
+			v.Routing,
+			v.Description,
+			v.LastModified,
+		}
+	}
+	return rows
+}
+
+// GetID returns the alias name or version at the given index
+func (l *LambdaVersions) GetID(index int) string {
+	if index >= 0 && index < len(l.versions) {
+		return l.versions[index].Name
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for Lambda versions and aliases
+func (l *LambdaVersions) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:             'P',
+			Label:           "repoint",
+			Description:     "Repoint alias to version",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "[yellow]repoint[-] alias [white]%s[-]?",
+			InputLabel:      "Version (optionally version,other=weight): ",
+			InputHandler:    l.RepointAlias,
+		},
+	}
+}
+
+// RepointAlias points an alias to a version, the input is either a version or
+// "version,other=weight" to shift a share of the traffic to another version
+func (l *LambdaVersions) RepointAlias(ctx context.Context, c *client.Client, aliasName, input string) error {
+	isAlias := false
+	for _, v := range l.versions {
+		if v.Kind == "alias" && v.Name == aliasName {
+			isAlias = true
+			break
+		}
+	}
+	if !isAlias {
+		return fmt.Errorf("%s is not an alias", aliasName)
+	}
+
+	parts := strings.Split(strings.TrimSpace(input), ",")
+	version := strings.TrimSpace(parts[0])
+
+	// An empty map removes any previous traffic shifting
+	routing := &lambdatypes.AliasRoutingConfiguration{
+		AdditionalVersionWeights: map[string]float64{},
+	}
+	for _, part := range parts[1:] {
+		other, weight, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return fmt.Errorf("invalid traffic weight %q, expected version=weight", part)
+		}
+		w, err := strconv.ParseFloat(weight, 64)
+		if err != nil || w <= 0 || w >= 1 {
+			return fmt.Errorf("invalid traffic weight %q, expected a value between 0 and 1", weight)
+		}
+		routing.AdditionalVersionWeights[other] = w
+	}
+
+	_, err := c.Lambda().UpdateAlias(ctx, &lambda.UpdateAliasInput{
+		FunctionName:    &l.functionName,
+		Name:            &aliasName,
+		FunctionVersion: &version,
+		RoutingConfig:   routing,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to repoint alias %s: %w", aliasName, err)
+	}
+	return nil
 }
@@ -0,0 +1,367 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+)
+
+// lambdaMinUnreserved is the concurrency Lambda keeps unreserved for the functions of the account
+const lambdaMinUnreserved = 100
+
+// LambdaConcurrencySetting represents the reserved concurrency of a function, or the
+// provisioned concurrency of one of its aliases or versions
+type LambdaConcurrencySetting struct {
+	ID        string // function name, or function:qualifier for provisioned concurrency
+	Qualifier string // empty for the reserved concurrency of the function
+	Requested *int32
+	Allocated *int32
+	Available *int32
+	Status    string
+	Reason    string
+}
+
+// LambdaConcurrency implements Resource for the reserved concurrency of a function and the
+// provisioned concurrency of its aliases
+type LambdaConcurrency struct {
+	functionName string
+	unreserved   int32
+	settings     []LambdaConcurrencySetting
+}
+
+// NewLambdaConcurrency creates a new LambdaConcurrency resource
+func NewLambdaConcurrency(functionName string) *LambdaConcurrency {
+	return &LambdaConcurrency{
+		functionName: functionName,
+		settings:     make([]LambdaConcurrencySetting, 0),
+	}
+}
+
+// Name returns the display name
+func (l *LambdaConcurrency) Name() string {
+	if l.unreserved == 0 {
+		return fmt.Sprintf("Concurrency (%s)", l.functionName)
+	}
+	return fmt.Sprintf("Concurrency (%s, %d unreserved in the account)", l.functionName, l.unreserved)
+}
+
+// Columns returns the column definitions
+func (l *LambdaConcurrency) Columns() []Column {
+	return []Column{
+		{Name: "Concurrency", Width: 12},
+		{Name: "Qualifier", Width: 25},
+		{Name: "Requested", Width: 10},
+		{Name: "Allocated", Width: 10},
+		{Name: "Available", Width: 10},
+		{Name: "Status", Width: 12},
+		{Name: "Reason", Width: 60},
+	}
+}
+
+// Fetch retrieves the reserved concurrency of the function, its aliases and the provisioned
+// concurrency configured on them or on versions
+func (l *LambdaConcurrency) Fetch(ctx context.Context, c *client.Client) error {
+	l.settings = make([]LambdaConcurrencySetting, 0)
+
+	account, err := c.Lambda().GetAccountSettings(ctx, &lambda.GetAccountSettingsInput{})
+	if err != nil {
+		return fmt.Errorf("failed to get account settings: %w", err)
+	}
+	if account.AccountLimit != nil {
+		l.unreserved = ptrInt32Value(account.AccountLimit.UnreservedConcurrentExecutions)
+	}
+
+	reserved, err := c.Lambda().GetFunctionConcurrency(ctx, &lambda.GetFunctionConcurrencyInput{FunctionName: &l.functionName})
+	if err != nil {
+		return fmt.Errorf("failed to get concurrency of %s: %w", l.functionName, err)
+	}
+	l.settings = append(l.settings, LambdaConcurrencySetting{
+		ID:        l.functionName,
+		Requested: reserved.ReservedConcurrentExecutions,
+	})
+
+	configs := make(map[string]lambdatypes.ProvisionedConcurrencyConfigListItem)
+	provisioned := lambda.NewListProvisionedConcurrencyConfigsPaginator(c.Lambda(), &lambda.ListProvisionedConcurrencyConfigsInput{
+		FunctionName: &l.functionName,
+	})
+	for provisioned.HasMorePages() {
+		output, err := provisioned.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list provisioned concurrency of %s: %w", l.functionName, err)
+		}
+		for _, config := range output.ProvisionedConcurrencyConfigs {
+			configs[lambdaQualifier(stringValue(config.FunctionArn))] = config
+		}
+	}
+
+	// Every alias is listed so that provisioned concurrency can be added to it
+	qualifiers := make([]string, 0)
+	aliases := lambda.NewListAliasesPaginator(c.Lambda(), &lambda.ListAliasesInput{FunctionName: &l.functionName})
+	for aliases.HasMorePages() {
+		output, err := aliases.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list aliases of %s: %w", l.functionName, err)
+		}
+		for _, alias := range output.Aliases {
+			qualifiers = append(qualifiers, stringValue(alias.Name))
+		}
+	}
+	for qualifier := range configs {
+		if !slices.Contains(qualifiers, qualifier) {
+			qualifiers = append(qualifiers, qualifier)
+		}
+	}
+	sort.Strings(qualifiers)
+
+	for _, qualifier := range qualifiers {
+		setting := LambdaConcurrencySetting{ID: l.functionName + ":" + qualifier, Qualifier: qualifier}
+		if config, ok := configs[qualifier]; ok {
+			setting.Requested = config.RequestedProvisionedConcurrentExecutions
+			setting.Allocated = config.AllocatedProvisionedConcurrentExecutions
+			setting.Available = config.AvailableProvisionedConcurrentExecutions
+			setting.Status = string(config.Status)
+			setting.Reason = stringValue(config.StatusReason)
+		}
+		l.settings = append(l.settings, setting)
+	}
+
+	return nil
+}
+
+// lambdaQualifier returns the alias or version a qualified function ARN ends with
+func lambdaQualifier(functionARN string) string {
+	return functionARN[strings.LastIndex(functionARN, ":")+1:]
+}
+
+// Rows returns the table data
+func (l *LambdaConcurrency) Rows() [][]string {
+	rows := make([][]string, len(l.settings))
+	for i, setting := range l.settings {
+		kind, qualifier := "provisioned", setting.Qualifier
+		if setting.Qualifier == "" {
+			kind, qualifier = "reserved", "(function)"
+		}
+		requested := "-"
+		if setting.Requested != nil {
+			requested = strconv.Itoa(int(*setting.Requested))
+		} else if setting.Qualifier == "" {
+			requested = "unreserved"
+		}
+		allocated, available := "", ""
+		if setting.Allocated != nil {
+			allocated = strconv.Itoa(int(*setting.Allocated))
+		}
+		if setting.Available != nil {
+			available = strconv.Itoa(int(*setting.Available))
+		}
+		rows[i] = []string{
+			kind,
+			qualifier,
+			requested,
+			allocated,
+			available,
+			setting.Status,
+			setting.Reason,
+		}
+	}
+	return rows
+}
+
+// GetID returns the function name, or function:qualifier, at the given index
+func (l *LambdaConcurrency) GetID(index int) string {
+	if index >= 0 && index < len(l.settings) {
+		return l.settings[index].ID
+	}
+	return ""
+}
+
+// Highlight flags a function throttled by a reserved concurrency of 0, and failed allocations
+func (l *LambdaConcurrency) Highlight(index int) bool {
+	if index < 0 || index >= len(l.settings) {
+		return false
+	}
+	setting := l.settings[index]
+	if setting.Qualifier == "" {
+		return setting.Requested != nil && *setting.Requested == 0
+	}
+	return setting.Status == string(lambdatypes.ProvisionedConcurrencyStatusEnumFailed)
+}
+
+// QuickActions returns the available quick actions for the concurrency settings
+func (l *LambdaConcurrency) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:            'e',
+			Label:          "set",
+			Description:    "Set the concurrency, none removes it",
+			NeedsSelection: true,
+			InputLabel:     "Concurrency (or none): ",
+			InputDefault: func(id string) string {
+				setting, ok := l.setting(id)
+				if !ok || setting.Requested == nil {
+					return ""
+				}
+				return strconv.Itoa(int(*setting.Requested))
+			},
+			InputPlan: l.planConcurrency,
+		},
+	}
+}
+
+// setting returns the listed setting with the given ID
+func (l *LambdaConcurrency) setting(id string) (LambdaConcurrencySetting, bool) {
+	for _, setting := range l.settings {
+		if setting.ID == id {
+			return setting, true
+		}
+	}
+	return LambdaConcurrencySetting{}, false
+}
+
+// provisionedTotal returns the provisioned concurrency requested on the function, except on a qualifier
+func (l *LambdaConcurrency) provisionedTotal(except string) int32 {
+	var total int32
+	for _, setting := range l.settings {
+		if setting.Qualifier != "" && setting.Qualifier != except {
+			total += ptrInt32Value(setting.Requested)
+		}
+	}
+	return total
+}
+
+// planConcurrency parses a new concurrency, or none, and returns the change of the reserved
+// or provisioned concurrency as steps
+func (l *LambdaConcurrency) planConcurrency(ctx context.Context, c *client.Client, id, input string) ([]Step, error) {
+	setting, ok := l.setting(id)
+	if !ok {
+		return nil, fmt.Errorf("%s is not listed", id)
+	}
+
+	remove := strings.EqualFold(input, "none")
+	var value int32
+	if !remove {
+		parsed, err := strconv.Atoi(input)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("expected a concurrency or none, got %q", input)
+		}
+		value = int32(parsed)
+	}
+
+	if setting.Qualifier == "" {
+		return l.planReserved(setting, value, remove)
+	}
+	return l.planProvisioned(setting, value, remove)
+}
+
+// planReserved returns the steps setting or removing the reserved concurrency of the function
+func (l *LambdaConcurrency) planReserved(setting LambdaConcurrencySetting, value int32, remove bool) ([]Step, error) {
+	if remove {
+		if setting.Requested == nil {
+			return nil, fmt.Errorf("%s has no reserved concurrency", l.functionName)
+		}
+		return []Step{{
+			Description: fmt.Sprintf("Remove the reserved concurrency of %d from %s, it uses the unreserved pool", *setting.Requested, l.functionName),
+			Run: func(ctx context.Context, c *client.Client) error {
+				_, err := c.Lambda().DeleteFunctionConcurrency(ctx, &lambda.DeleteFunctionConcurrencyInput{FunctionName: &l.functionName})
+				if err != nil {
+					return fmt.Errorf("failed to remove reserved concurrency of %s: %w", l.functionName, err)
+				}
+				return nil
+			},
+		}}, nil
+	}
+
+	// The account keeps a minimum unreserved, the current reservation goes back to it first
+	available := l.unreserved + ptrInt32Value(setting.Requested) - lambdaMinUnreserved
+	if value > available {
+		return nil, fmt.Errorf("at most %d can be reserved, the account keeps %d unreserved", available, lambdaMinUnreserved)
+	}
+	if provisioned := l.provisionedTotal(""); value < provisioned {
+		return nil, fmt.Errorf("the aliases of %s have %d provisioned, the reserved concurrency cannot be lower", l.functionName, provisioned)
+	}
+
+	current := "unreserved"
+	if setting.Requested != nil {
+		current = strconv.Itoa(int(*setting.Requested))
+	}
+	steps := make([]Step, 0, 2)
+	if value == 0 {
+		steps = append(steps, Step{Description: fmt.Sprintf("Warning: a reserved concurrency of 0 throttles every invocation of %s", l.functionName)})
+	}
+	return append(steps, Step{
+		Description: fmt.Sprintf("Set the reserved concurrency of %s: %s → %d", l.functionName, current, value),
+		Run: func(ctx context.Context, c *client.Client) error {
+			_, err := c.Lambda().PutFunctionConcurrency(ctx, &lambda.PutFunctionConcurrencyInput{
+				FunctionName:                 &l.functionName,
+				ReservedConcurrentExecutions: aws.Int32(value),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to set reserved concurrency of %s: %w", l.functionName, err)
+			}
+			return nil
+		},
+	}), nil
+}
+
+// planProvisioned returns the steps setting or removing the provisioned concurrency of an alias or version
+func (l *LambdaConcurrency) planProvisioned(setting LambdaConcurrencySetting, value int32, remove bool) ([]Step, error) {
+	qualifier := setting.Qualifier
+	if remove || value == 0 {
+		if setting.Requested == nil {
+			return nil, fmt.Errorf("%s has no provisioned concurrency", setting.ID)
+		}
+		return []Step{{
+			Description: fmt.Sprintf("Remove the provisioned concurrency of %d from %s", *setting.Requested, setting.ID),
+			Run: func(ctx context.Context, c *client.Client) error {
+				_, err := c.Lambda().DeleteProvisionedConcurrencyConfig(ctx, &lambda.DeleteProvisionedConcurrencyConfigInput{
+					FunctionName: &l.functionName,
+					Qualifier:    aws.String(qualifier),
+				})
+				if err != nil {
+					return fmt.Errorf("failed to remove provisioned concurrency of %s: %w", setting.ID, err)
+				}
+				return nil
+			},
+		}}, nil
+	}
+
+	// With a reservation, the provisioned concurrency of all the qualifiers fits in it
+	if reserved := l.settings[0].Requested; reserved != nil {
+		if total := l.provisionedTotal(qualifier) + value; total > *reserved {
+			return nil, fmt.Errorf("%d would be provisioned on %s, above its reserved concurrency of %d", total, l.functionName, *reserved)
+		}
+	} else if value > l.unreserved-lambdaMinUnreserved {
+		return nil, fmt.Errorf("at most %d can be provisioned, the account keeps %d unreserved", l.unreserved-lambdaMinUnreserved, lambdaMinUnreserved)
+	}
+
+	current := "none"
+	if setting.Requested != nil {
+		current = strconv.Itoa(int(*setting.Requested))
+	}
+	return []Step{
+		{Description: "The allocation takes a few minutes, the status turns READY once done, provisioned concurrency is billed while configured"},
+		{
+			Description: fmt.Sprintf("Set the provisioned concurrency of %s: %s → %d", setting.ID, current, value),
+			Run: func(ctx context.Context, c *client.Client) error {
+				_, err := c.Lambda().PutProvisionedConcurrencyConfig(ctx, &lambda.PutProvisionedConcurrencyConfigInput{
+					FunctionName:                    &l.functionName,
+					Qualifier:                       aws.String(qualifier),
+					ProvisionedConcurrentExecutions: aws.Int32(value),
+				})
+				if err != nil {
+					return fmt.Errorf("failed to set provisioned concurrency of %s: %w", setting.ID, err)
+				}
+				return nil
+			},
+		},
+	}, nil
+}
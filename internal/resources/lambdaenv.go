@@ -0,0 +1,236 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+)
+
+// maskedValue replaces the sensitive values until they are revealed
+const maskedValue = "••••••••"
+
+// LambdaEnvVariable represents an environment variable of a Lambda function
+type LambdaEnvVariable struct {
+	Key   string
+	Value string
+}
+
+// LambdaEnvironment implements Resource for the environment variables of a function,
+// which are masked until revealed and changed after a confirmation of the diff
+type LambdaEnvironment struct {
+	functionName string
+	revealed     bool
+	variables    []LambdaEnvVariable
+}
+
+// NewLambdaEnvironment creates a new LambdaEnvironment resource
+func NewLambdaEnvironment(functionName string) *LambdaEnvironment {
+	return &LambdaEnvironment{
+		functionName: functionName,
+		variables:    make([]LambdaEnvVariable, 0),
+	}
+}
+
+// Name returns the display name
+func (l *LambdaEnvironment) Name() string {
+	return fmt.Sprintf("Environment (%s)", l.functionName)
+}
+
+// Columns returns the column definitions
+func (l *LambdaEnvironment) Columns() []Column {
+	return []Column{
+		{Name: "Key", Width: 40},
+		{Name: "Value", Width: 80},
+	}
+}
+
+// Fetch retrieves the environment variables of the function
+func (l *LambdaEnvironment) Fetch(ctx context.Context, c *client.Client) error {
+	l.variables = make([]LambdaEnvVariable, 0)
+
+	variables, err := l.current(ctx, c)
+	if err != nil {
+		return err
+	}
+	for key, value := range variables {
+		l.variables = append(l.variables, LambdaEnvVariable{Key: key, Value: value})
+	}
+	sort.Slice(l.variables, func(i, j int) bool {
+		return l.variables[i].Key < l.variables[j].Key
+	})
+
+	return nil
+}
+
+// current gets the environment variables as deployed, the diff is computed against them
+func (l *LambdaEnvironment) current(ctx context.Context, c *client.Client) (map[string]string, error) {
+	output, err := c.Lambda().GetFunctionConfiguration(ctx, &lambda.GetFunctionConfigurationInput{
+		FunctionName: &l.functionName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get configuration of %s: %w", l.functionName, err)
+	}
+
+	variables := make(map[string]string)
+	if output.Environment != nil {
+		for key, value := range output.Environment.Variables {
+			variables[key] = value
+		}
+	}
+	return variables, nil
+}
+
+// Rows returns the table data
+func (l *LambdaEnvironment) Rows() [][]string {
+	rows := make([][]string, len(l.variables))
+	for i, variable := range l.variables {
+		rows[i] = []string{variable.Key, l.display(variable.Value)}
+	}
+	return rows
+}
+
+// display masks a value unless the values are revealed
+func (l *LambdaEnvironment) display(value string) string {
+	if l.revealed || value == "" {
+		return value
+	}
+	return maskedValue
+}
+
+// GetID returns the variable key at the given index
+func (l *LambdaEnvironment) GetID(index int) string {
+	if index >= 0 && index < len(l.variables) {
+		return l.variables[index].Key
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for environment variables
+func (l *LambdaEnvironment) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:         'v',
+			Label:       "reveal",
+			Description: "Toggle the masking of the values",
+			Toggle: func() {
+				l.revealed = !l.revealed
+			},
+		},
+		{
+			Key:         'c',
+			Label:       "add",
+			Description: "Add or update a variable",
+			InputLabel:  "KEY=value: ",
+			InputPlan: func(ctx context.Context, c *client.Client, _, input string) ([]Step, error) {
+				key, value, ok := strings.Cut(input, "=")
+				key = strings.TrimSpace(key)
+				if !ok || key == "" {
+					return nil, fmt.Errorf("expected KEY=value, got %q", input)
+				}
+				return l.planUpdate(ctx, c, func(variables map[string]string) {
+					variables[key] = value
+				})
+			},
+		},
+		{
+			Key:            'e',
+			Label:          "edit",
+			Description:    "Edit the variable value",
+			NeedsSelection: true,
+			InputLabel:     "Value: ",
+			InputDefault:   l.value,
+			InputPlan: func(ctx context.Context, c *client.Client, key, value string) ([]Step, error) {
+				return l.planUpdate(ctx, c, func(variables map[string]string) {
+					variables[key] = value
+				})
+			},
+		},
+		{
+			Key:            'd',
+			Label:          "remove",
+			Description:    "Remove the variable",
+			NeedsSelection: true,
+			Plan: func(ctx context.Context, c *client.Client, key string) ([]Step, error) {
+				return l.planUpdate(ctx, c, func(variables map[string]string) {
+					delete(variables, key)
+				})
+			},
+		},
+	}
+}
+
+// value returns the current value of a variable, pre-filled only once revealed
+func (l *LambdaEnvironment) value(key string) string {
+	if !l.revealed {
+		return ""
+	}
+	for _, variable := range l.variables {
+		if variable.Key == key {
+			return variable.Value
+		}
+	}
+	return ""
+}
+
+// planUpdate applies a change to the deployed variables and returns the diff as notes,
+// followed by the step updating the function with the whole new environment
+func (l *LambdaEnvironment) planUpdate(ctx context.Context, c *client.Client, change func(map[string]string)) ([]Step, error) {
+	before, err := l.current(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	after := make(map[string]string, len(before))
+	for key, value := range before {
+		after[key] = value
+	}
+	change(after)
+
+	keys := make([]string, 0, len(before)+1)
+	for key := range before {
+		keys = append(keys, key)
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	steps := make([]Step, 0)
+	for _, key := range keys {
+		old, existed := before[key]
+		value, exists := after[key]
+		switch {
+		case !existed:
+			steps = append(steps, Step{Description: fmt.Sprintf("+ %s=%s", key, l.display(value))})
+		case !exists:
+			steps = append(steps, Step{Description: fmt.Sprintf("- %s", key)})
+		case old != value:
+			steps = append(steps, Step{Description: fmt.Sprintf("~ %s: %s → %s", key, l.display(old), l.display(value))})
+		}
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("no change to the environment of %s", l.functionName)
+	}
+
+	steps = append(steps, Step{
+		Description: fmt.Sprintf("Update the environment of %s", l.functionName),
+		Run: func(ctx context.Context, c *client.Client) error {
+			_, err := c.Lambda().UpdateFunctionConfiguration(ctx, &lambda.UpdateFunctionConfigurationInput{
+				FunctionName: &l.functionName,
+				Environment:  &lambdatypes.Environment{Variables: after},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to update environment of %s: %w", l.functionName, err)
+			}
+			return nil
+		},
+	})
+	return steps, nil
+}
@@ -0,0 +1,214 @@
+package resources
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	logstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+// logTailWindow is how far back the log tail looks for events
+const logTailWindow = 30 * time.Minute
+
+// maxLogEvents is the number of most recent events kept by the log tail
+const maxLogEvents = 1000
+
+// latestLogStreams is the number of most recently written streams the tail is limited to, when asked
+const latestLogStreams = 5
+
+// errorFilterPattern is the filter pattern applied when only errors are shown
+const errorFilterPattern = "?ERROR ?Error ?Exception"
+
+// LogEvent represents an event of a CloudWatch Logs group
+type LogEvent struct {
+	ID        string
+	Timestamp time.Time
+	Stream    string
+	Message   string
+}
+
+// LogEvents implements Resource for the recent events of a log group, newest first
+type LogEvents struct {
+	group         string
+	errorsOnly    bool
+	latestStreams bool
+	events        []LogEvent
+}
+
+// NewLogEvents creates a new LogEvents resource, errorsOnly pre-applies the error filter
+func NewLogEvents(group string, errorsOnly bool) *LogEvents {
+	return &LogEvents{
+		group:      group,
+		errorsOnly: errorsOnly,
+		events:     make([]LogEvent, 0),
+	}
+}
+
+// NewLatestLogEvents creates a new LogEvents resource limited to the most recently
+// written streams, e.g. the last invocations of a Lambda function
+func NewLatestLogEvents(group string) *LogEvents {
+	logs := NewLogEvents(group, false)
+	logs.latestStreams = true
+	return logs
+}
+
+// Name returns the display name
+func (l *LogEvents) Name() string {
+	filters := make([]string, 0, 2)
+	if l.latestStreams {
+		filters = append(filters, fmt.Sprintf("latest %d streams", latestLogStreams))
+	}
+	if l.errorsOnly {
+		filters = append(filters, "errors")
+	}
+	if len(filters) > 0 {
+		return fmt.Sprintf("Logs: %s (%s)", l.group, strings.Join(filters, ", "))
+	}
+	return fmt.Sprintf("Logs: %s", l.group)
+}
+
+// Columns returns the column definitions
+func (l *LogEvents) Columns() []Column {
+	return []Column{
+		{Name: "Time", Width: 20},
+		{Name: "Stream", Width: 30},
+		{Name: "Message", Width: 120},
+	}
+}
+
+// Fetch retrieves the events of the last minutes, keeping the most recent ones
+func (l *LogEvents) Fetch(ctx context.Context, c *client.Client) error {
+	l.events = make([]LogEvent, 0)
+
+	input := &cloudwatchlogs.FilterLogEventsInput{
+		LogGroupName: &l.group,
+		StartTime:    aws.Int64(time.Now().Add(-logTailWindow).UnixMilli()),
+	}
+	if l.errorsOnly {
+		input.FilterPattern = aws.String(errorFilterPattern)
+	}
+	if l.latestStreams {
+		streams, err := c.CloudWatchLogs().DescribeLogStreams(ctx, &cloudwatchlogs.DescribeLogStreamsInput{
+			LogGroupName: &l.group,
+			OrderBy:      logstypes.OrderByLastEventTime,
+			Descending:   aws.Bool(true),
+			Limit:        aws.Int32(latestLogStreams),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to describe streams of %s: %w", l.group, err)
+		}
+		if len(streams.LogStreams) == 0 {
+			return nil
+		}
+		for _, stream := range streams.LogStreams {
+			input.LogStreamNames = append(input.LogStreamNames, stringValue(stream.LogStreamName))
+		}
+	}
+
+	// Events come oldest first, drop the oldest ones beyond the limit
+	paginator := cloudwatchlogs.NewFilterLogEventsPaginator(c.CloudWatchLogs(), input)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get events of %s: %w", l.group, err)
+		}
+
+		for _, event := range output.Events {
+			l.events = append(l.events, LogEvent{
+				ID:        stringValue(event.EventId),
+				Timestamp: time.UnixMilli(ptrInt64Value(event.Timestamp)),
+				Stream:    stringValue(event.LogStreamName),
+				Message:   strings.TrimSpace(stringValue(event.Message)),
+			})
+		}
+		if len(l.events) > maxLogEvents {
+			l.events = l.events[len(l.events)-maxLogEvents:]
+		}
+	}
+
+	// Newest first, like a tail read from the top
+	for i, j := 0, len(l.events)-1; i < j; i, j = i+1, j-1 {
+		l.events[i], l.events[j] = l.events[j], l.events[i]
+	}
+
+	return nil
+}
+
+// Rows returns the table data
+func (l *LogEvents) Rows() [][]string {
+	rows := make([][]string, len(l.events))
+	for i, event := range l.events {
+		rows[i] = []string{
+			event.Timestamp.Format("2006-01-02 15:04:05"),
+			event.Stream,
+			strings.ReplaceAll(event.Message, "\n", " "),
+		}
+	}
+	return rows
+}
+
+// GetID returns the event ID at the given index
+func (l *LogEvents) GetID(index int) string {
+	if index >= 0 && index < len(l.events) {
+		return l.events[index].ID
+	}
+	return ""
+}
+
+// Highlight flags the events looking like errors
+func (l *LogEvents) Highlight(index int) bool {
+	if index < 0 || index >= len(l.events) {
+		return false
+	}
+	message := l.events[index].Message
+	return strings.Contains(message, "ERROR") || strings.Contains(message, "Exception")
+}
+
+// QuickActions returns the available quick actions for log events
+func (l *LogEvents) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:         'e',
+			Label:       "errors",
+			Description: "Toggle the error filter",
+			Toggle: func() {
+				l.errorsOnly = !l.errorsOnly
+			},
+		},
+		{
+			Key:         's',
+			Label:       "streams",
+			Description: "Toggle the latest streams filter",
+			Toggle: func() {
+				l.latestStreams = !l.latestStreams
+			},
+		},
+		{
+			Key:            'd',
+			Label:          "detail",
+			Description:    "Show event",
+			NeedsSelection: true,
+			TextHandler: func(ctx context.Context, c *client.Client, id string) (string, error) {
+				for _, event := range l.events {
+					if event.ID != id {
+						continue
+					}
+					var buf bytes.Buffer
+					if err := json.Indent(&buf, []byte(event.Message), "", "  "); err == nil {
+						return buf.String(), nil
+					}
+					return event.Message, nil
+				}
+				return "", fmt.Errorf("event %s not found", id)
+			},
+		},
+	}
+}
@@ -0,0 +1,88 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// maxMetricQueries is the maximum number of queries of a GetMetricData call
+const maxMetricQueries = 500
+
+// sparkBlocks are the bars of a sparkline, from lowest to highest
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws the values as a line of bars scaled between zero and their maximum
+func sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	highest := 0.0
+	for _, value := range values {
+		highest = math.Max(highest, value)
+	}
+
+	line := make([]rune, len(values))
+	for i, value := range values {
+		level := 0
+		if highest > 0 {
+			level = int(math.Round(value / highest * float64(len(sparkBlocks)-1)))
+		}
+		line[i] = sparkBlocks[level]
+	}
+	return string(line)
+}
+
+// metricQuery builds a query of a single metric statistic
+func metricQuery(id, namespace, metric, stat string, period int32, dimensions ...cwtypes.Dimension) cwtypes.MetricDataQuery {
+	return cwtypes.MetricDataQuery{
+		Id: aws.String(id),
+		MetricStat: &cwtypes.MetricStat{
+			Metric: &cwtypes.Metric{
+				Namespace:  aws.String(namespace),
+				MetricName: aws.String(metric),
+				Dimensions: dimensions,
+			},
+			Period: aws.Int32(period),
+			Stat:   aws.String(stat),
+		},
+	}
+}
+
+// metricSeries runs the queries over the last given duration and returns their
+// values by query ID, oldest first
+func metricSeries(ctx context.Context, c *client.Client, queries []cwtypes.MetricDataQuery, last time.Duration) (map[string][]float64, error) {
+	series := make(map[string][]float64)
+	end := time.Now()
+
+	for start := 0; start < len(queries); start += maxMetricQueries {
+		batch := queries[start:min(start+maxMetricQueries, len(queries))]
+
+		paginator := cloudwatch.NewGetMetricDataPaginator(c.CloudWatch(), &cloudwatch.GetMetricDataInput{
+			MetricDataQueries: batch,
+			StartTime:         aws.Time(end.Add(-last)),
+			EndTime:           aws.Time(end),
+			ScanBy:            cwtypes.ScanByTimestampAscending,
+		})
+		for paginator.HasMorePages() {
+			output, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get metrics: %w", err)
+			}
+			for _, result := range output.MetricDataResults {
+				id := stringValue(result.Id)
+				series[id] = append(series[id], result.Values...)
+			}
+		}
+	}
+
+	return series, nil
+}
@@ -0,0 +1,129 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+type progressKey struct{}
+
+// WithProgress returns a context carrying the function long running handlers report their progress to
+func WithProgress(ctx context.Context, report func(string)) context.Context {
+	return context.WithValue(ctx, progressKey{}, report)
+}
+
+// reportProgress reports the progress of a handler, if the context carries a progress function
+func reportProgress(ctx context.Context, text string) {
+	if report, ok := ctx.Value(progressKey{}).(func(string)); ok {
+		report(text)
+	}
+}
+
+// transferProgress counts the bytes of a transfer and reports each new percent
+type transferProgress struct {
+	ctx   context.Context
+	label string
+	total int64
+
+	mu      sync.Mutex
+	done    int64
+	percent int64
+}
+
+// add counts transferred bytes, parts of a transfer run concurrently
+func (p *transferProgress) add(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.done += int64(n)
+	if p.total <= 0 {
+		return
+	}
+	percent := p.done * 100 / p.total
+	if percent == p.percent {
+		return
+	}
+	p.percent = percent
+	reportProgress(p.ctx, fmt.Sprintf("%s %d%% (%s / %s)", p.label, percent, formatSize(p.done), formatSize(p.total)))
+}
+
+// progressWriterAt counts the bytes written by a concurrent download
+type progressWriterAt struct {
+	w        io.WriterAt
+	progress *transferProgress
+}
+
+// WriteAt writes a part of the download and counts it
+func (w *progressWriterAt) WriteAt(b []byte, off int64) (int, error) {
+	n, err := w.w.WriteAt(b, off)
+	w.progress.add(n)
+	return n, err
+}
+
+// progressReader counts the bytes read by an upload, the uploader reads its parts
+// concurrently at their offset and reads them again on retries, so only the bytes of
+// a part past those already read are counted
+type progressReader struct {
+	r        io.ReaderAt
+	size     int64
+	partSize int64
+	progress *transferProgress
+
+	off     int64
+	mu      sync.Mutex
+	reached map[int64]int64
+}
+
+// newProgressReader returns a reader of a file of the given size uploaded in parts of the given size
+func newProgressReader(r io.ReaderAt, size, partSize int64, progress *transferProgress) *progressReader {
+	return &progressReader{r: r, size: size, partSize: partSize, progress: progress, reached: make(map[int64]int64)}
+}
+
+// ReadAt reads a part of the upload and counts the bytes not read yet
+func (r *progressReader) ReadAt(b []byte, off int64) (int, error) {
+	n, err := r.r.ReadAt(b, off)
+	if n > 0 {
+		part := off / r.partSize
+		end := off + int64(n) - part*r.partSize
+		r.mu.Lock()
+		added := end - r.reached[part]
+		if added > 0 {
+			r.reached[part] = end
+		}
+		r.mu.Unlock()
+		if added > 0 {
+			r.progress.add(int(added))
+		}
+	}
+	return n, err
+}
+
+// Read reads the upload from the current offset
+func (r *progressReader) Read(b []byte) (int, error) {
+	if r.off >= r.size {
+		return 0, io.EOF
+	}
+	n, err := r.ReadAt(b, r.off)
+	r.off += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+// Seek moves the current offset, the uploader seeks to learn the size of the upload
+func (r *progressReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += r.off
+	case io.SeekEnd:
+		offset += r.size
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("negative offset %d", offset)
+	}
+	r.off = offset
+	return offset, nil
+}
@@ -2,16 +2,28 @@ package resources
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"a9s/internal/client"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/rds/auth"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 )
 
 // RDSInstance represents an RDS database instance
 type RDSInstance struct {
+	ARN              string
 	DBInstanceID     string
+	ClusterID        string
 	DBInstanceClass  string
 	Engine           string
 	EngineVersion    string
@@ -21,17 +33,20 @@ type RDSInstance struct {
 	MultiAZ          string
 	StorageType      string
 	AllocatedStorage string
+	Snapshot         string
 }
 
 // RDSInstances implements Resource for RDS instances
 type RDSInstances struct {
 	instances []RDSInstance
+	snapshots *rdsSnapshots
 }
 
 // NewRDSInstances creates a new RDSInstances resource
 func NewRDSInstances() *RDSInstances {
 	return &RDSInstances{
 		instances: make([]RDSInstance, 0),
+		snapshots: newRDSSnapshots(),
 	}
 }
 
@@ -51,6 +66,7 @@ func (r *RDSInstances) Columns() []Column {
 		{Name: "Endpoint", Width: 50},
 		{Name: "AZ", Width: 15},
 		{Name: "Multi-AZ", Width: 10},
+		{Name: "Snapshot", Width: 40},
 	}
 }
 
@@ -68,7 +84,9 @@ func (r *RDSInstances) Fetch(ctx context.Context, c *client.Client) error {
 
 		for _, db := range output.DBInstances {
 			instance := RDSInstance{
+				ARN:              stringValue(db.DBInstanceArn),
 				DBInstanceID:     stringValue(db.DBInstanceIdentifier),
+				ClusterID:        stringValue(db.DBClusterIdentifier),
 				DBInstanceClass:  stringValue(db.DBInstanceClass),
 				Engine:           stringValue(db.Engine),
 				EngineVersion:    stringValue(db.EngineVersion),
@@ -87,6 +105,17 @@ func (r *RDSInstances) Fetch(ctx context.Context, c *client.Client) error {
 		}
 	}
 
+	progress := r.snapshots.refresh(ctx, func(ctx context.Context, id string) (string, int32, error) {
+		output, err := c.RDS().DescribeDBSnapshots(ctx, &rds.DescribeDBSnapshotsInput{DBSnapshotIdentifier: &id})
+		if err != nil || len(output.DBSnapshots) == 0 {
+			return "", 0, err
+		}
+		return stringValue(output.DBSnapshots[0].Status), ptrInt32Value(output.DBSnapshots[0].PercentProgress), nil
+	})
+	for i := range r.instances {
+		r.instances[i].Snapshot = progress[r.instances[i].DBInstanceID]
+	}
+
 	return nil
 }
 
@@ -103,6 +132,7 @@ func (r *RDSInstances) Rows() [][]string {
 			instance.Endpoint,
 			instance.AvailabilityZone,
 			instance.MultiAZ,
+			instance.Snapshot,
 		}
 	}
 	return rows
@@ -118,7 +148,369 @@ func (r *RDSInstances) GetID(index int) string {
 
 // QuickActions returns the available quick actions for RDS instances
 func (r *RDSInstances) QuickActions() []QuickAction {
-	return []QuickAction{}
+	return []QuickAction{
+		{
+			Key:             's',
+			Label:           "stop",
+			Description:     "Stop instance",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "[red]stop[-] instance [white]%s[-]?",
+			ConfirmCheck:    r.checkStandalone,
+			Handler:         r.StopInstance,
+		},
+		{
+			Key:             'S',
+			Label:           "start",
+			Description:     "Start instance",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "[green]start[-] instance [white]%s[-]?",
+			ConfirmCheck:    r.checkStandalone,
+			Handler:         r.StartInstance,
+		},
+		{
+			Key:             'R',
+			Label:           "reboot",
+			Description:     "Reboot instance",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "[yellow]reboot[-] instance [white]%s[-]?",
+			Handler:         r.RebootInstance,
+		},
+		{
+			Key:            'b',
+			Label:          "snapshot",
+			Description:    "Create a manual snapshot",
+			NeedsSelection: true,
+			InputLabel:     "Snapshot identifier: ",
+			InputDefault:   defaultSnapshotID,
+			InputHandler:   r.CreateSnapshot,
+		},
+		{
+			Key:            'c',
+			Label:          "connect",
+			Description:    "Copy connection command",
+			NeedsSelection: true,
+			Clipboard:      true,
+			TextHandler: func(ctx context.Context, c *client.Client, id string) (string, error) {
+				conn, err := describeDBConnection(ctx, c, id, false)
+				if err != nil {
+					return "", err
+				}
+				return conn.command(), nil
+			},
+		},
+		{
+			Key:            'C',
+			Label:          "connect+secret",
+			Description:    "Copy connection command with the master credentials",
+			NeedsSelection: true,
+			Clipboard:      true,
+			TextHandler: func(ctx context.Context, c *client.Client, id string) (string, error) {
+				conn, err := describeDBConnection(ctx, c, id, true)
+				if err != nil {
+					return "", err
+				}
+				return conn.command(), nil
+			},
+		},
+		{
+			Key:            'i',
+			Label:          "iam-token",
+			Description:    "Copy IAM authentication token",
+			NeedsSelection: true,
+			Clipboard:      true,
+			InputLabel:     "Database user: ",
+			InputTextHandler: func(ctx context.Context, c *client.Client, id, user string) (string, error) {
+				return buildIAMAuthToken(ctx, c, id, user)
+			},
+		},
+		{
+			Key:            'F',
+			Label:          "forward",
+			Description:    "Forward the database port through an SSM instance",
+			NeedsSelection: true,
+			InputLabel:     "Bastion instance[:local port]: ",
+			InputTunnel: func(ctx context.Context, c *client.Client, id, input string) (*exec.Cmd, error) {
+				conn, err := describeDBConnection(ctx, c, id, false)
+				if err != nil {
+					return nil, err
+				}
+				remotePort := fmt.Sprintf("%d", conn.Port)
+				bastion, localPort, _ := strings.Cut(input, ":")
+				if localPort == "" {
+					localPort = remotePort
+				}
+				return portForwardCommand(ctx, c, bastion, localPort, conn.Host, remotePort)
+			},
+		},
+		tagsAction(r),
+	}
+}
+
+// checkStandalone refuses to stop or start an instance of an Aurora cluster, which
+// only the whole cluster can be
+func (r *RDSInstances) checkStandalone(ctx context.Context, c *client.Client, id string) (string, error) {
+	for _, instance := range r.instances {
+		if instance.DBInstanceID == id && instance.ClusterID != "" {
+			return "", fmt.Errorf("instance %s belongs to cluster %s, stop or start it from rds-clusters", id, instance.ClusterID)
+		}
+	}
+	return "", nil
+}
+
+// StopInstance stops an RDS instance, which AWS starts again automatically after seven days
+func (r *RDSInstances) StopInstance(ctx context.Context, c *client.Client, id string) error {
+	_, err := c.RDS().StopDBInstance(ctx, &rds.StopDBInstanceInput{
+		DBInstanceIdentifier: &id,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stop instance %s: %w", id, err)
+	}
+	return nil
+}
+
+// StartInstance starts a stopped RDS instance
+func (r *RDSInstances) StartInstance(ctx context.Context, c *client.Client, id string) error {
+	_, err := c.RDS().StartDBInstance(ctx, &rds.StartDBInstanceInput{
+		DBInstanceIdentifier: &id,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start instance %s: %w", id, err)
+	}
+	return nil
+}
+
+// RebootInstance reboots an RDS instance
+func (r *RDSInstances) RebootInstance(ctx context.Context, c *client.Client, id string) error {
+	_, err := c.RDS().RebootDBInstance(ctx, &rds.RebootDBInstanceInput{
+		DBInstanceIdentifier: &id,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reboot instance %s: %w", id, err)
+	}
+	return nil
+}
+
+// CreateSnapshot creates a manual snapshot of an instance, its progress shows on the next refreshes
+func (r *RDSInstances) CreateSnapshot(ctx context.Context, c *client.Client, id, snapshotID string) error {
+	_, err := c.RDS().CreateDBSnapshot(ctx, &rds.CreateDBSnapshotInput{
+		DBInstanceIdentifier: &id,
+		DBSnapshotIdentifier: &snapshotID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot of instance %s: %w", id, err)
+	}
+	r.snapshots.add(id, snapshotID)
+	return nil
+}
+
+// arn returns the ARN of an instance, which the tagging API expects
+func (r *RDSInstances) arn(id string) (string, error) {
+	for _, instance := range r.instances {
+		if instance.DBInstanceID == id {
+			return instance.ARN, nil
+		}
+	}
+	return "", fmt.Errorf("instance %s not found", id)
+}
+
+// Tags returns the tags of an instance
+func (r *RDSInstances) Tags(ctx context.Context, c *client.Client, id string) (map[string]string, error) {
+	arn, err := r.arn(id)
+	if err != nil {
+		return nil, err
+	}
+	output, err := c.RDS().ListTagsForResource(ctx, &rds.ListTagsForResourceInput{ResourceName: &arn})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags of %s: %w", id, err)
+	}
+	tags := make(map[string]string)
+	for _, tag := range output.TagList {
+		tags[stringValue(tag.Key)] = stringValue(tag.Value)
+	}
+	return tags, nil
+}
+
+// SetTags adds or updates tags of an instance
+func (r *RDSInstances) SetTags(ctx context.Context, c *client.Client, id string, tags map[string]string) error {
+	arn, err := r.arn(id)
+	if err != nil {
+		return err
+	}
+	rdsTags := make([]rdstypes.Tag, 0, len(tags))
+	for key, value := range tags {
+		rdsTags = append(rdsTags, rdstypes.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	_, err = c.RDS().AddTagsToResource(ctx, &rds.AddTagsToResourceInput{
+		ResourceName: &arn,
+		Tags:         rdsTags,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to tag %s: %w", id, err)
+	}
+	return nil
+}
+
+// RemoveTags removes tags from an instance
+func (r *RDSInstances) RemoveTags(ctx context.Context, c *client.Client, id string, keys []string) error {
+	arn, err := r.arn(id)
+	if err != nil {
+		return err
+	}
+	_, err = c.RDS().RemoveTagsFromResource(ctx, &rds.RemoveTagsFromResourceInput{
+		ResourceName: &arn,
+		TagKeys:      keys,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to untag %s: %w", id, err)
+	}
+	return nil
+}
+
+// buildIAMAuthToken generates an authentication token of a database user for the
+// current credentials, valid 15 minutes, on an instance with IAM authentication
+func buildIAMAuthToken(ctx context.Context, c *client.Client, id, user string) (string, error) {
+	output, err := c.RDS().DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: &id,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe %s: %w", id, err)
+	}
+	if len(output.DBInstances) == 0 {
+		return "", fmt.Errorf("instance %s not found", id)
+	}
+
+	db := output.DBInstances[0]
+	if !ptrBoolValue(db.IAMDatabaseAuthenticationEnabled) {
+		return "", fmt.Errorf("IAM database authentication is not enabled on %s", id)
+	}
+	if db.Endpoint == nil {
+		return "", fmt.Errorf("instance %s has no endpoint yet", id)
+	}
+
+	endpoint := fmt.Sprintf("%s:%d", stringValue(db.Endpoint.Address), ptrInt32Value(db.Endpoint.Port))
+	token, err := auth.BuildAuthToken(ctx, endpoint, c.Region(), user, c.Credentials())
+	if err != nil {
+		return "", fmt.Errorf("failed to build authentication token for %s: %w", id, err)
+	}
+	return token, nil
+}
+
+// dbConnection holds the parameters to connect to a database instance
+type dbConnection struct {
+	Engine   string
+	Host     string
+	Port     int32
+	User     string
+	Password string
+	Database string
+}
+
+// describeDBConnection gets the connection parameters of an instance, with the
+// password of its master user secret managed by RDS when withSecret is set
+func describeDBConnection(ctx context.Context, c *client.Client, id string, withSecret bool) (dbConnection, error) {
+	output, err := c.RDS().DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: &id,
+	})
+	if err != nil {
+		return dbConnection{}, fmt.Errorf("failed to describe %s: %w", id, err)
+	}
+	if len(output.DBInstances) == 0 {
+		return dbConnection{}, fmt.Errorf("instance %s not found", id)
+	}
+
+	db := output.DBInstances[0]
+	if db.Endpoint == nil {
+		return dbConnection{}, fmt.Errorf("instance %s has no endpoint yet", id)
+	}
+
+	conn := dbConnection{
+		Engine:   stringValue(db.Engine),
+		Host:     stringValue(db.Endpoint.Address),
+		Port:     ptrInt32Value(db.Endpoint.Port),
+		User:     stringValue(db.MasterUsername),
+		Database: stringValue(db.DBName),
+	}
+
+	if !withSecret {
+		return conn, nil
+	}
+	if db.MasterUserSecret == nil || db.MasterUserSecret.SecretArn == nil {
+		return dbConnection{}, fmt.Errorf("instance %s has no master user secret managed by RDS", id)
+	}
+
+	secret, err := c.SecretsManager().GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: db.MasterUserSecret.SecretArn,
+	})
+	if err != nil {
+		return dbConnection{}, fmt.Errorf("failed to get master user secret of %s: %w", id, err)
+	}
+
+	var credentials struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	if err := json.Unmarshal([]byte(stringValue(secret.SecretString)), &credentials); err != nil {
+		return dbConnection{}, fmt.Errorf("failed to parse master user secret of %s: %w", id, err)
+	}
+	if credentials.Username != "" {
+		conn.User = credentials.Username
+	}
+	conn.Password = credentials.Password
+
+	return conn, nil
+}
+
+// command returns the client command line of the engine, or a URL for the other engines
+func (d dbConnection) command() string {
+	switch {
+	case strings.Contains(d.Engine, "postgres"):
+		database := d.Database
+		if database == "" {
+			database = "postgres"
+		}
+		cmd := fmt.Sprintf("psql %s", shellQuote(fmt.Sprintf("host=%s port=%d user=%s dbname=%s sslmode=require", d.Host, d.Port, d.User, database)))
+		if d.Password != "" {
+			cmd = fmt.Sprintf("PGPASSWORD=%s %s", shellQuote(d.Password), cmd)
+		}
+		return cmd
+	case strings.Contains(d.Engine, "mysql"), strings.Contains(d.Engine, "mariadb"):
+		cmd := fmt.Sprintf("mysql -h %s -P %d -u %s", d.Host, d.Port, shellQuote(d.User))
+		if d.Password != "" {
+			cmd = fmt.Sprintf("MYSQL_PWD=%s %s", shellQuote(d.Password), cmd)
+		} else {
+			cmd += " -p"
+		}
+		if d.Database != "" {
+			cmd += " " + d.Database
+		}
+		return cmd
+	case strings.HasPrefix(d.Engine, "sqlserver"):
+		cmd := fmt.Sprintf("sqlcmd -S %s,%d -U %s", d.Host, d.Port, shellQuote(d.User))
+		if d.Password != "" {
+			cmd += " -P " + shellQuote(d.Password)
+		}
+		return cmd
+	case strings.HasPrefix(d.Engine, "oracle"):
+		credentials := d.User
+		if d.Password != "" {
+			credentials += "/" + d.Password
+		}
+		return fmt.Sprintf("sqlplus %s", shellQuote(fmt.Sprintf("%s@//%s:%d/%s", credentials, d.Host, d.Port, d.Database)))
+	}
+
+	credentials := d.User
+	if d.Password != "" {
+		credentials += ":" + d.Password
+	}
+	return fmt.Sprintf("%s://%s@%s:%d/%s", d.Engine, credentials, d.Host, d.Port, d.Database)
+}
+
+// shellQuote quotes a value for a POSIX shell
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
 }
 
 // ptrBoolValue safely dereferences a bool pointer
@@ -128,3 +520,596 @@ func ptrBoolValue(b *bool) bool {
 	}
 	return *b
 }
+
+// RDSCluster represents an Aurora or Multi-AZ DB cluster
+type RDSCluster struct {
+	ARN           string
+	ClusterID     string
+	Engine        string
+	EngineVersion string
+	Status        string
+	Writer        string
+	Members       []string
+	Endpoint      string
+	MultiAZ       bool
+	Snapshot      string
+}
+
+// RDSClusters implements Resource for RDS DB clusters
+type RDSClusters struct {
+	clusters  []RDSCluster
+	snapshots *rdsSnapshots
+}
+
+// NewRDSClusters creates a new RDSClusters resource
+func NewRDSClusters() *RDSClusters {
+	return &RDSClusters{
+		clusters:  make([]RDSCluster, 0),
+		snapshots: newRDSSnapshots(),
+	}
+}
+
+// Name returns the display name
+func (r *RDSClusters) Name() string {
+	return "RDS Clusters"
+}
+
+// Columns returns the column definitions
+func (r *RDSClusters) Columns() []Column {
+	return []Column{
+		{Name: "Cluster ID", Width: 30},
+		{Name: "Engine", Width: 18},
+		{Name: "Version", Width: 12},
+		{Name: "Status", Width: 15},
+		{Name: "Writer", Width: 30},
+		{Name: "Members", Width: 8},
+		{Name: "Endpoint", Width: 60},
+		{Name: "Multi-AZ", Width: 10},
+		{Name: "Snapshot", Width: 40},
+	}
+}
+
+// Fetch retrieves the DB clusters from AWS
+func (r *RDSClusters) Fetch(ctx context.Context, c *client.Client) error {
+	r.clusters = make([]RDSCluster, 0)
+
+	paginator := rds.NewDescribeDBClustersPaginator(c.RDS(), &rds.DescribeDBClustersInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe RDS clusters: %w", err)
+		}
+
+		for _, db := range output.DBClusters {
+			cluster := RDSCluster{
+				ARN:           stringValue(db.DBClusterArn),
+				ClusterID:     stringValue(db.DBClusterIdentifier),
+				Engine:        stringValue(db.Engine),
+				EngineVersion: stringValue(db.EngineVersion),
+				Status:        stringValue(db.Status),
+				MultiAZ:       ptrBoolValue(db.MultiAZ),
+			}
+			if db.Endpoint != nil {
+				cluster.Endpoint = fmt.Sprintf("%s:%d", stringValue(db.Endpoint), ptrInt32Value(db.Port))
+			}
+			for _, member := range db.DBClusterMembers {
+				id := stringValue(member.DBInstanceIdentifier)
+				cluster.Members = append(cluster.Members, id)
+				if ptrBoolValue(member.IsClusterWriter) {
+					cluster.Writer = id
+				}
+			}
+			r.clusters = append(r.clusters, cluster)
+		}
+	}
+
+	progress := r.snapshots.refresh(ctx, func(ctx context.Context, id string) (string, int32, error) {
+		output, err := c.RDS().DescribeDBClusterSnapshots(ctx, &rds.DescribeDBClusterSnapshotsInput{DBClusterSnapshotIdentifier: &id})
+		if err != nil || len(output.DBClusterSnapshots) == 0 {
+			return "", 0, err
+		}
+		return stringValue(output.DBClusterSnapshots[0].Status), ptrInt32Value(output.DBClusterSnapshots[0].PercentProgress), nil
+	})
+	for i := range r.clusters {
+		r.clusters[i].Snapshot = progress[r.clusters[i].ClusterID]
+	}
+
+	return nil
+}
+
+// Rows returns the table data
+func (r *RDSClusters) Rows() [][]string {
+	rows := make([][]string, len(r.clusters))
+	for i, cluster := range r.clusters {
+		rows[i] = []string{
+			cluster.ClusterID,
+			cluster.Engine,
+			cluster.EngineVersion,
+			cluster.Status,
+			cluster.Writer,
+			fmt.Sprintf("%d", len(cluster.Members)),
+			cluster.Endpoint,
+			fmt.Sprintf("%t", cluster.MultiAZ),
+			cluster.Snapshot,
+		}
+	}
+	return rows
+}
+
+// GetID returns the cluster ID at the given index
+func (r *RDSClusters) GetID(index int) string {
+	if index >= 0 && index < len(r.clusters) {
+		return r.clusters[index].ClusterID
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for RDS clusters
+func (r *RDSClusters) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:             's',
+			Label:           "stop",
+			Description:     "Stop cluster",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "[red]stop[-] cluster [white]%s[-] and all its instances?",
+			Handler:         r.StopCluster,
+		},
+		{
+			Key:             'S',
+			Label:           "start",
+			Description:     "Start cluster",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "[green]start[-] cluster [white]%s[-] and all its instances?",
+			Handler:         r.StartCluster,
+		},
+		{
+			Key:             'R',
+			Label:           "reboot",
+			Description:     "Reboot cluster",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "[yellow]reboot[-] cluster [white]%s[-] and all its instances?",
+			Handler:         r.RebootCluster,
+		},
+		{
+			Key:            'b',
+			Label:          "snapshot",
+			Description:    "Create a manual snapshot",
+			NeedsSelection: true,
+			InputLabel:     "Snapshot identifier: ",
+			InputDefault:   defaultSnapshotID,
+			InputHandler:   r.CreateSnapshot,
+		},
+	}
+}
+
+// StopCluster stops a DB cluster with its instances, which AWS starts again automatically after seven days
+func (r *RDSClusters) StopCluster(ctx context.Context, c *client.Client, id string) error {
+	_, err := c.RDS().StopDBCluster(ctx, &rds.StopDBClusterInput{
+		DBClusterIdentifier: &id,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stop cluster %s: %w", id, err)
+	}
+	return nil
+}
+
+// StartCluster starts a stopped DB cluster with its instances
+func (r *RDSClusters) StartCluster(ctx context.Context, c *client.Client, id string) error {
+	_, err := c.RDS().StartDBCluster(ctx, &rds.StartDBClusterInput{
+		DBClusterIdentifier: &id,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start cluster %s: %w", id, err)
+	}
+	return nil
+}
+
+// RebootCluster reboots a DB cluster, Aurora clusters have no such call so their
+// instances are rebooted one after the other
+func (r *RDSClusters) RebootCluster(ctx context.Context, c *client.Client, id string) error {
+	var cluster *RDSCluster
+	for i := range r.clusters {
+		if r.clusters[i].ClusterID == id {
+			cluster = &r.clusters[i]
+			break
+		}
+	}
+	if cluster == nil {
+		return fmt.Errorf("cluster %s not found", id)
+	}
+
+	if !strings.HasPrefix(cluster.Engine, "aurora") {
+		_, err := c.RDS().RebootDBCluster(ctx, &rds.RebootDBClusterInput{
+			DBClusterIdentifier: &id,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to reboot cluster %s: %w", id, err)
+		}
+		return nil
+	}
+
+	for _, member := range cluster.Members {
+		_, err := c.RDS().RebootDBInstance(ctx, &rds.RebootDBInstanceInput{
+			DBInstanceIdentifier: &member,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to reboot instance %s of cluster %s: %w", member, id, err)
+		}
+	}
+	return nil
+}
+
+// CreateSnapshot creates a manual snapshot of a cluster, its progress shows on the next refreshes
+func (r *RDSClusters) CreateSnapshot(ctx context.Context, c *client.Client, id, snapshotID string) error {
+	_, err := c.RDS().CreateDBClusterSnapshot(ctx, &rds.CreateDBClusterSnapshotInput{
+		DBClusterIdentifier:         &id,
+		DBClusterSnapshotIdentifier: &snapshotID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot of cluster %s: %w", id, err)
+	}
+	r.snapshots.add(id, snapshotID)
+	return nil
+}
+
+// defaultSnapshotID proposes a snapshot identifier made of the source and the current time
+func defaultSnapshotID(id string) string {
+	return fmt.Sprintf("%s-%s", id, time.Now().Format("20060102-1504"))
+}
+
+// rdsSnapshots follows the manual snapshots created from a9s until they are available,
+// the actions creating them run concurrently with the fetches
+type rdsSnapshots struct {
+	mu      sync.Mutex
+	pending map[string]string
+}
+
+// newRDSSnapshots creates an empty snapshot tracker
+func newRDSSnapshots() *rdsSnapshots {
+	return &rdsSnapshots{
+		pending: make(map[string]string),
+	}
+}
+
+// add follows the snapshot of a source instance or cluster
+func (s *rdsSnapshots) add(source, snapshotID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending[source] = snapshotID
+}
+
+// refresh describes the followed snapshots and returns their progress by source, the
+// ones no longer being created are shown one last time then forgotten
+func (s *rdsSnapshots) refresh(ctx context.Context, describe func(ctx context.Context, snapshotID string) (string, int32, error)) map[string]string {
+	s.mu.Lock()
+	pending := make(map[string]string, len(s.pending))
+	for source, snapshotID := range s.pending {
+		pending[source] = snapshotID
+	}
+	s.mu.Unlock()
+
+	progress := make(map[string]string, len(pending))
+	for source, snapshotID := range pending {
+		status, percent, err := describe(ctx, snapshotID)
+		switch {
+		case err != nil:
+			progress[source] = fmt.Sprintf("%s: %v", snapshotID, err)
+		case status == "creating":
+			progress[source] = fmt.Sprintf("%s %d%%", snapshotID, percent)
+			continue
+		case status == "":
+			progress[source] = fmt.Sprintf("%s pending", snapshotID)
+			continue
+		default:
+			progress[source] = fmt.Sprintf("%s %s", snapshotID, status)
+		}
+
+		s.mu.Lock()
+		if s.pending[source] == snapshotID {
+			delete(s.pending, source)
+		}
+		s.mu.Unlock()
+	}
+	return progress
+}
+
+// RDSConfigGroup represents an RDS parameter group or option group
+type RDSConfigGroup struct {
+	Type        string // "parameter" or "option"
+	Name        string
+	Family      string // Parameter group family, or engine and version of option groups
+	Description string
+	Instances   int
+	ApplyStatus string // Apply status of the instances using the group, e.g. "pending-reboot: 1"
+}
+
+// ID returns the identifier of the group, unique across both types
+func (g RDSConfigGroup) ID() string {
+	return g.Type + "/" + g.Name
+}
+
+// RDSConfigGroups implements Resource for RDS parameter and option groups
+type RDSConfigGroups struct {
+	groups []RDSConfigGroup
+}
+
+// NewRDSConfigGroups creates a new RDSConfigGroups resource
+func NewRDSConfigGroups() *RDSConfigGroups {
+	return &RDSConfigGroups{
+		groups: make([]RDSConfigGroup, 0),
+	}
+}
+
+// Name returns the display name
+func (r *RDSConfigGroups) Name() string {
+	return "RDS Parameter and Option Groups"
+}
+
+// Columns returns the column definitions
+func (r *RDSConfigGroups) Columns() []Column {
+	return []Column{
+		{Name: "Type", Width: 10},
+		{Name: "Name", Width: 40},
+		{Name: "Family", Width: 20},
+		{Name: "Instances", Width: 10},
+		{Name: "Apply Status", Width: 30},
+		{Name: "Description", Width: 50},
+	}
+}
+
+// Fetch retrieves the parameter and option groups with the apply status of their instances
+func (r *RDSConfigGroups) Fetch(ctx context.Context, c *client.Client) error {
+	r.groups = make([]RDSConfigGroup, 0)
+
+	// Count the apply statuses per group from the instances using them
+	statuses := make(map[string]map[string]int)
+	count := func(id, status string) {
+		if statuses[id] == nil {
+			statuses[id] = make(map[string]int)
+		}
+		statuses[id][status]++
+	}
+
+	instances := rds.NewDescribeDBInstancesPaginator(c.RDS(), &rds.DescribeDBInstancesInput{})
+	for instances.HasMorePages() {
+		output, err := instances.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe RDS instances: %w", err)
+		}
+		for _, db := range output.DBInstances {
+			for _, group := range db.DBParameterGroups {
+				count("parameter/"+stringValue(group.DBParameterGroupName), stringValue(group.ParameterApplyStatus))
+			}
+			for _, group := range db.OptionGroupMemberships {
+				count("option/"+stringValue(group.OptionGroupName), stringValue(group.Status))
+			}
+		}
+	}
+
+	parameterGroups := rds.NewDescribeDBParameterGroupsPaginator(c.RDS(), &rds.DescribeDBParameterGroupsInput{})
+	for parameterGroups.HasMorePages() {
+		output, err := parameterGroups.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe RDS parameter groups: %w", err)
+		}
+		for _, group := range output.DBParameterGroups {
+			r.groups = append(r.groups, RDSConfigGroup{
+				Type:        "parameter",
+				Name:        stringValue(group.DBParameterGroupName),
+				Family:      stringValue(group.DBParameterGroupFamily),
+				Description: stringValue(group.Description),
+			})
+		}
+	}
+
+	optionGroups := rds.NewDescribeOptionGroupsPaginator(c.RDS(), &rds.DescribeOptionGroupsInput{})
+	for optionGroups.HasMorePages() {
+		output, err := optionGroups.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe RDS option groups: %w", err)
+		}
+		for _, group := range output.OptionGroupsList {
+			r.groups = append(r.groups, RDSConfigGroup{
+				Type:        "option",
+				Name:        stringValue(group.OptionGroupName),
+				Family:      fmt.Sprintf("%s %s", stringValue(group.EngineName), stringValue(group.MajorEngineVersion)),
+				Description: stringValue(group.OptionGroupDescription),
+			})
+		}
+	}
+
+	for i, group := range r.groups {
+		counts := statuses[group.ID()]
+		parts := make([]string, 0, len(counts))
+		for status, n := range counts {
+			r.groups[i].Instances += n
+			parts = append(parts, fmt.Sprintf("%s: %d", status, n))
+		}
+		sort.Strings(parts)
+		r.groups[i].ApplyStatus = strings.Join(parts, ", ")
+	}
+
+	return nil
+}
+
+// Rows returns the table data
+func (r *RDSConfigGroups) Rows() [][]string {
+	rows := make([][]string, len(r.groups))
+	for i, group := range r.groups {
+		rows[i] = []string{
+			group.Type,
+			group.Name,
+			group.Family,
+			fmt.Sprintf("%d", group.Instances),
+			group.ApplyStatus,
+			group.Description,
+		}
+	}
+	return rows
+}
+
+// GetID returns the group identifier at the given index
+func (r *RDSConfigGroups) GetID(index int) string {
+	if index >= 0 && index < len(r.groups) {
+		return r.groups[index].ID()
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for RDS parameter and option groups
+func (r *RDSConfigGroups) QuickActions() []QuickAction {
+	return []QuickAction{}
+}
+
+// DrillDown opens the non-default parameters of a parameter group, or the options of an option group
+func (r *RDSConfigGroups) DrillDown(id string) Resource {
+	groupType, name, ok := strings.Cut(id, "/")
+	if !ok {
+		return nil
+	}
+	return NewRDSGroupSettings(groupType, name)
+}
+
+// RDSGroupSetting represents a parameter of a parameter group, or a setting of an option
+type RDSGroupSetting struct {
+	Name       string
+	Value      string
+	Detail     string // Apply type of parameters, option of settings
+	Method     string // Apply method of parameters, option version of settings
+	Modifiable string
+}
+
+// RDSGroupSettings implements Resource for the non-default parameters of a
+// parameter group, or the option settings of an option group
+type RDSGroupSettings struct {
+	groupType string
+	group     string
+	settings  []RDSGroupSetting
+}
+
+// NewRDSGroupSettings creates a new RDSGroupSettings resource
+func NewRDSGroupSettings(groupType, group string) *RDSGroupSettings {
+	return &RDSGroupSettings{
+		groupType: groupType,
+		group:     group,
+		settings:  make([]RDSGroupSetting, 0),
+	}
+}
+
+// Name returns the display name
+func (r *RDSGroupSettings) Name() string {
+	if r.groupType == "option" {
+		return fmt.Sprintf("RDS Options: %s", r.group)
+	}
+	return fmt.Sprintf("RDS Parameters: %s (non-default)", r.group)
+}
+
+// Columns returns the column definitions
+func (r *RDSGroupSettings) Columns() []Column {
+	if r.groupType == "option" {
+		return []Column{
+			{Name: "Setting", Width: 40},
+			{Name: "Value", Width: 40},
+			{Name: "Option", Width: 30},
+			{Name: "Version", Width: 12},
+			{Name: "Modifiable", Width: 10},
+		}
+	}
+	return []Column{
+		{Name: "Parameter", Width: 40},
+		{Name: "Value", Width: 40},
+		{Name: "Apply Type", Width: 12},
+		{Name: "Apply Method", Width: 16},
+		{Name: "Modifiable", Width: 10},
+	}
+}
+
+// Fetch retrieves the user-modified parameters, or the options and their settings
+func (r *RDSGroupSettings) Fetch(ctx context.Context, c *client.Client) error {
+	r.settings = make([]RDSGroupSetting, 0)
+
+	if r.groupType == "option" {
+		output, err := c.RDS().DescribeOptionGroups(ctx, &rds.DescribeOptionGroupsInput{
+			OptionGroupName: &r.group,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to describe option group %s: %w", r.group, err)
+		}
+		for _, group := range output.OptionGroupsList {
+			for _, option := range group.Options {
+				// Options without settings still get a row
+				if len(option.OptionSettings) == 0 {
+					r.settings = append(r.settings, RDSGroupSetting{
+						Detail: stringValue(option.OptionName),
+						Method: stringValue(option.OptionVersion),
+					})
+				}
+				for _, setting := range option.OptionSettings {
+					r.settings = append(r.settings, RDSGroupSetting{
+						Name:       stringValue(setting.Name),
+						Value:      stringValue(setting.Value),
+						Detail:     stringValue(option.OptionName),
+						Method:     stringValue(option.OptionVersion),
+						Modifiable: fmt.Sprintf("%t", ptrBoolValue(setting.IsModifiable)),
+					})
+				}
+			}
+		}
+		return nil
+	}
+
+	// Parameters changed from the family defaults have the "user" source
+	paginator := rds.NewDescribeDBParametersPaginator(c.RDS(), &rds.DescribeDBParametersInput{
+		DBParameterGroupName: &r.group,
+		Source:               aws.String("user"),
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe parameters of %s: %w", r.group, err)
+		}
+		for _, parameter := range output.Parameters {
+			r.settings = append(r.settings, RDSGroupSetting{
+				Name:       stringValue(parameter.ParameterName),
+				Value:      stringValue(parameter.ParameterValue),
+				Detail:     stringValue(parameter.ApplyType),
+				Method:     string(parameter.ApplyMethod),
+				Modifiable: fmt.Sprintf("%t", ptrBoolValue(parameter.IsModifiable)),
+			})
+		}
+	}
+
+	return nil
+}
+
+// Rows returns the table data
+func (r *RDSGroupSettings) Rows() [][]string {
+	rows := make([][]string, len(r.settings))
+	for i, setting := range r.settings {
+		rows[i] = []string{
+			setting.Name,
+			setting.Value,
+			setting.Detail,
+			setting.Method,
+			setting.Modifiable,
+		}
+	}
+	return rows
+}
+
+// GetID returns the setting name at the given index
+func (r *RDSGroupSettings) GetID(index int) string {
+	if index >= 0 && index < len(r.settings) {
+		return r.settings[index].Name
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for RDS group settings
+func (r *RDSGroupSettings) QuickActions() []QuickAction {
+	return []QuickAction{}
+}
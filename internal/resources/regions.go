@@ -0,0 +1,203 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+// regionMatrixConcurrency is the number of regions counted at the same time
+const regionMatrixConcurrency = 8
+
+// regionMatrixBarWidth is the width of the bar of the region with the most resources
+const regionMatrixBarWidth = 30
+
+// regionMatrixTypes maps the resource keys of a9s to the CloudFormation types
+// counted through the Cloud Control API
+var regionMatrixTypes = map[string]string{
+	"ec2":                  "AWS::EC2::Instance",
+	"vpc":                  "AWS::EC2::VPC",
+	"subnets":              "AWS::EC2::Subnet",
+	"security-groups":      "AWS::EC2::SecurityGroup",
+	"lambda":               "AWS::Lambda::Function",
+	"ecs":                  "AWS::ECS::Cluster",
+	"eks":                  "AWS::EKS::Cluster",
+	"rds":                  "AWS::RDS::DBInstance",
+	"rds-clusters":         "AWS::RDS::DBCluster",
+	"dynamodb":             "AWS::DynamoDB::Table",
+	"elasticache-clusters": "AWS::ElastiCache::CacheCluster",
+	"alb":                  "AWS::ElasticLoadBalancingV2::LoadBalancer",
+	"acm":                  "AWS::CertificateManager::Certificate",
+	"kms":                  "AWS::KMS::Key",
+	"secrets":              "AWS::SecretsManager::Secret",
+	"ecr":                  "AWS::ECR::Repository",
+	"sqs":                  "AWS::SQS::Queue",
+	"sns":                  "AWS::SNS::Topic",
+	"cognito":              "AWS::Cognito::UserPool",
+	"ssm-params":           "AWS::SSM::Parameter",
+}
+
+// RegionCount represents the number of resources of a type in a region
+type RegionCount struct {
+	Region string
+	Count  int
+	Err    error
+}
+
+// RegionMatrix implements Resource for the number of resources of a type in
+// every region enabled for the account
+type RegionMatrix struct {
+	typeName string
+	current  string
+	counts   []RegionCount
+}
+
+// NewRegionMatrix creates a new RegionMatrix resource, the type is either a
+// resource key of a9s or a CloudFormation type, e.g. AWS::EC2::Instance
+func NewRegionMatrix(typeName string) *RegionMatrix {
+	if mapped, ok := regionMatrixTypes[strings.ToLower(typeName)]; ok {
+		typeName = mapped
+	}
+	return &RegionMatrix{
+		typeName: typeName,
+		counts:   make([]RegionCount, 0),
+	}
+}
+
+// Name returns the display name
+func (r *RegionMatrix) Name() string {
+	total := 0
+	for _, count := range r.counts {
+		total += count.Count
+	}
+	return fmt.Sprintf("Regions (%s, %d total)", r.typeName, total)
+}
+
+// Columns returns the column definitions
+func (r *RegionMatrix) Columns() []Column {
+	return []Column{
+		{Name: "Region", Width: 16},
+		{Name: "Count", Width: 8},
+		{Name: "Share", Width: regionMatrixBarWidth},
+		{Name: "Status", Width: 50},
+	}
+}
+
+// Fetch counts the resources of the type in every enabled region
+func (r *RegionMatrix) Fetch(ctx context.Context, c *client.Client) error {
+	r.counts = make([]RegionCount, 0)
+	r.current = c.Region()
+
+	output, err := c.EC2().DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
+	if err != nil {
+		return fmt.Errorf("failed to describe regions: %w", err)
+	}
+
+	counts := make([]RegionCount, len(output.Regions))
+	sem := make(chan struct{}, regionMatrixConcurrency)
+	var wg sync.WaitGroup
+	for i, region := range output.Regions {
+		counts[i].Region = stringValue(region.RegionName)
+
+		wg.Add(1)
+		go func(count *RegionCount) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			count.Count, count.Err = r.count(ctx, c, count.Region)
+		}(&counts[i])
+	}
+	wg.Wait()
+
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Region < counts[j].Region
+	})
+	r.counts = counts
+
+	return nil
+}
+
+// count lists the resources of the type in a region
+func (r *RegionMatrix) count(ctx context.Context, c *client.Client, region string) (int, error) {
+	regional, err := c.InRegion(ctx, region)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	paginator := cloudcontrol.NewListResourcesPaginator(regional.CloudControl(), &cloudcontrol.ListResourcesInput{
+		TypeName: &r.typeName,
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return total, err
+		}
+		total += len(output.ResourceDescriptions)
+	}
+	return total, nil
+}
+
+// Rows returns the table data
+func (r *RegionMatrix) Rows() [][]string {
+	highest := 0
+	for _, count := range r.counts {
+		highest = max(highest, count.Count)
+	}
+
+	rows := make([][]string, len(r.counts))
+	for i, count := range r.counts {
+		bar := ""
+		if highest > 0 && count.Count > 0 {
+			bar = strings.Repeat("█", max(1, count.Count*regionMatrixBarWidth/highest))
+		}
+
+		status := ""
+		switch {
+		case count.Err != nil:
+			status = count.Err.Error()
+		case count.Region == r.current:
+			status = "current region"
+		}
+
+		rows[i] = []string{
+			count.Region,
+			fmt.Sprintf("%d", count.Count),
+			bar,
+			status,
+		}
+	}
+	return rows
+}
+
+// GetID returns the region at the given index
+func (r *RegionMatrix) GetID(index int) string {
+	if index >= 0 && index < len(r.counts) {
+		return r.counts[index].Region
+	}
+	return ""
+}
+
+// Highlight reports whether resources exist outside of the current region
+func (r *RegionMatrix) Highlight(index int) bool {
+	if index < 0 || index >= len(r.counts) {
+		return false
+	}
+	return r.counts[index].Count > 0 && r.counts[index].Region != r.current
+}
+
+// QuickActions returns the available quick actions for the region matrix
+func (r *RegionMatrix) QuickActions() []QuickAction {
+	return []QuickAction{}
+}
@@ -2,14 +2,17 @@ package resources
 
 import (
 	"context"
+	"os/exec"
+	"time"
 
 	"a9s/internal/client"
 )
 
 // Column represents a table column definition
 type Column struct {
-	Name  string
-	Width int
+	Name      string
+	Width     int
+	Sensitive bool // Whether values are masked until revealed
 }
 
 // QuickAction represents a user-triggered action on a resource
@@ -21,6 +24,89 @@ type QuickAction struct {
 	NeedsConfirm    bool   // Whether to show a confirmation dialog
 	ConfirmTemplate string // Template for confirmation message, use %s for ID
 	Handler         func(ctx context.Context, client *client.Client, selectedID string) error
+
+	// ConfirmCheck runs before the confirmation dialog, the returned warning is shown in
+	// it and an error cancels the action, e.g. when a protection is enabled
+	ConfirmCheck func(ctx context.Context, client *client.Client, selectedID string) (string, error)
+	TypedConfirm bool // Whether the selected ID must be typed to confirm instead of choosing Yes
+
+	// ReadOnly marks a handler changing nothing in the account, e.g. a download or a role
+	// switch, no change ticket is asked for it
+	ReadOnly bool
+
+	// InputLabel asks for a value before running InputHandler instead of Handler,
+	// InputTextHandler instead of TextHandler, or before opening the child resource
+	// returned by InputView
+	InputLabel       string
+	InputHandler     func(ctx context.Context, client *client.Client, selectedID, input string) error
+	InputTextHandler func(ctx context.Context, client *client.Client, selectedID, input string) (string, error)
+	InputView        func(selectedID, input string) Resource
+	InputDefault     func(selectedID string) string // Pre-filled value, e.g. the current one when editing
+	InputOptional    bool                           // Whether an empty value is accepted, e.g. an MFA code only some roles require
+
+	// View opens the returned child resource instead of running a handler
+	View func(selectedID string) Resource
+
+	// TextHandler produces a text that is displayed, or copied when Clipboard is set
+	TextHandler func(ctx context.Context, client *client.Client, selectedID string) (string, error)
+	Clipboard   bool
+
+	// Plan enumerates the steps of a multi-step action, which the user walks through one by one
+	Plan func(ctx context.Context, client *client.Client, selectedID string) ([]Step, error)
+
+	// InputPlan is like Plan but receives the text the user entered in an input dialog
+	InputPlan func(ctx context.Context, client *client.Client, selectedID, input string) ([]Step, error)
+
+	// Jump returns the ID of a related row of the same resource to select
+	Jump func(selectedID string) (string, error)
+
+	// Goto returns the registry key of another resource and the ID of the related row to select in it
+	Goto func(selectedID string) (key, id string, err error)
+
+	// Toggle switches a display option of the resource, which is then reloaded
+	Toggle func()
+
+	// Mark ticks or unticks the selected row, the table is redrawn without fetching again
+	Mark func(selectedID string)
+
+	// Command returns an interactive program to run in the terminal, the UI is suspended meanwhile
+	Command func(ctx context.Context, client *client.Client, selectedID string) (*exec.Cmd, error)
+
+	// EditTemplate is the text opened in the editor of the user, EditHandler then receives the
+	// edited text, e.g. a message with its headers, an unchanged text cancels the action
+	EditTemplate func(selectedID string) string
+	EditHandler  func(ctx context.Context, client *client.Client, selectedID, text string) error
+
+	// EditPrepare runs before EditTemplate, e.g. to load the choices listed in the template
+	EditPrepare func(ctx context.Context, client *client.Client, selectedID string) error
+
+	// EditPlan is like EditHandler but enumerates steps, e.g. the diff of the edit and its update
+	EditPlan func(ctx context.Context, client *client.Client, selectedID, text string) ([]Step, error)
+
+	// Tunnel returns a program kept running in the background, e.g. a port forwarding
+	// session, until the user closes it or quits
+	Tunnel func(ctx context.Context, client *client.Client, selectedID string) (*exec.Cmd, error)
+
+	// InputTunnel is like Tunnel but receives the text the user entered in an input dialog
+	InputTunnel func(ctx context.Context, client *client.Client, selectedID, input string) (*exec.Cmd, error)
+
+	// Follow opens the returned child resource once Handler succeeded or all the steps of
+	// a plan ran, e.g. the events of the operation the handler started
+	Follow func(selectedID string) Resource
+}
+
+// Step is one operation of a multi-step action, steps without Run nor RunText are notes for the user
+type Step struct {
+	Description string
+	Run         func(ctx context.Context, client *client.Client) error
+
+	// RunText is run instead of Run when the step produces a text shown once, e.g. a new secret
+	RunText func(ctx context.Context, client *client.Client) (string, error)
+}
+
+// IsNote reports whether the step is only informative
+func (s Step) IsNote() bool {
+	return s.Run == nil && s.RunText == nil
 }
 
 // Resource defines the interface for all AWS resources
@@ -44,21 +130,96 @@ type Resource interface {
 	QuickActions() []QuickAction
 }
 
+// Drillable is implemented by resources whose rows can be opened with Enter
+type Drillable interface {
+	// DrillDown returns the child resource for the given ID
+	DrillDown(id string) Resource
+}
+
+// Pageable is implemented by resources loading their listing one page at a time
+type Pageable interface {
+	// HasMore reports whether more pages can be loaded
+	HasMore() bool
+	// FetchMore appends the next page to the already loaded ones
+	FetchMore(ctx context.Context, c *client.Client) error
+}
+
+// pageSize is the number of items requested per page by Pageable resources
+const pageSize int32 = 100
+
+// Highlighter is implemented by resources flagging some of their rows, e.g. stale access keys
+type Highlighter interface {
+	// Highlight reports whether the row at the given index needs attention
+	Highlight(index int) bool
+}
+
+// Streamer is implemented by resources following an operation, e.g. the events of a stack
+// being deleted, which are refreshed more often while it runs, even with auto-refresh off
+type Streamer interface {
+	// Streaming reports whether the operation is still running
+	Streaming() bool
+}
+
+// TagEditor is implemented by resources whose rows can be tagged, through the tagging API of their service
+type TagEditor interface {
+	// Tags returns the tags of the resource with the given ID
+	Tags(ctx context.Context, c *client.Client, id string) (map[string]string, error)
+	// SetTags adds the tags to the resource, or updates their values
+	SetTags(ctx context.Context, c *client.Client, id string, tags map[string]string) error
+	// RemoveTags removes the tags with the given keys from the resource
+	RemoveTags(ctx context.Context, c *client.Client, id string, keys []string) error
+}
+
+// Category groups resource types in the menu
+type Category string
+
+// Resource categories, in menu order
+const (
+	CategoryCompute     Category = "compute"
+	CategoryData        Category = "data"
+	CategoryNetwork     Category = "network"
+	CategorySecurity    Category = "security"
+	CategoryIntegration Category = "integration"
+	CategoryManagement  Category = "management"
+)
+
+// Categories returns all categories in menu order
+func Categories() []Category {
+	return []Category{
+		CategoryCompute,
+		CategoryData,
+		CategoryNetwork,
+		CategorySecurity,
+		CategoryIntegration,
+		CategoryManagement,
+	}
+}
+
+// Metadata describes a registered resource type
+type Metadata struct {
+	Category    Category
+	Description string
+	Permissions []string // IAM actions required to list the resource
+}
+
 // Registry holds all available resource types
 type Registry struct {
 	resources map[string]Resource
+	metadata  map[string]Metadata
 }
 
 // NewRegistry creates a new resource registry
 func NewRegistry() *Registry {
 	return &Registry{
 		resources: make(map[string]Resource),
+		metadata:  make(map[string]Metadata),
 	}
 }
 
-// Register adds a resource to the registry
-func (r *Registry) Register(key string, resource Resource) {
+// Register adds a resource and its metadata to the registry
+func (r *Registry) Register(key string, resource Resource, meta Metadata) {
 	r.resources[key] = resource
+	r.metadata[key] = meta
 }
 
 // Get returns a resource by key
@@ -67,6 +228,12 @@ func (r *Registry) Get(key string) (Resource, bool) {
 	return res, ok
 }
 
+// Metadata returns the metadata of a resource by key
+func (r *Registry) Metadata(key string) (Metadata, bool) {
+	meta, ok := r.metadata[key]
+	return meta, ok
+}
+
 // List returns all registered resource keys
 func (r *Registry) List() []string {
 	keys := make([]string, 0, len(r.resources))
@@ -76,36 +243,286 @@ func (r *Registry) List() []string {
 	return keys
 }
 
+// MetricsToggler is implemented by resources with optional CloudWatch metric columns,
+// which are slower to fetch and therefore off by default
+type MetricsToggler interface {
+	// ToggleMetrics switches the metric columns on or off and reports whether they are on
+	ToggleMetrics() bool
+}
+
+// Options tunes the default resources
+type Options struct {
+	// KeyMaxAge is the age above which IAM access keys are highlighted
+	KeyMaxAge time.Duration
+
+	// RequiredTags are the tags checked by the tag compliance report
+	RequiredTags []string
+
+	// SSHRules set the user and key used to ssh into EC2 instances
+	SSHRules []SSHRule
+
+	// Accounts are the roles of the account switcher, OrganizationRole the role assumed in
+	// the discovered accounts of the organization
+	Accounts         []AccountRole
+	OrganizationRole string
+}
+
 // DefaultRegistry creates a registry with all default resources
-func DefaultRegistry() *Registry {
+func DefaultRegistry(opts Options) *Registry {
 	reg := NewRegistry()
-	reg.Register("ec2", NewEC2Instances())
-	reg.Register("s3", NewS3Buckets())
-	reg.Register("lambda", NewLambdaFunctions())
-	reg.Register("ecs", NewECSClusters())
-	reg.Register("eks", NewEKSClusters())
-	reg.Register("rds", NewRDSInstances())
-	reg.Register("acm", NewACMCertificates())
-	reg.Register("billing", NewBilling())
-	reg.Register("cloudfront", NewCloudFrontDistributions())
-	reg.Register("alb", NewALBs())
-	reg.Register("dynamodb", NewDynamoDBTables())
-	reg.Register("secrets", NewSecrets())
-	reg.Register("kms", NewKMSKeys())
-	reg.Register("ecr", NewECRRepositories())
-	reg.Register("cognito", NewCognitoUserPools())
-	reg.Register("iam-users", NewIAMUsers())
-	reg.Register("iam-roles", NewIAMRoles())
-	reg.Register("iam-policies", NewIAMPolicies())
-	reg.Register("vpc", NewVPCs())
-	reg.Register("subnets", NewSubnets())
-	reg.Register("security-groups", NewSecurityGroups())
-	reg.Register("sqs", NewSQSQueues())
-	reg.Register("sns", NewSNSTopics())
-	reg.Register("api-gateway", NewRestAPIs())
-	reg.Register("api-gateway-v2", NewHttpAPIs())
-	reg.Register("elasticache-clusters", NewElastiCacheClusters())
-	reg.Register("elasticache-groups", NewElastiCacheReplicationGroups())
-	reg.Register("route53", NewHostedZones())
+	reg.Register("ec2", NewEC2Instances(opts.SSHRules), Metadata{
+		Category:    CategoryCompute,
+		Description: "EC2 instances",
+		Permissions: []string{"ec2:DescribeInstances"},
+	})
+	reg.Register("spot", NewSpotRequests(), Metadata{
+		Category:    CategoryCompute,
+		Description: "Spot instance requests and spot fleets",
+		Permissions: []string{"ec2:DescribeSpotInstanceRequests", "ec2:DescribeSpotFleetRequests"},
+	})
+	reg.Register("ebs", NewEBSVolumes(), Metadata{
+		Category:    CategoryCompute,
+		Description: "EBS volumes",
+		Permissions: []string{"ec2:DescribeVolumes"},
+	})
+	reg.Register("s3", NewS3Buckets(), Metadata{
+		Category:    CategoryData,
+		Description: "S3 buckets and objects",
+		Permissions: []string{"s3:ListAllMyBuckets", "s3:GetBucketLocation", "cloudwatch:GetMetricData"},
+	})
+	reg.Register("lambda", NewLambdaFunctions(), Metadata{
+		Category:    CategoryCompute,
+		Description: "Lambda functions, versions and aliases",
+		Permissions: []string{"lambda:ListFunctions"},
+	})
+	reg.Register("ecs", NewECSClusters(), Metadata{
+		Category:    CategoryCompute,
+		Description: "ECS clusters and their services",
+		Permissions: []string{"ecs:ListClusters", "ecs:DescribeClusters"},
+	})
+	reg.Register("ecs-taskdefs", NewECSTaskDefinitions(), Metadata{
+		Category:    CategoryCompute,
+		Description: "Latest ECS task definitions, with their image provenance",
+		Permissions: []string{"ecs:ListTaskDefinitions"},
+	})
+	reg.Register("eks", NewEKSClusters(), Metadata{
+		Category:    CategoryCompute,
+		Description: "EKS clusters, node groups and Fargate profiles",
+		Permissions: []string{"eks:ListClusters", "eks:DescribeCluster"},
+	})
+	reg.Register("rds", NewRDSInstances(), Metadata{
+		Category:    CategoryData,
+		Description: "RDS database instances",
+		Permissions: []string{"rds:DescribeDBInstances"},
+	})
+	reg.Register("rds-clusters", NewRDSClusters(), Metadata{
+		Category:    CategoryData,
+		Description: "RDS Aurora and Multi-AZ DB clusters",
+		Permissions: []string{"rds:DescribeDBClusters"},
+	})
+	reg.Register("rds-params", NewRDSConfigGroups(), Metadata{
+		Category:    CategoryData,
+		Description: "RDS parameter and option groups, with their non-default settings",
+		Permissions: []string{"rds:DescribeDBInstances", "rds:DescribeDBParameterGroups", "rds:DescribeOptionGroups"},
+	})
+	reg.Register("acm", NewACMCertificates(), Metadata{
+		Category:    CategorySecurity,
+		Description: "ACM certificates",
+		Permissions: []string{"acm:ListCertificates", "acm:DescribeCertificate"},
+	})
+	reg.Register("billing", NewBilling(), Metadata{
+		Category:    CategoryManagement,
+		Description: "Cost of the current month per service",
+		Permissions: []string{"ce:GetCostAndUsage"},
+	})
+	reg.Register("billing-anomalies", NewBillingAnomalies(), Metadata{
+		Category:    CategoryManagement,
+		Description: "Cost anomalies of the last 30 days",
+		Permissions: []string{"ce:GetAnomalies"},
+	})
+	reg.Register("commitments", NewCommitments(), Metadata{
+		Category:    CategoryManagement,
+		Description: "Active Reserved Instances and Savings Plans with their utilization",
+		Permissions: []string{"ec2:DescribeReservedInstances", "savingsplans:DescribeSavingsPlans"},
+	})
+	reg.Register("cloudfront", NewCloudFrontDistributions(), Metadata{
+		Category:    CategoryNetwork,
+		Description: "CloudFront distributions",
+		Permissions: []string{"cloudfront:ListDistributions"},
+	})
+	reg.Register("alb", NewALBs(), Metadata{
+		Category:    CategoryNetwork,
+		Description: "Application and network load balancers, their listeners and target health",
+		Permissions: []string{"elasticloadbalancing:DescribeLoadBalancers"},
+	})
+	reg.Register("dynamodb", NewDynamoDBTables(), Metadata{
+		Category:    CategoryData,
+		Description: "DynamoDB tables and items",
+		Permissions: []string{"dynamodb:ListTables", "dynamodb:DescribeTable"},
+	})
+	reg.Register("secrets", NewSecrets(), Metadata{
+		Category:    CategorySecurity,
+		Description: "Secrets Manager secrets",
+		Permissions: []string{"secretsmanager:ListSecrets", "kms:ListAliases"},
+	})
+	reg.Register("ssm-params", NewSSMParameters(), Metadata{
+		Category:    CategorySecurity,
+		Description: "Systems Manager parameters",
+		Permissions: []string{"ssm:DescribeParameters"},
+	})
+	reg.Register("kms", NewKMSKeys(), Metadata{
+		Category:    CategorySecurity,
+		Description: "KMS keys and aliases",
+		Permissions: []string{"kms:ListKeys", "kms:ListAliases", "kms:DescribeKey"},
+	})
+	reg.Register("ecr", NewECRRepositories(), Metadata{
+		Category:    CategoryCompute,
+		Description: "ECR container repositories",
+		Permissions: []string{"ecr:DescribeRepositories", "ecr:DescribeImages"},
+	})
+	reg.Register("cognito", NewCognitoUserPools(), Metadata{
+		Category:    CategorySecurity,
+		Description: "Cognito user pools, with user export",
+		Permissions: []string{"cognito-idp:ListUserPools", "cognito-idp:DescribeUserPool"},
+	})
+	reg.Register("iam-users", NewIAMUsers(), Metadata{
+		Category:    CategorySecurity,
+		Description: "IAM users",
+		Permissions: []string{"iam:ListUsers"},
+	})
+	reg.Register("iam-roles", NewIAMRoles(), Metadata{
+		Category:    CategorySecurity,
+		Description: "IAM roles",
+		Permissions: []string{"iam:ListRoles"},
+	})
+	reg.Register("iam-groups", NewIAMGroups(), Metadata{
+		Category:    CategorySecurity,
+		Description: "IAM groups",
+		Permissions: []string{"iam:ListGroups"},
+	})
+	reg.Register("iam-keys", NewIAMAccessKeys(opts.KeyMaxAge), Metadata{
+		Category:    CategorySecurity,
+		Description: "Access keys of all IAM users with their age and last use",
+		Permissions: []string{"iam:ListUsers", "iam:ListAccessKeys", "iam:GetAccessKeyLastUsed"},
+	})
+	reg.Register("iam-policies", NewIAMPolicies(), Metadata{
+		Category:    CategorySecurity,
+		Description: "Customer managed IAM policies",
+		Permissions: []string{"iam:ListPolicies"},
+	})
+	reg.Register("vpc", NewVPCs(), Metadata{
+		Category:    CategoryNetwork,
+		Description: "VPCs",
+		Permissions: []string{"ec2:DescribeVpcs"},
+	})
+	reg.Register("subnets", NewSubnets(), Metadata{
+		Category:    CategoryNetwork,
+		Description: "VPC subnets",
+		Permissions: []string{"ec2:DescribeSubnets"},
+	})
+	reg.Register("security-groups", NewSecurityGroups(), Metadata{
+		Category:    CategoryNetwork,
+		Description: "EC2 security groups and their rules",
+		Permissions: []string{"ec2:DescribeSecurityGroups"},
+	})
+	reg.Register("eip", NewElasticIPs(), Metadata{
+		Category:    CategoryNetwork,
+		Description: "Elastic IP addresses",
+		Permissions: []string{"ec2:DescribeAddresses"},
+	})
+	reg.Register("sqs", NewSQSQueues(), Metadata{
+		Category:    CategoryIntegration,
+		Description: "SQS queues, with backlog sparklines",
+		Permissions: []string{"sqs:ListQueues", "sqs:GetQueueAttributes"},
+	})
+	reg.Register("sns", NewSNSTopics(), Metadata{
+		Category:    CategoryIntegration,
+		Description: "SNS topics and their subscriptions",
+		Permissions: []string{"sns:ListTopics", "sns:GetTopicAttributes", "sns:ListSubscriptionsByTopic"},
+	})
+	reg.Register("eventbridge", NewEventRules(), Metadata{
+		Category:    CategoryIntegration,
+		Description: "EventBridge rules of all the event buses",
+		Permissions: []string{"events:ListEventBuses", "events:ListRules"},
+	})
+	reg.Register("sfn", NewStateMachines(), Metadata{
+		Category:    CategoryIntegration,
+		Description: "Step Functions state machines, executions and their history",
+		Permissions: []string{"states:ListStateMachines", "states:ListExecutions", "states:DescribeExecution", "states:GetExecutionHistory"},
+	})
+	reg.Register("api-gateway", NewRestAPIs(), Metadata{
+		Category:    CategoryIntegration,
+		Description: "API Gateway REST APIs, stages and resources",
+		Permissions: []string{"apigateway:GET"},
+	})
+	reg.Register("api-gateway-v2", NewHttpAPIs(), Metadata{
+		Category:    CategoryIntegration,
+		Description: "API Gateway HTTP and WebSocket APIs, stages and routes",
+		Permissions: []string{"apigateway:GET"},
+	})
+	reg.Register("dms", NewDMSReplicationInstances(), Metadata{
+		Category:    CategoryData,
+		Description: "DMS replication instances and their tasks",
+		Permissions: []string{"dms:DescribeReplicationInstances", "dms:DescribeReplicationTasks", "dms:DescribeTableStatistics"},
+	})
+	reg.Register("dms-tasks", NewDMSReplicationTasks("", ""), Metadata{
+		Category:    CategoryData,
+		Description: "DMS replication tasks with their progress and table statistics",
+		Permissions: []string{"dms:DescribeReplicationTasks", "dms:DescribeTableStatistics"},
+	})
+	reg.Register("elasticache-clusters", NewElastiCacheClusters(), Metadata{
+		Category:    CategoryData,
+		Description: "ElastiCache clusters",
+		Permissions: []string{"elasticache:DescribeCacheClusters"},
+	})
+	reg.Register("elasticache-groups", NewElastiCacheReplicationGroups(), Metadata{
+		Category:    CategoryData,
+		Description: "ElastiCache replication groups",
+		Permissions: []string{"elasticache:DescribeReplicationGroups"},
+	})
+	reg.Register("elasticache-serverless", NewElastiCacheServerlessCaches(), Metadata{
+		Category:    CategoryData,
+		Description: "ElastiCache serverless caches with their usage limits",
+		Permissions: []string{"elasticache:DescribeServerlessCaches"},
+	})
+	reg.Register("route53", NewHostedZones(), Metadata{
+		Category:    CategoryNetwork,
+		Description: "Route53 hosted zones and their records",
+		Permissions: []string{"route53:ListHostedZones"},
+	})
+	reg.Register("cloudformation", NewCloudFormationStacks(), Metadata{
+		Category:    CategoryManagement,
+		Description: "CloudFormation stacks and their events",
+		Permissions: []string{"cloudformation:DescribeStacks", "cloudformation:DescribeStackEvents"},
+	})
+	accountPermissions := []string{"sts:AssumeRole"}
+	if opts.OrganizationRole != "" {
+		accountPermissions = append(accountPermissions, "organizations:ListAccounts")
+	}
+	reg.Register("accounts", NewAccounts(opts.Accounts, opts.OrganizationRole), Metadata{
+		Category:    CategoryManagement,
+		Description: "Accounts of the configuration and the organization, switched to by assuming a role",
+		Permissions: accountPermissions,
+	})
+	reg.Register("advisor", NewTrustedAdvisorChecks(), Metadata{
+		Category:    CategoryManagement,
+		Description: "Trusted Advisor checks with their flagged resources, needs a Business or Enterprise support plan",
+		Permissions: []string{"support:DescribeTrustedAdvisorChecks", "support:DescribeTrustedAdvisorCheckSummaries"},
+	})
+	reg.Register("cleanup", NewCleanup(opts.KeyMaxAge), Metadata{
+		Category:    CategoryManagement,
+		Description: "Orphaned volumes, Elastic IPs, load balancers, default VPCs and unused access keys to tick and delete",
+		Permissions: []string{
+			"ec2:DescribeVolumes", "ec2:DescribeAddresses", "ec2:DescribeVpcs", "ec2:DescribeNetworkInterfaces",
+			"elasticloadbalancing:DescribeLoadBalancers", "elasticloadbalancing:DescribeTargetGroups",
+			"elasticloadbalancing:DescribeTargetHealth", "elasticloadbalancing:DescribeListeners",
+			"elasticloadbalancing:DescribeRules", "iam:ListUsers", "iam:ListAccessKeys", "iam:GetAccessKeyLastUsed",
+		},
+	})
+	reg.Register("tag-compliance", NewTagCompliance(opts.RequiredTags), Metadata{
+		Category:    CategoryManagement,
+		Description: "Resources missing required tags",
+		Permissions: []string{"tag:GetResources"},
+	})
 	return reg
 }
@@ -1,15 +1,25 @@
 package resources
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 
 	"a9s/internal/client"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/route53"
+	route53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
 )
 
+// route53ChangeBatchSize is the maximum number of changes in a single change batch
+const route53ChangeBatchSize = 1000
+
 // HostedZone represents a Route53 hosted zone
 type HostedZone struct {
 	ID             string
@@ -115,5 +125,401 @@ func (h *HostedZones) GetID(index int) string {
 
 // QuickActions returns the available quick actions for Route53 hosted zones
 func (h *HostedZones) QuickActions() []QuickAction {
-	return []QuickAction{}
+	return []QuickAction{
+		{
+			Key:            'e',
+			Label:          "export",
+			Description:    "Export records to a zone file or .json file",
+			NeedsSelection: true,
+			ReadOnly:       true,
+			InputLabel:     "File: ",
+			InputDefault: func(zoneID string) string {
+				for _, zone := range h.zones {
+					if zone.ID == zoneID {
+						return strings.TrimSuffix(zone.Name, ".") + ".zone"
+					}
+				}
+				return zoneID + ".zone"
+			},
+			InputHandler: exportZoneRecords,
+		},
+		{
+			Key:            'i',
+			Label:          "import",
+			Description:    "Import records from a zone file or .json file",
+			NeedsSelection: true,
+			InputLabel:     "File: ",
+			InputPlan:      planImportZoneRecords,
+		},
+	}
+}
+
+// DrillDown opens the record sets of the hosted zone
+func (h *HostedZones) DrillDown(zoneID string) Resource {
+	for _, zone := range h.zones {
+		if zone.ID == zoneID {
+			return NewRecordSets(zoneID, zone.Name)
+		}
+	}
+	return nil
+}
+
+// zoneRecords is the JSON export of a hosted zone, in the format of the AWS CLI
+type zoneRecords struct {
+	ResourceRecordSets []route53types.ResourceRecordSet
+}
+
+// hostedZoneName returns the name of a hosted zone, with its trailing dot
+func hostedZoneName(ctx context.Context, c *client.Client, zoneID string) (string, error) {
+	output, err := c.Route53().GetHostedZone(ctx, &route53.GetHostedZoneInput{Id: &zoneID})
+	if err != nil {
+		return "", fmt.Errorf("failed to get hosted zone %s: %w", zoneID, err)
+	}
+	return stringValue(output.HostedZone.Name), nil
+}
+
+// listZoneRecords retrieves all the record sets of a hosted zone
+func listZoneRecords(ctx context.Context, c *client.Client, zoneID string) ([]route53types.ResourceRecordSet, error) {
+	records := make([]route53types.ResourceRecordSet, 0)
+
+	paginator := route53.NewListResourceRecordSetsPaginator(c.Route53(), &route53.ListResourceRecordSetsInput{
+		HostedZoneId: &zoneID,
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list records of %s: %w", zoneID, err)
+		}
+		records = append(records, output.ResourceRecordSets...)
+	}
+
+	return records, nil
+}
+
+// exportZoneRecords writes the records of a hosted zone to a file, as JSON when
+// its extension is .json and as a BIND zone file otherwise
+func exportZoneRecords(ctx context.Context, c *client.Client, zoneID, path string) error {
+	zoneName, err := hostedZoneName(ctx, c, zoneID)
+	if err != nil {
+		return err
+	}
+	records, err := listZoneRecords(ctx, c, zoneID)
+	if err != nil {
+		return err
+	}
+
+	path, err = expandHome(path)
+	if err != nil {
+		return err
+	}
+	// An existing file is never overwritten
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(zoneRecords{ResourceRecordSets: records}); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		return nil
+	}
+
+	writer := bufio.NewWriter(file)
+	fmt.Fprintf(writer, "$ORIGIN %s\n", zoneName)
+	for _, record := range records {
+		name := recordName(stringValue(record.Name))
+
+		// Alias and routing policy records have no zone file syntax, keep them as comments
+		switch {
+		case record.AliasTarget != nil:
+			fmt.Fprintf(writer, "; %s ALIAS %s %s\n", name, record.Type, stringValue(record.AliasTarget.DNSName))
+			continue
+		case record.SetIdentifier != nil:
+			fmt.Fprintf(writer, "; %s %s routing policy record %s\n", name, record.Type, stringValue(record.SetIdentifier))
+			continue
+		}
+
+		for _, value := range record.ResourceRecords {
+			fmt.Fprintf(writer, "%s\t%d\tIN\t%s\t%s\n", name, ptrInt64Value(record.TTL), record.Type, stringValue(value.Value))
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// recordName normalizes a record name for comparison and display, Route53 escapes wildcards
+func recordName(name string) string {
+	name = strings.ReplaceAll(name, `\052`, "*")
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+	return strings.ToLower(name)
+}
+
+// recordKey identifies a record set in a hosted zone
+func recordKey(record route53types.ResourceRecordSet) string {
+	return fmt.Sprintf("%s %s %s", recordName(stringValue(record.Name)), record.Type, stringValue(record.SetIdentifier))
+}
+
+// sameRecord reports whether two record sets of the same key have the same values
+func sameRecord(a, b route53types.ResourceRecordSet) bool {
+	a.Name = aws.String(recordName(stringValue(a.Name)))
+	b.Name = aws.String(recordName(stringValue(b.Name)))
+	left, _ := json.Marshal(a)
+	right, _ := json.Marshal(b)
+	return string(left) == string(right)
+}
+
+// planImportZoneRecords previews the records of a file that differ from the hosted
+// zone, the records missing from the file are left untouched
+func planImportZoneRecords(ctx context.Context, c *client.Client, zoneID, path string) ([]Step, error) {
+	zoneName, err := hostedZoneName(ctx, c, zoneID)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err = expandHome(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var imported []route53types.ResourceRecordSet
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		var records zoneRecords
+		if err := json.Unmarshal(data, &records); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		imported = records.ResourceRecordSets
+	} else {
+		imported, err = parseZoneFile(string(data), zoneName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+
+	existing, err := listZoneRecords(ctx, c, zoneID)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]route53types.ResourceRecordSet, len(existing))
+	for _, record := range existing {
+		current[recordKey(record)] = record
+	}
+
+	steps := make([]Step, 0)
+	changes := make([]route53types.Change, 0)
+	unchanged := 0
+	for _, record := range imported {
+		name := recordName(stringValue(record.Name))
+
+		// The SOA and apex NS records belong to the hosted zone itself
+		if record.Type == route53types.RRTypeSoa || (record.Type == route53types.RRTypeNs && name == recordName(zoneName)) {
+			continue
+		}
+
+		verb := "Create"
+		if old, ok := current[recordKey(record)]; ok {
+			if sameRecord(old, record) {
+				unchanged++
+				continue
+			}
+			verb = "Update"
+		}
+
+		values := make([]string, 0, len(record.ResourceRecords))
+		for _, value := range record.ResourceRecords {
+			values = append(values, stringValue(value.Value))
+		}
+		if record.AliasTarget != nil {
+			values = append(values, "alias "+stringValue(record.AliasTarget.DNSName))
+		}
+		steps = append(steps, Step{Description: fmt.Sprintf("%s %s %s %s", verb, name, record.Type, strings.Join(values, ", "))})
+		changes = append(changes, route53types.Change{
+			Action:            route53types.ChangeActionUpsert,
+			ResourceRecordSet: &record,
+		})
+	}
+
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("%d records of %s already match the hosted zone, nothing to import", unchanged, path)
+	}
+	if unchanged > 0 {
+		steps = append(steps, Step{Description: fmt.Sprintf("%d records are unchanged", unchanged)})
+	}
+
+	for start := 0; start < len(changes); start += route53ChangeBatchSize {
+		batch := changes[start:min(start+route53ChangeBatchSize, len(changes))]
+		steps = append(steps, Step{
+			Description: fmt.Sprintf("Apply %d changes to %s", len(batch), zoneName),
+			Run: func(ctx context.Context, c *client.Client) error {
+				_, err := c.Route53().ChangeResourceRecordSets(ctx, &route53.ChangeResourceRecordSetsInput{
+					HostedZoneId: &zoneID,
+					ChangeBatch: &route53types.ChangeBatch{
+						Comment: aws.String(fmt.Sprintf("Imported from %s", filepath.Base(path))),
+						Changes: batch,
+					},
+				})
+				return err
+			},
+		})
+	}
+
+	return steps, nil
+}
+
+// parseZoneFile parses the records of a BIND zone file, values of the same name and
+// type are grouped into one record set
+func parseZoneFile(data, origin string) ([]route53types.ResourceRecordSet, error) {
+	records := make([]route53types.ResourceRecordSet, 0)
+	index := make(map[string]int)
+
+	ttl := int64(300)
+	previous := origin
+	for number, line := range zoneFileLines(data) {
+		fields := zoneFileFields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch strings.ToUpper(fields[0]) {
+		case "$ORIGIN":
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("entry %d: missing origin", number+1)
+			}
+			origin = absoluteName(fields[1], origin)
+			continue
+		case "$TTL":
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("entry %d: missing TTL", number+1)
+			}
+			value, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("entry %d: invalid TTL %s", number+1, fields[1])
+			}
+			ttl = value
+			continue
+		}
+
+		// A line starting with a blank continues the previous name
+		name := previous
+		if line[0] != ' ' && line[0] != '\t' {
+			name = absoluteName(fields[0], origin)
+			fields = fields[1:]
+		}
+		previous = name
+
+		recordTTL := ttl
+		for len(fields) > 0 {
+			if value, err := strconv.ParseInt(fields[0], 10, 64); err == nil {
+				recordTTL = value
+			} else if !strings.EqualFold(fields[0], "IN") {
+				break
+			}
+			fields = fields[1:]
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("entry %d: missing type or value", number+1)
+		}
+
+		recordType := route53types.RRType(strings.ToUpper(fields[0]))
+		value := route53types.ResourceRecord{Value: aws.String(strings.Join(fields[1:], " "))}
+
+		key := name + " " + string(recordType)
+		if i, ok := index[key]; ok {
+			records[i].ResourceRecords = append(records[i].ResourceRecords, value)
+			continue
+		}
+		index[key] = len(records)
+		records = append(records, route53types.ResourceRecordSet{
+			Name:            aws.String(name),
+			Type:            recordType,
+			TTL:             aws.Int64(recordTTL),
+			ResourceRecords: []route53types.ResourceRecord{value},
+		})
+	}
+
+	return records, nil
+}
+
+// zoneFileLines returns the entries of a zone file without comments, entries
+// spanning several lines between parentheses are joined
+func zoneFileLines(data string) []string {
+	lines := make([]string, 0)
+	var entry strings.Builder
+	depth := 0
+	for _, line := range strings.Split(data, "\n") {
+		inQuote := false
+		for _, r := range line {
+			if r == ';' && !inQuote {
+				break
+			}
+			switch {
+			case r == '"':
+				inQuote = !inQuote
+			case r == '(' && !inQuote:
+				depth++
+				r = ' '
+			case r == ')' && !inQuote:
+				depth--
+				r = ' '
+			}
+			entry.WriteRune(r)
+		}
+		if depth > 0 {
+			entry.WriteRune(' ')
+			continue
+		}
+		if strings.TrimSpace(entry.String()) != "" {
+			lines = append(lines, strings.TrimRight(entry.String(), " \t\r"))
+		}
+		entry.Reset()
+	}
+	return lines
+}
+
+// zoneFileFields splits a zone file entry on blanks, quoted strings are kept whole
+func zoneFileFields(line string) []string {
+	fields := make([]string, 0)
+	var field strings.Builder
+	inQuote := false
+	for _, r := range line {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			field.WriteRune(r)
+		case (r == ' ' || r == '\t' || r == '\r') && !inQuote:
+			if field.Len() > 0 {
+				fields = append(fields, field.String())
+				field.Reset()
+			}
+		default:
+			field.WriteRune(r)
+		}
+	}
+	if field.Len() > 0 {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
+
+// absoluteName resolves a zone file name against the origin
+func absoluteName(name, origin string) string {
+	switch {
+	case name == "@":
+		return origin
+	case strings.HasSuffix(name, "."):
+		return name
+	}
+	return name + "." + origin
 }
@@ -0,0 +1,441 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	route53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+)
+
+// route53ChangeTimeout is how long a submitted change is polled before giving up on INSYNC
+const route53ChangeTimeout = 3 * time.Minute
+
+// editableRecordTypes are the record types of the simple records the editor handles
+var editableRecordTypes = []route53types.RRType{
+	route53types.RRTypeA,
+	route53types.RRTypeAaaa,
+	route53types.RRTypeCname,
+	route53types.RRTypeTxt,
+}
+
+// RecordSets implements Resource for the record sets of a hosted zone
+type RecordSets struct {
+	zoneID   string
+	zoneName string
+	records  []route53types.ResourceRecordSet
+}
+
+// NewRecordSets creates a new RecordSets resource, zoneName ends with a dot
+func NewRecordSets(zoneID, zoneName string) *RecordSets {
+	return &RecordSets{
+		zoneID:   zoneID,
+		zoneName: zoneName,
+		records:  make([]route53types.ResourceRecordSet, 0),
+	}
+}
+
+// Name returns the display name
+func (r *RecordSets) Name() string {
+	return fmt.Sprintf("Route53 Records (%s)", r.zoneName)
+}
+
+// Columns returns the column definitions
+func (r *RecordSets) Columns() []Column {
+	return []Column{
+		{Name: "Name", Width: 40},
+		{Name: "Type", Width: 8},
+		{Name: "TTL", Width: 8},
+		{Name: "Values", Width: 60},
+		{Name: "Routing", Width: 20},
+	}
+}
+
+// Fetch retrieves the record sets of the hosted zone
+func (r *RecordSets) Fetch(ctx context.Context, c *client.Client) error {
+	r.records = make([]route53types.ResourceRecordSet, 0)
+
+	records, err := listZoneRecords(ctx, c, r.zoneID)
+	if err != nil {
+		return err
+	}
+	r.records = records
+
+	return nil
+}
+
+// recordValues returns the values of a record set, or its alias target
+func recordValues(record route53types.ResourceRecordSet) []string {
+	if record.AliasTarget != nil {
+		return []string{"alias " + stringValue(record.AliasTarget.DNSName)}
+	}
+	values := make([]string, 0, len(record.ResourceRecords))
+	for _, value := range record.ResourceRecords {
+		values = append(values, stringValue(value.Value))
+	}
+	return values
+}
+
+// Rows returns the table data
+func (r *RecordSets) Rows() [][]string {
+	rows := make([][]string, len(r.records))
+	for i, record := range r.records {
+		ttl := ""
+		if record.TTL != nil {
+			ttl = fmt.Sprintf("%d", *record.TTL)
+		}
+		rows[i] = []string{
+			recordName(stringValue(record.Name)),
+			string(record.Type),
+			ttl,
+			strings.Join(recordValues(record), ", "),
+			stringValue(record.SetIdentifier),
+		}
+	}
+	return rows
+}
+
+// GetID returns the record key at the given index
+func (r *RecordSets) GetID(index int) string {
+	if index >= 0 && index < len(r.records) {
+		return recordKey(r.records[index])
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for record sets
+func (r *RecordSets) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:          'c',
+			Label:        "create",
+			Description:  "Create a record in the editor",
+			EditTemplate: r.newRecordTemplate,
+			EditPlan: func(ctx context.Context, c *client.Client, _, text string) ([]Step, error) {
+				return r.planPutRecord(nil, text)
+			},
+		},
+		{
+			Key:            'e',
+			Label:          "edit",
+			Description:    "Edit the record in the editor",
+			NeedsSelection: true,
+			EditTemplate: func(key string) string {
+				record, err := r.editableRecord(key)
+				if err != nil {
+					return "# " + err.Error() + "\n"
+				}
+				return r.recordTemplate(record)
+			},
+			EditPlan: func(ctx context.Context, c *client.Client, key, text string) ([]Step, error) {
+				record, err := r.editableRecord(key)
+				if err != nil {
+					return nil, err
+				}
+				return r.planPutRecord(&record, text)
+			},
+		},
+		{
+			Key:            'D',
+			Label:          "delete",
+			Description:    "Delete the record",
+			NeedsSelection: true,
+			Plan: func(ctx context.Context, c *client.Client, key string) ([]Step, error) {
+				record, err := r.editableRecord(key)
+				if err != nil {
+					return nil, err
+				}
+				return r.planChange(fmt.Sprintf("Delete %s", describeRecord(record)), []route53types.Change{
+					{Action: route53types.ChangeActionDelete, ResourceRecordSet: &record},
+				}), nil
+			},
+		},
+	}
+}
+
+// editableRecord returns the listed record set with the given key, if the editor handles it
+func (r *RecordSets) editableRecord(key string) (route53types.ResourceRecordSet, error) {
+	for _, record := range r.records {
+		if recordKey(record) != key {
+			continue
+		}
+		switch {
+		case record.SetIdentifier != nil:
+			return record, fmt.Errorf("routing policy records cannot be edited here")
+		case record.Type == route53types.RRTypeNs && recordName(stringValue(record.Name)) == recordName(r.zoneName),
+			record.Type == route53types.RRTypeSoa:
+			return record, fmt.Errorf("the SOA and apex NS records belong to the hosted zone")
+		case !isEditableRecordType(record.Type):
+			return record, fmt.Errorf("%s records cannot be edited here", record.Type)
+		}
+		return record, nil
+	}
+	return route53types.ResourceRecordSet{}, fmt.Errorf("record %s is not listed", key)
+}
+
+// isEditableRecordType reports whether the editor handles a record type
+func isEditableRecordType(recordType route53types.RRType) bool {
+	for _, editable := range editableRecordTypes {
+		if recordType == editable {
+			return true
+		}
+	}
+	return false
+}
+
+// describeRecord describes a record set on one line for the plan steps
+func describeRecord(record route53types.ResourceRecordSet) string {
+	return fmt.Sprintf("%s %s %s", recordName(stringValue(record.Name)), record.Type, strings.Join(recordValues(record), ", "))
+}
+
+// recordTemplateHelp explains the record template, shared by creation and edition
+const recordTemplateHelp = `# Lines starting with # are ignored. Type is one of A, AAAA, CNAME or TXT.
+# Values go one per line below the --- line, TXT values are quoted if needed.
+# For an alias, set Alias: to the target DNS name and hosted zone ID, e.g.
+# Alias: my-lb-1234.eu-west-1.elb.amazonaws.com. Z32O12XQLNTSW2, and leave the values empty.
+`
+
+// newRecordTemplate returns the editor template of a new record
+func (r *RecordSets) newRecordTemplate(string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# New record of %s, names are relative to the zone unless ending with a dot.\n", r.zoneName)
+	b.WriteString(recordTemplateHelp)
+	b.WriteString("Name: \n")
+	b.WriteString("Type: A\n")
+	b.WriteString("TTL: 300\n")
+	b.WriteString("Alias: \n")
+	b.WriteString("---\n")
+	return b.String()
+}
+
+// recordTemplate returns the editor template of an existing record
+func (r *RecordSets) recordTemplate(record route53types.ResourceRecordSet) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Record of %s, changing the name or type replaces it.\n", r.zoneName)
+	b.WriteString(recordTemplateHelp)
+	fmt.Fprintf(&b, "Name: %s\n", recordName(stringValue(record.Name)))
+	fmt.Fprintf(&b, "Type: %s\n", record.Type)
+	if record.AliasTarget != nil {
+		b.WriteString("TTL: \n")
+		fmt.Fprintf(&b, "Alias: %s %s\n", stringValue(record.AliasTarget.DNSName), stringValue(record.AliasTarget.HostedZoneId))
+		b.WriteString("---\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "TTL: %d\n", ptrInt64Value(record.TTL))
+	b.WriteString("Alias: \n")
+	b.WriteString("---\n")
+	for _, value := range record.ResourceRecords {
+		b.WriteString(stringValue(value.Value) + "\n")
+	}
+	return b.String()
+}
+
+// parseRecordTemplate parses and validates an edited record template
+func (r *RecordSets) parseRecordTemplate(text string) (route53types.ResourceRecordSet, error) {
+	var record route53types.ResourceRecordSet
+
+	head, body, ok := strings.Cut(text, "\n---\n")
+	if !ok {
+		head, body, ok = strings.Cut(text, "\n---")
+	}
+	if !ok {
+		return record, fmt.Errorf("the --- line separating the fields from the values is missing")
+	}
+
+	fields := make(map[string]string)
+	for _, line := range strings.Split(head, "\n") {
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+		field, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return record, fmt.Errorf("expected a Field: value line, got %q", line)
+		}
+		fields[strings.ToLower(strings.TrimSpace(field))] = strings.TrimSpace(value)
+	}
+
+	name := fields["name"]
+	if name == "" {
+		return record, fmt.Errorf("the Name: field is required")
+	}
+	name = recordName(absoluteName(name, r.zoneName))
+	if name != recordName(r.zoneName) && !strings.HasSuffix(name, "."+recordName(r.zoneName)) {
+		return record, fmt.Errorf("%s is not in the zone %s", name, r.zoneName)
+	}
+	record.Name = aws.String(name)
+
+	record.Type = route53types.RRType(strings.ToUpper(fields["type"]))
+	if !isEditableRecordType(record.Type) {
+		return record, fmt.Errorf("type must be one of A, AAAA, CNAME or TXT, got %q", fields["type"])
+	}
+
+	values := make([]string, 0)
+	for _, line := range strings.Split(body, "\n") {
+		if value := strings.TrimSpace(line); value != "" && !strings.HasPrefix(value, "#") {
+			values = append(values, value)
+		}
+	}
+
+	if alias := strings.Fields(fields["alias"]); len(alias) > 0 {
+		if len(alias) != 2 {
+			return record, fmt.Errorf("expected Alias: <DNS name> <hosted zone ID>, got %q", fields["alias"])
+		}
+		if len(values) > 0 {
+			return record, fmt.Errorf("an alias record has no values")
+		}
+		record.AliasTarget = &route53types.AliasTarget{
+			DNSName:      aws.String(alias[0]),
+			HostedZoneId: aws.String(alias[1]),
+		}
+		return record, nil
+	}
+
+	var ttl int64
+	if _, err := fmt.Sscanf(fields["ttl"], "%d", &ttl); err != nil || ttl < 0 {
+		return record, fmt.Errorf("TTL must be a number of seconds, got %q", fields["ttl"])
+	}
+	record.TTL = aws.Int64(ttl)
+
+	if len(values) == 0 {
+		return record, fmt.Errorf("at least one value is required")
+	}
+	if record.Type == route53types.RRTypeCname && len(values) > 1 {
+		return record, fmt.Errorf("a CNAME record has a single value")
+	}
+	for _, value := range values {
+		value, err := validateRecordValue(record.Type, value)
+		if err != nil {
+			return record, err
+		}
+		record.ResourceRecords = append(record.ResourceRecords, route53types.ResourceRecord{Value: aws.String(value)})
+	}
+
+	return record, nil
+}
+
+// validateRecordValue checks a value against its record type, and quotes TXT values
+func validateRecordValue(recordType route53types.RRType, value string) (string, error) {
+	switch recordType {
+	case route53types.RRTypeA:
+		if ip := net.ParseIP(value); ip == nil || ip.To4() == nil {
+			return "", fmt.Errorf("%s is not an IPv4 address", value)
+		}
+	case route53types.RRTypeAaaa:
+		if ip := net.ParseIP(value); ip == nil || ip.To4() != nil {
+			return "", fmt.Errorf("%s is not an IPv6 address", value)
+		}
+	case route53types.RRTypeCname:
+		if strings.ContainsAny(value, " \t\"") {
+			return "", fmt.Errorf("%s is not a hostname", value)
+		}
+	case route53types.RRTypeTxt:
+		if !strings.HasPrefix(value, `"`) {
+			value = `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+		}
+		// Route53 rejects strings over 255 characters, long values are split in several strings
+		for _, field := range zoneFileFields(value) {
+			if len(strings.Trim(field, `"`)) > 255 {
+				return "", fmt.Errorf("TXT strings are limited to 255 characters, split the value in several quoted strings")
+			}
+		}
+	}
+	return value, nil
+}
+
+// planPutRecord validates an edited record and returns the changes as steps, a record
+// whose name or type changed is deleted and created again in the same change batch
+func (r *RecordSets) planPutRecord(before *route53types.ResourceRecordSet, text string) ([]Step, error) {
+	after, err := r.parseRecordTemplate(text)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]bool, len(r.records))
+	for _, record := range r.records {
+		existing[recordKey(record)] = true
+	}
+
+	switch {
+	case before == nil:
+		if existing[recordKey(after)] {
+			return nil, fmt.Errorf("%s %s already exists, edit it instead", stringValue(after.Name), after.Type)
+		}
+		return r.planChange(fmt.Sprintf("Create %s", describeRecord(after)), []route53types.Change{
+			{Action: route53types.ChangeActionCreate, ResourceRecordSet: &after},
+		}), nil
+	case recordKey(*before) == recordKey(after):
+		if sameRecord(*before, after) {
+			return nil, fmt.Errorf("the record is unchanged")
+		}
+		return r.planChange(fmt.Sprintf("Update %s → %s", describeRecord(*before), strings.Join(recordValues(after), ", ")), []route53types.Change{
+			{Action: route53types.ChangeActionUpsert, ResourceRecordSet: &after},
+		}), nil
+	default:
+		if existing[recordKey(after)] {
+			return nil, fmt.Errorf("%s %s already exists", stringValue(after.Name), after.Type)
+		}
+		return r.planChange(fmt.Sprintf("Replace %s with %s", describeRecord(*before), describeRecord(after)), []route53types.Change{
+			{Action: route53types.ChangeActionDelete, ResourceRecordSet: before},
+			{Action: route53types.ChangeActionCreate, ResourceRecordSet: &after},
+		}), nil
+	}
+}
+
+// planChange returns the steps submitting a change batch, then waiting for Route53 to
+// propagate it to all its servers
+func (r *RecordSets) planChange(description string, changes []route53types.Change) []Step {
+	var changeID *string
+	return []Step{
+		{
+			Description: description,
+			Run: func(ctx context.Context, c *client.Client) error {
+				output, err := c.Route53().ChangeResourceRecordSets(ctx, &route53.ChangeResourceRecordSetsInput{
+					HostedZoneId: &r.zoneID,
+					ChangeBatch:  &route53types.ChangeBatch{Changes: changes},
+				})
+				if err != nil {
+					return fmt.Errorf("failed to change records of %s: %w", r.zoneName, err)
+				}
+				changeID = output.ChangeInfo.Id
+				return nil
+			},
+		},
+		{
+			Description: "Wait for the change to be in sync on all Route53 servers",
+			Run: func(ctx context.Context, c *client.Client) error {
+				return waitRoute53Change(ctx, c, changeID)
+			},
+		},
+	}
+}
+
+// waitRoute53Change polls a change until Route53 reports it INSYNC
+func waitRoute53Change(ctx context.Context, c *client.Client, changeID *string) error {
+	if changeID == nil {
+		return fmt.Errorf("the change was not submitted")
+	}
+	deadline := time.Now().Add(route53ChangeTimeout)
+	for {
+		output, err := c.Route53().GetChange(ctx, &route53.GetChangeInput{Id: changeID})
+		if err != nil {
+			return fmt.Errorf("failed to get change %s: %w", stringValue(changeID), err)
+		}
+		if output.ChangeInfo.Status == route53types.ChangeStatusInsync {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("change %s still %s after %s", stringValue(changeID), output.ChangeInfo.Status, route53ChangeTimeout)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
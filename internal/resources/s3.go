@@ -2,12 +2,21 @@ package resources
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"a9s/internal/client"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 )
 
 // S3Bucket represents an S3 bucket
@@ -15,11 +24,15 @@ type S3Bucket struct {
 	Name         string
 	CreationDate string
 	Region       string
+	// Size and Objects are approximate, from the daily CloudWatch storage metrics
+	Size    string
+	Objects string
 }
 
 // S3Buckets implements Resource for S3 buckets
 type S3Buckets struct {
 	buckets []S3Bucket
+	metrics s3MetricsCache
 }
 
 // NewS3Buckets creates a new S3Buckets resource
@@ -40,6 +53,8 @@ func (s *S3Buckets) Columns() []Column {
 		{Name: "Name", Width: 50},
 		{Name: "Creation Date", Width: 25},
 		{Name: "Region", Width: 20},
+		{Name: "Size", Width: 12},
+		{Name: "Objects", Width: 12},
 	}
 }
 
@@ -76,6 +91,15 @@ func (s *S3Buckets) Fetch(ctx context.Context, c *client.Client) error {
 		s.buckets = append(s.buckets, b)
 	}
 
+	metrics := s.metrics.fetch(ctx, c, s.buckets)
+	for i := range s.buckets {
+		s.buckets[i].Size, s.buckets[i].Objects = "-", "-"
+		if bucketMetrics, ok := metrics[s.buckets[i].Name]; ok && bucketMetrics.known {
+			s.buckets[i].Size = formatSize(bucketMetrics.size)
+			s.buckets[i].Objects = fmt.Sprintf("%d", bucketMetrics.objects)
+		}
+	}
+
 	return nil
 }
 
@@ -87,6 +111,8 @@ func (s *S3Buckets) Rows() [][]string {
 			bucket.Name,
 			bucket.CreationDate,
 			bucket.Region,
+			bucket.Size,
+			bucket.Objects,
 		}
 	}
 	return rows
@@ -130,9 +156,110 @@ func (s *S3Buckets) QuickActions() []QuickAction {
 			ConfirmTemplate: "[red]Empty[-] bucket [white]%s[-]?\n\n[yellow]WARNING: This will permanently delete ALL objects!\nThis action cannot be undone!",
 			Handler:         s.EmptyBucket,
 		},
+		{
+			Key:            'i',
+			Label:          "configuration",
+			Description:    "Show versioning, encryption, public access, lifecycle and policy",
+			NeedsSelection: true,
+			View: func(bucketName string) Resource {
+				for _, bucket := range s.buckets {
+					if bucket.Name == bucketName {
+						return NewS3BucketConfig(bucketName, bucket.Region)
+					}
+				}
+				return NewS3BucketConfig(bucketName, "")
+			},
+		},
+		tagsAction(s),
+	}
+}
+
+// DrillDown opens the object browser of the bucket
+func (s *S3Buckets) DrillDown(bucketName string) Resource {
+	for _, bucket := range s.buckets {
+		if bucket.Name == bucketName {
+			return NewS3Objects(bucketName, bucket.Region, "")
+		}
+	}
+	return NewS3Objects(bucketName, "", "")
+}
+
+// bucketRegion returns an option sending a request to the region of a bucket
+func (s *S3Buckets) bucketRegion(bucketName string) func(*s3.Options) {
+	return func(o *s3.Options) {
+		for _, bucket := range s.buckets {
+			if bucket.Name == bucketName && bucket.Region != "" {
+				o.Region = bucket.Region
+			}
+		}
 	}
 }
 
+// Tags returns the tags of a bucket
+func (s *S3Buckets) Tags(ctx context.Context, c *client.Client, bucketName string) (map[string]string, error) {
+	output, err := c.S3().GetBucketTagging(ctx, &s3.GetBucketTaggingInput{Bucket: &bucketName}, s.bucketRegion(bucketName))
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchTagSet" {
+			return map[string]string{}, nil
+		}
+		return nil, fmt.Errorf("failed to get tags of %s: %w", bucketName, err)
+	}
+	tags := make(map[string]string)
+	for _, tag := range output.TagSet {
+		tags[stringValue(tag.Key)] = stringValue(tag.Value)
+	}
+	return tags, nil
+}
+
+// SetTags adds or updates tags of a bucket, S3 replacing the whole tag set at once
+func (s *S3Buckets) SetTags(ctx context.Context, c *client.Client, bucketName string, tags map[string]string) error {
+	current, err := s.Tags(ctx, c, bucketName)
+	if err != nil {
+		return err
+	}
+	for key, value := range tags {
+		current[key] = value
+	}
+	return s.putTags(ctx, c, bucketName, current)
+}
+
+// RemoveTags removes tags from a bucket
+func (s *S3Buckets) RemoveTags(ctx context.Context, c *client.Client, bucketName string, keys []string) error {
+	current, err := s.Tags(ctx, c, bucketName)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		delete(current, key)
+	}
+
+	if len(current) == 0 {
+		_, err := c.S3().DeleteBucketTagging(ctx, &s3.DeleteBucketTaggingInput{Bucket: &bucketName}, s.bucketRegion(bucketName))
+		if err != nil {
+			return fmt.Errorf("failed to untag %s: %w", bucketName, err)
+		}
+		return nil
+	}
+	return s.putTags(ctx, c, bucketName, current)
+}
+
+// putTags replaces the tag set of a bucket
+func (s *S3Buckets) putTags(ctx context.Context, c *client.Client, bucketName string, tags map[string]string) error {
+	tagSet := make([]s3types.Tag, 0, len(tags))
+	for key, value := range tags {
+		tagSet = append(tagSet, s3types.Tag{Key: &key, Value: &value})
+	}
+	_, err := c.S3().PutBucketTagging(ctx, &s3.PutBucketTaggingInput{
+		Bucket:  &bucketName,
+		Tagging: &s3types.Tagging{TagSet: tagSet},
+	}, s.bucketRegion(bucketName))
+	if err != nil {
+		return fmt.Errorf("failed to tag %s: %w", bucketName, err)
+	}
+	return nil
+}
+
 // CreateBucket creates a new S3 bucket
 func (s *S3Buckets) CreateBucket(ctx context.Context, c *client.Client, bucketName string) error {
 	input := &s3.CreateBucketInput{
@@ -303,3 +430,332 @@ func (s *S3Buckets) deleteBatch(ctx context.Context, c *client.Client, bucketNam
 func boolPtr(b bool) *bool {
 	return &b
 }
+
+// S3Object represents an object, or a common prefix, of an S3 bucket
+type S3Object struct {
+	Key          string
+	Size         string
+	StorageClass string
+	LastModified string
+}
+
+// S3Objects implements Resource and Pageable for the objects of a bucket under
+// a prefix, prefixes are listed as directories which can be opened
+type S3Objects struct {
+	bucket  string
+	region  string
+	prefix  string
+	objects []S3Object
+	pages   int
+	token   *string
+}
+
+// NewS3Objects creates a new S3Objects resource, region is the bucket region
+func NewS3Objects(bucket, region, prefix string) *S3Objects {
+	return &S3Objects{
+		bucket:  bucket,
+		region:  region,
+		prefix:  prefix,
+		objects: make([]S3Object, 0),
+	}
+}
+
+// Name returns the display name
+func (s *S3Objects) Name() string {
+	return fmt.Sprintf("S3 Objects (s3://%s/%s)", s.bucket, s.prefix)
+}
+
+// Columns returns the column definitions
+func (s *S3Objects) Columns() []Column {
+	return []Column{
+		{Name: "Key", Width: 60},
+		{Name: "Size", Width: 12},
+		{Name: "Storage Class", Width: 20},
+		{Name: "Last Modified", Width: 20},
+	}
+}
+
+// Fetch retrieves the objects under the prefix, as many pages as already loaded
+func (s *S3Objects) Fetch(ctx context.Context, c *client.Client) error {
+	s.objects = make([]S3Object, 0)
+	s.token = nil
+
+	pages := max(s.pages, 1)
+	s.pages = 0
+	for page := 0; page < pages; page++ {
+		if err := s.fetchPage(ctx, c); err != nil {
+			return err
+		}
+		if s.token == nil {
+			break
+		}
+	}
+
+	return nil
+}
+
+// HasMore reports whether more objects can be loaded
+func (s *S3Objects) HasMore() bool {
+	return s.token != nil
+}
+
+// FetchMore retrieves the next page of objects
+func (s *S3Objects) FetchMore(ctx context.Context, c *client.Client) error {
+	if s.token == nil {
+		return nil
+	}
+	return s.fetchPage(ctx, c)
+}
+
+// fetchPage retrieves the page of objects starting at the continuation token
+func (s *S3Objects) fetchPage(ctx context.Context, c *client.Client) error {
+	maxKeys := pageSize
+	output, err := c.S3().ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+		Bucket:            &s.bucket,
+		Prefix:            &s.prefix,
+		Delimiter:         aws.String("/"),
+		MaxKeys:           &maxKeys,
+		ContinuationToken: s.token,
+	}, s.inRegion)
+	if err != nil {
+		return fmt.Errorf("failed to list objects of %s: %w", s.bucket, err)
+	}
+
+	for _, prefix := range output.CommonPrefixes {
+		s.objects = append(s.objects, S3Object{
+			Key:          stringValue(prefix.Prefix),
+			Size:         "-",
+			StorageClass: "PREFIX",
+		})
+	}
+
+	for _, obj := range output.Contents {
+		o := S3Object{
+			Key:          stringValue(obj.Key),
+			Size:         formatSize(ptrInt64Value(obj.Size)),
+			StorageClass: string(obj.StorageClass),
+		}
+		if obj.LastModified != nil {
+			o.LastModified = obj.LastModified.Format("2006-01-02 15:04:05")
+		}
+		s.objects = append(s.objects, o)
+	}
+
+	s.pages++
+	s.token = nil
+	if ptrBoolValue(output.IsTruncated) {
+		s.token = output.NextContinuationToken
+	}
+	return nil
+}
+
+// inRegion sends the request to the bucket region, S3 rejects requests sent to another region
+func (s *S3Objects) inRegion(o *s3.Options) {
+	if s.region != "" {
+		o.Region = s.region
+	}
+}
+
+// Rows returns the table data, keys are shown relative to the prefix
+func (s *S3Objects) Rows() [][]string {
+	rows := make([][]string, len(s.objects))
+	for i, obj := range s.objects {
+		rows[i] = []string{
+			strings.TrimPrefix(obj.Key, s.prefix),
+			obj.Size,
+			obj.StorageClass,
+			obj.LastModified,
+		}
+	}
+	return rows
+}
+
+// GetID returns the full object key at the given index
+func (s *S3Objects) GetID(index int) string {
+	if index >= 0 && index < len(s.objects) {
+		return s.objects[index].Key
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for S3 objects
+func (s *S3Objects) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:            'u',
+			Label:          "presign",
+			Description:    "Copy a presigned download URL",
+			NeedsSelection: true,
+			Clipboard:      true,
+			InputLabel:     "Expires in: ",
+			InputDefault:   defaultPresignExpiry,
+			InputTextHandler: func(ctx context.Context, c *client.Client, key, expiry string) (string, error) {
+				return s.presign(ctx, c, key, expiry, false)
+			},
+		},
+		{
+			Key:            'U',
+			Label:          "presign put",
+			Description:    "Copy a presigned upload URL, to replace the object",
+			NeedsSelection: true,
+			Clipboard:      true,
+			InputLabel:     "Expires in: ",
+			InputDefault:   defaultPresignExpiry,
+			InputTextHandler: func(ctx context.Context, c *client.Client, key, expiry string) (string, error) {
+				return s.presign(ctx, c, key, expiry, true)
+			},
+		},
+		{
+			Key:            'd',
+			Label:          "download",
+			Description:    "Download the object to a local file",
+			NeedsSelection: true,
+			ReadOnly:       true,
+			InputLabel:     "Local path: ",
+			InputDefault: func(key string) string {
+				return path.Base(key)
+			},
+			InputHandler: s.Download,
+		},
+		{
+			Key:          'P',
+			Label:        "upload",
+			Description:  "Upload a local file under the current prefix",
+			InputLabel:   "Local file: ",
+			InputHandler: s.Upload,
+		},
+	}
+}
+
+// Download downloads an object to a new local file, reporting the progress of large objects
+func (s *S3Objects) Download(ctx context.Context, c *client.Client, key, file string) error {
+	if strings.HasSuffix(key, "/") {
+		return fmt.Errorf("%s is a prefix, only objects can be downloaded", key)
+	}
+	file, err := expandHome(strings.TrimSpace(file))
+	if err != nil {
+		return err
+	}
+
+	head, err := c.S3().HeadObject(ctx, &s3.HeadObjectInput{Bucket: &s.bucket, Key: &key}, s.inRegion)
+	if err != nil {
+		return fmt.Errorf("failed to get object %s: %w", key, err)
+	}
+
+	// An existing file is never overwritten
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", file, err)
+	}
+	defer f.Close()
+
+	downloader := manager.NewDownloader(c.S3(), func(d *manager.Downloader) {
+		d.ClientOptions = append(d.ClientOptions, s.inRegion)
+	})
+	progress := &transferProgress{ctx: ctx, label: "Downloading " + path.Base(key), total: ptrInt64Value(head.ContentLength)}
+	_, err = downloader.Download(ctx, &progressWriterAt{w: f, progress: progress}, &s3.GetObjectInput{
+		Bucket: &s.bucket,
+		Key:    &key,
+	})
+	if err != nil {
+		f.Close()
+		os.Remove(file)
+		return fmt.Errorf("failed to download %s: %w", key, err)
+	}
+	return f.Close()
+}
+
+// Upload uploads a local file under the current prefix, an existing object is never
+// replaced, reporting the progress of large files
+func (s *S3Objects) Upload(ctx context.Context, c *client.Client, _, file string) error {
+	file, err := expandHome(strings.TrimSpace(file))
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", file, err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory, only files can be uploaded", file)
+	}
+
+	key := s.prefix + filepath.Base(file)
+	_, err = c.S3().HeadObject(ctx, &s3.HeadObjectInput{Bucket: &s.bucket, Key: &key}, s.inRegion)
+	if err == nil {
+		return fmt.Errorf("s3://%s/%s already exists", s.bucket, key)
+	}
+	if !isS3ErrorCode(err, "NotFound") {
+		return fmt.Errorf("failed to check object %s: %w", key, err)
+	}
+
+	// The part size is fixed for the progress to know the parts, large enough to fit the parts limit
+	partSize := max(manager.DefaultUploadPartSize, info.Size()/int64(manager.MaxUploadParts)+1)
+	uploader := manager.NewUploader(c.S3(), func(u *manager.Uploader) {
+		u.PartSize = partSize
+		u.ClientOptions = append(u.ClientOptions, s.inRegion)
+	})
+	progress := &transferProgress{ctx: ctx, label: "Uploading " + filepath.Base(file), total: info.Size()}
+	// S3 refuses the upload if an object was created meanwhile, the check above only fails early
+	_, err = uploader.Upload(ctx, &s3.PutObjectInput{
+		Bucket:      &s.bucket,
+		Key:         &key,
+		Body:        newProgressReader(f, info.Size(), partSize, progress),
+		IfNoneMatch: aws.String("*"),
+	})
+	if isS3ErrorCode(err, "PreconditionFailed") {
+		return fmt.Errorf("s3://%s/%s already exists", s.bucket, key)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", file, err)
+	}
+	return nil
+}
+
+// maxPresignExpiry is the longest validity of a SigV4 presigned URL
+const maxPresignExpiry = 7 * 24 * time.Hour
+
+// defaultPresignExpiry returns the expiry pre-filled when presigning an object
+func defaultPresignExpiry(string) string {
+	return "1h"
+}
+
+// presign returns a presigned GET or PUT URL of an object, valid for the given duration
+func (s *S3Objects) presign(ctx context.Context, c *client.Client, key, expiry string, put bool) (string, error) {
+	if strings.HasSuffix(key, "/") {
+		return "", fmt.Errorf("%s is a prefix, only objects can be presigned", key)
+	}
+	expires, err := time.ParseDuration(strings.TrimSpace(expiry))
+	if err != nil || expires <= 0 || expires > maxPresignExpiry {
+		return "", fmt.Errorf("expected a duration up to 7 days, e.g. 15m or 24h, got %q", expiry)
+	}
+
+	presigner := c.S3Presign(s3.WithPresignExpires(expires), func(o *s3.PresignOptions) {
+		o.ClientOptions = append(o.ClientOptions, s.inRegion)
+	})
+	if put {
+		request, err := presigner.PresignPutObject(ctx, &s3.PutObjectInput{Bucket: &s.bucket, Key: &key})
+		if err != nil {
+			return "", fmt.Errorf("failed to presign upload of %s: %w", key, err)
+		}
+		return request.URL, nil
+	}
+	request, err := presigner.PresignGetObject(ctx, &s3.GetObjectInput{Bucket: &s.bucket, Key: &key})
+	if err != nil {
+		return "", fmt.Errorf("failed to presign download of %s: %w", key, err)
+	}
+	return request.URL, nil
+}
+
+// DrillDown opens a prefix, objects cannot be opened
+func (s *S3Objects) DrillDown(key string) Resource {
+	if !strings.HasSuffix(key, "/") {
+		return nil
+	}
+	return NewS3Objects(s.bucket, s.region, key)
+}
@@ -0,0 +1,409 @@
+package resources
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// Settings of the bucket configuration which can be toggled
+const (
+	bucketSettingVersioning   = "versioning"
+	bucketSettingPublicAccess = "public-access-block"
+)
+
+// S3BucketSetting represents a setting of the configuration of a bucket
+type S3BucketSetting struct {
+	ID      string
+	Setting string
+	Value   string
+	// Detail is the full configuration of the setting, e.g. the bucket policy document
+	Detail string
+}
+
+// S3BucketConfig implements Resource for the versioning, encryption, public access
+// block, lifecycle rules and policy of a bucket
+type S3BucketConfig struct {
+	bucket   string
+	region   string
+	settings []S3BucketSetting
+
+	versioning   s3types.BucketVersioningStatus
+	publicAccess *s3types.PublicAccessBlockConfiguration
+}
+
+// NewS3BucketConfig creates a new S3BucketConfig resource, region is the bucket region
+func NewS3BucketConfig(bucket, region string) *S3BucketConfig {
+	return &S3BucketConfig{
+		bucket:   bucket,
+		region:   region,
+		settings: make([]S3BucketSetting, 0),
+	}
+}
+
+// Name returns the display name
+func (b *S3BucketConfig) Name() string {
+	return fmt.Sprintf("S3 Bucket Configuration (%s)", b.bucket)
+}
+
+// Columns returns the column definitions
+func (b *S3BucketConfig) Columns() []Column {
+	return []Column{
+		{Name: "Setting", Width: 30},
+		{Name: "Value", Width: 80},
+	}
+}
+
+// inRegion sends the request to the bucket region, S3 rejects requests sent to another region
+func (b *S3BucketConfig) inRegion(o *s3.Options) {
+	if b.region != "" {
+		o.Region = b.region
+	}
+}
+
+// isS3ErrorCode reports whether an S3 error has the given code, S3 reports a missing
+// configuration with an error rather than an empty one
+func isS3ErrorCode(err error, code string) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == code
+}
+
+// Fetch retrieves the configuration of the bucket
+func (b *S3BucketConfig) Fetch(ctx context.Context, c *client.Client) error {
+	b.settings = make([]S3BucketSetting, 0)
+	b.versioning = ""
+	b.publicAccess = nil
+
+	for _, fetch := range []func(context.Context, *client.Client) error{
+		b.fetchVersioning,
+		b.fetchEncryption,
+		b.fetchPublicAccessBlock,
+		b.fetchLifecycle,
+		b.fetchPolicy,
+	} {
+		if err := fetch(ctx, c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fetchVersioning retrieves the versioning status of the bucket
+func (b *S3BucketConfig) fetchVersioning(ctx context.Context, c *client.Client) error {
+	output, err := c.S3().GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{Bucket: &b.bucket}, b.inRegion)
+	if err != nil {
+		return fmt.Errorf("failed to get versioning of %s: %w", b.bucket, err)
+	}
+	b.versioning = output.Status
+
+	value := string(output.Status)
+	if value == "" {
+		value = "Disabled"
+	}
+	if output.MFADelete == s3types.MFADeleteStatusEnabled {
+		value += ", MFA delete"
+	}
+	b.settings = append(b.settings, S3BucketSetting{ID: bucketSettingVersioning, Setting: "Versioning", Value: value, Detail: value})
+	return nil
+}
+
+// fetchEncryption retrieves the default encryption of the bucket
+func (b *S3BucketConfig) fetchEncryption(ctx context.Context, c *client.Client) error {
+	output, err := c.S3().GetBucketEncryption(ctx, &s3.GetBucketEncryptionInput{Bucket: &b.bucket}, b.inRegion)
+	if err != nil {
+		if isS3ErrorCode(err, "ServerSideEncryptionConfigurationNotFoundError") {
+			b.settings = append(b.settings, S3BucketSetting{ID: "encryption", Setting: "Encryption", Value: "None"})
+			return nil
+		}
+		return fmt.Errorf("failed to get encryption of %s: %w", b.bucket, err)
+	}
+
+	values := make([]string, 0)
+	if output.ServerSideEncryptionConfiguration != nil {
+		for _, rule := range output.ServerSideEncryptionConfiguration.Rules {
+			if rule.ApplyServerSideEncryptionByDefault == nil {
+				continue
+			}
+			value := string(rule.ApplyServerSideEncryptionByDefault.SSEAlgorithm)
+			if key := stringValue(rule.ApplyServerSideEncryptionByDefault.KMSMasterKeyID); key != "" {
+				value += " " + key
+			}
+			if ptrBoolValue(rule.BucketKeyEnabled) {
+				value += ", bucket key"
+			}
+			values = append(values, value)
+		}
+	}
+	value := strings.Join(values, "; ")
+	b.settings = append(b.settings, S3BucketSetting{ID: "encryption", Setting: "Encryption", Value: value, Detail: value})
+	return nil
+}
+
+// fetchPublicAccessBlock retrieves the public access block of the bucket
+func (b *S3BucketConfig) fetchPublicAccessBlock(ctx context.Context, c *client.Client) error {
+	output, err := c.S3().GetPublicAccessBlock(ctx, &s3.GetPublicAccessBlockInput{Bucket: &b.bucket}, b.inRegion)
+	if err != nil && !isS3ErrorCode(err, "NoSuchPublicAccessBlockConfiguration") {
+		return fmt.Errorf("failed to get public access block of %s: %w", b.bucket, err)
+	}
+	if err == nil {
+		b.publicAccess = output.PublicAccessBlockConfiguration
+	}
+
+	setting := S3BucketSetting{ID: bucketSettingPublicAccess, Setting: "Public Access Block", Value: "Off"}
+	if b.publicAccess != nil {
+		flags := []struct {
+			name string
+			on   *bool
+		}{
+			{"BlockPublicAcls", b.publicAccess.BlockPublicAcls},
+			{"IgnorePublicAcls", b.publicAccess.IgnorePublicAcls},
+			{"BlockPublicPolicy", b.publicAccess.BlockPublicPolicy},
+			{"RestrictPublicBuckets", b.publicAccess.RestrictPublicBuckets},
+		}
+		on := make([]string, 0, len(flags))
+		lines := make([]string, 0, len(flags))
+		for _, flag := range flags {
+			if ptrBoolValue(flag.on) {
+				on = append(on, flag.name)
+			}
+			lines = append(lines, fmt.Sprintf("%s: %t", flag.name, ptrBoolValue(flag.on)))
+		}
+		switch len(on) {
+		case len(flags):
+			setting.Value = "All blocked"
+		case 0:
+			setting.Value = "Off"
+		default:
+			setting.Value = "Partial: " + strings.Join(on, ", ")
+		}
+		setting.Detail = strings.Join(lines, "\n")
+	}
+	b.settings = append(b.settings, setting)
+	return nil
+}
+
+// publicAccessBlocked reports whether all the public access of the bucket is blocked
+func (b *S3BucketConfig) publicAccessBlocked() bool {
+	return b.publicAccess != nil &&
+		ptrBoolValue(b.publicAccess.BlockPublicAcls) &&
+		ptrBoolValue(b.publicAccess.IgnorePublicAcls) &&
+		ptrBoolValue(b.publicAccess.BlockPublicPolicy) &&
+		ptrBoolValue(b.publicAccess.RestrictPublicBuckets)
+}
+
+// fetchLifecycle retrieves the lifecycle rules of the bucket, one setting per rule
+func (b *S3BucketConfig) fetchLifecycle(ctx context.Context, c *client.Client) error {
+	output, err := c.S3().GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{Bucket: &b.bucket}, b.inRegion)
+	if err != nil {
+		if isS3ErrorCode(err, "NoSuchLifecycleConfiguration") {
+			b.settings = append(b.settings, S3BucketSetting{ID: "lifecycle", Setting: "Lifecycle", Value: "No rules"})
+			return nil
+		}
+		return fmt.Errorf("failed to get lifecycle of %s: %w", b.bucket, err)
+	}
+
+	for i, rule := range output.Rules {
+		id := stringValue(rule.ID)
+		if id == "" {
+			id = fmt.Sprintf("#%d", i+1)
+		}
+		detail, _ := json.MarshalIndent(rule, "", "  ")
+		b.settings = append(b.settings, S3BucketSetting{
+			ID:      "lifecycle:" + id,
+			Setting: "Lifecycle: " + id,
+			Value:   lifecycleRuleSummary(rule),
+			Detail:  string(detail),
+		})
+	}
+	return nil
+}
+
+// lifecycleRuleSummary describes a lifecycle rule on one line, e.g. "Enabled, logs/: Glacier after 30d, expire after 365d"
+func lifecycleRuleSummary(rule s3types.LifecycleRule) string {
+	parts := []string{string(rule.Status)}
+
+	scope := "whole bucket"
+	if rule.Filter != nil {
+		switch {
+		case rule.Filter.Prefix != nil && *rule.Filter.Prefix != "":
+			scope = *rule.Filter.Prefix
+		case rule.Filter.Tag != nil:
+			scope = fmt.Sprintf("tag %s=%s", stringValue(rule.Filter.Tag.Key), stringValue(rule.Filter.Tag.Value))
+		case rule.Filter.And != nil:
+			scope = "filtered"
+		}
+	}
+	parts = append(parts, scope)
+
+	for _, transition := range rule.Transitions {
+		parts = append(parts, fmt.Sprintf("%s after %dd", transition.StorageClass, ptrInt32Value(transition.Days)))
+	}
+	if rule.Expiration != nil && rule.Expiration.Days != nil {
+		parts = append(parts, fmt.Sprintf("expire after %dd", *rule.Expiration.Days))
+	}
+	if rule.NoncurrentVersionExpiration != nil && rule.NoncurrentVersionExpiration.NoncurrentDays != nil {
+		parts = append(parts, fmt.Sprintf("noncurrent expire after %dd", *rule.NoncurrentVersionExpiration.NoncurrentDays))
+	}
+	if rule.AbortIncompleteMultipartUpload != nil && rule.AbortIncompleteMultipartUpload.DaysAfterInitiation != nil {
+		parts = append(parts, fmt.Sprintf("abort uploads after %dd", *rule.AbortIncompleteMultipartUpload.DaysAfterInitiation))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// fetchPolicy retrieves the policy of the bucket
+func (b *S3BucketConfig) fetchPolicy(ctx context.Context, c *client.Client) error {
+	output, err := c.S3().GetBucketPolicy(ctx, &s3.GetBucketPolicyInput{Bucket: &b.bucket}, b.inRegion)
+	if err != nil {
+		if isS3ErrorCode(err, "NoSuchBucketPolicy") {
+			b.settings = append(b.settings, S3BucketSetting{ID: "policy", Setting: "Bucket Policy", Value: "None"})
+			return nil
+		}
+		return fmt.Errorf("failed to get policy of %s: %w", b.bucket, err)
+	}
+
+	policy := stringValue(output.Policy)
+	var document struct {
+		Statement []json.RawMessage
+	}
+	value := "Set"
+	if err := json.Unmarshal([]byte(policy), &document); err == nil {
+		value = fmt.Sprintf("%d statements, press d to show", len(document.Statement))
+	}
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, []byte(policy), "", "  "); err == nil {
+		policy = pretty.String()
+	}
+	b.settings = append(b.settings, S3BucketSetting{ID: "policy", Setting: "Bucket Policy", Value: value, Detail: policy})
+	return nil
+}
+
+// Rows returns the table data
+func (b *S3BucketConfig) Rows() [][]string {
+	rows := make([][]string, len(b.settings))
+	for i, setting := range b.settings {
+		rows[i] = []string{setting.Setting, setting.Value}
+	}
+	return rows
+}
+
+// GetID returns the setting ID at the given index
+func (b *S3BucketConfig) GetID(index int) string {
+	if index >= 0 && index < len(b.settings) {
+		return b.settings[index].ID
+	}
+	return ""
+}
+
+// Highlight marks the settings leaving the bucket exposed or unprotected
+func (b *S3BucketConfig) Highlight(index int) bool {
+	if index < 0 || index >= len(b.settings) {
+		return false
+	}
+	switch b.settings[index].ID {
+	case bucketSettingPublicAccess:
+		return !b.publicAccessBlocked()
+	case bucketSettingVersioning:
+		return b.versioning != s3types.BucketVersioningStatusEnabled
+	}
+	return false
+}
+
+// QuickActions returns the available quick actions for the bucket configuration
+func (b *S3BucketConfig) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:            'd',
+			Label:          "detail",
+			Description:    "Show the full setting, e.g. the policy document",
+			NeedsSelection: true,
+			TextHandler: func(ctx context.Context, c *client.Client, id string) (string, error) {
+				for _, setting := range b.settings {
+					if setting.ID == id {
+						if setting.Detail == "" {
+							return setting.Value, nil
+						}
+						return setting.Detail, nil
+					}
+				}
+				return "", fmt.Errorf("setting %s is not listed", id)
+			},
+		},
+		{
+			Key:             't',
+			Label:           "toggle",
+			Description:     "Toggle versioning or the public access block",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: fmt.Sprintf("[yellow]toggle[-] [white]%%s[-] of bucket [white]%s[-]?", b.bucket),
+			ConfirmCheck: func(ctx context.Context, c *client.Client, id string) (string, error) {
+				switch id {
+				case bucketSettingVersioning:
+					if b.versioning == s3types.BucketVersioningStatusEnabled {
+						return "Versioning will be suspended, new writes replace the objects and existing versions are kept.", nil
+					}
+					return "Versioning will be enabled, it can only be suspended afterwards, never disabled.", nil
+				case bucketSettingPublicAccess:
+					if b.publicAccessBlocked() {
+						return "The public access block will be removed, the bucket policy and ACLs may then expose objects publicly.", nil
+					}
+					return "All public access will be blocked, public ACLs and policies stop applying.", nil
+				}
+				return "", fmt.Errorf("only versioning and the public access block can be toggled")
+			},
+			Handler: b.toggle,
+		},
+	}
+}
+
+// toggle switches versioning between enabled and suspended, or the public access block
+// between all blocked and removed
+func (b *S3BucketConfig) toggle(ctx context.Context, c *client.Client, id string) error {
+	switch id {
+	case bucketSettingVersioning:
+		status := s3types.BucketVersioningStatusEnabled
+		if b.versioning == s3types.BucketVersioningStatusEnabled {
+			status = s3types.BucketVersioningStatusSuspended
+		}
+		_, err := c.S3().PutBucketVersioning(ctx, &s3.PutBucketVersioningInput{
+			Bucket:                  &b.bucket,
+			VersioningConfiguration: &s3types.VersioningConfiguration{Status: status},
+		}, b.inRegion)
+		if err != nil {
+			return fmt.Errorf("failed to set versioning of %s: %w", b.bucket, err)
+		}
+		return nil
+	case bucketSettingPublicAccess:
+		if b.publicAccessBlocked() {
+			_, err := c.S3().DeletePublicAccessBlock(ctx, &s3.DeletePublicAccessBlockInput{Bucket: &b.bucket}, b.inRegion)
+			if err != nil {
+				return fmt.Errorf("failed to remove public access block of %s: %w", b.bucket, err)
+			}
+			return nil
+		}
+		_, err := c.S3().PutPublicAccessBlock(ctx, &s3.PutPublicAccessBlockInput{
+			Bucket: &b.bucket,
+			PublicAccessBlockConfiguration: &s3types.PublicAccessBlockConfiguration{
+				BlockPublicAcls:       aws.Bool(true),
+				IgnorePublicAcls:      aws.Bool(true),
+				BlockPublicPolicy:     aws.Bool(true),
+				RestrictPublicBuckets: aws.Bool(true),
+			},
+		}, b.inRegion)
+		if err != nil {
+			return fmt.Errorf("failed to block public access of %s: %w", b.bucket, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("setting %s cannot be toggled", id)
+}
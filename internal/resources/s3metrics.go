@@ -0,0 +1,153 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// s3MetricsTTL is how long the bucket sizes are cached, S3 publishes them once a day
+const s3MetricsTTL = 6 * time.Hour
+
+// s3SizeStorageTypes are the storage classes summed into the size of a bucket
+var s3SizeStorageTypes = []string{
+	"StandardStorage",
+	"IntelligentTieringFAStorage",
+	"IntelligentTieringIAStorage",
+	"IntelligentTieringAIAStorage",
+	"StandardIAStorage",
+	"OneZoneIAStorage",
+	"ReducedRedundancyStorage",
+	"GlacierInstantRetrievalStorage",
+	"GlacierStorage",
+	"DeepArchiveStorage",
+}
+
+// s3BucketMetrics is the approximate size and object count of a bucket from CloudWatch
+type s3BucketMetrics struct {
+	size    int64
+	objects int64
+	known   bool
+	fetched time.Time
+}
+
+// s3MetricsCache keeps the bucket metrics by profile and bucket between refreshes
+type s3MetricsCache struct {
+	mu      sync.Mutex
+	metrics map[string]s3BucketMetrics
+}
+
+// get returns the cached metrics of a bucket, if fresh
+func (m *s3MetricsCache) get(profile, bucket string) (s3BucketMetrics, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	metrics, ok := m.metrics[profile+"/"+bucket]
+	return metrics, ok && time.Since(metrics.fetched) < s3MetricsTTL
+}
+
+// put caches the metrics of a bucket
+func (m *s3MetricsCache) put(profile, bucket string, metrics s3BucketMetrics) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.metrics == nil {
+		m.metrics = make(map[string]s3BucketMetrics)
+	}
+	m.metrics[profile+"/"+bucket] = metrics
+}
+
+// fetch returns the metrics of the buckets, the ones missing from the cache being queried
+// concurrently from the CloudWatch of each bucket region
+func (m *s3MetricsCache) fetch(ctx context.Context, c *client.Client, buckets []S3Bucket) map[string]s3BucketMetrics {
+	result := make(map[string]s3BucketMetrics, len(buckets))
+	byRegion := make(map[string][]string)
+	for _, bucket := range buckets {
+		if metrics, ok := m.get(c.Profile(), bucket.Name); ok {
+			result[bucket.Name] = metrics
+			continue
+		}
+		if bucket.Region != "" {
+			byRegion[bucket.Region] = append(byRegion[bucket.Region], bucket.Name)
+		}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for region, names := range byRegion {
+		wg.Add(1)
+		go func(region string, names []string) {
+			defer wg.Done()
+
+			metrics, err := queryS3Metrics(ctx, c, region, names)
+			if err != nil {
+				// The columns stay empty without cloudwatch:GetMetricData
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for name, bucketMetrics := range metrics {
+				m.put(c.Profile(), name, bucketMetrics)
+				result[name] = bucketMetrics
+			}
+		}(region, names)
+	}
+	wg.Wait()
+
+	return result
+}
+
+// queryS3Metrics queries the latest daily size and object count of buckets of a region
+func queryS3Metrics(ctx context.Context, c *client.Client, region string, names []string) (map[string]s3BucketMetrics, error) {
+	regional, err := c.InRegion(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+
+	queries := make([]cwtypes.MetricDataQuery, 0, len(names)*(len(s3SizeStorageTypes)+1))
+	for i, name := range names {
+		bucket := cwtypes.Dimension{Name: aws.String("BucketName"), Value: aws.String(name)}
+		for j, storageType := range s3SizeStorageTypes {
+			queries = append(queries, metricQuery(fmt.Sprintf("size%d_%d", i, j), "AWS/S3", "BucketSizeBytes", "Average", 86400,
+				bucket, cwtypes.Dimension{Name: aws.String("StorageType"), Value: aws.String(storageType)}))
+		}
+		queries = append(queries, metricQuery(fmt.Sprintf("objects%d", i), "AWS/S3", "NumberOfObjects", "Average", 86400,
+			bucket, cwtypes.Dimension{Name: aws.String("StorageType"), Value: aws.String("AllStorageTypes")}))
+	}
+
+	// The metrics are published daily, sometimes with a delay of a day
+	series, err := metricSeries(ctx, regional, queries, 3*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := func(id string) (float64, bool) {
+		values := series[id]
+		if len(values) == 0 {
+			return 0, false
+		}
+		return values[len(values)-1], true
+	}
+
+	metrics := make(map[string]s3BucketMetrics, len(names))
+	now := time.Now()
+	for i, name := range names {
+		bucketMetrics := s3BucketMetrics{fetched: now}
+		for j := range s3SizeStorageTypes {
+			if size, ok := latest(fmt.Sprintf("size%d_%d", i, j)); ok {
+				bucketMetrics.size += int64(size)
+				bucketMetrics.known = true
+			}
+		}
+		if objects, ok := latest(fmt.Sprintf("objects%d", i)); ok {
+			bucketMetrics.objects = int64(objects)
+			bucketMetrics.known = true
+		}
+		metrics[name] = bucketMetrics
+	}
+	return metrics, nil
+}
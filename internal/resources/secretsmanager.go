@@ -1,19 +1,37 @@
 package resources
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 
+	"a9s/internal/audit"
 	"a9s/internal/client"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 )
 
+// defaultSecretKMSAlias is the AWS managed key encrypting secrets created without a KMS key
+const defaultSecretKMSAlias = "alias/aws/secretsmanager"
+
 // Secret represents a Secrets Manager secret
 type Secret struct {
 	ARN              string
 	Name             string
 	Description      string
+	KMSKeyID         string
 	RotationEnabled  bool
 	LastAccessedDate string
 	LastChangedDate  string
@@ -23,12 +41,15 @@ type Secret struct {
 // Secrets implements Resource for Secrets Manager secrets
 type Secrets struct {
 	secrets []Secret
+	// kmsAliases are offered in the editor when a secret is created or updated
+	kmsAliases *secretKMSAliases
 }
 
 // NewSecrets creates a new Secrets resource
 func NewSecrets() *Secrets {
 	return &Secrets{
-		secrets: make([]Secret, 0),
+		secrets:    make([]Secret, 0),
+		kmsAliases: &secretKMSAliases{},
 	}
 }
 
@@ -52,6 +73,8 @@ func (s *Secrets) Columns() []Column {
 // Fetch retrieves secrets from AWS Secrets Manager
 func (s *Secrets) Fetch(ctx context.Context, c *client.Client) error {
 	s.secrets = make([]Secret, 0)
+	// The aliases are listed again on the next edit, e.g. in the newly selected region
+	s.kmsAliases.aliases = nil
 
 	paginator := secretsmanager.NewListSecretsPaginator(c.SecretsManager(), &secretsmanager.ListSecretsInput{})
 
@@ -66,6 +89,7 @@ func (s *Secrets) Fetch(ctx context.Context, c *client.Client) error {
 				ARN:             stringValue(secret.ARN),
 				Name:            stringValue(secret.Name),
 				Description:     stringValue(secret.Description),
+				KMSKeyID:        stringValue(secret.KmsKeyId),
 				RotationEnabled: secret.RotationEnabled != nil && *secret.RotationEnabled,
 			}
 
@@ -88,6 +112,42 @@ func (s *Secrets) Fetch(ctx context.Context, c *client.Client) error {
 	return nil
 }
 
+// secretKMSAliases are the KMS aliases listed in the secret editor, loaded on the first edit
+type secretKMSAliases struct {
+	aliases []string
+}
+
+// load lists the aliases unless they already were
+func (a *secretKMSAliases) load(ctx context.Context, c *client.Client, _ string) error {
+	if a.aliases == nil {
+		a.aliases = listKMSAliases(ctx, c)
+	}
+	return nil
+}
+
+// listKMSAliases returns the aliases of the keys that can encrypt a secret, it is only a
+// help to pick a key so an account without kms:ListAliases gets an empty list
+func listKMSAliases(ctx context.Context, c *client.Client) []string {
+	aliases := make([]string, 0)
+	paginator := kms.NewListAliasesPaginator(c.KMS(), &kms.ListAliasesInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return aliases
+		}
+		for _, alias := range output.Aliases {
+			name := stringValue(alias.AliasName)
+			// AWS managed keys other than the Secrets Manager one cannot be used
+			if alias.TargetKeyId == nil || (strings.HasPrefix(name, "alias/aws/") && name != defaultSecretKMSAlias) {
+				continue
+			}
+			aliases = append(aliases, name)
+		}
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
 // Rows returns the table data
 func (s *Secrets) Rows() [][]string {
 	rows := make([][]string, len(s.secrets))
@@ -118,5 +178,868 @@ func (s *Secrets) GetID(index int) string {
 
 // QuickActions returns the available quick actions for secrets
 func (s *Secrets) QuickActions() []QuickAction {
-	return []QuickAction{}
+	return []QuickAction{
+		{
+			Key:            'v',
+			Label:          "value",
+			Description:    "Show the secret value, masked until revealed",
+			NeedsSelection: true,
+			View: func(arn string) Resource {
+				value := NewSecretValue(arn, s.secretName(arn), s.secretKMSKey(arn))
+				value.kmsAliases = s.kmsAliases
+				return value
+			},
+		},
+		{
+			Key:          'c',
+			Label:        "create",
+			Description:  "Create a secret in the editor",
+			EditPrepare:  s.kmsAliases.load,
+			EditTemplate: s.newSecretTemplate,
+			EditHandler: func(ctx context.Context, c *client.Client, _, text string) error {
+				return createSecret(ctx, c, text)
+			},
+		},
+		{
+			Key:            'e',
+			Label:          "put value",
+			Description:    "Put a new value on the secret in the editor",
+			NeedsSelection: true,
+			EditPrepare:    s.kmsAliases.load,
+			EditTemplate: func(arn string) string {
+				return secretValueTemplate(s.secretName(arn), s.secretKMSKey(arn), "", s.kmsAliases.aliases)
+			},
+			EditPlan: func(ctx context.Context, c *client.Client, arn, text string) ([]Step, error) {
+				return planSecretValue(ctx, c, arn, s.secretName(arn), s.secretKMSKey(arn), text)
+			},
+		},
+		{
+			Key:            'u',
+			Label:          "usages",
+			Description:    "Find the resources referencing the secret",
+			NeedsSelection: true,
+			View: func(arn string) Resource {
+				return NewSecretUsages(arn, s.secretName(arn))
+			},
+		},
+		{
+			Key:             'x',
+			Label:           "export env",
+			Description:     "Export the secret JSON keys to a .env or .sh file",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ReadOnly:        true,
+			ConfirmTemplate: "The value of %s will be written in clear to a local file. Continue?",
+			InputLabel:      "File: ",
+			InputDefault: func(string) string {
+				return ".env"
+			},
+			InputHandler: exportSecretEnv,
+		},
+	}
+}
+
+// exportSecretEnv writes the keys of a JSON secret to an environment file
+func exportSecretEnv(ctx context.Context, c *client.Client, arn, file string) error {
+	output, err := c.SecretsManager().GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: &arn,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get value of %s: %w", arn, err)
+	}
+
+	var values map[string]any
+	if err := json.Unmarshal([]byte(stringValue(output.SecretString)), &values); err != nil {
+		return fmt.Errorf("secret %s is not a JSON object", stringValue(output.Name))
+	}
+
+	variables := newEnvVariables()
+	for key, value := range values {
+		if text, ok := value.(string); ok {
+			variables.set(key, text)
+			continue
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to encode key %s: %w", key, err)
+		}
+		variables.set(key, string(encoded))
+	}
+
+	return writeEnvFile(file, variables)
+}
+
+// secretName returns the name of the listed secret with the given ARN
+func (s *Secrets) secretName(arn string) string {
+	for _, secret := range s.secrets {
+		if secret.ARN == arn {
+			return secret.Name
+		}
+	}
+	return arn
+}
+
+// secretKMSKey returns the KMS key of the listed secret with the given ARN, empty for the default key
+func (s *Secrets) secretKMSKey(arn string) string {
+	for _, secret := range s.secrets {
+		if secret.ARN == arn {
+			return secret.KMSKeyID
+		}
+	}
+	return ""
+}
+
+// newSecretTemplate returns the editor template of a new secret
+func (s *Secrets) newSecretTemplate(string) string {
+	var b strings.Builder
+	b.WriteString("# New secret, header lines starting with # are ignored.\n")
+	writeKMSAliases(&b, s.kmsAliases.aliases)
+	b.WriteString("# The value goes below the --- line, JSON objects are validated.\n")
+	b.WriteString("Name: \n")
+	b.WriteString("Description: \n")
+	b.WriteString("KMS key: \n")
+	b.WriteString("---\n")
+	return b.String()
+}
+
+// secretValueTemplate returns the editor template of a new value of a secret
+func secretValueTemplate(name, kmsKey, value string, aliases []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# New value of %s, header lines starting with # are ignored.\n", name)
+	writeKMSAliases(&b, aliases)
+	b.WriteString("# The value goes below the --- line, it replaces the whole current value.\n")
+	fmt.Fprintf(&b, "KMS key: %s\n", kmsKey)
+	b.WriteString("---\n")
+	b.WriteString(value)
+	return b.String()
+}
+
+// writeKMSAliases lists the KMS keys a secret can be encrypted with in a template
+func writeKMSAliases(b *strings.Builder, aliases []string) {
+	fmt.Fprintf(b, "# KMS key: an alias, key ID or ARN, empty for %s. Available aliases:\n", defaultSecretKMSAlias)
+	for _, alias := range aliases {
+		fmt.Fprintf(b, "#   %s\n", alias)
+	}
+}
+
+// parseSecretTemplate parses the headers and the value of an edited secret template
+func parseSecretTemplate(text string) (map[string]string, string, error) {
+	head, value, ok := strings.Cut(text, "\n---\n")
+	if !ok {
+		return nil, "", fmt.Errorf("the --- line separating the headers from the value is missing")
+	}
+
+	headers := make(map[string]string)
+	for _, line := range strings.Split(head, "\n") {
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+		header, content, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, "", fmt.Errorf("expected a Header: value line, got %q", line)
+		}
+		headers[strings.ToLower(strings.TrimSpace(header))] = strings.TrimSpace(content)
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, "", fmt.Errorf("the value is empty")
+	}
+	if strings.HasPrefix(value, "{") && !json.Valid([]byte(value)) {
+		return nil, "", fmt.Errorf("the value starts like a JSON object but is not valid JSON")
+	}
+	return headers, value, nil
+}
+
+// createSecret parses an edited secret template and creates the secret
+func createSecret(ctx context.Context, c *client.Client, text string) error {
+	headers, value, err := parseSecretTemplate(text)
+	if err != nil {
+		return err
+	}
+	name := headers["name"]
+	if name == "" {
+		return fmt.Errorf("the Name: header is required")
+	}
+
+	input := &secretsmanager.CreateSecretInput{
+		Name:         &name,
+		SecretString: &value,
+	}
+	if description := headers["description"]; description != "" {
+		input.Description = &description
+	}
+	if kmsKey := headers["kms key"]; kmsKey != "" {
+		input.KmsKeyId = &kmsKey
+	}
+
+	if _, err := c.SecretsManager().CreateSecret(ctx, input); err != nil {
+		return fmt.Errorf("failed to create secret %s: %w", name, err)
+	}
+	return nil
+}
+
+// planSecretValue parses an edited value template and returns the changed keys as notes,
+// values are never shown, followed by the step storing the new current version of the
+// secret, re-encrypted when the KMS key changed
+func planSecretValue(ctx context.Context, c *client.Client, arn, name, currentKMSKey, text string) ([]Step, error) {
+	headers, value, err := parseSecretTemplate(text)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := c.SecretsManager().GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: &arn,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get value of %s: %w", name, err)
+	}
+
+	steps := make([]Step, 0)
+	for _, line := range secretValueDiff(stringValue(output.SecretString), value) {
+		steps = append(steps, Step{Description: line})
+	}
+
+	input := &secretsmanager.UpdateSecretInput{
+		SecretId:     &arn,
+		SecretString: &value,
+	}
+	if kmsKey := headers["kms key"]; kmsKey != currentKMSKey {
+		if kmsKey == "" {
+			kmsKey = defaultSecretKMSAlias
+		}
+		input.KmsKeyId = &kmsKey
+		steps = append(steps, Step{Description: fmt.Sprintf("~ KMS key: %s → %s", currentKMSKey, kmsKey)})
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("no change to the value of %s", name)
+	}
+
+	return append(steps, Step{
+		Description: fmt.Sprintf("Put the new value on %s, applications will read it from now on", name),
+		Run: func(ctx context.Context, c *client.Client) error {
+			if _, err := c.SecretsManager().UpdateSecret(ctx, input); err != nil {
+				return fmt.Errorf("failed to update value of %s: %w", name, err)
+			}
+			return nil
+		},
+	}), nil
+}
+
+// secretValueDiff describes the keys added, removed and changed between two JSON object
+// values, or a whole value change, without showing any value
+func secretValueDiff(before, after string) []string {
+	if before == after {
+		return nil
+	}
+	var old, updated map[string]json.RawMessage
+	if json.Unmarshal([]byte(before), &old) != nil || json.Unmarshal([]byte(after), &updated) != nil ||
+		old == nil || updated == nil {
+		return []string{"~ value replaced"}
+	}
+
+	keys := make([]string, 0, len(old)+len(updated))
+	for key := range old {
+		keys = append(keys, key)
+	}
+	for key := range updated {
+		if _, ok := old[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	compact := func(raw json.RawMessage) string {
+		var b bytes.Buffer
+		if json.Compact(&b, raw) != nil {
+			return string(raw)
+		}
+		return b.String()
+	}
+
+	lines := make([]string, 0)
+	for _, key := range keys {
+		oldValue, existed := old[key]
+		value, exists := updated[key]
+		switch {
+		case !existed:
+			lines = append(lines, "+ "+key)
+		case !exists:
+			lines = append(lines, "- "+key)
+		case compact(oldValue) != compact(value):
+			lines = append(lines, "~ "+key)
+		}
+	}
+	if len(lines) == 0 {
+		// Only the formatting changed
+		lines = append(lines, "~ value reformatted")
+	}
+	return lines
+}
+
+// DrillDown opens the versions of the secret
+func (s *Secrets) DrillDown(arn string) Resource {
+	return NewSecretVersions(arn, s.secretName(arn))
+}
+
+// SecretVersion represents a version of a Secrets Manager secret
+type SecretVersion struct {
+	ID               string
+	Stages           []string
+	CreatedDate      string
+	LastAccessedDate string
+}
+
+// SecretVersions implements Resource for the versions of a Secrets Manager secret
+type SecretVersions struct {
+	secretARN  string
+	secretName string
+	versions   []SecretVersion
+}
+
+// NewSecretVersions creates a new SecretVersions resource
+func NewSecretVersions(secretARN, secretName string) *SecretVersions {
+	return &SecretVersions{
+		secretARN:  secretARN,
+		secretName: secretName,
+		versions:   make([]SecretVersion, 0),
+	}
+}
+
+// Name returns the display name
+func (v *SecretVersions) Name() string {
+	return fmt.Sprintf("Secret Versions: %s", v.secretName)
+}
+
+// Columns returns the column definitions
+func (v *SecretVersions) Columns() []Column {
+	return []Column{
+		{Name: "Version ID", Width: 40},
+		{Name: "Stages", Width: 30},
+		{Name: "Created", Width: 20},
+		{Name: "Last Accessed", Width: 15},
+	}
+}
+
+// Fetch retrieves the versions of the secret, the newest first
+func (v *SecretVersions) Fetch(ctx context.Context, c *client.Client) error {
+	v.versions = make([]SecretVersion, 0)
+
+	paginator := secretsmanager.NewListSecretVersionIdsPaginator(c.SecretsManager(), &secretsmanager.ListSecretVersionIdsInput{
+		SecretId:          &v.secretARN,
+		IncludeDeprecated: aws.Bool(true),
+	})
+
+	type dated struct {
+		version SecretVersion
+		created int64
+	}
+	list := make([]dated, 0)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list versions of %s: %w", v.secretName, err)
+		}
+
+		for _, entry := range output.Versions {
+			version := SecretVersion{
+				ID:     stringValue(entry.VersionId),
+				Stages: entry.VersionStages,
+			}
+			var created int64
+			if entry.CreatedDate != nil {
+				version.CreatedDate = entry.CreatedDate.Format("2006-01-02 15:04:05")
+				created = entry.CreatedDate.UnixNano()
+			}
+			if entry.LastAccessedDate != nil {
+				version.LastAccessedDate = entry.LastAccessedDate.Format("2006-01-02")
+			}
+			list = append(list, dated{version: version, created: created})
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].created > list[j].created })
+	for _, entry := range list {
+		v.versions = append(v.versions, entry.version)
+	}
+
+	return nil
+}
+
+// Rows returns the table data
+func (v *SecretVersions) Rows() [][]string {
+	rows := make([][]string, len(v.versions))
+	for i, version := range v.versions {
+		stages := strings.Join(version.Stages, ", ")
+		if stages == "" {
+			stages = "deprecated"
+		}
+		rows[i] = []string{
+			version.ID,
+			stages,
+			version.CreatedDate,
+			version.LastAccessedDate,
+		}
+	}
+	return rows
+}
+
+// GetID returns the version ID at the given index
+func (v *SecretVersions) GetID(index int) string {
+	if index >= 0 && index < len(v.versions) {
+		return v.versions[index].ID
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for secret versions
+func (v *SecretVersions) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:             'P',
+			Label:           "promote",
+			Description:     "Promote version to AWSCURRENT",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "Are you sure you want to make version %s the current one? Applications will read it from now on.",
+			Handler: func(ctx context.Context, c *client.Client, versionID string) error {
+				var current *string
+				for _, version := range v.versions {
+					if slices.Contains(version.Stages, "AWSCURRENT") {
+						current = aws.String(version.ID)
+					}
+				}
+				if stringValue(current) == versionID {
+					return fmt.Errorf("version %s is already current", versionID)
+				}
+
+				// Secrets Manager moves AWSPREVIOUS to the demoted version itself
+				_, err := c.SecretsManager().UpdateSecretVersionStage(ctx, &secretsmanager.UpdateSecretVersionStageInput{
+					SecretId:            &v.secretARN,
+					VersionStage:        aws.String("AWSCURRENT"),
+					MoveToVersionId:     &versionID,
+					RemoveFromVersionId: current,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to promote version %s: %w", versionID, err)
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// SecretUsage represents a reference to a secret found in another resource
+type SecretUsage struct {
+	Service  string
+	Resource string
+	Location string
+	// Registry key and ID of the referencing resource in a9s, empty when it has no view
+	ViewKey string
+	ViewID  string
+}
+
+// SecretUsages implements Resource for the Lambda functions, ECS task definitions
+// and CloudFormation stacks referencing a secret
+type SecretUsages struct {
+	secretARN  string
+	secretName string
+	usages     []SecretUsage
+}
+
+// NewSecretUsages creates a new SecretUsages resource
+func NewSecretUsages(secretARN, secretName string) *SecretUsages {
+	return &SecretUsages{
+		secretARN:  secretARN,
+		secretName: secretName,
+		usages:     make([]SecretUsage, 0),
+	}
+}
+
+// Name returns the display name
+func (u *SecretUsages) Name() string {
+	return fmt.Sprintf("Secret Usages: %s", u.secretName)
+}
+
+// Columns returns the column definitions
+func (u *SecretUsages) Columns() []Column {
+	return []Column{
+		{Name: "Service", Width: 15},
+		{Name: "Resource", Width: 50},
+		{Name: "Location", Width: 50},
+	}
+}
+
+// references reports whether a value references the secret, by full or partial
+// ARN, by dynamic reference or by exact name
+func (u *SecretUsages) references(value string) bool {
+	// Secret ARNs end with a random 6 characters suffix that partial ARNs omit
+	partialARN := u.secretARN
+	if len(partialARN) > 7 && partialARN[len(partialARN)-7] == '-' {
+		partialARN = partialARN[:len(partialARN)-7]
+	}
+	return value == u.secretName ||
+		strings.Contains(value, partialARN) ||
+		strings.Contains(value, "secretsmanager:"+u.secretName)
+}
+
+// Fetch searches the Lambda functions, ECS task definitions and CloudFormation stacks for the secret
+func (u *SecretUsages) Fetch(ctx context.Context, c *client.Client) error {
+	u.usages = make([]SecretUsage, 0)
+
+	if err := u.fetchLambdaUsages(ctx, c); err != nil {
+		return err
+	}
+	if err := u.fetchECSUsages(ctx, c); err != nil {
+		return err
+	}
+	return u.fetchStackUsages(ctx, c)
+}
+
+// fetchLambdaUsages searches the environment variables of the Lambda functions
+func (u *SecretUsages) fetchLambdaUsages(ctx context.Context, c *client.Client) error {
+	paginator := lambda.NewListFunctionsPaginator(c.Lambda(), &lambda.ListFunctionsInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list Lambda functions: %w", err)
+		}
+
+		for _, fn := range output.Functions {
+			if fn.Environment == nil {
+				continue
+			}
+
+			names := make([]string, 0, len(fn.Environment.Variables))
+			for name := range fn.Environment.Variables {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				if !u.references(fn.Environment.Variables[name]) {
+					continue
+				}
+				u.usages = append(u.usages, SecretUsage{
+					Service:  "lambda",
+					Resource: stringValue(fn.FunctionName),
+					Location: "environment " + name,
+					ViewKey:  "lambda",
+					ViewID:   stringValue(fn.FunctionName),
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// fetchECSUsages searches the container secrets and environment of the latest
+// active revision of each task definition family
+func (u *SecretUsages) fetchECSUsages(ctx context.Context, c *client.Client) error {
+	paginator := ecs.NewListTaskDefinitionFamiliesPaginator(c.ECS(), &ecs.ListTaskDefinitionFamiliesInput{
+		Status: ecstypes.TaskDefinitionFamilyStatusActive,
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list ECS task definition families: %w", err)
+		}
+
+		for _, family := range output.Families {
+			describeOutput, err := c.ECS().DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{
+				TaskDefinition: aws.String(family),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to describe task definition %s: %w", family, err)
+			}
+			definition := describeOutput.TaskDefinition
+			if definition == nil {
+				continue
+			}
+
+			arn := stringValue(definition.TaskDefinitionArn)
+			resource := fmt.Sprintf("%s:%d", family, definition.Revision)
+			for _, container := range definition.ContainerDefinitions {
+				for _, secret := range container.Secrets {
+					if u.references(stringValue(secret.ValueFrom)) {
+						u.usages = append(u.usages, SecretUsage{
+							Service:  "ecs",
+							Resource: resource,
+							Location: fmt.Sprintf("container %s secret %s", stringValue(container.Name), stringValue(secret.Name)),
+							ViewKey:  "ecs-taskdefs",
+							ViewID:   arn,
+						})
+					}
+				}
+				for _, variable := range container.Environment {
+					if u.references(stringValue(variable.Value)) {
+						u.usages = append(u.usages, SecretUsage{
+							Service:  "ecs",
+							Resource: resource,
+							Location: fmt.Sprintf("container %s environment %s", stringValue(container.Name), stringValue(variable.Name)),
+							ViewKey:  "ecs-taskdefs",
+							ViewID:   arn,
+						})
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// fetchStackUsages searches the parameters and templates of the CloudFormation stacks
+func (u *SecretUsages) fetchStackUsages(ctx context.Context, c *client.Client) error {
+	paginator := cloudformation.NewDescribeStacksPaginator(c.CloudFormation(), &cloudformation.DescribeStacksInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list CloudFormation stacks: %w", err)
+		}
+
+		for _, stack := range output.Stacks {
+			name := stringValue(stack.StackName)
+
+			for _, parameter := range stack.Parameters {
+				if u.references(stringValue(parameter.ParameterValue)) {
+					u.usages = append(u.usages, SecretUsage{
+						Service:  "cloudformation",
+						Resource: name,
+						Location: "parameter " + stringValue(parameter.ParameterKey),
+					})
+				}
+			}
+
+			templateOutput, err := c.CloudFormation().GetTemplate(ctx, &cloudformation.GetTemplateInput{
+				StackName: stack.StackId,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to get template of stack %s: %w", name, err)
+			}
+			if u.references(stringValue(templateOutput.TemplateBody)) {
+				u.usages = append(u.usages, SecretUsage{
+					Service:  "cloudformation",
+					Resource: name,
+					Location: "template",
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// Rows returns the table data
+func (u *SecretUsages) Rows() [][]string {
+	rows := make([][]string, len(u.usages))
+	for i, usage := range u.usages {
+		rows[i] = []string{
+			usage.Service,
+			usage.Resource,
+			usage.Location,
+		}
+	}
+	return rows
+}
+
+// GetID returns the position of the usage at the given index, usages have no ID of their own
+func (u *SecretUsages) GetID(index int) string {
+	if index >= 0 && index < len(u.usages) {
+		return strconv.Itoa(index)
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for secret usages
+func (u *SecretUsages) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:            'g',
+			Label:          "goto",
+			Description:    "Open the referencing resource in its view",
+			NeedsSelection: true,
+			Goto: func(id string) (string, string, error) {
+				index, err := strconv.Atoi(id)
+				if err != nil || index < 0 || index >= len(u.usages) {
+					return "", "", fmt.Errorf("usage %s is not listed", id)
+				}
+				usage := u.usages[index]
+				if usage.ViewKey == "" {
+					return "", "", fmt.Errorf("no view for %s resources", usage.Service)
+				}
+				return usage.ViewKey, usage.ViewID, nil
+			},
+		},
+	}
+}
+
+// SecretValueEntry represents a key of a JSON secret, or the whole value of a plain secret
+type SecretValueEntry struct {
+	Key   string
+	Value string
+}
+
+// SecretValue implements Resource for the current value of a secret, one row per key of a
+// JSON object, masked until revealed and recorded in the audit log when revealed
+type SecretValue struct {
+	secretARN  string
+	secretName string
+	kmsKey     string
+	kmsAliases *secretKMSAliases
+	revealed   bool
+	// pendingAudit is set when the value was just revealed and is recorded on the next fetch
+	pendingAudit bool
+	raw          string
+	entries      []SecretValueEntry
+}
+
+// NewSecretValue creates a new SecretValue resource
+func NewSecretValue(secretARN, secretName, kmsKey string) *SecretValue {
+	return &SecretValue{
+		secretARN:  secretARN,
+		secretName: secretName,
+		kmsKey:     kmsKey,
+		kmsAliases: &secretKMSAliases{},
+		entries:    make([]SecretValueEntry, 0),
+	}
+}
+
+// Name returns the display name
+func (v *SecretValue) Name() string {
+	if v.revealed {
+		return fmt.Sprintf("Secret Value: %s (revealed)", v.secretName)
+	}
+	return fmt.Sprintf("Secret Value: %s", v.secretName)
+}
+
+// Columns returns the column definitions
+func (v *SecretValue) Columns() []Column {
+	return []Column{
+		{Name: "Key", Width: 30},
+		{Name: "Value", Width: 90},
+	}
+}
+
+// Fetch retrieves the current value of the secret
+func (v *SecretValue) Fetch(ctx context.Context, c *client.Client) error {
+	v.entries = make([]SecretValueEntry, 0)
+	v.raw = ""
+
+	output, err := c.SecretsManager().GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: &v.secretARN,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get value of %s: %w", v.secretName, err)
+	}
+
+	if v.pendingAudit {
+		v.pendingAudit = false
+		audit.Record(audit.Entry{
+			Profile:  c.Profile(),
+			Region:   c.Region(),
+			Resource: "Secrets Manager",
+			Action:   "reveal value",
+			Target:   v.secretARN,
+			Ticket:   client.TicketFromContext(ctx),
+		})
+	}
+
+	if output.SecretString == nil {
+		v.raw = base64.StdEncoding.EncodeToString(output.SecretBinary)
+		v.entries = append(v.entries, SecretValueEntry{Key: "(binary, base64)", Value: v.raw})
+		return nil
+	}
+	v.raw = *output.SecretString
+
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(v.raw), &values); err != nil || values == nil {
+		v.entries = append(v.entries, SecretValueEntry{Key: "(value)", Value: v.raw})
+		return nil
+	}
+	for key, value := range values {
+		var text string
+		if err := json.Unmarshal(value, &text); err != nil {
+			text = string(value)
+		}
+		v.entries = append(v.entries, SecretValueEntry{Key: key, Value: text})
+	}
+	sort.Slice(v.entries, func(i, j int) bool {
+		return v.entries[i].Key < v.entries[j].Key
+	})
+
+	return nil
+}
+
+// Rows returns the table data
+func (v *SecretValue) Rows() [][]string {
+	rows := make([][]string, len(v.entries))
+	for i, entry := range v.entries {
+		value := entry.Value
+		if !v.revealed && value != "" {
+			value = maskedValue
+		}
+		rows[i] = []string{entry.Key, value}
+	}
+	return rows
+}
+
+// GetID returns the key at the given index
+func (v *SecretValue) GetID(index int) string {
+	if index >= 0 && index < len(v.entries) {
+		return v.entries[index].Key
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for the secret value
+func (v *SecretValue) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:         'v',
+			Label:       "reveal",
+			Description: "Toggle the masking of the value, revealing it is audited",
+			Toggle: func() {
+				v.revealed = !v.revealed
+				v.pendingAudit = v.revealed
+			},
+		},
+		{
+			Key:         'd',
+			Label:       "detail",
+			Description: "Show the whole value, JSON pretty-printed",
+			TextHandler: func(ctx context.Context, c *client.Client, _ string) (string, error) {
+				if !v.revealed {
+					return "", fmt.Errorf("reveal the value first with v")
+				}
+				var pretty bytes.Buffer
+				if err := json.Indent(&pretty, []byte(v.raw), "", "  "); err != nil {
+					return v.raw, nil
+				}
+				return pretty.String(), nil
+			},
+		},
+		{
+			Key:         'e',
+			Label:       "edit",
+			Description: "Edit the value in the editor, pre-filled once revealed",
+			EditPrepare: v.kmsAliases.load,
+			EditTemplate: func(string) string {
+				return secretValueTemplate(v.secretName, v.kmsKey, v.editableValue(), v.kmsAliases.aliases)
+			},
+			EditPlan: func(ctx context.Context, c *client.Client, _, text string) ([]Step, error) {
+				return planSecretValue(ctx, c, v.secretARN, v.secretName, v.kmsKey, text)
+			},
+		},
+	}
+}
+
+// editableValue returns the value pre-filled in the editor, JSON pretty-printed, only once revealed
+func (v *SecretValue) editableValue() string {
+	if !v.revealed {
+		return ""
+	}
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, []byte(v.raw), "", "  "); err != nil {
+		return v.raw + "\n"
+	}
+	return pretty.String() + "\n"
 }
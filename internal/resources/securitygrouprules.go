@@ -0,0 +1,591 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// webPorts are the ports whose opening to the whole internet is not worth a warning
+var webPorts = map[int32]bool{80: true, 443: true}
+
+// SecurityGroupRule represents an inbound or outbound rule of a security group
+type SecurityGroupRule struct {
+	RuleID      string
+	Egress      bool
+	Protocol    string // tcp, udp, icmp, icmpv6, -1 for all or a protocol number
+	FromPort    int32  // ICMP type for ICMP rules, -1 for all
+	ToPort      int32  // ICMP code for ICMP rules, -1 for all
+	Source      string // CIDR, security group or prefix list, the destination of outbound rules
+	Description string
+}
+
+// SecurityGroupRules implements Resource for the rules of a security group
+type SecurityGroupRules struct {
+	groupID string
+	rules   []SecurityGroupRule
+}
+
+// NewSecurityGroupRules creates a new SecurityGroupRules resource
+func NewSecurityGroupRules(groupID string) *SecurityGroupRules {
+	return &SecurityGroupRules{
+		groupID: groupID,
+		rules:   make([]SecurityGroupRule, 0),
+	}
+}
+
+// Name returns the display name
+func (s *SecurityGroupRules) Name() string {
+	return fmt.Sprintf("Security Group Rules (%s)", s.groupID)
+}
+
+// Columns returns the column definitions
+func (s *SecurityGroupRules) Columns() []Column {
+	return []Column{
+		{Name: "Direction", Width: 10},
+		{Name: "Protocol", Width: 10},
+		{Name: "Ports", Width: 13},
+		{Name: "Source / Destination", Width: 45},
+		{Name: "Description", Width: 40},
+		{Name: "Rule ID", Width: 25},
+	}
+}
+
+// Fetch retrieves the rules of the security group, inbound rules first
+func (s *SecurityGroupRules) Fetch(ctx context.Context, c *client.Client) error {
+	s.rules = make([]SecurityGroupRule, 0)
+
+	var egress []SecurityGroupRule
+	paginator := ec2.NewDescribeSecurityGroupRulesPaginator(c.EC2(), &ec2.DescribeSecurityGroupRulesInput{
+		Filters: []ec2types.Filter{{Name: aws.String("group-id"), Values: []string{s.groupID}}},
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe security group rules: %w", err)
+		}
+		for _, rule := range output.SecurityGroupRules {
+			item := SecurityGroupRule{
+				RuleID:      stringValue(rule.SecurityGroupRuleId),
+				Egress:      ptrBoolValue(rule.IsEgress),
+				Protocol:    stringValue(rule.IpProtocol),
+				FromPort:    ptrInt32Value(rule.FromPort),
+				ToPort:      ptrInt32Value(rule.ToPort),
+				Description: stringValue(rule.Description),
+			}
+			switch {
+			case rule.CidrIpv4 != nil:
+				item.Source = *rule.CidrIpv4
+			case rule.CidrIpv6 != nil:
+				item.Source = *rule.CidrIpv6
+			case rule.PrefixListId != nil:
+				item.Source = *rule.PrefixListId
+			case rule.ReferencedGroupInfo != nil:
+				item.Source = stringValue(rule.ReferencedGroupInfo.GroupId)
+			}
+			if item.Egress {
+				egress = append(egress, item)
+			} else {
+				s.rules = append(s.rules, item)
+			}
+		}
+	}
+	s.rules = append(s.rules, egress...)
+
+	return nil
+}
+
+// Rows returns the table data
+func (s *SecurityGroupRules) Rows() [][]string {
+	rows := make([][]string, len(s.rules))
+	for i, rule := range s.rules {
+		rows[i] = []string{
+			rule.direction(),
+			protocolName(rule.Protocol),
+			rule.ports(),
+			rule.Source,
+			rule.Description,
+			rule.RuleID,
+		}
+	}
+	return rows
+}
+
+// GetID returns the rule ID at the given index
+func (s *SecurityGroupRules) GetID(index int) string {
+	if index >= 0 && index < len(s.rules) {
+		return s.rules[index].RuleID
+	}
+	return ""
+}
+
+// Highlight flags the inbound rules open to the whole internet on other ports than HTTP(S)
+func (s *SecurityGroupRules) Highlight(index int) bool {
+	return index >= 0 && index < len(s.rules) && s.rules[index].exposed()
+}
+
+// QuickActions returns the available quick actions for security group rules
+func (s *SecurityGroupRules) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:          'c',
+			Label:        "add",
+			Description:  "Add a rule in the editor",
+			EditTemplate: s.newRuleTemplate,
+			EditPlan: func(ctx context.Context, c *client.Client, _, text string) ([]Step, error) {
+				return s.planAddRule(text)
+			},
+		},
+		{
+			Key:            'e',
+			Label:          "edit",
+			Description:    "Edit the rule in the editor",
+			NeedsSelection: true,
+			EditTemplate: func(ruleID string) string {
+				rule, ok := s.rule(ruleID)
+				if !ok {
+					return "# Rule " + ruleID + " is not listed\n"
+				}
+				return s.ruleTemplate(rule)
+			},
+			EditPlan: s.planEditRule,
+		},
+		{
+			Key:            'D',
+			Label:          "revoke",
+			Description:    "Revoke the rule",
+			NeedsSelection: true,
+			Plan: func(ctx context.Context, c *client.Client, ruleID string) ([]Step, error) {
+				rule, ok := s.rule(ruleID)
+				if !ok {
+					return nil, fmt.Errorf("rule %s is not listed", ruleID)
+				}
+				return []Step{
+					{
+						Description: fmt.Sprintf("Revoke %s", rule.describe()),
+						Run: func(ctx context.Context, c *client.Client) error {
+							return s.revokeRule(ctx, c, rule)
+						},
+					},
+				}, nil
+			},
+		},
+	}
+}
+
+// rule returns the listed rule with the given ID
+func (s *SecurityGroupRules) rule(ruleID string) (SecurityGroupRule, bool) {
+	for _, rule := range s.rules {
+		if rule.RuleID == ruleID {
+			return rule, true
+		}
+	}
+	return SecurityGroupRule{}, false
+}
+
+// direction returns inbound or outbound
+func (r SecurityGroupRule) direction() string {
+	if r.Egress {
+		return "outbound"
+	}
+	return "inbound"
+}
+
+// isICMP reports whether the ports of the rule are an ICMP type and code
+func (r SecurityGroupRule) isICMP() bool {
+	return r.Protocol == "icmp" || r.Protocol == "icmpv6" || r.Protocol == "1" || r.Protocol == "58"
+}
+
+// hasPorts reports whether the protocol of the rule has ports
+func (r SecurityGroupRule) hasPorts() bool {
+	return r.Protocol == "tcp" || r.Protocol == "udp" || r.Protocol == "6" || r.Protocol == "17"
+}
+
+// ports returns the ports of the rule as written in the editor
+func (r SecurityGroupRule) ports() string {
+	switch {
+	case r.isICMP():
+		if r.FromPort == -1 {
+			return "all"
+		}
+		if r.ToPort == -1 {
+			return strconv.Itoa(int(r.FromPort))
+		}
+		return fmt.Sprintf("%d:%d", r.FromPort, r.ToPort)
+	case !r.hasPorts(), r.FromPort == 0 && r.ToPort == 65535:
+		return "all"
+	case r.FromPort == r.ToPort:
+		return strconv.Itoa(int(r.FromPort))
+	default:
+		return fmt.Sprintf("%d-%d", r.FromPort, r.ToPort)
+	}
+}
+
+// exposed reports whether an inbound rule opens other ports than HTTP(S) to the whole internet
+func (r SecurityGroupRule) exposed() bool {
+	if r.Egress || (r.Source != "0.0.0.0/0" && r.Source != "::/0") {
+		return false
+	}
+	return !r.hasPorts() || r.FromPort != r.ToPort || !webPorts[r.FromPort]
+}
+
+// describe describes a rule on one line for the plan steps
+func (r SecurityGroupRule) describe() string {
+	preposition := "from"
+	if r.Egress {
+		preposition = "to"
+	}
+	return fmt.Sprintf("%s %s %s %s %s", r.direction(), protocolName(r.Protocol), r.ports(), preposition, r.Source)
+}
+
+// same reports whether two rules allow the same traffic, regardless of their descriptions
+func (r SecurityGroupRule) same(other SecurityGroupRule) bool {
+	return r.Egress == other.Egress && protocolName(r.Protocol) == protocolName(other.Protocol) &&
+		r.ports() == other.ports() && r.Source == other.Source
+}
+
+// protocolName returns the name of a protocol as written in the editor
+func protocolName(protocol string) string {
+	switch protocol {
+	case "-1":
+		return "all"
+	case "6":
+		return "tcp"
+	case "17":
+		return "udp"
+	case "1":
+		return "icmp"
+	case "58":
+		return "icmpv6"
+	}
+	return protocol
+}
+
+// ruleTemplateHelp explains the rule template, shared by creation and edition
+const ruleTemplateHelp = `# Lines starting with # are ignored. Direction is inbound or outbound.
+# Protocol is tcp, udp, icmp, icmpv6, all or a protocol number.
+# Ports is a port, a range like 8000-8080 or all, for ICMP a type or type:code.
+# Source is a CIDR like 203.0.113.0/24 or ::/0, a security group ID or a prefix
+# list ID, it is the destination of outbound rules.
+`
+
+// newRuleTemplate returns the editor template of a new rule
+func (s *SecurityGroupRules) newRuleTemplate(string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# New rule of %s.\n", s.groupID)
+	b.WriteString(ruleTemplateHelp)
+	b.WriteString("Direction: inbound\n")
+	b.WriteString("Protocol: tcp\n")
+	b.WriteString("Ports: 443\n")
+	b.WriteString("Source: \n")
+	b.WriteString("Description: \n")
+	return b.String()
+}
+
+// ruleTemplate returns the editor template of an existing rule
+func (s *SecurityGroupRules) ruleTemplate(rule SecurityGroupRule) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Rule %s of %s, the direction cannot be changed.\n", rule.RuleID, s.groupID)
+	b.WriteString(ruleTemplateHelp)
+	fmt.Fprintf(&b, "Direction: %s\n", rule.direction())
+	fmt.Fprintf(&b, "Protocol: %s\n", protocolName(rule.Protocol))
+	fmt.Fprintf(&b, "Ports: %s\n", rule.ports())
+	fmt.Fprintf(&b, "Source: %s\n", rule.Source)
+	fmt.Fprintf(&b, "Description: %s\n", rule.Description)
+	return b.String()
+}
+
+// parseRuleTemplate parses and validates an edited rule template
+func parseRuleTemplate(text string) (SecurityGroupRule, error) {
+	var rule SecurityGroupRule
+
+	fields := make(map[string]string)
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+		field, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return rule, fmt.Errorf("expected a Field: value line, got %q", line)
+		}
+		fields[strings.ToLower(strings.TrimSpace(field))] = strings.TrimSpace(value)
+	}
+
+	switch strings.ToLower(fields["direction"]) {
+	case "inbound":
+	case "outbound":
+		rule.Egress = true
+	default:
+		return rule, fmt.Errorf("direction must be inbound or outbound, got %q", fields["direction"])
+	}
+
+	protocol := strings.ToLower(fields["protocol"])
+	switch protocol {
+	case "tcp", "udp", "icmp", "icmpv6":
+		rule.Protocol = protocol
+	case "all", "-1":
+		rule.Protocol = "-1"
+	default:
+		number, err := strconv.Atoi(protocol)
+		if err != nil || number < 0 || number > 255 {
+			return rule, fmt.Errorf("protocol must be tcp, udp, icmp, icmpv6, all or a number up to 255, got %q", fields["protocol"])
+		}
+		rule.Protocol = protocolName(protocol)
+	}
+
+	if err := parseRulePorts(&rule, strings.ToLower(fields["ports"])); err != nil {
+		return rule, err
+	}
+
+	source, err := validateRuleSource(fields["source"])
+	if err != nil {
+		return rule, err
+	}
+	rule.Source = source
+
+	rule.Description = fields["description"]
+	if len(rule.Description) > 255 {
+		return rule, fmt.Errorf("the description is limited to 255 characters")
+	}
+
+	return rule, nil
+}
+
+// parseRulePorts sets the ports of a rule from a port, a range, an ICMP type[:code] or all
+func parseRulePorts(rule *SecurityGroupRule, ports string) error {
+	switch {
+	case rule.isICMP():
+		rule.FromPort, rule.ToPort = -1, -1
+		if ports == "all" || ports == "" {
+			return nil
+		}
+		icmpType, icmpCode, hasCode := strings.Cut(ports, ":")
+		value, err := strconv.Atoi(icmpType)
+		if err != nil || value < 0 || value > 255 {
+			return fmt.Errorf("ICMP ports are a type or type:code, got %q", ports)
+		}
+		rule.FromPort = int32(value)
+		if hasCode {
+			value, err := strconv.Atoi(icmpCode)
+			if err != nil || value < 0 || value > 255 {
+				return fmt.Errorf("ICMP ports are a type or type:code, got %q", ports)
+			}
+			rule.ToPort = int32(value)
+		}
+		return nil
+	case !rule.hasPorts():
+		if ports != "all" && ports != "" {
+			return fmt.Errorf("protocol %s has no ports, use all", protocolName(rule.Protocol))
+		}
+		rule.FromPort, rule.ToPort = -1, -1
+		return nil
+	case ports == "all":
+		rule.FromPort, rule.ToPort = 0, 65535
+		return nil
+	}
+
+	from, to, isRange := strings.Cut(ports, "-")
+	if !isRange {
+		to = from
+	}
+	fromPort, err := strconv.Atoi(strings.TrimSpace(from))
+	if err != nil || fromPort < 0 || fromPort > 65535 {
+		return fmt.Errorf("ports must be a port, a range like 8000-8080 or all, got %q", ports)
+	}
+	toPort, err := strconv.Atoi(strings.TrimSpace(to))
+	if err != nil || toPort < fromPort || toPort > 65535 {
+		return fmt.Errorf("ports must be a port, a range like 8000-8080 or all, got %q", ports)
+	}
+	rule.FromPort, rule.ToPort = int32(fromPort), int32(toPort)
+	return nil
+}
+
+// validateRuleSource checks that a source is a CIDR, a security group or a prefix list
+func validateRuleSource(source string) (string, error) {
+	switch {
+	case source == "":
+		return "", fmt.Errorf("the Source: field is required")
+	case strings.HasPrefix(source, "sg-"), strings.HasPrefix(source, "pl-"):
+		return source, nil
+	}
+
+	ip, network, err := net.ParseCIDR(source)
+	if err != nil {
+		if net.ParseIP(source) != nil {
+			return "", fmt.Errorf("%s is an address, write it as a CIDR like %s/32", source, source)
+		}
+		return "", fmt.Errorf("source must be a CIDR, a security group ID or a prefix list ID, got %q", source)
+	}
+	if !ip.Equal(network.IP) {
+		return "", fmt.Errorf("%s has host bits set, did you mean %s?", source, network)
+	}
+	return network.String(), nil
+}
+
+// exposureNote warns about a rule opening other ports than HTTP(S) to the whole internet
+func exposureNote(rule SecurityGroupRule) []Step {
+	if !rule.exposed() {
+		return nil
+	}
+	return []Step{{Description: fmt.Sprintf("Warning: %s %s is opened to the whole internet", protocolName(rule.Protocol), rule.ports())}}
+}
+
+// planAddRule validates an edited rule template and returns its authorization as steps
+func (s *SecurityGroupRules) planAddRule(text string) ([]Step, error) {
+	rule, err := parseRuleTemplate(text)
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range s.rules {
+		if existing.same(rule) {
+			return nil, fmt.Errorf("rule %s already allows %s", existing.RuleID, rule.describe())
+		}
+	}
+
+	steps := exposureNote(rule)
+	return append(steps, Step{
+		Description: fmt.Sprintf("Authorize %s", rule.describe()),
+		Run: func(ctx context.Context, c *client.Client) error {
+			return s.authorizeRule(ctx, c, rule)
+		},
+	}), nil
+}
+
+// planEditRule validates an edited rule template and returns its modification as steps
+func (s *SecurityGroupRules) planEditRule(ctx context.Context, c *client.Client, ruleID, text string) ([]Step, error) {
+	before, ok := s.rule(ruleID)
+	if !ok {
+		return nil, fmt.Errorf("rule %s is not listed", ruleID)
+	}
+	after, err := parseRuleTemplate(text)
+	if err != nil {
+		return nil, err
+	}
+	if after.Egress != before.Egress {
+		return nil, fmt.Errorf("the direction cannot be changed, add an %s rule instead", after.direction())
+	}
+	if after.same(before) && after.Description == before.Description {
+		return nil, fmt.Errorf("the rule is unchanged")
+	}
+	for _, existing := range s.rules {
+		if existing.RuleID != ruleID && existing.same(after) {
+			return nil, fmt.Errorf("rule %s already allows %s", existing.RuleID, after.describe())
+		}
+	}
+
+	description := fmt.Sprintf("Change %s to %s", before.describe(), after.describe())
+	if after.same(before) {
+		description = fmt.Sprintf("Change the description of %s to %q", before.describe(), after.Description)
+	}
+	after.RuleID = ruleID
+
+	steps := exposureNote(after)
+	return append(steps, Step{
+		Description: description,
+		Run: func(ctx context.Context, c *client.Client) error {
+			_, err := c.EC2().ModifySecurityGroupRules(ctx, &ec2.ModifySecurityGroupRulesInput{
+				GroupId: aws.String(s.groupID),
+				SecurityGroupRules: []ec2types.SecurityGroupRuleUpdate{
+					{SecurityGroupRuleId: aws.String(ruleID), SecurityGroupRule: after.request()},
+				},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to modify rule %s: %w", ruleID, err)
+			}
+			return nil
+		},
+	}), nil
+}
+
+// request returns the rule as a modification request
+func (r SecurityGroupRule) request() *ec2types.SecurityGroupRuleRequest {
+	request := &ec2types.SecurityGroupRuleRequest{
+		IpProtocol:  aws.String(r.Protocol),
+		FromPort:    aws.Int32(r.FromPort),
+		ToPort:      aws.Int32(r.ToPort),
+		Description: aws.String(r.Description),
+	}
+	switch {
+	case strings.HasPrefix(r.Source, "sg-"):
+		request.ReferencedGroupId = aws.String(r.Source)
+	case strings.HasPrefix(r.Source, "pl-"):
+		request.PrefixListId = aws.String(r.Source)
+	case strings.Contains(r.Source, ":"):
+		request.CidrIpv6 = aws.String(r.Source)
+	default:
+		request.CidrIpv4 = aws.String(r.Source)
+	}
+	return request
+}
+
+// permission returns the rule as an IP permission to authorize
+func (r SecurityGroupRule) permission() ec2types.IpPermission {
+	permission := ec2types.IpPermission{
+		IpProtocol: aws.String(r.Protocol),
+		FromPort:   aws.Int32(r.FromPort),
+		ToPort:     aws.Int32(r.ToPort),
+	}
+	var description *string
+	if r.Description != "" {
+		description = aws.String(r.Description)
+	}
+	switch {
+	case strings.HasPrefix(r.Source, "sg-"):
+		permission.UserIdGroupPairs = []ec2types.UserIdGroupPair{{GroupId: aws.String(r.Source), Description: description}}
+	case strings.HasPrefix(r.Source, "pl-"):
+		permission.PrefixListIds = []ec2types.PrefixListId{{PrefixListId: aws.String(r.Source), Description: description}}
+	case strings.Contains(r.Source, ":"):
+		permission.Ipv6Ranges = []ec2types.Ipv6Range{{CidrIpv6: aws.String(r.Source), Description: description}}
+	default:
+		permission.IpRanges = []ec2types.IpRange{{CidrIp: aws.String(r.Source), Description: description}}
+	}
+	return permission
+}
+
+// authorizeRule adds a rule to the security group
+func (s *SecurityGroupRules) authorizeRule(ctx context.Context, c *client.Client, rule SecurityGroupRule) error {
+	var err error
+	if rule.Egress {
+		_, err = c.EC2().AuthorizeSecurityGroupEgress(ctx, &ec2.AuthorizeSecurityGroupEgressInput{
+			GroupId:       aws.String(s.groupID),
+			IpPermissions: []ec2types.IpPermission{rule.permission()},
+		})
+	} else {
+		_, err = c.EC2().AuthorizeSecurityGroupIngress(ctx, &ec2.AuthorizeSecurityGroupIngressInput{
+			GroupId:       aws.String(s.groupID),
+			IpPermissions: []ec2types.IpPermission{rule.permission()},
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to authorize %s: %w", rule.describe(), err)
+	}
+	return nil
+}
+
+// revokeRule removes a rule from the security group
+func (s *SecurityGroupRules) revokeRule(ctx context.Context, c *client.Client, rule SecurityGroupRule) error {
+	var err error
+	if rule.Egress {
+		_, err = c.EC2().RevokeSecurityGroupEgress(ctx, &ec2.RevokeSecurityGroupEgressInput{
+			GroupId:              aws.String(s.groupID),
+			SecurityGroupRuleIds: []string{rule.RuleID},
+		})
+	} else {
+		_, err = c.EC2().RevokeSecurityGroupIngress(ctx, &ec2.RevokeSecurityGroupIngressInput{
+			GroupId:              aws.String(s.groupID),
+			SecurityGroupRuleIds: []string{rule.RuleID},
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to revoke rule %s: %w", rule.RuleID, err)
+	}
+	return nil
+}
@@ -2,12 +2,16 @@ package resources
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"a9s/internal/client"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
 )
 
 // SNSTopic represents an SNS topic
@@ -124,5 +128,308 @@ func (s *SNSTopics) GetID(index int) string {
 
 // QuickActions returns the available quick actions for SNS topics
 func (s *SNSTopics) QuickActions() []QuickAction {
-	return []QuickAction{}
+	return []QuickAction{
+		{
+			Key:             'P',
+			Label:           "publish",
+			Description:     "Publish a message",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "[yellow]publish[-] the message to topic [white]%s[-]?",
+			EditTemplate:    snsPublishTemplate,
+			EditHandler: func(ctx context.Context, c *client.Client, name, text string) error {
+				for _, topic := range s.topics {
+					if topic.Name == name {
+						return publishMessage(ctx, c, topic.ARN, text)
+					}
+				}
+				return fmt.Errorf("topic %s not found", name)
+			},
+		},
+	}
+}
+
+// snsPublishTemplate returns the message template edited before publishing to a topic
+func snsPublishTemplate(name string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Message published to %s, header lines starting with # are ignored.\n", name)
+	b.WriteString("# Headers are optional, add one Attribute: name=value line per message attribute.\n")
+	b.WriteString("# The message goes below the --- line.\n")
+	b.WriteString("Subject: \n")
+	b.WriteString("Attribute: \n")
+	if strings.HasSuffix(name, ".fifo") {
+		b.WriteString("Group: a9s\n")
+	}
+	b.WriteString("---\n")
+	return b.String()
+}
+
+// publishMessage parses an edited message template and publishes it to a topic
+func publishMessage(ctx context.Context, c *client.Client, topicARN, text string) error {
+	input := &sns.PublishInput{
+		TopicArn:          &topicARN,
+		MessageAttributes: make(map[string]snstypes.MessageAttributeValue),
+	}
+
+	headers, message, ok := strings.Cut(text, "\n---\n")
+	if !ok {
+		return fmt.Errorf("the --- line separating the headers from the message is missing")
+	}
+	for _, line := range strings.Split(headers, "\n") {
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+		header, value, _ := strings.Cut(line, ":")
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(header)) {
+		case "subject":
+			input.Subject = aws.String(value)
+		case "group":
+			input.MessageGroupId = aws.String(value)
+			input.MessageDeduplicationId = aws.String(fmt.Sprintf("a9s-%d", time.Now().UnixNano()))
+		case "attribute":
+			key, attribute, ok := strings.Cut(value, "=")
+			if !ok || strings.TrimSpace(key) == "" {
+				return fmt.Errorf("expected Attribute: name=value, got %q", line)
+			}
+			input.MessageAttributes[strings.TrimSpace(key)] = snstypes.MessageAttributeValue{
+				DataType:    aws.String("String"),
+				StringValue: aws.String(strings.TrimSpace(attribute)),
+			}
+		default:
+			return fmt.Errorf("unknown header %q", line)
+		}
+	}
+
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return fmt.Errorf("the message is empty")
+	}
+	input.Message = &message
+
+	if _, err := c.SNS().Publish(ctx, input); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", topicARN, err)
+	}
+	return nil
+}
+
+// DrillDown opens the subscriptions of the topic
+func (s *SNSTopics) DrillDown(name string) Resource {
+	for _, topic := range s.topics {
+		if topic.Name == name {
+			return NewSNSSubscriptions(topic.ARN, topic.Name)
+		}
+	}
+	return nil
+}
+
+// SNSSubscription represents a subscription of an SNS topic
+type SNSSubscription struct {
+	ID           string
+	ARN          string
+	Protocol     string
+	Endpoint     string
+	Status       string
+	FilterPolicy string
+}
+
+// SNSSubscriptions implements Resource for the subscriptions of an SNS topic
+type SNSSubscriptions struct {
+	topicARN      string
+	topicName     string
+	subscriptions []SNSSubscription
+}
+
+// NewSNSSubscriptions creates a new SNSSubscriptions resource
+func NewSNSSubscriptions(topicARN, topicName string) *SNSSubscriptions {
+	return &SNSSubscriptions{
+		topicARN:      topicARN,
+		topicName:     topicName,
+		subscriptions: make([]SNSSubscription, 0),
+	}
+}
+
+// Name returns the display name
+func (s *SNSSubscriptions) Name() string {
+	return fmt.Sprintf("SNS Subscriptions: %s", s.topicName)
+}
+
+// Columns returns the column definitions
+func (s *SNSSubscriptions) Columns() []Column {
+	return []Column{
+		{Name: "Protocol", Width: 10},
+		{Name: "Endpoint", Width: 50},
+		{Name: "Status", Width: 10},
+		{Name: "Filter Policy", Width: 50},
+		{Name: "ARN", Width: 60},
+	}
+}
+
+// Fetch retrieves the subscriptions of the topic and their attributes
+func (s *SNSSubscriptions) Fetch(ctx context.Context, c *client.Client) error {
+	s.subscriptions = make([]SNSSubscription, 0)
+
+	paginator := sns.NewListSubscriptionsByTopicPaginator(c.SNS(), &sns.ListSubscriptionsByTopicInput{
+		TopicArn: &s.topicARN,
+	})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list subscriptions of %s: %w", s.topicName, err)
+		}
+
+		for _, subscription := range output.Subscriptions {
+			arn := stringValue(subscription.SubscriptionArn)
+			sub := SNSSubscription{
+				ID:       arn,
+				ARN:      arn,
+				Protocol: stringValue(subscription.Protocol),
+				Endpoint: stringValue(subscription.Endpoint),
+				Status:   "confirmed",
+			}
+
+			// Pending subscriptions have no ARN yet, only a placeholder
+			if !strings.HasPrefix(arn, "arn:") {
+				sub.ID = fmt.Sprintf("pending:%s:%s", sub.Protocol, sub.Endpoint)
+				sub.ARN = ""
+				sub.Status = "pending"
+				s.subscriptions = append(s.subscriptions, sub)
+				continue
+			}
+
+			attrs, err := c.SNS().GetSubscriptionAttributes(ctx, &sns.GetSubscriptionAttributesInput{
+				SubscriptionArn: subscription.SubscriptionArn,
+			})
+			if err == nil && attrs.Attributes != nil {
+				sub.FilterPolicy = attrs.Attributes["FilterPolicy"]
+				if attrs.Attributes["PendingConfirmation"] == "true" {
+					sub.Status = "pending"
+				}
+			}
+
+			s.subscriptions = append(s.subscriptions, sub)
+		}
+	}
+
+	return nil
+}
+
+// Rows returns the table data
+func (s *SNSSubscriptions) Rows() [][]string {
+	rows := make([][]string, len(s.subscriptions))
+	for i, sub := range s.subscriptions {
+		rows[i] = []string{
+			sub.Protocol,
+			sub.Endpoint,
+			sub.Status,
+			sub.FilterPolicy,
+			sub.ARN,
+		}
+	}
+	return rows
+}
+
+// GetID returns the subscription identifier at the given index
+func (s *SNSSubscriptions) GetID(index int) string {
+	if index >= 0 && index < len(s.subscriptions) {
+		return s.subscriptions[index].ID
+	}
+	return ""
+}
+
+// subscriptionARN returns the ARN of a confirmed subscription
+func (s *SNSSubscriptions) subscriptionARN(id string) (string, error) {
+	for _, sub := range s.subscriptions {
+		if sub.ID != id {
+			continue
+		}
+		if sub.ARN == "" {
+			return "", fmt.Errorf("subscription to %s is pending confirmation", sub.Endpoint)
+		}
+		return sub.ARN, nil
+	}
+	return "", fmt.Errorf("subscription %s not found", id)
+}
+
+// QuickActions returns the available quick actions for SNS subscriptions
+func (s *SNSSubscriptions) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:         'c',
+			Label:       "confirm",
+			Description: "Confirm a subscription with the token sent to the endpoint",
+			InputLabel:  "Token: ",
+			InputHandler: func(ctx context.Context, c *client.Client, _ string, token string) error {
+				_, err := c.SNS().ConfirmSubscription(ctx, &sns.ConfirmSubscriptionInput{
+					TopicArn: &s.topicARN,
+					Token:    &token,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to confirm subscription: %w", err)
+				}
+				return nil
+			},
+		},
+		{
+			Key:             'u',
+			Label:           "unsubscribe",
+			Description:     "Delete subscription",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "Are you sure you want to unsubscribe %s?",
+			Handler: func(ctx context.Context, c *client.Client, id string) error {
+				arn, err := s.subscriptionARN(id)
+				if err != nil {
+					return err
+				}
+				_, err = c.SNS().Unsubscribe(ctx, &sns.UnsubscribeInput{
+					SubscriptionArn: &arn,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to unsubscribe %s: %w", arn, err)
+				}
+				return nil
+			},
+		},
+		{
+			Key:            'e',
+			Label:          "filter",
+			Description:    "Edit filter policy ('none' removes it)",
+			NeedsSelection: true,
+			InputLabel:     "Filter policy: ",
+			InputDefault: func(id string) string {
+				for _, sub := range s.subscriptions {
+					if sub.ID == id {
+						return sub.FilterPolicy
+					}
+				}
+				return ""
+			},
+			InputHandler: func(ctx context.Context, c *client.Client, id, policy string) error {
+				arn, err := s.subscriptionARN(id)
+				if err != nil {
+					return err
+				}
+				if policy == "none" {
+					policy = "{}"
+				}
+				if !json.Valid([]byte(policy)) {
+					return fmt.Errorf("filter policy is not valid JSON")
+				}
+				_, err = c.SNS().SetSubscriptionAttributes(ctx, &sns.SetSubscriptionAttributesInput{
+					SubscriptionArn: &arn,
+					AttributeName:   aws.String("FilterPolicy"),
+					AttributeValue:  &policy,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to set filter policy of %s: %w", arn, err)
+				}
+				return nil
+			},
+		},
+	}
 }
@@ -0,0 +1,211 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// SpotRequest represents a spot instance request or a spot fleet request
+type SpotRequest struct {
+	ID           string
+	Kind         string
+	State        string
+	InstanceType string
+	MaxPrice     string
+	Status       string
+	Instances    string
+	Created      string
+}
+
+// SpotRequests implements Resource for spot instance requests and spot fleets
+type SpotRequests struct {
+	requests []SpotRequest
+}
+
+// NewSpotRequests creates a new SpotRequests resource
+func NewSpotRequests() *SpotRequests {
+	return &SpotRequests{
+		requests: make([]SpotRequest, 0),
+	}
+}
+
+// Name returns the display name
+func (s *SpotRequests) Name() string {
+	return "Spot Requests"
+}
+
+// Columns returns the column definitions
+func (s *SpotRequests) Columns() []Column {
+	return []Column{
+		{Name: "ID", Width: 45},
+		{Name: "Kind", Width: 8},
+		{Name: "State", Width: 12},
+		{Name: "Instance Type", Width: 20},
+		{Name: "Max Price", Width: 10},
+		{Name: "Status", Width: 30},
+		{Name: "Instances", Width: 22},
+		{Name: "Created", Width: 20},
+	}
+}
+
+// Fetch retrieves the spot instance requests and spot fleet requests
+func (s *SpotRequests) Fetch(ctx context.Context, c *client.Client) error {
+	s.requests = make([]SpotRequest, 0)
+
+	requestPaginator := ec2.NewDescribeSpotInstanceRequestsPaginator(c.EC2(), &ec2.DescribeSpotInstanceRequestsInput{})
+	for requestPaginator.HasMorePages() {
+		output, err := requestPaginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list spot instance requests: %w", err)
+		}
+
+		for _, request := range output.SpotInstanceRequests {
+			r := SpotRequest{
+				ID:        stringValue(request.SpotInstanceRequestId),
+				Kind:      "request",
+				State:     string(request.State),
+				MaxPrice:  stringValue(request.SpotPrice),
+				Instances: stringValue(request.InstanceId),
+			}
+			if request.LaunchSpecification != nil {
+				r.InstanceType = string(request.LaunchSpecification.InstanceType)
+			}
+			if request.Status != nil {
+				r.Status = stringValue(request.Status.Code)
+			}
+			if request.CreateTime != nil {
+				r.Created = request.CreateTime.Format("2006-01-02 15:04:05")
+			}
+			s.requests = append(s.requests, r)
+		}
+	}
+
+	fleetPaginator := ec2.NewDescribeSpotFleetRequestsPaginator(c.EC2(), &ec2.DescribeSpotFleetRequestsInput{})
+	for fleetPaginator.HasMorePages() {
+		output, err := fleetPaginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list spot fleet requests: %w", err)
+		}
+
+		for _, fleet := range output.SpotFleetRequestConfigs {
+			r := SpotRequest{
+				ID:     stringValue(fleet.SpotFleetRequestId),
+				Kind:   "fleet",
+				State:  string(fleet.SpotFleetRequestState),
+				Status: string(fleet.ActivityStatus),
+			}
+			if config := fleet.SpotFleetRequestConfig; config != nil {
+				r.MaxPrice = stringValue(config.SpotPrice)
+				r.Instances = fmt.Sprintf("%.0f / %d", aws.ToFloat64(config.FulfilledCapacity), ptrInt32Value(config.TargetCapacity))
+				r.InstanceType = fleetInstanceTypes(config)
+			}
+			if fleet.CreateTime != nil {
+				r.Created = fleet.CreateTime.Format("2006-01-02 15:04:05")
+			}
+			s.requests = append(s.requests, r)
+		}
+	}
+
+	return nil
+}
+
+// fleetInstanceTypes lists the instance types of the launch specifications of a fleet,
+// fleets using launch templates only show their overrides
+func fleetInstanceTypes(config *ec2types.SpotFleetRequestConfigData) string {
+	seen := make(map[string]bool)
+	instanceTypes := make([]string, 0)
+	add := func(instanceType string) {
+		if instanceType != "" && !seen[instanceType] {
+			seen[instanceType] = true
+			instanceTypes = append(instanceTypes, instanceType)
+		}
+	}
+
+	for _, spec := range config.LaunchSpecifications {
+		add(string(spec.InstanceType))
+	}
+	for _, launchTemplate := range config.LaunchTemplateConfigs {
+		for _, override := range launchTemplate.Overrides {
+			add(string(override.InstanceType))
+		}
+	}
+	if len(instanceTypes) == 0 && len(config.LaunchTemplateConfigs) > 0 {
+		return "launch template"
+	}
+	return strings.Join(instanceTypes, ", ")
+}
+
+// Rows returns the table data
+func (s *SpotRequests) Rows() [][]string {
+	rows := make([][]string, len(s.requests))
+	for i, request := range s.requests {
+		rows[i] = []string{
+			request.ID,
+			request.Kind,
+			request.State,
+			request.InstanceType,
+			request.MaxPrice,
+			request.Status,
+			request.Instances,
+			request.Created,
+		}
+	}
+	return rows
+}
+
+// GetID returns the request ID at the given index
+func (s *SpotRequests) GetID(index int) string {
+	if index >= 0 && index < len(s.requests) {
+		return s.requests[index].ID
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for spot requests
+func (s *SpotRequests) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:             'X',
+			Label:           "cancel",
+			Description:     "Cancel spot request",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "[red]cancel[-] spot request [white]%s[-]? Its running instances are kept.",
+			Handler:         cancelSpotRequest,
+		},
+	}
+}
+
+// cancelSpotRequest cancels a spot instance request or a spot fleet, keeping their instances
+func cancelSpotRequest(ctx context.Context, c *client.Client, id string) error {
+	if strings.HasPrefix(id, "sfr-") {
+		output, err := c.EC2().CancelSpotFleetRequests(ctx, &ec2.CancelSpotFleetRequestsInput{
+			SpotFleetRequestIds: []string{id},
+			TerminateInstances:  aws.Bool(false),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to cancel spot fleet %s: %w", id, err)
+		}
+		for _, failure := range output.UnsuccessfulFleetRequests {
+			if failure.Error != nil {
+				return fmt.Errorf("failed to cancel spot fleet %s: %s", id, stringValue(failure.Error.Message))
+			}
+		}
+		return nil
+	}
+
+	_, err := c.EC2().CancelSpotInstanceRequests(ctx, &ec2.CancelSpotInstanceRequestsInput{
+		SpotInstanceRequestIds: []string{id},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to cancel spot request %s: %w", id, err)
+	}
+	return nil
+}
@@ -2,10 +2,15 @@ package resources
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"a9s/internal/client"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
 )
@@ -17,11 +22,24 @@ type SQSQueue struct {
 	ApproximateMessages           string
 	ApproximateMessagesNotVisible string
 	MessageRetentionPeriod        string
+	DeadLetterQueue               string
+	MaxReceiveCount               string
+	Sources                       []string // Queues using this queue as dead-letter queue
+	MessagesTrend                 string   // Sparkline of the visible messages, with metrics on
+	OldestAgeTrend                string   // Sparkline of the age of the oldest message, with metrics on
 }
 
+// sqsMetricsWindow and sqsMetricsPeriod are the span and the resolution of the queue sparklines
+const (
+	sqsMetricsWindow = 6 * time.Hour
+	sqsMetricsPeriod = 30 * 60
+)
+
 // SQSQueues implements Resource for SQS queues
 type SQSQueues struct {
-	queues []SQSQueue
+	queues     []SQSQueue
+	lastSource string // last source queue jumped to
+	metrics    bool
 }
 
 // NewSQSQueues creates a new SQSQueues resource
@@ -38,13 +56,30 @@ func (s *SQSQueues) Name() string {
 
 // Columns returns the column definitions
 func (s *SQSQueues) Columns() []Column {
-	return []Column{
+	columns := []Column{
 		{Name: "Queue Name", Width: 40},
 		{Name: "Messages", Width: 12},
 		{Name: "In Flight", Width: 12},
+	}
+	if s.metrics {
+		columns = append(columns,
+			Column{Name: "Messages (6h)", Width: 22},
+			Column{Name: "Oldest Age (6h)", Width: 22},
+		)
+	}
+	return append(columns, []Column{
 		{Name: "Retention (s)", Width: 15},
+		{Name: "DLQ", Width: 30},
+		{Name: "Max Receives", Width: 12},
+		{Name: "DLQ For", Width: 30},
 		{Name: "URL", Width: 60},
-	}
+	}...)
+}
+
+// ToggleMetrics switches the sparkline columns on or off
+func (s *SQSQueues) ToggleMetrics() bool {
+	s.metrics = !s.metrics
+	return s.metrics
 }
 
 // Fetch retrieves SQS queues from AWS
@@ -68,6 +103,7 @@ func (s *SQSQueues) Fetch(ctx context.Context, c *client.Client) error {
 					sqstypes.QueueAttributeNameApproximateNumberOfMessages,
 					sqstypes.QueueAttributeNameApproximateNumberOfMessagesNotVisible,
 					sqstypes.QueueAttributeNameMessageRetentionPeriod,
+					sqstypes.QueueAttributeNameRedrivePolicy,
 				},
 			})
 
@@ -97,26 +133,104 @@ func (s *SQSQueues) Fetch(ctx context.Context, c *client.Client) error {
 				if val, ok := attrs.Attributes["MessageRetentionPeriod"]; ok {
 					queue.MessageRetentionPeriod = val
 				}
+				if val, ok := attrs.Attributes["RedrivePolicy"]; ok {
+					queue.DeadLetterQueue, queue.MaxReceiveCount = parseRedrivePolicy(val)
+				}
 			}
 
 			s.queues = append(s.queues, queue)
 		}
 	}
 
+	// Link the dead-letter queues back to their source queues
+	byName := make(map[string]int, len(s.queues))
+	for i, queue := range s.queues {
+		byName[queue.Name] = i
+	}
+	for _, queue := range s.queues {
+		if i, ok := byName[queue.DeadLetterQueue]; ok {
+			s.queues[i].Sources = append(s.queues[i].Sources, queue.Name)
+		}
+	}
+
+	if s.metrics {
+		return s.fetchMetrics(ctx, c)
+	}
+
+	return nil
+}
+
+// fetchMetrics sets the sparklines of the message count and of the age of the oldest message
+func (s *SQSQueues) fetchMetrics(ctx context.Context, c *client.Client) error {
+	queries := make([]cwtypes.MetricDataQuery, 0, 2*len(s.queues))
+	for i, queue := range s.queues {
+		dimension := cwtypes.Dimension{Name: aws.String("QueueName"), Value: aws.String(queue.Name)}
+		queries = append(queries,
+			metricQuery(fmt.Sprintf("m%d", i), "AWS/SQS", "ApproximateNumberOfMessagesVisible", "Maximum", sqsMetricsPeriod, dimension),
+			metricQuery(fmt.Sprintf("a%d", i), "AWS/SQS", "ApproximateAgeOfOldestMessage", "Maximum", sqsMetricsPeriod, dimension),
+		)
+	}
+
+	series, err := metricSeries(ctx, c, queries, sqsMetricsWindow)
+	if err != nil {
+		return err
+	}
+
+	for i := range s.queues {
+		if values := series[fmt.Sprintf("m%d", i)]; len(values) > 0 {
+			s.queues[i].MessagesTrend = fmt.Sprintf("%s %.0f", sparkline(values), values[len(values)-1])
+		}
+		if values := series[fmt.Sprintf("a%d", i)]; len(values) > 0 {
+			age := time.Duration(values[len(values)-1]) * time.Second
+			s.queues[i].OldestAgeTrend = fmt.Sprintf("%s %s", sparkline(values), age)
+		}
+	}
+
 	return nil
 }
 
+// parseRedrivePolicy returns the dead-letter queue name and the max receive count of a redrive policy
+func parseRedrivePolicy(policy string) (string, string) {
+	var redrive struct {
+		DeadLetterTargetArn string `json:"deadLetterTargetArn"`
+		MaxReceiveCount     any    `json:"maxReceiveCount"` // number or string
+	}
+	if err := json.Unmarshal([]byte(policy), &redrive); err != nil {
+		return "", ""
+	}
+
+	// ARN format: arn:aws:sqs:region:account-id:queue-name
+	name := redrive.DeadLetterTargetArn
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	maxReceiveCount := ""
+	if redrive.MaxReceiveCount != nil {
+		maxReceiveCount = fmt.Sprint(redrive.MaxReceiveCount)
+	}
+	return name, maxReceiveCount
+}
+
 // Rows returns the table data
 func (s *SQSQueues) Rows() [][]string {
 	rows := make([][]string, len(s.queues))
 	for i, queue := range s.queues {
-		rows[i] = []string{
+		row := []string{
 			queue.Name,
 			queue.ApproximateMessages,
 			queue.ApproximateMessagesNotVisible,
+		}
+		if s.metrics {
+			row = append(row, queue.MessagesTrend, queue.OldestAgeTrend)
+		}
+		rows[i] = append(row,
 			queue.MessageRetentionPeriod,
+			queue.DeadLetterQueue,
+			queue.MaxReceiveCount,
+			strings.Join(queue.Sources, ","),
 			queue.URL,
-		}
+		)
 	}
 	return rows
 }
@@ -131,5 +245,100 @@ func (s *SQSQueues) GetID(index int) string {
 
 // QuickActions returns the available quick actions for SQS queues
 func (s *SQSQueues) QuickActions() []QuickAction {
-	return []QuickAction{}
+	return []QuickAction{
+		{
+			Key:            'j',
+			Label:          "dlq",
+			Description:    "Jump to dead-letter queue",
+			NeedsSelection: true,
+			Jump: func(name string) (string, error) {
+				queue, ok := s.queue(name)
+				if !ok || queue.DeadLetterQueue == "" {
+					return "", fmt.Errorf("%s has no dead-letter queue", name)
+				}
+				return queue.DeadLetterQueue, nil
+			},
+		},
+		{
+			Key:            'J',
+			Label:          "source",
+			Description:    "Jump to source queue",
+			NeedsSelection: true,
+			Jump: func(name string) (string, error) {
+				queue, ok := s.queue(name)
+				if !ok || len(queue.Sources) == 0 {
+					return "", fmt.Errorf("%s is not a dead-letter queue", name)
+				}
+
+				// Cycle through the sources when the queue serves several of them
+				for i, source := range queue.Sources {
+					if source == s.lastSource && i+1 < len(queue.Sources) {
+						s.lastSource = queue.Sources[i+1]
+						return s.lastSource, nil
+					}
+				}
+				s.lastSource = queue.Sources[0]
+				return s.lastSource, nil
+			},
+		},
+		tagsAction(s),
+	}
+}
+
+// queue returns a queue by name
+func (s *SQSQueues) queue(name string) (SQSQueue, bool) {
+	for _, queue := range s.queues {
+		if queue.Name == name {
+			return queue, true
+		}
+	}
+	return SQSQueue{}, false
+}
+
+// queueURL returns the URL of a queue, which the tagging API expects
+func (s *SQSQueues) queueURL(name string) (string, error) {
+	queue, ok := s.queue(name)
+	if !ok {
+		return "", fmt.Errorf("queue %s not found", name)
+	}
+	return queue.URL, nil
+}
+
+// Tags returns the tags of a queue
+func (s *SQSQueues) Tags(ctx context.Context, c *client.Client, name string) (map[string]string, error) {
+	url, err := s.queueURL(name)
+	if err != nil {
+		return nil, err
+	}
+	output, err := c.SQS().ListQueueTags(ctx, &sqs.ListQueueTagsInput{QueueUrl: &url})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags of %s: %w", name, err)
+	}
+	return output.Tags, nil
+}
+
+// SetTags adds or updates tags of a queue
+func (s *SQSQueues) SetTags(ctx context.Context, c *client.Client, name string, tags map[string]string) error {
+	url, err := s.queueURL(name)
+	if err != nil {
+		return err
+	}
+	_, err = c.SQS().TagQueue(ctx, &sqs.TagQueueInput{QueueUrl: &url, Tags: tags})
+	if err != nil {
+		return fmt.Errorf("failed to tag %s: %w", name, err)
+	}
+	return nil
+}
+
+// RemoveTags removes tags from a queue
+func (s *SQSQueues) RemoveTags(ctx context.Context, c *client.Client, name string, keys []string) error {
+	url, err := s.queueURL(name)
+	if err != nil {
+		return err
+	}
+	_, err = c.SQS().UntagQueue(ctx, &sqs.UntagQueueInput{QueueUrl: &url, TagKeys: keys})
+	if err != nil {
+		return fmt.Errorf("failed to untag %s: %w", name, err)
+	}
+	return nil
 }
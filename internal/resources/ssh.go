@@ -0,0 +1,90 @@
+package resources
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+)
+
+// defaultSSHUser is the user of the instances no SSH rule matches, the one of Amazon Linux
+const defaultSSHUser = "ec2-user"
+
+// SSHRule sets the user and key used to ssh into the instances it matches. Rules are
+// defined in the configuration file, the first matching one applies:
+//
+//	ssh:
+//	  - name: "bastion-*"
+//	    user: ubuntu
+//	    key: ~/.ssh/bastion.pem
+//	  - tag: "Environment=prod"
+//	    user: ec2-user
+//	    key: ~/.ssh/prod.pem
+//	    private: true
+type SSHRule struct {
+	Name    string `mapstructure:"name"`    // Glob matched against the Name tag
+	Tag     string `mapstructure:"tag"`     // Tag key, or key=value where the value is a glob
+	User    string `mapstructure:"user"`    // Login user, ec2-user by default
+	Key     string `mapstructure:"key"`     // Private key file, the ssh defaults otherwise
+	Private bool   `mapstructure:"private"` // Connect to the private IP even when a public one exists
+}
+
+// matches reports whether the rule applies to an instance, a rule without name nor tag matches all
+func (r SSHRule) matches(inst EC2Instance) bool {
+	if r.Name != "" {
+		if ok, _ := path.Match(r.Name, inst.Name); !ok {
+			return false
+		}
+	}
+	if r.Tag != "" {
+		key, pattern, hasValue := strings.Cut(r.Tag, "=")
+		value, ok := inst.Tags[key]
+		if !ok {
+			return false
+		}
+		if hasValue {
+			if ok, _ := path.Match(pattern, value); !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// sshCommand returns the ssh command connecting to an instance with the first matching rule
+func sshCommand(inst EC2Instance, rules []SSHRule) (*exec.Cmd, error) {
+	program, err := exec.LookPath("ssh")
+	if err != nil {
+		return nil, fmt.Errorf("ssh not found in PATH")
+	}
+
+	rule := SSHRule{User: defaultSSHUser}
+	for _, r := range rules {
+		if r.matches(inst) {
+			rule = r
+			break
+		}
+	}
+	if rule.User == "" {
+		rule.User = defaultSSHUser
+	}
+
+	address := inst.PublicIP
+	if rule.Private || address == "" {
+		address = inst.PrivateIP
+	}
+	if address == "" {
+		return nil, fmt.Errorf("%s has no IP address", inst.InstanceID)
+	}
+
+	args := make([]string, 0, 3)
+	if rule.Key != "" {
+		key, err := expandHome(rule.Key)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "-i", key)
+	}
+	args = append(args, fmt.Sprintf("%s@%s", rule.User, address))
+	return exec.Command(program, args...), nil
+}
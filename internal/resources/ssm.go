@@ -0,0 +1,266 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// SSMParameter represents a Systems Manager parameter
+type SSMParameter struct {
+	Name         string
+	Type         string
+	Tier         string
+	Version      int64
+	LastModified string
+	Description  string
+}
+
+// SSMParameters implements Resource for Systems Manager parameters
+type SSMParameters struct {
+	parameters []SSMParameter
+}
+
+// NewSSMParameters creates a new SSMParameters resource
+func NewSSMParameters() *SSMParameters {
+	return &SSMParameters{
+		parameters: make([]SSMParameter, 0),
+	}
+}
+
+// Name returns the display name
+func (s *SSMParameters) Name() string {
+	return "SSM Parameters"
+}
+
+// Columns returns the column definitions
+func (s *SSMParameters) Columns() []Column {
+	return []Column{
+		{Name: "Name", Width: 50},
+		{Name: "Type", Width: 14},
+		{Name: "Tier", Width: 12},
+		{Name: "Version", Width: 8},
+		{Name: "Modified", Width: 20},
+		{Name: "Description", Width: 40},
+	}
+}
+
+// Fetch retrieves the parameters metadata, values are only read on export
+func (s *SSMParameters) Fetch(ctx context.Context, c *client.Client) error {
+	s.parameters = make([]SSMParameter, 0)
+
+	paginator := ssm.NewDescribeParametersPaginator(c.SSM(), &ssm.DescribeParametersInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list SSM parameters: %w", err)
+		}
+
+		for _, parameter := range output.Parameters {
+			p := SSMParameter{
+				Name:        stringValue(parameter.Name),
+				Type:        string(parameter.Type),
+				Tier:        string(parameter.Tier),
+				Version:     parameter.Version,
+				Description: stringValue(parameter.Description),
+			}
+			if parameter.LastModifiedDate != nil {
+				p.LastModified = parameter.LastModifiedDate.Format("2006-01-02 15:04:05")
+			}
+			s.parameters = append(s.parameters, p)
+		}
+	}
+
+	return nil
+}
+
+// Rows returns the table data
+func (s *SSMParameters) Rows() [][]string {
+	rows := make([][]string, len(s.parameters))
+	for i, parameter := range s.parameters {
+		rows[i] = []string{
+			parameter.Name,
+			parameter.Type,
+			parameter.Tier,
+			fmt.Sprintf("%d", parameter.Version),
+			parameter.LastModified,
+			parameter.Description,
+		}
+	}
+	return rows
+}
+
+// GetID returns the parameter name at the given index
+func (s *SSMParameters) GetID(index int) string {
+	if index >= 0 && index < len(s.parameters) {
+		return s.parameters[index].Name
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for SSM parameters
+func (s *SSMParameters) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:             'x',
+			Label:           "export env",
+			Description:     "Export the parameters of the same path to a .env or .sh file",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ReadOnly:        true,
+			ConfirmTemplate: "The parameters next to %s will be decrypted and written in clear to a local file. Continue?",
+			InputLabel:      "File: ",
+			InputDefault: func(string) string {
+				return ".env"
+			},
+			InputHandler: exportParametersEnv,
+		},
+	}
+}
+
+// exportParametersEnv writes the decrypted parameters sharing the path of a
+// parameter to an environment file, named after the last part of their names
+func exportParametersEnv(ctx context.Context, c *client.Client, name, file string) error {
+	parent := path.Dir(name)
+	variables := newEnvVariables()
+
+	if parent == "." {
+		// Parameters outside of any hierarchy are exported alone
+		output, err := c.SSM().GetParameter(ctx, &ssm.GetParameterInput{
+			Name:           &name,
+			WithDecryption: aws.Bool(true),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get parameter %s: %w", name, err)
+		}
+		variables.set(name, stringValue(output.Parameter.Value))
+		return writeEnvFile(file, variables)
+	}
+
+	paginator := ssm.NewGetParametersByPathPaginator(c.SSM(), &ssm.GetParametersByPathInput{
+		Path:           &parent,
+		WithDecryption: aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get parameters of %s: %w", parent, err)
+		}
+		for _, parameter := range output.Parameters {
+			variables.set(stringValue(parameter.Name), stringValue(parameter.Value))
+		}
+	}
+
+	return writeEnvFile(file, variables)
+}
+
+// sessionManagerPlugin is the program attaching the terminal to a Session Manager session
+const sessionManagerPlugin = "session-manager-plugin"
+
+// startSessionCommand starts a Session Manager session on an instance managed by SSM
+// and returns the plugin command attaching the terminal to it
+func startSessionCommand(ctx context.Context, c *client.Client, target string) (*exec.Cmd, error) {
+	return sessionCommand(ctx, c, target, "", nil)
+}
+
+// portForwardCommand starts a port forwarding session through an instance managed by SSM,
+// to a port of the instance or, when host is set, of a host it reaches, and returns the
+// plugin command listening on the local port
+func portForwardCommand(ctx context.Context, c *client.Client, target, localPort, host, remotePort string) (*exec.Cmd, error) {
+	parameters := map[string][]string{
+		"portNumber":      {remotePort},
+		"localPortNumber": {localPort},
+	}
+	if host == "" {
+		return sessionCommand(ctx, c, target, "AWS-StartPortForwardingSession", parameters)
+	}
+	parameters["host"] = []string{host}
+	return sessionCommand(ctx, c, target, "AWS-StartPortForwardingSessionToRemoteHost", parameters)
+}
+
+// parsePortForward parses "port", "local:remote" or "local:host:remote" into the
+// local port, the remote host and the remote port
+func parsePortForward(input string) (localPort, host, remotePort string, err error) {
+	parts := strings.Split(input, ":")
+	switch len(parts) {
+	case 1:
+		localPort, remotePort = parts[0], parts[0]
+	case 2:
+		localPort, remotePort = parts[0], parts[1]
+	case 3:
+		localPort, host, remotePort = parts[0], parts[1], parts[2]
+	default:
+		return "", "", "", fmt.Errorf("invalid forwarding %q, expected local:remote or local:host:remote", input)
+	}
+	for _, port := range []string{localPort, remotePort} {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return "", "", "", fmt.Errorf("invalid port %q", port)
+		}
+	}
+	return localPort, host, remotePort, nil
+}
+
+// sessionCommand starts a Session Manager session on an instance managed by SSM, with
+// the default shell document when document is empty, and returns the plugin command
+func sessionCommand(ctx context.Context, c *client.Client, target, document string, parameters map[string][]string) (*exec.Cmd, error) {
+	plugin, err := exec.LookPath(sessionManagerPlugin)
+	if err != nil {
+		return nil, fmt.Errorf("%s not found in PATH", sessionManagerPlugin)
+	}
+
+	info, err := c.SSM().DescribeInstanceInformation(ctx, &ssm.DescribeInstanceInformationInput{
+		Filters: []ssmtypes.InstanceInformationStringFilter{{
+			Key:    aws.String("InstanceIds"),
+			Values: []string{target},
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe SSM instance information of %s: %w", target, err)
+	}
+	if len(info.InstanceInformationList) == 0 {
+		return nil, fmt.Errorf("%s is not managed by SSM, check its agent and instance profile", target)
+	}
+	if status := info.InstanceInformationList[0].PingStatus; status != ssmtypes.PingStatusOnline {
+		return nil, fmt.Errorf("the SSM agent of %s is %s", target, status)
+	}
+
+	input := &ssm.StartSessionInput{Target: &target}
+	request := map[string]any{"Target": target}
+	if document != "" {
+		input.DocumentName = &document
+		input.Parameters = parameters
+		request["DocumentName"] = document
+		request["Parameters"] = parameters
+	}
+
+	session, err := c.SSM().StartSession(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session on %s: %w", target, err)
+	}
+
+	response, err := json.Marshal(map[string]string{
+		"SessionId":  stringValue(session.SessionId),
+		"TokenValue": stringValue(session.TokenValue),
+		"StreamUrl":  stringValue(session.StreamUrl),
+	})
+	if err != nil {
+		return nil, err
+	}
+	requestJSON, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("https://ssm.%s.amazonaws.com", c.Region())
+
+	return exec.Command(plugin, string(response), c.Region(), "StartSession", c.Profile(), string(requestJSON), endpoint), nil
+}
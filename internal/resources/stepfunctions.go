@@ -0,0 +1,508 @@
+package resources
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sfn"
+	sfntypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
+)
+
+// sfnExecutionsLimit is the number of most recent executions listed for a state machine
+const sfnExecutionsLimit int32 = 100
+
+// StateMachines implements Resource for Step Functions state machines
+type StateMachines struct {
+	machines []sfntypes.StateMachineListItem
+}
+
+// NewStateMachines creates a new StateMachines resource
+func NewStateMachines() *StateMachines {
+	return &StateMachines{
+		machines: make([]sfntypes.StateMachineListItem, 0),
+	}
+}
+
+// Name returns the display name
+func (s *StateMachines) Name() string {
+	return "Step Functions State Machines"
+}
+
+// Columns returns the column definitions
+func (s *StateMachines) Columns() []Column {
+	return []Column{
+		{Name: "Name", Width: 45},
+		{Name: "Type", Width: 10},
+		{Name: "Created", Width: 20},
+		{Name: "ARN", Width: 80},
+	}
+}
+
+// Fetch retrieves the state machines from AWS
+func (s *StateMachines) Fetch(ctx context.Context, c *client.Client) error {
+	s.machines = make([]sfntypes.StateMachineListItem, 0)
+
+	paginator := sfn.NewListStateMachinesPaginator(c.SFN(), &sfn.ListStateMachinesInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list state machines: %w", err)
+		}
+		s.machines = append(s.machines, output.StateMachines...)
+	}
+
+	return nil
+}
+
+// Rows returns the table data
+func (s *StateMachines) Rows() [][]string {
+	rows := make([][]string, len(s.machines))
+	for i, machine := range s.machines {
+		created := ""
+		if machine.CreationDate != nil {
+			created = machine.CreationDate.Format("2006-01-02 15:04:05")
+		}
+		rows[i] = []string{
+			stringValue(machine.Name),
+			string(machine.Type),
+			created,
+			stringValue(machine.StateMachineArn),
+		}
+	}
+	return rows
+}
+
+// GetID returns the state machine ARN at the given index
+func (s *StateMachines) GetID(index int) string {
+	if index >= 0 && index < len(s.machines) {
+		return stringValue(s.machines[index].StateMachineArn)
+	}
+	return ""
+}
+
+// DrillDown opens the recent executions of the state machine
+func (s *StateMachines) DrillDown(machineARN string) Resource {
+	return NewSFNExecutions(machineARN)
+}
+
+// QuickActions returns the available quick actions for state machines
+func (s *StateMachines) QuickActions() []QuickAction {
+	return []QuickAction{}
+}
+
+// SFNExecutions implements Resource for the recent executions of a state machine
+type SFNExecutions struct {
+	machineARN string
+	executions []sfntypes.ExecutionListItem
+}
+
+// NewSFNExecutions creates a new SFNExecutions resource
+func NewSFNExecutions(machineARN string) *SFNExecutions {
+	return &SFNExecutions{
+		machineARN: machineARN,
+		executions: make([]sfntypes.ExecutionListItem, 0),
+	}
+}
+
+// Name returns the display name
+func (s *SFNExecutions) Name() string {
+	return fmt.Sprintf("Executions (%s)", sfnResourceName(s.machineARN))
+}
+
+// Columns returns the column definitions
+func (s *SFNExecutions) Columns() []Column {
+	return []Column{
+		{Name: "Name", Width: 45},
+		{Name: "Status", Width: 12},
+		{Name: "Started", Width: 20},
+		{Name: "Stopped", Width: 20},
+		{Name: "Duration", Width: 12},
+	}
+}
+
+// Fetch retrieves the most recent executions of the state machine
+func (s *SFNExecutions) Fetch(ctx context.Context, c *client.Client) error {
+	s.executions = make([]sfntypes.ExecutionListItem, 0)
+
+	output, err := c.SFN().ListExecutions(ctx, &sfn.ListExecutionsInput{
+		StateMachineArn: &s.machineARN,
+		MaxResults:      sfnExecutionsLimit,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list executions: %w", err)
+	}
+	s.executions = output.Executions
+
+	return nil
+}
+
+// Rows returns the table data
+func (s *SFNExecutions) Rows() [][]string {
+	rows := make([][]string, len(s.executions))
+	for i, execution := range s.executions {
+		started, stopped := "", ""
+		if execution.StartDate != nil {
+			started = execution.StartDate.Format("2006-01-02 15:04:05")
+		}
+		if execution.StopDate != nil {
+			stopped = execution.StopDate.Format("2006-01-02 15:04:05")
+		}
+		rows[i] = []string{
+			stringValue(execution.Name),
+			string(execution.Status),
+			started,
+			stopped,
+			sfnDuration(execution.StartDate, execution.StopDate),
+		}
+	}
+	return rows
+}
+
+// GetID returns the execution ARN at the given index
+func (s *SFNExecutions) GetID(index int) string {
+	if index >= 0 && index < len(s.executions) {
+		return stringValue(s.executions[index].ExecutionArn)
+	}
+	return ""
+}
+
+// Highlight flags the executions which failed, timed out or were aborted
+func (s *SFNExecutions) Highlight(index int) bool {
+	if index < 0 || index >= len(s.executions) {
+		return false
+	}
+	status := s.executions[index].Status
+	return status == sfntypes.ExecutionStatusFailed || status == sfntypes.ExecutionStatusTimedOut || status == sfntypes.ExecutionStatusAborted
+}
+
+// DrillDown opens the state history of the execution
+func (s *SFNExecutions) DrillDown(executionARN string) Resource {
+	return NewSFNExecutionHistory(executionARN)
+}
+
+// QuickActions returns the available quick actions for executions
+func (s *SFNExecutions) QuickActions() []QuickAction {
+	return executionActions(func(executionARN string) string { return executionARN })
+}
+
+// executionActions returns the actions on an execution, shared by the executions and the
+// history of one of them, executionARN maps the selected ID to the execution
+func executionActions(executionARN func(selectedID string) string) []QuickAction {
+	return []QuickAction{
+		{
+			Key:            'i',
+			Label:          "input",
+			Description:    "Show the input of the execution",
+			NeedsSelection: true,
+			TextHandler: func(ctx context.Context, c *client.Client, id string) (string, error) {
+				execution, err := describeExecution(ctx, c, executionARN(id))
+				if err != nil {
+					return "", err
+				}
+				return indentJSON(stringValue(execution.Input)), nil
+			},
+		},
+		{
+			Key:            'o',
+			Label:          "output",
+			Description:    "Show the output, or the error, of the execution",
+			NeedsSelection: true,
+			TextHandler: func(ctx context.Context, c *client.Client, id string) (string, error) {
+				execution, err := describeExecution(ctx, c, executionARN(id))
+				if err != nil {
+					return "", err
+				}
+				switch {
+				case execution.Status == sfntypes.ExecutionStatusRunning:
+					return "", fmt.Errorf("the execution is still running")
+				case execution.Output != nil:
+					return indentJSON(*execution.Output), nil
+				}
+				return fmt.Sprintf("%s: %s\n\n%s", execution.Status, stringValue(execution.Error), indentJSON(stringValue(execution.Cause))), nil
+			},
+		},
+		{
+			Key:             'X',
+			Label:           "stop",
+			Description:     "Stop the running execution",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "[red]stop[-] execution [white]%s[-]?",
+			ConfirmCheck: func(ctx context.Context, c *client.Client, id string) (string, error) {
+				execution, err := describeExecution(ctx, c, executionARN(id))
+				if err != nil {
+					return "", err
+				}
+				if execution.Status != sfntypes.ExecutionStatusRunning {
+					return "", fmt.Errorf("the execution is %s", execution.Status)
+				}
+				return fmt.Sprintf("Started %s", execution.StartDate.Local().Format("2006-01-02 15:04:05")), nil
+			},
+			Handler: func(ctx context.Context, c *client.Client, id string) error {
+				_, err := c.SFN().StopExecution(ctx, &sfn.StopExecutionInput{
+					ExecutionArn: aws.String(executionARN(id)),
+					Cause:        aws.String("Stopped from a9s"),
+				})
+				if err != nil {
+					return fmt.Errorf("failed to stop execution: %w", err)
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// describeExecution returns the status, input and output of an execution
+func describeExecution(ctx context.Context, c *client.Client, executionARN string) (*sfn.DescribeExecutionOutput, error) {
+	output, err := c.SFN().DescribeExecution(ctx, &sfn.DescribeExecutionInput{ExecutionArn: aws.String(executionARN)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe execution %s: %w", sfnResourceName(executionARN), err)
+	}
+	return output, nil
+}
+
+// sfnResourceName returns the name part of a state machine or execution ARN
+func sfnResourceName(resourceARN string) string {
+	if i := strings.LastIndex(resourceARN, ":"); i >= 0 {
+		return resourceARN[i+1:]
+	}
+	return resourceARN
+}
+
+// sfnDuration returns the duration between two dates, until now when not stopped
+func sfnDuration(start, stop *time.Time) string {
+	if start == nil {
+		return ""
+	}
+	end := time.Now()
+	if stop != nil {
+		end = *stop
+	}
+	return end.Sub(*start).Round(time.Millisecond).String()
+}
+
+// indentJSON returns a JSON document indented, or the text itself when it is not JSON
+func indentJSON(text string) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(text), "", "  "); err != nil {
+		return text
+	}
+	return buf.String()
+}
+
+// SFNStateRun represents one run of a state in an execution
+type SFNStateRun struct {
+	Name    string
+	Type    string
+	Status  string
+	Entered *time.Time
+	Exited  *time.Time
+	Input   string
+	Output  string
+	Error   string
+}
+
+// SFNExecutionHistory implements Resource for the states an execution went through
+type SFNExecutionHistory struct {
+	executionARN string
+	status       string
+	runs         []SFNStateRun
+}
+
+// NewSFNExecutionHistory creates a new SFNExecutionHistory resource
+func NewSFNExecutionHistory(executionARN string) *SFNExecutionHistory {
+	return &SFNExecutionHistory{
+		executionARN: executionARN,
+		runs:         make([]SFNStateRun, 0),
+	}
+}
+
+// Name returns the display name
+func (s *SFNExecutionHistory) Name() string {
+	if s.status == "" {
+		return fmt.Sprintf("Execution %s", sfnResourceName(s.executionARN))
+	}
+	return fmt.Sprintf("Execution %s (%s)", sfnResourceName(s.executionARN), s.status)
+}
+
+// Columns returns the column definitions
+func (s *SFNExecutionHistory) Columns() []Column {
+	return []Column{
+		{Name: "State", Width: 35},
+		{Name: "Type", Width: 10},
+		{Name: "Status", Width: 10},
+		{Name: "Entered", Width: 20},
+		{Name: "Duration", Width: 12},
+		{Name: "Error", Width: 60},
+	}
+}
+
+// Fetch retrieves the status of the execution and groups its history events by state run
+func (s *SFNExecutionHistory) Fetch(ctx context.Context, c *client.Client) error {
+	s.runs = make([]SFNStateRun, 0)
+
+	execution, err := describeExecution(ctx, c, s.executionARN)
+	if err != nil {
+		return err
+	}
+	s.status = string(execution.Status)
+
+	// open is the index of the run of each state not exited yet
+	open := make(map[string]int)
+	last := -1
+	paginator := sfn.NewGetExecutionHistoryPaginator(c.SFN(), &sfn.GetExecutionHistoryInput{
+		ExecutionArn: &s.executionARN,
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get execution history: %w", err)
+		}
+		for _, event := range output.Events {
+			eventType := string(event.Type)
+			switch {
+			case event.StateEnteredEventDetails != nil:
+				details := event.StateEnteredEventDetails
+				s.runs = append(s.runs, SFNStateRun{
+					Name:    stringValue(details.Name),
+					Type:    strings.TrimSuffix(eventType, "StateEntered"),
+					Status:  "Running",
+					Entered: event.Timestamp,
+					Input:   stringValue(details.Input),
+				})
+				last = len(s.runs) - 1
+				open[stringValue(details.Name)] = last
+			case event.StateExitedEventDetails != nil:
+				details := event.StateExitedEventDetails
+				if i, ok := open[stringValue(details.Name)]; ok {
+					if s.runs[i].Status == "Running" {
+						s.runs[i].Status = "Succeeded"
+					}
+					s.runs[i].Exited = event.Timestamp
+					s.runs[i].Output = stringValue(details.Output)
+					delete(open, stringValue(details.Name))
+				}
+			case strings.HasSuffix(eventType, "Failed"), strings.HasSuffix(eventType, "TimedOut"), strings.HasSuffix(eventType, "Aborted"):
+				// The failure of a task is reported before its state exits, or ends the execution
+				if last >= 0 && s.runs[last].Exited == nil {
+					s.runs[last].Status = "Failed"
+					if strings.HasSuffix(eventType, "TimedOut") {
+						s.runs[last].Status = "TimedOut"
+					}
+					if s.runs[last].Error == "" {
+						s.runs[last].Error = sfnEventError(event)
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// sfnEventError returns the error and cause of a failure event
+func sfnEventError(event sfntypes.HistoryEvent) string {
+	var errorName, cause *string
+	switch {
+	case event.TaskFailedEventDetails != nil:
+		errorName, cause = event.TaskFailedEventDetails.Error, event.TaskFailedEventDetails.Cause
+	case event.TaskTimedOutEventDetails != nil:
+		errorName, cause = event.TaskTimedOutEventDetails.Error, event.TaskTimedOutEventDetails.Cause
+	case event.LambdaFunctionFailedEventDetails != nil:
+		errorName, cause = event.LambdaFunctionFailedEventDetails.Error, event.LambdaFunctionFailedEventDetails.Cause
+	case event.LambdaFunctionTimedOutEventDetails != nil:
+		errorName, cause = event.LambdaFunctionTimedOutEventDetails.Error, event.LambdaFunctionTimedOutEventDetails.Cause
+	case event.ExecutionFailedEventDetails != nil:
+		errorName, cause = event.ExecutionFailedEventDetails.Error, event.ExecutionFailedEventDetails.Cause
+	case event.ExecutionTimedOutEventDetails != nil:
+		errorName, cause = event.ExecutionTimedOutEventDetails.Error, event.ExecutionTimedOutEventDetails.Cause
+	default:
+		return string(event.Type)
+	}
+	if cause == nil {
+		return stringValue(errorName)
+	}
+	return fmt.Sprintf("%s: %s", stringValue(errorName), strings.Join(strings.Fields(*cause), " "))
+}
+
+// Rows returns the table data
+func (s *SFNExecutionHistory) Rows() [][]string {
+	rows := make([][]string, len(s.runs))
+	for i, run := range s.runs {
+		entered := ""
+		if run.Entered != nil {
+			entered = run.Entered.Format("2006-01-02 15:04:05")
+		}
+		duration := ""
+		if run.Exited != nil {
+			duration = sfnDuration(run.Entered, run.Exited)
+		}
+		rows[i] = []string{
+			run.Name,
+			run.Type,
+			run.Status,
+			entered,
+			duration,
+			run.Error,
+		}
+	}
+	return rows
+}
+
+// GetID returns the position of the state run at the given index, state names repeat in loops
+func (s *SFNExecutionHistory) GetID(index int) string {
+	if index >= 0 && index < len(s.runs) {
+		return strconv.Itoa(index)
+	}
+	return ""
+}
+
+// Highlight flags the state runs which failed
+func (s *SFNExecutionHistory) Highlight(index int) bool {
+	return index >= 0 && index < len(s.runs) && s.runs[index].Error != ""
+}
+
+// QuickActions returns the available quick actions for the execution history
+func (s *SFNExecutionHistory) QuickActions() []QuickAction {
+	actions := []QuickAction{
+		{
+			Key:            'd',
+			Label:          "detail",
+			Description:    "Show the input and output of the state",
+			NeedsSelection: true,
+			TextHandler: func(ctx context.Context, c *client.Client, id string) (string, error) {
+				index, err := strconv.Atoi(id)
+				if err != nil || index < 0 || index >= len(s.runs) {
+					return "", fmt.Errorf("state %s is not listed", id)
+				}
+				run := s.runs[index]
+				var b strings.Builder
+				fmt.Fprintf(&b, "%s (%s) %s\n", run.Name, run.Type, run.Status)
+				if run.Error != "" {
+					fmt.Fprintf(&b, "\n%s\n", run.Error)
+				}
+				fmt.Fprintf(&b, "\nInput:\n%s\n", indentJSON(run.Input))
+				if run.Exited != nil {
+					fmt.Fprintf(&b, "\nOutput:\n%s\n", indentJSON(run.Output))
+				}
+				return b.String(), nil
+			},
+		},
+	}
+	for _, action := range executionActions(func(string) string { return s.executionARN }) {
+		// The selected ID is a state run, the confirmation names the execution
+		if action.NeedsConfirm {
+			action.ConfirmTemplate = fmt.Sprintf("[red]stop[-] execution [white]%s[-]?", sfnResourceName(s.executionARN))
+		}
+		actions = append(actions, action)
+	}
+	return actions
+}
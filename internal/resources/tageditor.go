@@ -0,0 +1,146 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"a9s/internal/client"
+)
+
+// tagsAction returns the action opening the tag editor of the selected row
+func tagsAction(editor TagEditor) QuickAction {
+	return QuickAction{
+		Key:            't',
+		Label:          "tags",
+		Description:    "Edit tags",
+		NeedsSelection: true,
+		View: func(id string) Resource {
+			return NewResourceTags(editor, id)
+		},
+	}
+}
+
+// ResourceTag represents a tag of a resource
+type ResourceTag struct {
+	Key   string
+	Value string
+}
+
+// ResourceTags implements Resource for the tags of a resource, which can be added,
+// edited and removed through its TagEditor
+type ResourceTags struct {
+	editor TagEditor
+	id     string
+	tags   []ResourceTag
+}
+
+// NewResourceTags creates a new ResourceTags resource
+func NewResourceTags(editor TagEditor, id string) *ResourceTags {
+	return &ResourceTags{
+		editor: editor,
+		id:     id,
+		tags:   make([]ResourceTag, 0),
+	}
+}
+
+// Name returns the display name
+func (r *ResourceTags) Name() string {
+	return fmt.Sprintf("Tags (%s)", r.id)
+}
+
+// Columns returns the column definitions
+func (r *ResourceTags) Columns() []Column {
+	return []Column{
+		{Name: "Key", Width: 40},
+		{Name: "Value", Width: 60},
+	}
+}
+
+// Fetch retrieves the tags of the resource
+func (r *ResourceTags) Fetch(ctx context.Context, c *client.Client) error {
+	r.tags = make([]ResourceTag, 0)
+
+	tags, err := r.editor.Tags(ctx, c, r.id)
+	if err != nil {
+		return err
+	}
+	for key, value := range tags {
+		r.tags = append(r.tags, ResourceTag{Key: key, Value: value})
+	}
+	sort.Slice(r.tags, func(i, j int) bool {
+		return r.tags[i].Key < r.tags[j].Key
+	})
+
+	return nil
+}
+
+// Rows returns the table data
+func (r *ResourceTags) Rows() [][]string {
+	rows := make([][]string, len(r.tags))
+	for i, tag := range r.tags {
+		rows[i] = []string{tag.Key, tag.Value}
+	}
+	return rows
+}
+
+// GetID returns the tag key at the given index
+func (r *ResourceTags) GetID(index int) string {
+	if index >= 0 && index < len(r.tags) {
+		return r.tags[index].Key
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for tags
+func (r *ResourceTags) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:         'c',
+			Label:       "add",
+			Description: "Add or update a tag",
+			InputLabel:  "key=value: ",
+			InputHandler: func(ctx context.Context, c *client.Client, _, input string) error {
+				key, value, ok := strings.Cut(input, "=")
+				key = strings.TrimSpace(key)
+				if !ok || key == "" {
+					return fmt.Errorf("expected key=value, got %q", input)
+				}
+				return r.editor.SetTags(ctx, c, r.id, map[string]string{key: strings.TrimSpace(value)})
+			},
+		},
+		{
+			Key:            'e',
+			Label:          "edit",
+			Description:    "Edit the tag value",
+			NeedsSelection: true,
+			InputLabel:     "Value: ",
+			InputDefault:   r.value,
+			InputHandler: func(ctx context.Context, c *client.Client, key, value string) error {
+				return r.editor.SetTags(ctx, c, r.id, map[string]string{key: value})
+			},
+		},
+		{
+			Key:             'd',
+			Label:           "remove",
+			Description:     "Remove the tag",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "[red]remove[-] tag [white]%s[-]?",
+			Handler: func(ctx context.Context, c *client.Client, key string) error {
+				return r.editor.RemoveTags(ctx, c, r.id, []string{key})
+			},
+		},
+	}
+}
+
+// value returns the current value of a tag
+func (r *ResourceTags) value(key string) string {
+	for _, tag := range r.tags {
+		if tag.Key == key {
+			return tag.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,242 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+)
+
+// DefaultRequiredTags are the tags every resource must have by default
+var DefaultRequiredTags = []string{"Owner", "Environment", "CostCenter"}
+
+// tagBatchSize is the maximum number of resources tagged in a single call
+const tagBatchSize = 20
+
+// TagViolation represents a resource missing some of the required tags
+type TagViolation struct {
+	ARN      string
+	Service  string
+	Resource string
+	Missing  []string
+}
+
+// TagCompliance implements Resource for the resources missing required tags. It relies
+// on the tagging API, which only knows the resources that have or had tags
+type TagCompliance struct {
+	required   []string
+	violations []TagViolation
+	checked    int
+}
+
+// NewTagCompliance creates a new TagCompliance resource checking the required tags
+func NewTagCompliance(required []string) *TagCompliance {
+	if len(required) == 0 {
+		required = DefaultRequiredTags
+	}
+	return &TagCompliance{
+		required:   required,
+		violations: make([]TagViolation, 0),
+	}
+}
+
+// Name returns the display name
+func (t *TagCompliance) Name() string {
+	return fmt.Sprintf("Tag Compliance (%d of %d resources missing %s)",
+		len(t.violations), t.checked, strings.Join(t.required, ", "))
+}
+
+// Columns returns the column definitions
+func (t *TagCompliance) Columns() []Column {
+	return []Column{
+		{Name: "Service", Width: 15},
+		{Name: "Resource", Width: 50},
+		{Name: "Missing Tags", Width: 35},
+		{Name: "ARN", Width: 80},
+	}
+}
+
+// Fetch retrieves the tagged resources of the region and checks their tags, per service
+func (t *TagCompliance) Fetch(ctx context.Context, c *client.Client) error {
+	t.violations = make([]TagViolation, 0)
+	t.checked = 0
+
+	paginator := resourcegroupstaggingapi.NewGetResourcesPaginator(c.Tagging(), &resourcegroupstaggingapi.GetResourcesInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list tagged resources: %w", err)
+		}
+
+		for _, mapping := range output.ResourceTagMappingList {
+			t.checked++
+
+			present := make(map[string]bool, len(mapping.Tags))
+			for _, tag := range mapping.Tags {
+				if stringValue(tag.Value) != "" {
+					present[stringValue(tag.Key)] = true
+				}
+			}
+			missing := make([]string, 0)
+			for _, key := range t.required {
+				if !present[key] {
+					missing = append(missing, key)
+				}
+			}
+			if len(missing) == 0 {
+				continue
+			}
+
+			violation := TagViolation{
+				ARN:      stringValue(mapping.ResourceARN),
+				Resource: stringValue(mapping.ResourceARN),
+				Missing:  missing,
+			}
+			if parsed, err := arn.Parse(violation.ARN); err == nil {
+				violation.Service = parsed.Service
+				violation.Resource = parsed.Resource
+			}
+			t.violations = append(t.violations, violation)
+		}
+	}
+
+	sort.Slice(t.violations, func(i, j int) bool {
+		if t.violations[i].Service != t.violations[j].Service {
+			return t.violations[i].Service < t.violations[j].Service
+		}
+		return t.violations[i].Resource < t.violations[j].Resource
+	})
+
+	return nil
+}
+
+// Rows returns the table data
+func (t *TagCompliance) Rows() [][]string {
+	rows := make([][]string, len(t.violations))
+	for i, violation := range t.violations {
+		rows[i] = []string{
+			violation.Service,
+			violation.Resource,
+			strings.Join(violation.Missing, ", "),
+			violation.ARN,
+		}
+	}
+	return rows
+}
+
+// GetID returns the resource ARN at the given index
+func (t *TagCompliance) GetID(index int) string {
+	if index >= 0 && index < len(t.violations) {
+		return t.violations[index].ARN
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for the tag compliance report
+func (t *TagCompliance) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:         'T',
+			Label:       "fix tags",
+			Description: "Add the missing tags to all listed resources",
+			InputLabel:  "Tags (Key=Value ...): ",
+			InputDefault: func(string) string {
+				return strings.Join(t.required, "= ") + "="
+			},
+			InputPlan: t.planFixTags,
+		},
+	}
+}
+
+// planFixTags previews the tags added to each listed resource, existing tags are never overwritten
+func (t *TagCompliance) planFixTags(ctx context.Context, c *client.Client, _ string, input string) ([]Step, error) {
+	values := make(map[string]string)
+	for _, field := range strings.Fields(input) {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid tag %q, expected Key=Value", field)
+		}
+		if value != "" {
+			values[key] = value
+		}
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("no tag value given")
+	}
+
+	// Resources missing the same tags are tagged together
+	groups := make(map[string][]string)
+	tagSets := make(map[string]map[string]string)
+	for _, violation := range t.violations {
+		tags := make(map[string]string)
+		for _, key := range violation.Missing {
+			if value, ok := values[key]; ok {
+				tags[key] = value
+			}
+		}
+		if len(tags) == 0 {
+			continue
+		}
+
+		keys := make([]string, 0, len(tags))
+		for key := range tags {
+			keys = append(keys, key+"="+tags[key])
+		}
+		sort.Strings(keys)
+		group := strings.Join(keys, ", ")
+		groups[group] = append(groups[group], violation.ARN)
+		tagSets[group] = tags
+	}
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("no listed resource misses %s", strings.Join(sortedTagKeys(values), ", "))
+	}
+
+	names := make([]string, 0, len(groups))
+	for group := range groups {
+		names = append(names, group)
+	}
+	sort.Strings(names)
+
+	steps := make([]Step, 0)
+	for _, group := range names {
+		arns := groups[group]
+		tags := tagSets[group]
+		for start := 0; start < len(arns); start += tagBatchSize {
+			batch := arns[start:min(start+tagBatchSize, len(arns))]
+			steps = append(steps, Step{
+				Description: fmt.Sprintf("Tag %d resources with %s", len(batch), group),
+				Run: func(ctx context.Context, c *client.Client) error {
+					output, err := c.Tagging().TagResources(ctx, &resourcegroupstaggingapi.TagResourcesInput{
+						ResourceARNList: batch,
+						Tags:            tags,
+					})
+					if err != nil {
+						return err
+					}
+					for resourceARN, failure := range output.FailedResourcesMap {
+						return fmt.Errorf("failed to tag %s (and %d more): %s",
+							resourceARN, len(output.FailedResourcesMap)-1, stringValue(failure.ErrorMessage))
+					}
+					return nil
+				},
+			})
+		}
+	}
+
+	return steps, nil
+}
+
+// sortedTagKeys returns the keys of a tag map in order
+func sortedTagKeys(tags map[string]string) []string {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
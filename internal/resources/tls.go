@@ -0,0 +1,260 @@
+package resources
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"a9s/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+)
+
+// tlsDialTimeout bounds the connection to the inspected endpoint
+const tlsDialTimeout = 10 * time.Second
+
+// TLSCertificate represents a certificate of the chain served by an endpoint
+type TLSCertificate struct {
+	Position    string
+	Subject     string
+	Issuer      string
+	NotAfter    time.Time
+	Names       string
+	Fingerprint string
+	ACM         string
+	Problem     string
+}
+
+// TLSChain implements Resource for the certificate chain served by an endpoint,
+// the leaf is matched against the ACM certificates of the account
+type TLSChain struct {
+	address      string
+	serverName   string
+	certificates []TLSCertificate
+}
+
+// NewTLSChain creates a new TLSChain resource for a host[:port], the port defaults to 443
+func NewTLSChain(hostport string) *TLSChain {
+	host, port, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host, port = hostport, "443"
+	}
+	return &TLSChain{
+		address:      net.JoinHostPort(host, port),
+		serverName:   host,
+		certificates: make([]TLSCertificate, 0),
+	}
+}
+
+// Name returns the display name
+func (t *TLSChain) Name() string {
+	return fmt.Sprintf("TLS Chain (%s)", t.address)
+}
+
+// Columns returns the column definitions
+func (t *TLSChain) Columns() []Column {
+	return []Column{
+		{Name: "Position", Width: 12},
+		{Name: "Subject", Width: 40},
+		{Name: "Issuer", Width: 40},
+		{Name: "Not After", Width: 12},
+		{Name: "Names", Width: 40},
+		{Name: "ACM", Width: 30},
+		{Name: "Problem", Width: 40},
+	}
+}
+
+// Fetch connects to the endpoint, verifies its chain and looks the leaf up in ACM
+func (t *TLSChain) Fetch(ctx context.Context, c *client.Client) error {
+	t.certificates = make([]TLSCertificate, 0)
+
+	// Skip the verification at handshake to inspect invalid chains too, they are verified below
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: tlsDialTimeout},
+		Config:    &tls.Config{ServerName: t.serverName, InsecureSkipVerify: true},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", t.address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", t.address, err)
+	}
+	defer conn.Close()
+
+	chain := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(chain) == 0 {
+		return fmt.Errorf("%s served no certificate", t.address)
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, verifyErr := chain[0].Verify(x509.VerifyOptions{
+		DNSName:       t.serverName,
+		Intermediates: intermediates,
+	})
+
+	for i, cert := range chain {
+		certificate := TLSCertificate{
+			Position:    "intermediate",
+			Subject:     cert.Subject.CommonName,
+			Issuer:      cert.Issuer.CommonName,
+			NotAfter:    cert.NotAfter,
+			Names:       strings.Join(cert.DNSNames, ", "),
+			Fingerprint: fmt.Sprintf("%x", sha256.Sum256(cert.Raw)),
+		}
+		if certificate.Subject == "" {
+			certificate.Subject = cert.Subject.String()
+		}
+		if certificate.Issuer == "" {
+			certificate.Issuer = cert.Issuer.String()
+		}
+
+		switch {
+		case time.Now().After(cert.NotAfter):
+			certificate.Problem = "expired"
+		case time.Now().Before(cert.NotBefore):
+			certificate.Problem = "not yet valid"
+		}
+
+		if i == 0 {
+			certificate.Position = "leaf"
+			if verifyErr != nil && certificate.Problem == "" {
+				certificate.Problem = verifyErr.Error()
+			}
+
+			certificate.ACM, err = matchACMCertificate(ctx, c, t.serverName, cert)
+			if err != nil {
+				return err
+			}
+		}
+
+		t.certificates = append(t.certificates, certificate)
+	}
+
+	return nil
+}
+
+// matchACMCertificate returns the ARN of the ACM certificate covering the host
+// with the same serial number as the served one, "none" when there is no such certificate
+func matchACMCertificate(ctx context.Context, c *client.Client, host string, served *x509.Certificate) (string, error) {
+	serial := formatSerial(served.SerialNumber.Bytes())
+
+	paginator := acm.NewListCertificatesPaginator(c.ACM(), &acm.ListCertificatesInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to list ACM certificates: %w", err)
+		}
+
+		for _, summary := range output.CertificateSummaryList {
+			names := append([]string{stringValue(summary.DomainName)}, summary.SubjectAlternativeNameSummaries...)
+			if !coversHost(names, host) {
+				continue
+			}
+
+			describeOutput, err := c.ACM().DescribeCertificate(ctx, &acm.DescribeCertificateInput{
+				CertificateArn: summary.CertificateArn,
+			})
+			if err != nil {
+				return "", fmt.Errorf("failed to describe certificate %s: %w", stringValue(summary.CertificateArn), err)
+			}
+			if strings.EqualFold(stringValue(describeOutput.Certificate.Serial), serial) {
+				return stringValue(summary.CertificateArn), nil
+			}
+		}
+	}
+
+	return "none", nil
+}
+
+// formatSerial formats a serial number like ACM does, as colon separated hex bytes
+func formatSerial(serial []byte) string {
+	parts := make([]string, len(serial))
+	for i, b := range serial {
+		parts[i] = fmt.Sprintf("%02x", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+// coversHost reports whether one of the certificate names matches the host, wildcards included
+func coversHost(names []string, host string) bool {
+	host = strings.ToLower(host)
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if name == host {
+			return true
+		}
+		if suffix, ok := strings.CutPrefix(name, "*."); ok {
+			if label, rest, found := strings.Cut(host, "."); found && label != "" && rest == suffix {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Rows returns the table data
+func (t *TLSChain) Rows() [][]string {
+	rows := make([][]string, len(t.certificates))
+	for i, cert := range t.certificates {
+		acmValue := cert.ACM
+		if slash := strings.LastIndex(acmValue, "/"); slash >= 0 {
+			acmValue = acmValue[slash+1:]
+		}
+		rows[i] = []string{
+			cert.Position,
+			cert.Subject,
+			cert.Issuer,
+			cert.NotAfter.Format("2006-01-02"),
+			cert.Names,
+			acmValue,
+			cert.Problem,
+		}
+	}
+	return rows
+}
+
+// GetID returns the SHA-256 fingerprint of the certificate at the given index
+func (t *TLSChain) GetID(index int) string {
+	if index >= 0 && index < len(t.certificates) {
+		return t.certificates[index].Fingerprint
+	}
+	return ""
+}
+
+// Highlight reports whether the certificate has a problem
+func (t *TLSChain) Highlight(index int) bool {
+	if index < 0 || index >= len(t.certificates) {
+		return false
+	}
+	return t.certificates[index].Problem != ""
+}
+
+// QuickActions returns the available quick actions for the certificate chain
+func (t *TLSChain) QuickActions() []QuickAction {
+	return []QuickAction{
+		{
+			Key:            'g',
+			Label:          "goto",
+			Description:    "Open the matching ACM certificate",
+			NeedsSelection: true,
+			Goto: func(fingerprint string) (string, string, error) {
+				for _, cert := range t.certificates {
+					if cert.Fingerprint != fingerprint {
+						continue
+					}
+					if !strings.HasPrefix(cert.ACM, "arn:") {
+						return "", "", fmt.Errorf("%s is not an ACM certificate of the account", cert.Subject)
+					}
+					return "acm", cert.ACM, nil
+				}
+				return "", "", fmt.Errorf("certificate %s is not listed", fingerprint)
+			},
+		},
+	}
+}
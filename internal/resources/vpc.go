@@ -286,6 +286,11 @@ func (s *SecurityGroups) GetID(index int) string {
 	return ""
 }
 
+// DrillDown opens the inbound and outbound rules of the security group
+func (s *SecurityGroups) DrillDown(groupID string) Resource {
+	return NewSecurityGroupRules(groupID)
+}
+
 // QuickActions returns the available quick actions for security groups
 func (s *SecurityGroups) QuickActions() []QuickAction {
 	return []QuickAction{}
@@ -0,0 +1,65 @@
+package view
+
+import (
+	"time"
+
+	"a9s/internal/resources"
+	"a9s/pkg/log"
+
+	"go.uber.org/zap"
+)
+
+// anomalyWindow is the period checked for new cost anomalies
+const anomalyWindow = 7 * 24 * time.Hour
+
+// watchAnomalies polls Cost Anomaly Detection and flags the anomalies detected
+// during the session in the header, the ones known at start are not flagged
+func (a *App) watchAnomalies() {
+	ticker := time.NewTicker(a.config.AnomalyCheck)
+	defer ticker.Stop()
+
+	known := make(map[string]bool)
+	baseline := true
+	for {
+		anomalies, err := resources.ListCostAnomalies(a.ctx, a.client, time.Now().Add(-anomalyWindow))
+		if err != nil {
+			log.Warn("failed to check cost anomalies", zap.Error(err))
+		} else {
+			fresh := 0
+			for _, anomaly := range anomalies {
+				if known[anomaly.ID] {
+					continue
+				}
+				known[anomaly.ID] = true
+				if !baseline {
+					fresh++
+				}
+			}
+			baseline = false
+
+			if fresh > 0 {
+				a.app.QueueUpdateDraw(func() {
+					a.newAnomalies += fresh
+					a.updateHeader()
+				})
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-a.stopRefresh:
+			return
+		case <-a.ctx.Done():
+			return
+		}
+	}
+}
+
+// clearAnomalies removes the new anomalies badge once they are looked at
+func (a *App) clearAnomalies() {
+	if a.newAnomalies == 0 {
+		return
+	}
+	a.newAnomalies = 0
+	a.updateHeader()
+}
@@ -3,11 +3,14 @@ package view
 import (
 	"context"
 	"fmt"
+	"os/exec"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"a9s/internal/audit"
 	"a9s/internal/client"
 	"a9s/internal/resources"
 
@@ -29,30 +32,136 @@ type App struct {
 	registry  *resources.Registry
 	current   resources.Resource
 	ctx       context.Context
+	config    Config
 
-	// Resource keys for menu filtering
+	// Resource keys for menu filtering, and the key of each menu item ("" for category headers)
 	resourceKeys []string
+	menuKeys     []string
+
+	// Parent views of the current drill-down, most recent last
+	history []resources.Resource
+
+	// ID of the row to select once the current resource is fetched, after a cross-view jump
+	pendingSelect string
+
+	// Resources whose permissions passed the pre-flight, by profile and key
+	checkedPermissions map[string]bool
+
+	// Masking of sensitive values, nil when disabled
+	masker   *masker
+	revealed bool
+
+	// Cost anomalies detected during the session and not looked at yet
+	newAnomalies int
+
+	// Last fetch of each resource, displayed again without fetching while fresh
+	fetched map[resources.Resource]fetchRecord
 
 	// Auto-refresh
 	autoRefresh   bool
 	refreshTicker *time.Ticker
 	stopRefresh   chan struct{}
 	refreshMu     sync.Mutex
+
+	// Mutating actions in flight, cancelled through actionsCtx on quit
+	actions        sync.WaitGroup
+	pendingActions atomic.Int32
+	actionsCtx     context.Context
+	cancelActions  context.CancelFunc
+
+	// Fetches running in the background, for the debug stats
+	activeFetches atomic.Int32
+	startedAt     time.Time
+
+	// Port forwarding sessions running in the background, closed on quit
+	tunnels    map[int]*tunnel
+	tunnelsMu  sync.Mutex
+	nextTunnel int
+
+	// Idle lock
+	lastActivity atomic.Int64
+	locked       atomic.Bool
+	lockedFocus  tview.Primitive
+}
+
+// Config holds the user settings of the application
+type Config struct {
+	// TicketPrompt asks for a change ticket reference before mutating actions
+	TicketPrompt bool
+
+	// IdleTimeout locks the UI after this period of inactivity, zero disables it
+	IdleTimeout time.Duration
+
+	// Preflight checks the IAM permissions of a resource before its first fetch
+	Preflight bool
+
+	// Mask hides sensitive columns and values matching MaskPatterns until revealed
+	Mask         bool
+	MaskPatterns []string
+
+	// Resource is the key of the resource opened at start, if any
+	Resource string
+
+	// KeyMaxAge is the age above which IAM access keys are highlighted
+	KeyMaxAge time.Duration
+
+	// RequiredTags are the tags checked by the tag compliance report
+	RequiredTags []string
+
+	// AnomalyCheck is the interval of the cost anomaly checks, zero disables them
+	AnomalyCheck time.Duration
+
+	// SSHRules set the user and key used to ssh into EC2 instances
+	SSHRules []resources.SSHRule
+
+	// CacheTTL is how long the data of a resource is displayed again without fetching it
+	// when coming back to it, zero disables the cache
+	CacheTTL time.Duration
+
+	// Accounts are the roles of the account switcher, OrganizationRole the role assumed in
+	// the accounts of the organization, which are not discovered when empty
+	Accounts         []resources.AccountRole
+	OrganizationRole string
 }
 
 // Default refresh interval for auto-refresh
 const defaultRefreshInterval = 10 * time.Second
 
+// Refresh interval of the resources following a running operation
+const streamRefreshInterval = 3 * time.Second
+
 // New creates a new App instance
-func New(ctx context.Context, c *client.Client) *App {
+func New(ctx context.Context, c *client.Client, config Config) *App {
+	registry := resources.DefaultRegistry(resources.Options{
+		KeyMaxAge:        config.KeyMaxAge,
+		RequiredTags:     config.RequiredTags,
+		SSHRules:         config.SSHRules,
+		Accounts:         config.Accounts,
+		OrganizationRole: config.OrganizationRole,
+	})
 	a := &App{
 		app:         tview.NewApplication(),
 		pages:       tview.NewPages(),
-		registry:    resources.DefaultRegistry(),
+		registry:    registry,
 		client:      c,
 		ctx:         ctx,
+		config:      config,
 		autoRefresh: true,
 		stopRefresh: make(chan struct{}),
+
+		checkedPermissions: make(map[string]bool),
+		fetched:            make(map[resources.Resource]fetchRecord),
+		startedAt:          time.Now(),
+		tunnels:            make(map[int]*tunnel),
+	}
+	a.actionsCtx, a.cancelActions = context.WithCancel(ctx)
+
+	if config.Mask {
+		patterns := config.MaskPatterns
+		if len(patterns) == 0 {
+			patterns = defaultMaskPatterns
+		}
+		a.masker = newMasker(patterns)
 	}
 
 	a.setupUI()
@@ -73,6 +182,9 @@ func (a *App) setupUI() {
 		SetSelectable(true, false).
 		SetFixed(1, 0)
 	a.table.SetBorder(true).SetTitle(" Resources ")
+	a.table.SetSelectedFunc(func(row, column int) {
+		a.drillDown(row)
+	})
 
 	// Status bar
 	a.status = tview.NewTextView().
@@ -91,7 +203,7 @@ func (a *App) setupUI() {
 		AddItem(a.status, 1, 0, false)
 
 	a.pages.AddPage("main", mainFlex, true, true)
-	a.pages.AddPage("menu", a.createModal(a.menu, 40, 15), true, false)
+	a.pages.AddPage("menu", a.createModal(a.menu, 80, a.menuHeight()), true, false)
 
 	// Key bindings
 	a.setupKeyBindings()
@@ -142,11 +254,14 @@ func (a *App) setupResourceMenu() {
 			a.app.SetFocus(a.menuList)
 			return nil
 		case tcell.KeyEnter:
-			// Select first item if list has items
-			if a.menuList.GetItemCount() > 0 {
-				a.menuList.SetCurrentItem(0)
-				mainText, _ := a.menuList.GetItemText(0)
-				a.selectResource(mainText)
+			if a.runCommand(a.menuInput.GetText()) {
+				return nil
+			}
+
+			// Select first resource if list has items
+			if index := a.firstMenuResource(); index >= 0 {
+				a.menuList.SetCurrentItem(index)
+				a.selectResource(a.menuKeys[index])
 			}
 			return nil
 		case tcell.KeyEscape:
@@ -160,7 +275,7 @@ func (a *App) setupResourceMenu() {
 	a.menuList.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		switch event.Key() {
 		case tcell.KeyUp:
-			if a.menuList.GetCurrentItem() == 0 {
+			if a.menuList.GetCurrentItem() <= a.firstMenuResource() {
 				a.app.SetFocus(a.menuInput)
 				return nil
 			}
@@ -181,22 +296,62 @@ func (a *App) setupResourceMenu() {
 		SetDirection(tview.FlexRow).
 		AddItem(a.menuInput, 1, 0, true).
 		AddItem(a.menuList, 0, 1, false)
-	a.menu.SetBorder(true).SetTitle(" Select Resource (Esc to close) ")
+	a.menu.SetBorder(true).SetTitle(" Select Resource, or 'cc <type>' (Esc to close) ")
 }
 
-// populateMenuList populates the menu list based on search filter
+// populateMenuList populates the menu list based on search filter, grouped by category
 func (a *App) populateMenuList(filter string) {
 	a.menuList.Clear()
+	a.menuKeys = a.menuKeys[:0]
 	filter = strings.ToLower(filter)
 
-	for _, key := range a.resourceKeys {
-		if filter == "" || strings.Contains(strings.ToLower(key), filter) {
+	for _, category := range resources.Categories() {
+		headerAdded := false
+		for _, key := range a.resourceKeys {
+			meta, _ := a.registry.Metadata(key)
+			if meta.Category != category {
+				continue
+			}
+			if filter != "" &&
+				!strings.Contains(strings.ToLower(key), filter) &&
+				!strings.Contains(strings.ToLower(meta.Description), filter) {
+				continue
+			}
+
+			if !headerAdded {
+				a.menuList.AddItem(fmt.Sprintf("[yellow::b]%s", strings.ToUpper(string(category))), "", 0, nil)
+				a.menuKeys = append(a.menuKeys, "")
+				headerAdded = true
+			}
+
 			k := key // capture for closure
-			a.menuList.AddItem(key, "", 0, func() {
+			a.menuList.AddItem(fmt.Sprintf("  %-22s [gray]%s", key, meta.Description), "", 0, func() {
 				a.selectResource(k)
 			})
+			a.menuKeys = append(a.menuKeys, key)
+		}
+	}
+
+	if index := a.firstMenuResource(); index >= 0 {
+		a.menuList.SetCurrentItem(index)
+	}
+}
+
+// firstMenuResource returns the index of the first resource in the menu, -1 if none
+func (a *App) firstMenuResource() int {
+	for i, key := range a.menuKeys {
+		if key != "" {
+			return i
 		}
 	}
+	return -1
+}
+
+// menuHeight returns the menu height fitting all resources and category headers
+func (a *App) menuHeight() int {
+	// Search input, borders and one header per category
+	height := len(a.resourceKeys) + len(resources.Categories()) + 3
+	return min(height, 40)
 }
 
 // closeMenu closes the resource menu and returns to main view
@@ -210,8 +365,37 @@ func (a *App) closeMenu() {
 // setupKeyBindings configures global key bindings
 func (a *App) setupKeyBindings() {
 	a.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		a.touch()
+		if a.locked.Load() {
+			return a.handleLockedKey(event)
+		}
+
 		// Global key bindings
 		switch event.Key() {
+		case tcell.KeyCtrlC:
+			a.quit()
+			return nil
+		case tcell.KeyCtrlL:
+			a.lock()
+			return nil
+		case tcell.KeyCtrlR:
+			a.toggleReveal()
+			return nil
+		case tcell.KeyCtrlN:
+			if !a.interacting() {
+				a.loadMore()
+				return nil
+			}
+		case tcell.KeyCtrlT:
+			if !a.interacting() {
+				a.toggleMetrics()
+				return nil
+			}
+		case tcell.KeyCtrlU:
+			if !a.interacting() {
+				a.dropRole()
+				return nil
+			}
 		case tcell.KeyEscape:
 			if a.pages.HasPage("confirm") {
 				name, _ := a.pages.GetFrontPage()
@@ -237,6 +421,10 @@ func (a *App) setupKeyBindings() {
 					return nil
 				}
 			}
+			if name, _ := a.pages.GetFrontPage(); name == "main" && len(a.history) > 0 {
+				a.goBack()
+				return nil
+			}
 		case tcell.KeyRune:
 			// Only process these keys when on main page
 			name, _ := a.pages.GetFrontPage()
@@ -249,7 +437,7 @@ func (a *App) setupKeyBindings() {
 				a.app.SetFocus(a.menuInput)
 				return nil
 			case 'q':
-				a.app.Stop()
+				a.quit()
 				return nil
 			case 'f':
 				// Refresh current resource
@@ -289,12 +477,23 @@ func (a *App) setupKeyBindings() {
 		}
 		return event
 	})
+
+	a.app.SetMouseCapture(func(event *tcell.EventMouse, action tview.MouseAction) (*tcell.EventMouse, tview.MouseAction) {
+		a.touch()
+		if a.locked.Load() {
+			return nil, action
+		}
+		return event, action
+	})
 }
 
 // handleQuickAction executes a resource quick action
 func (a *App) handleQuickAction(action resources.QuickAction) {
 	// Special handling for S3 create (needs input dialog)
-	if action.Key == 'c' && action.Handler == nil {
+	if action.Key == 'c' && action.Handler == nil && action.InputHandler == nil && action.InputTextHandler == nil && action.InputPlan == nil &&
+		action.View == nil && action.InputView == nil && action.TextHandler == nil && action.Plan == nil &&
+		action.Jump == nil && action.Goto == nil && action.Toggle == nil && action.Mark == nil && action.Command == nil &&
+		action.Tunnel == nil && action.InputTunnel == nil && action.EditHandler == nil && action.EditPlan == nil {
 		a.handleS3CreateWithInput()
 		return
 	}
@@ -313,21 +512,148 @@ func (a *App) handleQuickAction(action resources.QuickAction) {
 			return
 		}
 
-		// Show confirmation if needed
-		if action.NeedsConfirm {
-			a.showActionConfirm(action, selectedID)
-		} else {
-			a.executeQuickAction(action, selectedID)
-		}
+		a.runQuickAction(action, selectedID)
 	} else {
 		// Actions that don't need selection
-		a.executeQuickAction(action, "")
+		a.runQuickAction(action, "")
 	}
 }
 
-// showActionConfirm displays a confirmation dialog for an action
+// runQuickAction dispatches an action to its view, input dialog, plan, confirmation or handler
+func (a *App) runQuickAction(action resources.QuickAction, selectedID string) {
+	switch {
+	case action.View != nil:
+		a.openView(action.View(selectedID))
+	case action.Jump != nil:
+		a.jump(action, selectedID)
+	case action.Goto != nil:
+		a.goTo(action, selectedID)
+	case action.Toggle != nil:
+		action.Toggle()
+		a.refreshResource()
+	case action.Mark != nil:
+		action.Mark(selectedID)
+		a.renderTable()
+		a.selectRow(selectedID)
+	case action.InputHandler != nil, action.InputTextHandler != nil, action.InputView != nil, action.InputPlan != nil, action.InputTunnel != nil:
+		a.showActionInput(action, selectedID)
+	case action.EditHandler != nil, action.EditPlan != nil:
+		a.editActionText(action, selectedID)
+	case action.Plan != nil:
+		a.promptTicket(func(ticket string) {
+			a.executePlan(action, selectedID, ticket)
+		})
+	case action.Command != nil:
+		a.executeCommand(action, selectedID)
+	case action.Tunnel != nil:
+		a.startTunnel(action, selectedID)
+	case action.NeedsConfirm:
+		a.showActionConfirm(action, selectedID)
+	case action.TextHandler != nil:
+		a.executeTextAction(action, selectedID)
+	default:
+		a.promptActionTicket(action, func(ticket string) {
+			a.executeQuickAction(action, selectedID, ticket)
+		})
+	}
+}
+
+// showActionInput asks for the value of an input action, then confirms and executes it
+func (a *App) showActionInput(action resources.QuickAction, selectedID string) {
+	input := tview.NewInputField().
+		SetLabel(action.InputLabel).
+		SetFieldWidth(40).
+		SetFieldBackgroundColor(tcell.ColorDarkSlateGray)
+	if action.InputDefault != nil {
+		input.SetText(action.InputDefault(selectedID))
+	}
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		a.pages.RemovePage("input")
+		a.pages.SwitchToPage("main")
+		a.app.SetFocus(a.table)
+
+		value := strings.TrimSpace(input.GetText())
+		if key != tcell.KeyEnter || (value == "" && !action.InputOptional) {
+			return
+		}
+
+		// Bind the input so the action runs like any other one
+		bound := action
+		bound.InputHandler = nil
+		bound.InputTextHandler = nil
+		bound.InputView = nil
+		bound.InputPlan = nil
+		bound.InputTunnel = nil
+		switch {
+		case action.InputView != nil:
+			bound.View = func(id string) resources.Resource {
+				return action.InputView(id, value)
+			}
+		case action.InputTextHandler != nil:
+			bound.TextHandler = func(ctx context.Context, c *client.Client, id string) (string, error) {
+				return action.InputTextHandler(ctx, c, id, value)
+			}
+		case action.InputPlan != nil:
+			bound.Plan = func(ctx context.Context, c *client.Client, id string) ([]resources.Step, error) {
+				return action.InputPlan(ctx, c, id, value)
+			}
+		case action.InputTunnel != nil:
+			bound.Description = fmt.Sprintf("%s %s", action.Description, value)
+			bound.Tunnel = func(ctx context.Context, c *client.Client, id string) (*exec.Cmd, error) {
+				return action.InputTunnel(ctx, c, id, value)
+			}
+		default:
+			bound.Handler = func(ctx context.Context, c *client.Client, id string) error {
+				return action.InputHandler(ctx, c, id, value)
+			}
+		}
+		a.runQuickAction(bound, selectedID)
+	})
+
+	form := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(input, 1, 0, true)
+	form.SetBorder(true).SetTitle(fmt.Sprintf(" %s (Enter to confirm, Esc to cancel) ", action.Description))
+
+	modal := a.createModal(form, 70, 3)
+	a.pages.AddPage("input", modal, true, true)
+	a.app.SetFocus(input)
+}
+
+// showActionConfirm runs the checks of an action, then displays its confirmation dialog
 func (a *App) showActionConfirm(action resources.QuickAction, selectedID string) {
+	if action.ConfirmCheck == nil {
+		a.showConfirmDialog(action, selectedID, "")
+		return
+	}
+
+	a.updateStatus(fmt.Sprintf("[yellow]Checking %s...", selectedID))
+	go func() {
+		warning, err := action.ConfirmCheck(a.ctx, a.client, selectedID)
+
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				a.updateStatus(fmt.Sprintf("[red]Cannot %s: %v", action.Label, err))
+				return
+			}
+			a.updateStatus("")
+			a.showConfirmDialog(action, selectedID, warning)
+		})
+	}()
+}
+
+// showConfirmDialog displays a confirmation dialog for an action, with the warning of its checks
+func (a *App) showConfirmDialog(action resources.QuickAction, selectedID, warning string) {
 	confirmText := fmt.Sprintf(action.ConfirmTemplate, selectedID)
+	if warning != "" {
+		confirmText += "\n\n[yellow]" + tview.Escape(warning) + "[-]"
+	}
+
+	if action.TypedConfirm {
+		a.showTypedConfirm(action, selectedID, confirmText)
+		return
+	}
 
 	modal := tview.NewModal().
 		SetText(confirmText).
@@ -338,7 +664,9 @@ func (a *App) showActionConfirm(action resources.QuickAction, selectedID string)
 			a.app.SetFocus(a.table)
 
 			if buttonLabel == "Yes" {
-				a.executeQuickAction(action, selectedID)
+				a.promptActionTicket(action, func(ticket string) {
+					a.executeQuickAction(action, selectedID, ticket)
+				})
 			}
 		})
 
@@ -346,20 +674,147 @@ func (a *App) showActionConfirm(action resources.QuickAction, selectedID string)
 	a.app.SetFocus(modal)
 }
 
-// executeQuickAction executes a quick action
-func (a *App) executeQuickAction(action resources.QuickAction, selectedID string) {
+// showTypedConfirm asks the user to type the selected ID to confirm a destructive action
+func (a *App) showTypedConfirm(action resources.QuickAction, selectedID, confirmText string) {
+	text := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(true).
+		SetText(confirmText)
+
+	input := tview.NewInputField().
+		SetLabel("Type the ID to confirm: ").
+		SetFieldWidth(40).
+		SetFieldBackgroundColor(tcell.ColorDarkSlateGray)
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		a.pages.RemovePage("confirm")
+		a.pages.SwitchToPage("main")
+		a.app.SetFocus(a.table)
+
+		if key != tcell.KeyEnter {
+			return
+		}
+		if strings.TrimSpace(input.GetText()) != selectedID {
+			a.updateStatus(fmt.Sprintf("[yellow]The typed ID does not match, %s cancelled", action.Label))
+			return
+		}
+		a.promptActionTicket(action, func(ticket string) {
+			a.executeQuickAction(action, selectedID, ticket)
+		})
+	})
+
+	form := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(text, 0, 1, false).
+		AddItem(input, 1, 0, true)
+	form.SetBorder(true).SetTitle(fmt.Sprintf(" %s (Enter to confirm, Esc to cancel) ", action.Description))
+
+	modal := a.createModal(form, 80, 9)
+	a.pages.AddPage("confirm", modal, true, true)
+	a.app.SetFocus(input)
+}
+
+// promptTicket asks for a change ticket reference when enabled, then calls fn with it
+func (a *App) promptTicket(fn func(ticket string)) {
+	if !a.config.TicketPrompt {
+		fn("")
+		return
+	}
+
+	input := tview.NewInputField().
+		SetLabel("Ticket: ").
+		SetFieldWidth(30).
+		SetFieldBackgroundColor(tcell.ColorDarkSlateGray)
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		a.pages.RemovePage("ticket")
+		a.pages.SwitchToPage("main")
+		a.app.SetFocus(a.table)
+
+		if key == tcell.KeyEnter {
+			ticket := strings.TrimSpace(input.GetText())
+			if ticket == "" {
+				a.updateStatus("[yellow]A change ticket is required for this action")
+				return
+			}
+			fn(ticket)
+		}
+	})
+
+	form := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(input, 1, 0, true)
+	form.SetBorder(true).SetTitle(" Change Ticket (Enter to confirm, Esc to cancel) ")
+
+	modal := a.createModal(form, 50, 3)
+	a.pages.AddPage("ticket", modal, true, true)
+	a.app.SetFocus(input)
+}
+
+// promptActionTicket asks for a change ticket before an action, unless it is read-only
+func (a *App) promptActionTicket(action resources.QuickAction, fn func(ticket string)) {
+	if action.ReadOnly {
+		fn("")
+		return
+	}
+	a.promptTicket(fn)
+}
+
+// recordAudit writes an action outcome to the audit log
+func (a *App) recordAudit(resourceName, action, target, ticket string, err error) {
+	entry := audit.Entry{
+		Profile:  a.client.Profile(),
+		Region:   a.client.Region(),
+		Resource: resourceName,
+		Action:   action,
+		Target:   target,
+		Ticket:   ticket,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	audit.Record(entry)
+}
+
+// executeQuickAction executes a quick action, annotated with the given change ticket
+func (a *App) executeQuickAction(action resources.QuickAction, selectedID, ticket string) {
+	if action.TextHandler != nil {
+		a.executeTextAction(action, selectedID)
+		return
+	}
+
 	a.updateStatus(fmt.Sprintf("[yellow]%sing %s...", action.Label, selectedID))
 
+	ctx := a.actionsCtx
+	if ticket != "" {
+		ctx = client.WithTicket(ctx, ticket)
+	}
+	ctx = resources.WithProgress(ctx, func(text string) {
+		a.app.QueueUpdateDraw(func() {
+			a.updateStatus("[yellow]" + text)
+		})
+	})
+	resourceName := a.current.Name()
+
+	a.beginAction()
 	go func() {
-		err := action.Handler(a.ctx, a.client, selectedID)
+		defer a.endAction()
+		err := action.Handler(ctx, a.client, selectedID)
+		a.recordAudit(resourceName, action.Label, selectedID, ticket, err)
 
 		a.app.QueueUpdateDraw(func() {
 			if err != nil {
-				a.updateStatus(fmt.Sprintf("[red]Failed to %s: %v", action.Label, err))
+				a.updateStatus(errorStatus("Failed to "+action.Label, err))
 				return
 			}
 
 			a.updateStatus(fmt.Sprintf("[green]Successfully initiated %s for %s", action.Label, selectedID))
+			// An action may have changed the credentials, e.g. by assuming a role
+			a.updateHeader()
+			if action.Follow != nil {
+				a.openView(action.Follow(selectedID))
+				return
+			}
 			// Refresh to show updated state
 			time.Sleep(2 * time.Second)
 			a.refreshResource()
@@ -416,7 +871,9 @@ func (a *App) showS3CreateConfirmDialog(bucketName string, s3Res *resources.S3Bu
 			a.app.SetFocus(a.table)
 
 			if buttonLabel == "Yes" {
-				a.executeS3CreateAction(bucketName, s3Res)
+				a.promptTicket(func(ticket string) {
+					a.executeS3CreateAction(bucketName, s3Res, ticket)
+				})
 			}
 		})
 
@@ -425,11 +882,19 @@ func (a *App) showS3CreateConfirmDialog(bucketName string, s3Res *resources.S3Bu
 }
 
 // executeS3CreateAction executes S3 bucket creation
-func (a *App) executeS3CreateAction(bucketName string, s3Res *resources.S3Buckets) {
+func (a *App) executeS3CreateAction(bucketName string, s3Res *resources.S3Buckets, ticket string) {
 	a.updateStatus(fmt.Sprintf("[yellow]Creating bucket %s...", bucketName))
 
+	ctx := a.actionsCtx
+	if ticket != "" {
+		ctx = client.WithTicket(ctx, ticket)
+	}
+
+	a.beginAction()
 	go func() {
-		err := s3Res.CreateBucket(a.ctx, a.client, bucketName)
+		defer a.endAction()
+		err := s3Res.CreateBucket(ctx, a.client, bucketName)
+		a.recordAudit(s3Res.Name(), "create", bucketName, ticket, err)
 
 		a.app.QueueUpdateDraw(func() {
 			if err != nil {
@@ -452,17 +917,111 @@ func (a *App) selectResource(key string) {
 		a.updateStatus(fmt.Sprintf("[red]Unknown resource: %s", key))
 		return
 	}
+	if key == "billing-anomalies" {
+		a.clearAnomalies()
+	}
+
+	if a.config.Preflight {
+		a.checkPermissions(key, func() { a.showResource(res) })
+		return
+	}
+	a.showResource(res)
+}
 
+// showResource makes a resource the current top-level view
+func (a *App) showResource(res resources.Resource) {
 	a.current = res
+	a.history = nil
 	// Clear search and close menu
 	a.menuInput.SetText("")
 	a.populateMenuList("")
 	a.pages.SwitchToPage("main")
 	a.app.SetFocus(a.table)
+	a.loadResource()
+	a.startAutoRefresh()
+}
+
+// drillDown opens the child view of the given table row, if the resource supports it
+func (a *App) drillDown(row int) {
+	drillable, ok := a.current.(resources.Drillable)
+	if !ok || row <= 0 {
+		return
+	}
+
+	selectedID := a.current.GetID(row - 1)
+	if selectedID == "" {
+		return
+	}
+
+	if child := drillable.DrillDown(selectedID); child != nil {
+		a.openView(child)
+	}
+}
+
+// openView shows a child resource, keeping the current one to go back to
+func (a *App) openView(res resources.Resource) {
+	if res == nil {
+		return
+	}
+	if a.current != nil {
+		a.history = append(a.history, a.current)
+	}
+	a.current = res
 	a.refreshResource()
 	a.startAutoRefresh()
 }
 
+// jump selects the row related to the selected one, e.g. the dead-letter queue of a queue
+func (a *App) jump(action resources.QuickAction, selectedID string) {
+	targetID, err := action.Jump(selectedID)
+	if err != nil {
+		a.updateStatus(fmt.Sprintf("[yellow]%v", err))
+		return
+	}
+
+	if a.selectRow(targetID) {
+		a.updateStatus(fmt.Sprintf("[green]Jumped to %s", targetID))
+		return
+	}
+	a.updateStatus(fmt.Sprintf("[yellow]%s is not listed", targetID))
+}
+
+// goTo opens the registered resource related to the selected row and selects the related row in it
+func (a *App) goTo(action resources.QuickAction, selectedID string) {
+	key, targetID, err := action.Goto(selectedID)
+	if err != nil {
+		a.updateStatus(fmt.Sprintf("[yellow]%v", err))
+		return
+	}
+
+	res, ok := a.registry.Get(key)
+	if !ok {
+		a.updateStatus(fmt.Sprintf("[red]Unknown resource: %s", key))
+		return
+	}
+	a.pendingSelect = targetID
+	a.openView(res)
+}
+
+// selectRow selects the row of the given ID in the table, if listed
+func (a *App) selectRow(id string) bool {
+	for i, row := 0, a.table.GetRowCount()-1; i < row; i++ {
+		if a.current.GetID(i) == id {
+			a.table.Select(i+1, 0)
+			return true
+		}
+	}
+	return false
+}
+
+// goBack returns to the parent of the current child view
+func (a *App) goBack() {
+	a.current = a.history[len(a.history)-1]
+	a.history = a.history[:len(a.history)-1]
+	a.loadResource()
+	a.startAutoRefresh()
+}
+
 // refreshResource fetches and displays the current resource
 func (a *App) refreshResource() {
 	if a.current == nil {
@@ -472,46 +1031,74 @@ func (a *App) refreshResource() {
 	a.updateStatus("[yellow]Loading...")
 	a.table.Clear()
 
+	// The fetch is recorded for the resource and the scope it started with, the user may
+	// have moved on or switched profile or region meanwhile
+	res, c, scope := a.current, a.client, a.cacheScope()
+
+	a.activeFetches.Add(1)
 	go func() {
-		err := a.current.Fetch(a.ctx, a.client)
+		defer a.activeFetches.Add(-1)
+		err := res.Fetch(a.ctx, c)
 
 		a.app.QueueUpdateDraw(func() {
+			if err == nil {
+				a.recordFetch(res, scope)
+			}
+			if a.current != res {
+				return
+			}
 			if err != nil {
-				a.updateStatus(fmt.Sprintf("[red]Error: %v", err))
+				a.updateStatus(errorStatus("Error", err))
 				return
 			}
 
 			a.renderTable()
-			rows := a.current.Rows()
-			autoStatus := "[gray]auto:off"
-			if a.autoRefresh {
-				autoStatus = "[green]auto:on"
+			if a.pendingSelect != "" {
+				a.selectRow(a.pendingSelect)
+				a.pendingSelect = ""
 			}
-
-			// Build resource-specific help text from quick actions
-			resourceHelp := a.buildQuickActionsHelp()
-
-			a.updateStatus(fmt.Sprintf("%s | [green]%s: %d items | [white]f: refresh | a: auto | p: profile | r: region | :: menu | q: quit%s",
-				autoStatus, a.current.Name(), len(rows), resourceHelp))
+			a.updateStatus(a.resourceStatus())
 		})
 	}()
 }
 
+// resourceStatus returns the status bar text of the current resource once displayed
+func (a *App) resourceStatus() string {
+	rows := a.current.Rows()
+	autoStatus := "[gray]auto:off"
+	if a.autoRefresh {
+		autoStatus = "[green]auto:on"
+	}
+
+	// Build resource-specific help text from quick actions
+	resourceHelp := a.buildQuickActionsHelp()
+
+	return fmt.Sprintf("%s | [green]%s: %s | [white]f: refresh | a: auto | p: profile | r: region | :: menu | q: quit%s",
+		autoStatus, a.current.Name(), a.itemCount(len(rows)), resourceHelp)
+}
+
 // buildQuickActionsHelp builds the help text for resource quick actions
 func (a *App) buildQuickActionsHelp() string {
 	if a.current == nil {
 		return ""
 	}
 
-	actions := a.current.QuickActions()
-	if len(actions) == 0 {
-		return ""
-	}
-
 	var parts []string
-	for _, action := range actions {
+	if _, ok := a.current.(resources.Drillable); ok {
+		parts = append(parts, "enter: open")
+	}
+	if len(a.history) > 0 {
+		parts = append(parts, "esc: back")
+	}
+	if _, ok := a.current.(resources.MetricsToggler); ok {
+		parts = append(parts, "^T: metrics")
+	}
+	for _, action := range a.current.QuickActions() {
 		parts = append(parts, fmt.Sprintf("%c: %s", action.Key, action.Label))
 	}
+	if len(parts) == 0 {
+		return ""
+	}
 
 	return " | " + strings.Join(parts, " | ")
 }
@@ -534,12 +1121,20 @@ func (a *App) renderTable() {
 		a.table.SetCell(0, i, cell)
 	}
 
-	// Data rows
+	// Data rows, highlighted ones in red
+	highlighter, _ := a.current.(resources.Highlighter)
 	rows := a.current.Rows()
 	for i, row := range rows {
+		color := tcell.ColorWhite
+		if highlighter != nil && highlighter.Highlight(i) {
+			color = tcell.ColorRed
+		}
 		for j, value := range row {
+			if a.masker != nil && !a.revealed && j < len(columns) && a.masker.shouldMask(columns[j], value) {
+				value = a.masker.mask(value)
+			}
 			cell := tview.NewTableCell(value).
-				SetTextColor(tcell.ColorWhite).
+				SetTextColor(color).
 				SetExpansion(1)
 			a.table.SetCell(i+1, j, cell)
 		}
@@ -549,10 +1144,28 @@ func (a *App) renderTable() {
 	a.table.ScrollToBeginning()
 }
 
+// toggleReveal shows or hides the masked sensitive values
+func (a *App) toggleReveal() {
+	if a.masker == nil {
+		a.updateStatus("[gray]Masking is disabled")
+		return
+	}
+
+	a.revealed = !a.revealed
+	a.renderTable()
+
+	if a.revealed {
+		a.updateStatus("[yellow]Sensitive values revealed, press Ctrl+R to mask them")
+	} else {
+		a.updateStatus("[green]Sensitive values masked")
+	}
+}
+
 // updateHeader updates the header text
 func (a *App) updateHeader() {
 	region := "not configured"
 	profile := "not configured"
+	role := ""
 	if a.client != nil {
 		if a.client.Region() != "" {
 			region = a.client.Region()
@@ -560,8 +1173,19 @@ func (a *App) updateHeader() {
 		if a.client.Profile() != "" {
 			profile = a.client.Profile()
 		}
+		if arn, expires := a.client.AssumedRole(); arn != "" {
+			role = fmt.Sprintf(" | [yellow]Account: %s | Role: %s until %s[gray], Ctrl+U to drop it",
+				a.client.AssumedAccount(), arn[strings.LastIndex(arn, "/")+1:], expires.Local().Format("15:04"))
+		}
 	}
-	a.header.SetText(fmt.Sprintf("[::b]a9s[-:-:-] - AWS Resource Browser\n[gray]Region: %s | Profile: %s", region, profile))
+	badge := role
+	if a.newAnomalies > 0 {
+		badge += fmt.Sprintf(" | [red::b]%d new cost anomalies[-:-:-][gray], see billing-anomalies", a.newAnomalies)
+	}
+	if n := a.tunnelCount(); n > 0 {
+		badge += fmt.Sprintf(" | [green]%d active tunnels[gray], see tunnels", n)
+	}
+	a.header.SetText(fmt.Sprintf("[::b]a9s[-:-:-] - AWS Resource Browser\n[gray]Region: %s | Profile: %s%s", region, profile, badge))
 }
 
 // updateStatus updates the status bar text
@@ -579,19 +1203,26 @@ func (a *App) startAutoRefresh() {
 		a.refreshTicker.Stop()
 	}
 
-	if !a.autoRefresh || a.current == nil {
+	if a.current == nil || (!a.autoRefresh && !a.streaming()) {
 		return
 	}
 
-	a.refreshTicker = time.NewTicker(defaultRefreshInterval)
+	interval := defaultRefreshInterval
+	if a.streaming() {
+		interval = streamRefreshInterval
+	}
+	a.refreshTicker = time.NewTicker(interval)
 
 	go func() {
 		for {
 			select {
 			case <-a.refreshTicker.C:
-				if a.autoRefresh && a.current != nil {
-					a.refreshResource()
-				}
+				// Skip the refresh while an overlay is open, it resumes on the next tick
+				a.app.QueueUpdate(func() {
+					if a.current != nil && (a.autoRefresh || a.streaming()) && !a.interacting() {
+						a.refreshResource()
+					}
+				})
 			case <-a.stopRefresh:
 				return
 			case <-a.ctx.Done():
@@ -601,6 +1232,18 @@ func (a *App) startAutoRefresh() {
 	}()
 }
 
+// streaming reports whether the current resource follows an operation still running
+func (a *App) streaming() bool {
+	streamer, ok := a.current.(resources.Streamer)
+	return ok && streamer.Streaming()
+}
+
+// interacting reports whether an overlay (menu, dialog, lock screen) is in front of the table
+func (a *App) interacting() bool {
+	name, _ := a.pages.GetFrontPage()
+	return name != "main"
+}
+
 // stopAutoRefresh stops the background auto-refresh ticker
 func (a *App) stopAutoRefresh() {
 	a.refreshMu.Lock()
@@ -635,8 +1278,8 @@ func (a *App) updateStatusWithAutoRefresh(prefix string) {
 	if a.current != nil {
 		rows := a.current.Rows()
 		resourceHelp := a.buildQuickActionsHelp()
-		a.updateStatus(fmt.Sprintf("%s | %s: %d items | [white]f: refresh | a: auto | p: profile | r: region | :: menu | q: quit%s",
-			autoStatus, a.current.Name(), len(rows), resourceHelp))
+		a.updateStatus(fmt.Sprintf("%s | %s: %s | [white]f: refresh | a: auto | p: profile | r: region | :: menu | q: quit%s",
+			autoStatus, a.current.Name(), a.itemCount(len(rows)), resourceHelp))
 	} else {
 		a.updateStatus(fmt.Sprintf("%s | [white]%s", autoStatus, prefix))
 	}
@@ -647,7 +1290,27 @@ func (a *App) Run() error {
 	defer func() {
 		close(a.stopRefresh)
 		a.stopAutoRefresh()
+		a.closeTunnels()
+		client.SetMFAPrompt(nil)
+		client.SetThrottleNotifier(nil)
 	}()
+
+	// Profiles and roles requiring MFA ask for the code in a modal from now on
+	client.SetMFAPrompt(a.promptMFA)
+	// and throttled calls are shown in the status bar while the SDK backs off
+	client.SetThrottleNotifier(a.notifyThrottle)
+
+	a.touch()
+	if a.config.IdleTimeout > 0 {
+		go a.watchIdle()
+	}
+	if a.config.AnomalyCheck > 0 {
+		go a.watchAnomalies()
+	}
+	if a.config.Resource != "" {
+		a.selectResource(a.config.Resource)
+	}
+
 	return a.app.SetRoot(a.pages, true).EnableMouse(true).Run()
 }
 
@@ -735,6 +1398,55 @@ func (a *App) switchProfile(profile string) {
 	}()
 }
 
+// assumeRole assumes a role on top of the profile and refreshes the view
+func (a *App) assumeRole(roleARN, mfaCode string) {
+	a.updateStatus(fmt.Sprintf("[yellow]Assuming role: %s...", roleARN))
+
+	go func() {
+		err := a.client.AssumeRole(a.ctx, roleARN, mfaCode)
+
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				a.updateStatus(fmt.Sprintf("[red]%v", err))
+				return
+			}
+
+			a.updateHeader()
+			a.updateStatus(fmt.Sprintf("[green]Assumed role: %s", roleARN))
+
+			if a.current != nil {
+				a.refreshResource()
+			}
+		})
+	}()
+}
+
+// dropRole goes back to the identity of the profile and refreshes the view
+func (a *App) dropRole() {
+	if arn, _ := a.client.AssumedRole(); arn == "" {
+		a.updateStatus("[yellow]No role assumed")
+		return
+	}
+
+	go func() {
+		err := a.client.DropRole(a.ctx)
+
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				a.updateStatus(fmt.Sprintf("[red]Failed to drop role: %v", err))
+				return
+			}
+
+			a.updateHeader()
+			a.updateStatus(fmt.Sprintf("[green]Back to profile: %s", a.client.Profile()))
+
+			if a.current != nil {
+				a.refreshResource()
+			}
+		})
+	}()
+}
+
 // switchRegion changes the AWS region and refreshes the view
 func (a *App) switchRegion(region string) {
 	a.updateStatus(fmt.Sprintf("[yellow]Switching to region: %s...", region))
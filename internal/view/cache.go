@@ -0,0 +1,65 @@
+package view
+
+import (
+	"fmt"
+	"time"
+
+	"a9s/internal/resources"
+)
+
+// fetchRecord is when a resource was last fetched, and for which identity and region
+type fetchRecord struct {
+	scope string
+	at    time.Time
+}
+
+// cacheScope identifies the profile, role and region the data of a resource belongs to
+func (a *App) cacheScope() string {
+	role, _ := a.client.AssumedRole()
+	return a.client.Profile() + "|" + role + "|" + a.client.Region()
+}
+
+// recordFetch remembers that a resource was just fetched in the given scope, and forgets
+// the records which expired
+func (a *App) recordFetch(res resources.Resource, scope string) {
+	if a.config.CacheTTL <= 0 {
+		return
+	}
+	for cached, record := range a.fetched {
+		if time.Since(record.at) > a.config.CacheTTL {
+			delete(a.fetched, cached)
+		}
+	}
+	a.fetched[res] = fetchRecord{scope: scope, at: time.Now()}
+}
+
+// cachedAge returns how long ago the current resource was fetched, false when its data
+// expired or belongs to another profile, role or region
+func (a *App) cachedAge() (time.Duration, bool) {
+	record, ok := a.fetched[a.current]
+	if !ok || record.scope != a.cacheScope() {
+		return 0, false
+	}
+	age := time.Since(record.at)
+	return age, age <= a.config.CacheTTL
+}
+
+// loadResource displays the current resource from its last fetch while it is fresh, and
+// fetches it otherwise, 'f' always fetches
+func (a *App) loadResource() {
+	if a.current == nil {
+		return
+	}
+	age, ok := a.cachedAge()
+	if !ok {
+		a.refreshResource()
+		return
+	}
+
+	a.renderTable()
+	if a.pendingSelect != "" {
+		a.selectRow(a.pendingSelect)
+		a.pendingSelect = ""
+	}
+	a.updateStatus(fmt.Sprintf("[gray]cached %s ago, f to refresh | %s", age.Round(time.Second), a.resourceStatus()))
+}
@@ -0,0 +1,72 @@
+package view
+
+import (
+	"strings"
+
+	"a9s/internal/resources"
+)
+
+// runCommand runs a command typed in the resource menu, it reports whether the text was a command
+//
+//	assume <arn>   assume a role, followed by an MFA code if required, Ctrl+U drops it
+//	cc <type>      list any Cloud Control supported type, e.g. "cc AWS::GameLift::Fleet"
+//	ctx [name]     switch to a saved context, or list them
+//	debug-stats    show the memory usage, goroutines and fetches of a9s itself
+//	dig <name>     compare the local and Route53 answers for a hostname
+//	regions <type> count the resources of a key or CloudFormation type in every enabled region
+//	tls <host>     inspect the certificate chain served by host[:port] and match it with ACM
+//	tunnels        list the port forwarding sessions running in the background
+func (a *App) runCommand(text string) bool {
+	name, args, _ := strings.Cut(strings.TrimSpace(text), " ")
+	args = strings.TrimSpace(args)
+
+	switch name {
+	case "ctx":
+		a.closeMenu()
+		if args == "" {
+			a.showContexts()
+		} else {
+			a.switchContext(args)
+		}
+		return true
+	case "debug-stats":
+		a.showResource(newDebugStats(a))
+		return true
+	case "tunnels":
+		a.showResource(newTunnelList(a))
+		return true
+	case "assume":
+		roleARN, mfaCode, _ := strings.Cut(args, " ")
+		if roleARN == "" {
+			return false
+		}
+		a.closeMenu()
+		a.assumeRole(roleARN, strings.TrimSpace(mfaCode))
+		return true
+	case "cc":
+		if args == "" {
+			return false
+		}
+		a.showResource(resources.NewCloudControlResources(args))
+		return true
+	case "dig":
+		if args == "" {
+			return false
+		}
+		a.showResource(resources.NewDNSLookup(args))
+		return true
+	case "regions":
+		if args == "" {
+			return false
+		}
+		a.showResource(resources.NewRegionMatrix(args))
+		return true
+	case "tls":
+		if args == "" {
+			return false
+		}
+		a.showResource(resources.NewTLSChain(args))
+		return true
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package view
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"a9s/internal/client"
+	"a9s/internal/resources"
+)
+
+// executeCommand runs the interactive program of an action, suspending the UI until it exits
+func (a *App) executeCommand(action resources.QuickAction, selectedID string) {
+	a.updateStatus(fmt.Sprintf("[yellow]Starting %s for %s...", action.Label, selectedID))
+	resourceName := a.current.Name()
+
+	go func() {
+		cmd, err := action.Command(a.ctx, a.client, selectedID)
+
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				a.updateStatus(fmt.Sprintf("[red]Failed to %s: %v", action.Label, err))
+				return
+			}
+
+			cmd.Stdin = os.Stdin
+			cmd.Stdout = os.Stdout
+			cmd.Stderr = os.Stderr
+			a.app.Suspend(func() {
+				err = cmd.Run()
+			})
+			a.touch()
+			a.recordAudit(resourceName, action.Label, selectedID, "", err)
+
+			if err != nil {
+				a.updateStatus(fmt.Sprintf("[red]%s exited: %v", action.Label, err))
+				return
+			}
+			a.updateStatus(fmt.Sprintf("[green]%s for %s exited", action.Label, selectedID))
+		})
+	}()
+}
+
+// editActionText prepares the template of an action, then opens it in the editor
+func (a *App) editActionText(action resources.QuickAction, selectedID string) {
+	if action.EditPrepare == nil {
+		a.openEditor(action, selectedID)
+		return
+	}
+
+	a.updateStatus(fmt.Sprintf("[yellow]Preparing %s...", action.Label))
+	go func() {
+		err := action.EditPrepare(a.ctx, a.client, selectedID)
+
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				a.updateStatus(fmt.Sprintf("[red]Failed to %s: %v", action.Label, err))
+				return
+			}
+			a.updateStatus("")
+			a.openEditor(action, selectedID)
+		})
+	}()
+}
+
+// openEditor opens the template of an action in the editor of the user, $VISUAL or
+// $EDITOR, then runs the action with the edited text once confirmed
+func (a *App) openEditor(action resources.QuickAction, selectedID string) {
+	template := action.EditTemplate(selectedID)
+
+	file, err := os.CreateTemp("", "a9s-*.txt")
+	if err != nil {
+		a.updateStatus(fmt.Sprintf("[red]Failed to %s: %v", action.Label, err))
+		return
+	}
+	defer os.Remove(file.Name())
+	_, err = file.WriteString(template)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		a.updateStatus(fmt.Sprintf("[red]Failed to %s: %v", action.Label, err))
+		return
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	// The editor may come with arguments, e.g. "code --wait"
+	args := append(strings.Fields(editor), file.Name())
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	a.app.Suspend(func() {
+		err = cmd.Run()
+	})
+	a.touch()
+	if err != nil {
+		a.updateStatus(fmt.Sprintf("[red]Editor exited: %v", err))
+		return
+	}
+
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		a.updateStatus(fmt.Sprintf("[red]Failed to %s: %v", action.Label, err))
+		return
+	}
+	text := string(data)
+	if text == template {
+		a.updateStatus(fmt.Sprintf("[yellow]Text unchanged, %s cancelled", action.Label))
+		return
+	}
+
+	// Bind the text so the action is confirmed and runs like any other one
+	bound := action
+	bound.EditTemplate = nil
+	bound.EditPrepare = nil
+	bound.EditHandler = nil
+	bound.EditPlan = nil
+	if action.EditPlan != nil {
+		bound.Plan = func(ctx context.Context, c *client.Client, id string) ([]resources.Step, error) {
+			return action.EditPlan(ctx, c, id, text)
+		}
+	} else {
+		bound.Handler = func(ctx context.Context, c *client.Client, id string) error {
+			return action.EditHandler(ctx, c, id, text)
+		}
+	}
+	a.runQuickAction(bound, selectedID)
+}
@@ -0,0 +1,81 @@
+package view
+
+import (
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// touch records user activity for the idle timeout
+func (a *App) touch() {
+	a.lastActivity.Store(time.Now().UnixNano())
+}
+
+// watchIdle locks the UI once no activity happened for the configured timeout
+func (a *App) watchIdle() {
+	interval := a.config.IdleTimeout / 10
+	if interval < time.Second {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			idle := time.Since(time.Unix(0, a.lastActivity.Load()))
+			if idle >= a.config.IdleTimeout && !a.locked.Load() {
+				a.app.QueueUpdateDraw(a.lock)
+			}
+		case <-a.stopRefresh:
+			return
+		case <-a.ctx.Done():
+			return
+		}
+	}
+}
+
+// lock hides all data behind a lock screen until the user confirms with Enter
+func (a *App) lock() {
+	if a.locked.Swap(true) {
+		return
+	}
+
+	a.lockedFocus = a.app.GetFocus()
+	a.revealed = false
+	a.table.Clear()
+
+	screen := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText("\n\n\n[::b]a9s is locked[-:-:-]\n\n[gray]Press Enter to unlock")
+
+	a.pages.AddPage("lock", screen, true, true)
+	a.app.SetFocus(screen)
+}
+
+// unlock removes the lock screen and reloads the current resource
+func (a *App) unlock() {
+	a.pages.RemovePage("lock")
+	a.locked.Store(false)
+	a.touch()
+
+	if a.lockedFocus != nil {
+		a.app.SetFocus(a.lockedFocus)
+		a.lockedFocus = nil
+	}
+
+	if a.current != nil {
+		a.refreshResource()
+	}
+}
+
+// handleLockedKey swallows all keys while locked, Enter unlocks the UI
+func (a *App) handleLockedKey(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() == tcell.KeyEnter {
+		a.unlock()
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package view
+
+import (
+	"regexp"
+	"strings"
+
+	"a9s/internal/resources"
+	"a9s/pkg/log"
+
+	"go.uber.org/zap"
+)
+
+// defaultMaskPatterns are the value patterns masked when none are configured
+var defaultMaskPatterns = []string{
+	`(?i)passw(or)?d`,
+	`(?i)secret`,
+	`(?i)token`,
+	`(?i)private[-_]?key`,
+	`[\w.+-]+@[\w-]+\.[\w.-]+`,
+}
+
+// masker hides the values of sensitive columns and of cells matching patterns
+type masker struct {
+	patterns []*regexp.Regexp
+}
+
+// newMasker compiles the mask patterns, invalid ones are skipped
+func newMasker(patterns []string) *masker {
+	m := &masker{}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			log.Warn("ignoring invalid mask pattern", zap.String("pattern", pattern), zap.Error(err))
+			continue
+		}
+		m.patterns = append(m.patterns, re)
+	}
+	return m
+}
+
+// shouldMask reports whether a cell value of the given column must be hidden
+func (m *masker) shouldMask(column resources.Column, value string) bool {
+	if value == "" {
+		return false
+	}
+	if column.Sensitive {
+		return true
+	}
+	for _, re := range m.patterns {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// mask hides a value, keeping its last characters when it is long enough to stay anonymous
+func (m *masker) mask(value string) string {
+	runes := []rune(value)
+	if len(runes) <= 8 {
+		return strings.Repeat("•", 8)
+	}
+	return strings.Repeat("•", 8) + string(runes[len(runes)-4:])
+}
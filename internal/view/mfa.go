@@ -0,0 +1,57 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// errMFACancelled is returned when the MFA prompt is closed without a code
+var errMFACancelled = errors.New("MFA code prompt cancelled")
+
+// promptMFA asks for the code of an MFA device in a modal, it blocks the calling goroutine
+// until the code is entered, which is never the UI one as AWS calls run in the background
+func (a *App) promptMFA(serial string) (string, error) {
+	codes := make(chan string, 1)
+
+	a.app.QueueUpdateDraw(func() {
+		input := tview.NewInputField().
+			SetLabel("MFA code: ").
+			SetFieldWidth(10).
+			SetAcceptanceFunc(tview.InputFieldInteger).
+			SetFieldBackgroundColor(tcell.ColorDarkSlateGray)
+
+		input.SetDoneFunc(func(key tcell.Key) {
+			a.pages.RemovePage("mfa")
+			a.pages.SwitchToPage("main")
+			a.app.SetFocus(a.table)
+
+			if key != tcell.KeyEnter {
+				codes <- ""
+				return
+			}
+			codes <- strings.TrimSpace(input.GetText())
+		})
+
+		form := tview.NewFlex().
+			SetDirection(tview.FlexRow).
+			AddItem(input, 1, 0, true)
+		form.SetBorder(true).SetTitle(fmt.Sprintf(" %s (Enter to confirm, Esc to cancel) ", serial))
+
+		a.pages.AddPage("mfa", a.createModal(form, 80, 3), true, true)
+		a.app.SetFocus(input)
+	})
+
+	select {
+	case code := <-codes:
+		if code == "" {
+			return "", errMFACancelled
+		}
+		return code, nil
+	case <-a.ctx.Done():
+		return "", a.ctx.Err()
+	}
+}
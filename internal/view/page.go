@@ -0,0 +1,58 @@
+package view
+
+import (
+	"fmt"
+
+	"a9s/internal/resources"
+)
+
+// loadMore fetches the next page of the current resource when it is pageable
+func (a *App) loadMore() {
+	pageable, ok := a.current.(resources.Pageable)
+	if !ok || !pageable.HasMore() {
+		return
+	}
+
+	a.updateStatus("[yellow]Loading more...")
+	current := a.current
+
+	a.activeFetches.Add(1)
+	go func() {
+		defer a.activeFetches.Add(-1)
+		err := pageable.FetchMore(a.ctx, a.client)
+
+		a.app.QueueUpdateDraw(func() {
+			if a.current != current {
+				return
+			}
+			if err != nil {
+				a.updateStatus(errorStatus("Error", err))
+				return
+			}
+
+			row, _ := a.table.GetSelection()
+			a.renderTable()
+			a.table.Select(row, 0)
+			a.updateStatusWithAutoRefresh("")
+		})
+	}()
+}
+
+// itemCount returns the item count of the status bar, hinting when more pages can be loaded
+func (a *App) itemCount(count int) string {
+	if pageable, ok := a.current.(resources.Pageable); ok && pageable.HasMore() {
+		return fmt.Sprintf("%d+ items (ctrl+n: more)", count)
+	}
+	return fmt.Sprintf("%d items", count)
+}
+
+// toggleMetrics switches the metric columns of the current resource and reloads it
+func (a *App) toggleMetrics() {
+	toggler, ok := a.current.(resources.MetricsToggler)
+	if !ok {
+		return
+	}
+
+	toggler.ToggleMetrics()
+	a.refreshResource()
+}
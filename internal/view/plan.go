@@ -0,0 +1,236 @@
+package view
+
+import (
+	"fmt"
+	"strings"
+
+	"a9s/internal/client"
+	"a9s/internal/resources"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// stepState is the progress of a plan step
+type stepState int
+
+const (
+	stepPending stepState = iota
+	stepRunning
+	stepDone
+	stepFailed
+)
+
+// plan is a multi-step action the user walks through
+type plan struct {
+	action       resources.QuickAction
+	selectedID   string
+	resourceName string
+	ticket       string
+	steps        []resources.Step
+	states       []stepState
+	errors       []error
+	outputs      []string
+	running      bool
+	view         *tview.TextView
+}
+
+// next returns the index of the next step to run, -1 when all steps are done
+func (p *plan) next() int {
+	for i, step := range p.steps {
+		if !step.IsNote() && p.states[i] != stepDone {
+			return i
+		}
+	}
+	return -1
+}
+
+// executePlan enumerates the steps of a multi-step action and displays them
+func (a *App) executePlan(action resources.QuickAction, selectedID, ticket string) {
+	a.updateStatus(fmt.Sprintf("[yellow]Checking dependencies of %s...", selectedID))
+	resourceName := a.current.Name()
+
+	go func() {
+		steps, err := action.Plan(a.ctx, a.client, selectedID)
+
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				a.updateStatus(fmt.Sprintf("[red]Failed to %s: %v", action.Label, err))
+				return
+			}
+
+			a.showPlan(&plan{
+				action:       action,
+				selectedID:   selectedID,
+				resourceName: resourceName,
+				ticket:       ticket,
+				steps:        steps,
+				states:       make([]stepState, len(steps)),
+				errors:       make([]error, len(steps)),
+				outputs:      make([]string, len(steps)),
+			})
+		})
+	}()
+}
+
+// showPlan displays the steps of a plan, Enter runs the next one and 'a' all the remaining ones
+func (a *App) showPlan(p *plan) {
+	p.view = tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(true)
+	p.view.SetBorder(true).SetTitle(fmt.Sprintf(" %s: %s ", p.action.Description, p.selectedID))
+	a.renderPlan(p)
+
+	p.view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape:
+			if !p.running {
+				a.closePlan(p)
+			}
+			return nil
+		case event.Key() == tcell.KeyEnter:
+			a.runPlan(p, false)
+			return nil
+		case event.Key() == tcell.KeyRune && event.Rune() == 'a':
+			a.runPlan(p, true)
+			return nil
+		case event.Key() == tcell.KeyRune && event.Rune() == 'y':
+			a.copyPlanOutput(p)
+			return nil
+		}
+		return event
+	})
+
+	a.pages.AddPage("plan", a.createModal(p.view, 100, min(len(p.steps)+6, 30)), true, true)
+	a.app.SetFocus(p.view)
+}
+
+// renderPlan renders the steps of a plan with their progress
+func (a *App) renderPlan(p *plan) {
+	var b strings.Builder
+	for i, step := range p.steps {
+		description := tview.Escape(step.Description)
+		switch {
+		case step.IsNote():
+			fmt.Fprintf(&b, "[gray]  • %s[-]\n", description)
+		case p.states[i] == stepRunning:
+			fmt.Fprintf(&b, "[yellow]  … %s[-]\n", description)
+		case p.states[i] == stepDone:
+			fmt.Fprintf(&b, "[green]  ✓ %s[-]\n", description)
+			for _, line := range strings.Split(p.outputs[i], "\n") {
+				if line != "" {
+					fmt.Fprintf(&b, "      [white::b]%s[-:-:-]\n", tview.Escape(line))
+				}
+			}
+		case p.states[i] == stepFailed:
+			fmt.Fprintf(&b, "[red]  ✗ %s: %s[-]\n", description, tview.Escape(p.errors[i].Error()))
+		default:
+			fmt.Fprintf(&b, "  ○ %s\n", description)
+		}
+	}
+
+	help := "Enter: run next step | a: run all remaining steps | Esc: stop here"
+	if p.next() < 0 {
+		help = "[green]All steps completed[-][gray], Enter or Esc to close"
+	}
+	for _, output := range p.outputs {
+		if output != "" {
+			help += " | y: copy output, it is shown only once"
+			break
+		}
+	}
+	fmt.Fprintf(&b, "\n[gray]%s[-]", help)
+	p.view.SetText(b.String())
+}
+
+// runPlan runs the next step of a plan, and the following ones when all is set
+func (a *App) runPlan(p *plan, all bool) {
+	if p.running {
+		return
+	}
+
+	index := p.next()
+	if index < 0 {
+		a.closePlan(p)
+		return
+	}
+
+	p.running = true
+	p.states[index] = stepRunning
+	a.renderPlan(p)
+
+	ctx := a.actionsCtx
+	if p.ticket != "" {
+		ctx = client.WithTicket(ctx, p.ticket)
+	}
+	step := p.steps[index]
+
+	a.beginAction()
+	go func() {
+		defer a.endAction()
+		var output string
+		var err error
+		if step.RunText != nil {
+			output, err = step.RunText(ctx, a.client)
+		} else {
+			err = step.Run(ctx, a.client)
+		}
+		a.recordAudit(p.resourceName, fmt.Sprintf("%s: %s", p.action.Label, step.Description), p.selectedID, p.ticket, err)
+
+		a.app.QueueUpdateDraw(func() {
+			p.running = false
+			if err != nil {
+				p.states[index] = stepFailed
+				p.errors[index] = err
+				a.renderPlan(p)
+				a.updateStatus(fmt.Sprintf("[red]Step failed: %s, Enter to retry", step.Description))
+				return
+			}
+
+			p.states[index] = stepDone
+			p.outputs[index] = output
+			a.renderPlan(p)
+			if p.next() < 0 {
+				a.updateStatus(fmt.Sprintf("[green]Successfully completed %s for %s", p.action.Label, p.selectedID))
+				return
+			}
+			if all {
+				a.runPlan(p, true)
+			}
+		})
+	}()
+}
+
+// copyPlanOutput copies the outputs of the completed steps to the clipboard
+func (a *App) copyPlanOutput(p *plan) {
+	outputs := make([]string, 0)
+	for _, output := range p.outputs {
+		if output != "" {
+			outputs = append(outputs, output)
+		}
+	}
+	if len(outputs) == 0 {
+		return
+	}
+	a.copyToClipboard(strings.Join(outputs, "\n"))
+}
+
+// closePlan closes the plan and refreshes the resource when steps were run, or opens the
+// view following the action once all of them ran
+func (a *App) closePlan(p *plan) {
+	a.pages.RemovePage("plan")
+	a.pages.SwitchToPage("main")
+	a.app.SetFocus(a.table)
+
+	if p.next() < 0 && p.action.Follow != nil {
+		a.openView(p.action.Follow(p.selectedID))
+		return
+	}
+	for _, state := range p.states {
+		if state == stepDone {
+			a.refreshResource()
+			return
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package view
+
+import (
+	"fmt"
+	"strings"
+
+	"a9s/pkg/log"
+
+	"go.uber.org/zap"
+)
+
+// checkPermissions simulates the IAM actions required by a resource before its
+// first fetch, fn runs once they are allowed or when the check itself fails
+func (a *App) checkPermissions(key string, fn func()) {
+	meta, ok := a.registry.Metadata(key)
+	checked := a.client.Profile() + "/" + key
+	if !ok || len(meta.Permissions) == 0 || a.checkedPermissions[checked] {
+		fn()
+		return
+	}
+
+	a.updateStatus(fmt.Sprintf("[yellow]Checking permissions for %s...", key))
+
+	go func() {
+		missing, err := a.client.MissingPermissions(a.ctx, meta.Permissions)
+
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				// The simulator needs iam:SimulatePrincipalPolicy, let the API report errors instead
+				log.Debug("skipping permission pre-flight", zap.String("resource", key), zap.Error(err))
+				a.checkedPermissions[checked] = true
+				fn()
+				return
+			}
+
+			if len(missing) > 0 {
+				a.closeMenu()
+				a.showText(fmt.Sprintf("Missing permissions for %s", key), fmt.Sprintf(
+					"Your identity is not allowed to list %s, you need:\n\n  %s\n\nDisable this check with --preflight=false.",
+					meta.Description, strings.Join(missing, "\n  ")))
+				a.updateStatus(fmt.Sprintf("[red]Missing permissions for %s", key))
+				return
+			}
+
+			a.checkedPermissions[checked] = true
+			fn()
+		})
+	}()
+}
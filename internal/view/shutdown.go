@@ -0,0 +1,70 @@
+package view
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+// cancelTimeout bounds the wait for cancelled actions to return before quitting
+const cancelTimeout = 10 * time.Second
+
+// beginAction registers a mutating action running in the background
+func (a *App) beginAction() {
+	a.pendingActions.Add(1)
+	a.actions.Add(1)
+}
+
+// endAction unregisters a finished mutating action
+func (a *App) endAction() {
+	a.pendingActions.Add(-1)
+	a.actions.Done()
+}
+
+// quit exits the application, asking whether to wait for or cancel the
+// mutating actions still in flight so they are not left half-completed
+func (a *App) quit() {
+	pending := a.pendingActions.Load()
+	if pending == 0 {
+		a.app.Stop()
+		return
+	}
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("[yellow]%d action(s) still running[-]\n\nQuitting now may leave them half-completed.", pending)).
+		AddButtons([]string{"Wait", "Cancel and quit", "Back"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			a.pages.RemovePage("quit")
+			a.pages.SwitchToPage("main")
+			a.app.SetFocus(a.table)
+
+			switch buttonLabel {
+			case "Wait":
+				a.updateStatus(fmt.Sprintf("[yellow]Waiting for %d action(s) to finish before quitting...", a.pendingActions.Load()))
+				go func() {
+					a.actions.Wait()
+					a.app.Stop()
+				}()
+			case "Cancel and quit":
+				a.updateStatus("[yellow]Cancelling running actions...")
+				a.cancelActions()
+				go func() {
+					done := make(chan struct{})
+					go func() {
+						a.actions.Wait()
+						close(done)
+					}()
+
+					select {
+					case <-done:
+					case <-time.After(cancelTimeout):
+					}
+					a.app.Stop()
+				}()
+			}
+		})
+
+	a.pages.AddPage("quit", modal, true, true)
+	a.app.SetFocus(modal)
+}
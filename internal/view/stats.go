@@ -0,0 +1,107 @@
+package view
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"time"
+
+	"a9s/internal/client"
+	"a9s/internal/resources"
+)
+
+// debugStats implements Resource for the runtime statistics of a9s itself,
+// to diagnose leaks in long-running sessions
+type debugStats struct {
+	app   *App
+	stats [][]string
+}
+
+// newDebugStats creates a new debugStats resource
+func newDebugStats(a *App) *debugStats {
+	return &debugStats{
+		app:   a,
+		stats: make([][]string, 0),
+	}
+}
+
+// Name returns the display name
+func (d *debugStats) Name() string {
+	return "Debug Stats"
+}
+
+// Columns returns the column definitions
+func (d *debugStats) Columns() []resources.Column {
+	return []resources.Column{
+		{Name: "Metric", Width: 30},
+		{Name: "Value", Width: 30},
+	}
+}
+
+// Fetch collects the runtime statistics
+func (d *debugStats) Fetch(ctx context.Context, c *client.Client) error {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	lastPause := time.Duration(0)
+	if mem.NumGC > 0 {
+		lastPause = time.Duration(mem.PauseNs[(mem.NumGC+255)%256])
+	}
+
+	// The view itself is being fetched, do not count it
+	fetches := d.app.activeFetches.Load() - 1
+
+	d.stats = [][]string{
+		{"Uptime", time.Since(d.app.startedAt).Truncate(time.Second).String()},
+		{"Goroutines", fmt.Sprintf("%d", runtime.NumGoroutine())},
+		{"Heap allocated", formatBytes(mem.HeapAlloc)},
+		{"Heap in use", formatBytes(mem.HeapInuse)},
+		{"Heap objects", fmt.Sprintf("%d", mem.HeapObjects)},
+		{"Memory from OS", formatBytes(mem.Sys)},
+		{"GC cycles", fmt.Sprintf("%d", mem.NumGC)},
+		{"Last GC pause", lastPause.String()},
+		{"Active fetches", fmt.Sprintf("%d", fetches)},
+		{"Running actions", fmt.Sprintf("%d", d.app.pendingActions.Load())},
+	}
+
+	return nil
+}
+
+// formatBytes renders a size in bytes with a binary unit
+func formatBytes(size uint64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := uint64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// Rows returns the table data, the view state is read here as Rows runs on the UI goroutine
+func (d *debugStats) Rows() [][]string {
+	rows := append([][]string{}, d.stats...)
+	return append(rows,
+		[]string{"Drill-down depth", fmt.Sprintf("%d", len(d.app.history))},
+		[]string{"Permission checks cached", fmt.Sprintf("%d", len(d.app.checkedPermissions))},
+		[]string{"Views cached", fmt.Sprintf("%d", len(d.app.fetched))},
+		[]string{"Auto-refresh", fmt.Sprintf("%t", d.app.autoRefresh)},
+	)
+}
+
+// GetID returns the metric name at the given index
+func (d *debugStats) GetID(index int) string {
+	rows := d.Rows()
+	if index >= 0 && index < len(rows) {
+		return rows[index][0]
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for the debug stats
+func (d *debugStats) QuickActions() []resources.QuickAction {
+	return []resources.QuickAction{}
+}
@@ -0,0 +1,81 @@
+package view
+
+import (
+	"fmt"
+
+	"a9s/internal/clipboard"
+	"a9s/internal/resources"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// executeTextAction runs a text action, then copies or displays its result
+func (a *App) executeTextAction(action resources.QuickAction, selectedID string) {
+	a.updateStatus(fmt.Sprintf("[yellow]%s %s...", action.Description, selectedID))
+
+	ctx := resources.WithProgress(a.ctx, func(text string) {
+		a.app.QueueUpdateDraw(func() {
+			a.updateStatus("[yellow]" + text)
+		})
+	})
+
+	go func() {
+		text, err := action.TextHandler(ctx, a.client, selectedID)
+
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				a.updateStatus(fmt.Sprintf("[red]Failed to %s: %v", action.Label, err))
+				return
+			}
+
+			if action.Clipboard {
+				a.copyToClipboard(text)
+				return
+			}
+
+			a.showText(fmt.Sprintf("%s: %s", action.Description, selectedID), text)
+		})
+	}()
+}
+
+// copyToClipboard copies a text to the system clipboard and reports it in the status bar
+func (a *App) copyToClipboard(text string) {
+	if err := clipboard.Write(text); err != nil {
+		a.updateStatus(fmt.Sprintf("[red]Failed to copy to clipboard: %v", err))
+		return
+	}
+	a.updateStatus(fmt.Sprintf("[green]Copied %d characters to clipboard", len(text)))
+}
+
+// showText displays a read-only text in a scrollable panel, 'y' copies it
+func (a *App) showText(title, text string) {
+	view := tview.NewTextView().
+		SetDynamicColors(false).
+		SetScrollable(true).
+		SetWrap(true).
+		SetText(text)
+	view.SetBorder(true).SetTitle(fmt.Sprintf(" %s (y to copy, Esc to close) ", title))
+
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape:
+			a.closeText()
+			return nil
+		case event.Key() == tcell.KeyRune && event.Rune() == 'y':
+			a.copyToClipboard(text)
+			return nil
+		}
+		return event
+	})
+
+	a.pages.AddPage("text", a.createModal(view, 100, 30), true, true)
+	a.app.SetFocus(view)
+}
+
+// closeText closes the text panel and returns to the table
+func (a *App) closeText() {
+	a.pages.RemovePage("text")
+	a.pages.SwitchToPage("main")
+	a.app.SetFocus(a.table)
+}
@@ -0,0 +1,23 @@
+package view
+
+import (
+	"fmt"
+
+	"a9s/internal/client"
+)
+
+// notifyThrottle shows in the status bar that AWS throttles a call, the SDK backs off and retries it
+func (a *App) notifyThrottle(service, operation, code string) {
+	a.app.QueueUpdateDraw(func() {
+		a.updateStatus(fmt.Sprintf("[yellow]Throttled by %s on %s (%s), backing off...", service, operation, code))
+	})
+}
+
+// errorStatus returns the status bar text of a failure, throttling which outlasted the
+// retries is explained rather than shown as the raw error
+func errorStatus(prefix string, err error) string {
+	if code, ok := client.ThrottleCode(err); ok {
+		return fmt.Sprintf("[yellow]%s: AWS is throttling the calls (%s) after every retry, try again in a moment or lower rate-limits", prefix, code)
+	}
+	return fmt.Sprintf("[red]%s: %v", prefix, err)
+}
@@ -0,0 +1,201 @@
+package view
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"time"
+
+	"a9s/internal/client"
+	"a9s/internal/resources"
+)
+
+// tunnel is a program running in the background for an action, e.g. a port forwarding session
+type tunnel struct {
+	id          int
+	description string
+	target      string
+	started     time.Time
+	cmd         *exec.Cmd
+}
+
+// startTunnel starts the background program of an action, it runs until closed or a9s quits
+func (a *App) startTunnel(action resources.QuickAction, selectedID string) {
+	a.updateStatus(fmt.Sprintf("[yellow]Starting %s for %s...", action.Label, selectedID))
+	resourceName := a.current.Name()
+
+	go func() {
+		cmd, err := action.Tunnel(a.ctx, a.client, selectedID)
+		if err == nil {
+			// The output of the program would corrupt the UI, it is discarded
+			detachTunnel(cmd)
+			err = cmd.Start()
+		}
+		a.recordAudit(resourceName, action.Label, selectedID, "", err)
+
+		var t *tunnel
+		if err == nil {
+			a.tunnelsMu.Lock()
+			a.nextTunnel++
+			t = &tunnel{
+				id:          a.nextTunnel,
+				description: action.Description,
+				target:      selectedID,
+				started:     time.Now(),
+				cmd:         cmd,
+			}
+			a.tunnels[t.id] = t
+			a.tunnelsMu.Unlock()
+			go a.waitTunnel(t)
+		}
+
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				a.updateStatus(fmt.Sprintf("[red]Failed to %s: %v", action.Label, err))
+				return
+			}
+			a.updateHeader()
+			a.updateStatus(fmt.Sprintf("[green]%s for %s started, type tunnels to list or close it", action.Label, selectedID))
+		})
+	}()
+}
+
+// waitTunnel forgets a tunnel once its program exits
+func (a *App) waitTunnel(t *tunnel) {
+	err := t.cmd.Wait()
+
+	a.tunnelsMu.Lock()
+	_, open := a.tunnels[t.id]
+	delete(a.tunnels, t.id)
+	a.tunnelsMu.Unlock()
+
+	a.app.QueueUpdateDraw(func() {
+		a.updateHeader()
+		if open {
+			a.updateStatus(fmt.Sprintf("[yellow]Tunnel %s exited: %v", t.description, err))
+		}
+	})
+}
+
+// closeTunnel kills the program of a tunnel
+func (a *App) closeTunnel(id int) error {
+	a.tunnelsMu.Lock()
+	t, ok := a.tunnels[id]
+	delete(a.tunnels, id)
+	a.tunnelsMu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("tunnel %d is not running", id)
+	}
+	return killTunnel(t.cmd)
+}
+
+// closeTunnels kills the programs of all tunnels, when a9s quits
+func (a *App) closeTunnels() {
+	a.tunnelsMu.Lock()
+	defer a.tunnelsMu.Unlock()
+
+	for id, t := range a.tunnels {
+		_ = killTunnel(t.cmd)
+		delete(a.tunnels, id)
+	}
+}
+
+// tunnelCount returns the number of running tunnels
+func (a *App) tunnelCount() int {
+	a.tunnelsMu.Lock()
+	defer a.tunnelsMu.Unlock()
+	return len(a.tunnels)
+}
+
+// tunnelList implements Resource for the tunnels running in the background
+type tunnelList struct {
+	app     *App
+	tunnels []tunnel
+}
+
+// newTunnelList creates a new tunnelList resource
+func newTunnelList(a *App) *tunnelList {
+	return &tunnelList{
+		app:     a,
+		tunnels: make([]tunnel, 0),
+	}
+}
+
+// Name returns the display name
+func (t *tunnelList) Name() string {
+	return "Tunnels"
+}
+
+// Columns returns the column definitions
+func (t *tunnelList) Columns() []resources.Column {
+	return []resources.Column{
+		{Name: "ID", Width: 4},
+		{Name: "Tunnel", Width: 50},
+		{Name: "Target", Width: 40},
+		{Name: "PID", Width: 8},
+		{Name: "Started", Width: 20},
+	}
+}
+
+// Fetch takes a snapshot of the running tunnels
+func (t *tunnelList) Fetch(ctx context.Context, c *client.Client) error {
+	t.app.tunnelsMu.Lock()
+	t.tunnels = make([]tunnel, 0, len(t.app.tunnels))
+	for _, running := range t.app.tunnels {
+		t.tunnels = append(t.tunnels, *running)
+	}
+	t.app.tunnelsMu.Unlock()
+
+	sort.Slice(t.tunnels, func(i, j int) bool {
+		return t.tunnels[i].id < t.tunnels[j].id
+	})
+
+	return nil
+}
+
+// Rows returns the table data
+func (t *tunnelList) Rows() [][]string {
+	rows := make([][]string, len(t.tunnels))
+	for i, running := range t.tunnels {
+		rows[i] = []string{
+			fmt.Sprintf("%d", running.id),
+			running.description,
+			running.target,
+			fmt.Sprintf("%d", running.cmd.Process.Pid),
+			running.started.Format("2006-01-02 15:04:05"),
+		}
+	}
+	return rows
+}
+
+// GetID returns the tunnel ID at the given index
+func (t *tunnelList) GetID(index int) string {
+	if index >= 0 && index < len(t.tunnels) {
+		return fmt.Sprintf("%d", t.tunnels[index].id)
+	}
+	return ""
+}
+
+// QuickActions returns the available quick actions for the tunnels
+func (t *tunnelList) QuickActions() []resources.QuickAction {
+	return []resources.QuickAction{
+		{
+			Key:             'X',
+			Label:           "close",
+			Description:     "Close tunnel",
+			NeedsSelection:  true,
+			NeedsConfirm:    true,
+			ConfirmTemplate: "[red]close[-] tunnel [white]%s[-]?",
+			Handler: func(ctx context.Context, c *client.Client, selectedID string) error {
+				id, err := strconv.Atoi(selectedID)
+				if err != nil {
+					return fmt.Errorf("invalid tunnel ID %s", selectedID)
+				}
+				return t.app.closeTunnel(id)
+			},
+		},
+	}
+}
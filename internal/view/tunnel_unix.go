@@ -0,0 +1,19 @@
+//go:build !windows
+
+package view
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detachTunnel runs the program of a tunnel in its own process group, so that the signals of
+// the terminal do not reach it and closing it also stops the programs it started
+func detachTunnel(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killTunnel kills the process group of a tunnel
+func killTunnel(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
@@ -0,0 +1,17 @@
+package view
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detachTunnel runs the program of a tunnel in its own process group, so that the Ctrl+C of
+// the console does not reach it
+func detachTunnel(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// killTunnel kills the program of a tunnel
+func killTunnel(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
@@ -0,0 +1,72 @@
+package view
+
+import (
+	"fmt"
+	"strings"
+
+	"a9s/internal/workspace"
+)
+
+// showContexts lists the saved contexts, the current profile and region are starred
+func (a *App) showContexts() {
+	contexts, err := workspace.List()
+	if err != nil {
+		a.updateStatus(fmt.Sprintf("[red]%v", err))
+		return
+	}
+	if len(contexts) == 0 {
+		a.updateStatus("[yellow]No contexts defined, add them under 'contexts' in ~/.a9s/config.yaml")
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "  %-20s %-20s %-15s %s\n", "NAME", "PROFILE", "REGION", "RESOURCE")
+	for _, name := range workspace.Names(contexts) {
+		c := contexts[name]
+		marker := " "
+		if c.Profile == a.client.Profile() && c.Region == a.client.Region() {
+			marker = "*"
+		}
+		fmt.Fprintf(&b, "%s %-20s %-20s %-15s %s\n", marker, name, c.Profile, c.Region, c.Resource)
+	}
+	b.WriteString("\nSwitch with 'ctx <name>' in the menu.")
+
+	a.showText("Contexts", b.String())
+}
+
+// switchContext applies the profile, region and resource of a saved context
+func (a *App) switchContext(name string) {
+	c, err := workspace.Get(name)
+	if err != nil {
+		a.updateStatus(fmt.Sprintf("[red]%v", err))
+		return
+	}
+
+	a.updateStatus(fmt.Sprintf("[yellow]Switching to context: %s...", name))
+
+	go func() {
+		var err error
+		if c.Profile != "" {
+			err = a.client.SetProfile(a.ctx, c.Profile)
+		}
+		if err == nil && c.Region != "" {
+			err = a.client.SetRegion(a.ctx, c.Region)
+		}
+
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				a.updateStatus(fmt.Sprintf("[red]Failed to switch context: %v", err))
+				return
+			}
+
+			a.updateHeader()
+			a.updateStatus(fmt.Sprintf("[green]Switched to context: %s", name))
+
+			if c.Resource != "" {
+				a.selectResource(c.Resource)
+			} else if a.current != nil {
+				a.refreshResource()
+			}
+		})
+	}()
+}
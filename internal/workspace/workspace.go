@@ -0,0 +1,101 @@
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// ErrNotFound is returned when a context is not defined in the configuration
+var ErrNotFound = errors.New("context not found")
+
+// Context is a saved set of profile, region and resource that a9s can start in.
+// Contexts are defined in the configuration file:
+//
+//	contexts:
+//	  prod:
+//	    profile: prod
+//	    region: eu-west-1
+//	    resource: ec2
+type Context struct {
+	Profile  string `mapstructure:"profile"`
+	Region   string `mapstructure:"region"`
+	Resource string `mapstructure:"resource"`
+}
+
+// List returns the contexts of the configuration, by name
+func List() (map[string]Context, error) {
+	contexts := make(map[string]Context)
+	if err := viper.UnmarshalKey("contexts", &contexts); err != nil {
+		return nil, fmt.Errorf("failed to read contexts: %w", err)
+	}
+	return contexts, nil
+}
+
+// Names returns the sorted names of the contexts
+func Names(contexts map[string]Context) []string {
+	names := make([]string, 0, len(contexts))
+	for name := range contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns a context by name
+func Get(name string) (Context, error) {
+	contexts, err := List()
+	if err != nil {
+		return Context{}, err
+	}
+
+	ctx, ok := contexts[name]
+	if !ok {
+		return Context{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return ctx, nil
+}
+
+// Current returns the name of the pinned context, the --context flag and the
+// A9S_CONTEXT environment variable take precedence over "a9s ctx use"
+func Current() string {
+	if name := viper.GetString("context"); name != "" {
+		return name
+	}
+
+	data, err := os.ReadFile(statePath())
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// Use pins a context, a9s starts in it until another one is used
+func Use(name string) error {
+	if _, err := Get(name); err != nil {
+		return err
+	}
+
+	path := statePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(name+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// statePath returns the path of the file holding the pinned context
+func statePath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".a9s", "context")
+	}
+	return filepath.Join(home, ".a9s", "context")
+}